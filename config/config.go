@@ -11,6 +11,22 @@ type Config struct {
 	Redis    Redis
 	Postgres Postgres
 	InfluxDB InfluxDB
+	Stream   Stream
+	Alerts   Alerts
+
+	Notifications Notifications
+	Anomalies     Anomalies
+	SLOs          SLOs
+	Tenancy       Tenancy
+	Auth          Auth
+	Mail          Mail
+	Waitlist      Waitlist
+	Digests       Digests
+	AgentEvents   AgentEvents
+	Lifecycles    Lifecycles
+	Reconciler    Reconciler
+
+	TransactionsCollector TransactionsCollector
 }
 
 // Redis is the struct that holds the configuration of the Redis connection
@@ -47,3 +63,151 @@ type InfluxDB struct {
 	TelegrafURL string `envconfig:"INFLUXDB_TELEGRAF_URL" default:"http://localhost:8087"`
 	Token       string `envconfig:"INFLUXDB_TOKEN" default:""`
 }
+
+// Stream is the struct that holds the configuration of the live stream of
+// ingested transactions. AllowedOrigins are the web pages that can open the
+// stream WebSockets, which can use a wildcard for the subdomains such as
+// "https://*.example.com", only the collector's own by default.
+type Stream struct {
+	BufferSize        int           `envconfig:"STREAM_BUFFER_SIZE" default:"256"`
+	HeartbeatInterval time.Duration `envconfig:"STREAM_HEARTBEAT_INTERVAL" default:"15s"`
+	AllowedOrigins    []string      `envconfig:"STREAM_ALLOWED_ORIGINS" default:""`
+}
+
+// Alerts is the struct that holds the configuration of the alert rules
+// evaluator and its webhook notifications.
+type Alerts struct {
+	EvaluationInterval time.Duration `envconfig:"ALERTS_EVALUATION_INTERVAL" default:"1m"`
+	WebhookURL         string        `envconfig:"ALERTS_WEBHOOK_URL" default:""`
+	WebhookSecret      string        `envconfig:"ALERTS_WEBHOOK_SECRET" default:""`
+}
+
+// Notifications is the struct that holds the configuration of the
+// notifications outbox dispatcher.
+type Notifications struct {
+	DispatchInterval time.Duration `envconfig:"NOTIFICATIONS_DISPATCH_INTERVAL" default:"10s"`
+	MaxAttempts      int           `envconfig:"NOTIFICATIONS_MAX_ATTEMPTS" default:"8"`
+}
+
+// Anomalies is the struct that holds the configuration of the anomaly
+// detector, Sensitivity is the z-score beyond which a window is flagged and
+// Alpha the weight of every new window in the hour of week baselines.
+type Anomalies struct {
+	Window      time.Duration `envconfig:"ANOMALIES_WINDOW" default:"5m"`
+	Sensitivity float64       `envconfig:"ANOMALIES_SENSITIVITY" default:"3"`
+	Alpha       float64       `envconfig:"ANOMALIES_ALPHA" default:"0.1"`
+	MinSamples  int64         `envconfig:"ANOMALIES_MIN_SAMPLES" default:"12"`
+}
+
+// SLOs is the struct that holds the configuration of the SLOs tracker, which
+// records the status history of every SLO every TrackingInterval.
+type SLOs struct {
+	TrackingInterval time.Duration `envconfig:"SLOS_TRACKING_INTERVAL" default:"15m"`
+}
+
+// Tenancy is the struct that holds the configuration of the organizations and
+// projects, when it's not enabled every request belongs to the default
+// project. BucketPerProject stores the metrics of every project in its own
+// InfluxDB buckets. APIKeysRequired rejects the agents events sent without
+// an API key, which otherwise belong to the default project.
+type Tenancy struct {
+	Enabled          bool `envconfig:"TENANCY_ENABLED" default:"false"`
+	BucketPerProject bool `envconfig:"TENANCY_BUCKET_PER_PROJECT" default:"false"`
+	APIKeysRequired  bool `envconfig:"TENANCY_API_KEYS_REQUIRED" default:"false"`
+
+	// Deprecated: AdminToken is an alias of one more Auth.AdminTokens token,
+	// set AUTH_ADMIN_TOKENS instead.
+	AdminToken string `envconfig:"TENANCY_ADMIN_TOKEN" default:""`
+}
+
+// Auth is the struct that holds the configuration of the authentication of
+// the manager and query endpoints. The JWTs are verified with the JWKS of
+// JWKSFile or, when not set, JWKSURL, and get their role and project from
+// the RoleClaim and ProjectClaim, which can be nested paths such as
+// "realm_access.roles". AdminTokens are static tokens for automation.
+type Auth struct {
+	Enabled             bool          `envconfig:"AUTH_ENABLED" default:"false"`
+	JWKSFile            string        `envconfig:"AUTH_JWKS_FILE" default:""`
+	JWKSURL             string        `envconfig:"AUTH_JWKS_URL" default:""`
+	JWKSRefreshInterval time.Duration `envconfig:"AUTH_JWKS_REFRESH_INTERVAL" default:"1h"`
+	Issuer              string        `envconfig:"AUTH_ISSUER" default:""`
+	Audience            string        `envconfig:"AUTH_AUDIENCE" default:""`
+	RoleClaim           string        `envconfig:"AUTH_ROLE_CLAIM" default:"role"`
+	ProjectClaim        string        `envconfig:"AUTH_PROJECT_CLAIM" default:"project_id"`
+	AdminTokens         []string      `envconfig:"AUTH_ADMIN_TOKENS" default:""`
+	ClockSkew           time.Duration `envconfig:"AUTH_CLOCK_SKEW" default:"1m"`
+}
+
+// Mail is the struct that holds the configuration of the SMTP server the
+// collector emails are sent through, the defaults point to a local SMTP
+// stand-in such as Mailpit.
+type Mail struct {
+	SMTPHost     string `envconfig:"MAIL_SMTP_HOST" default:"localhost"`
+	SMTPPort     string `envconfig:"MAIL_SMTP_PORT" default:"1025"`
+	SMTPUsername string `envconfig:"MAIL_SMTP_USERNAME" default:""`
+	SMTPPassword string `envconfig:"MAIL_SMTP_PASSWORD" default:""`
+	From         string `envconfig:"MAIL_FROM" default:"noreply@localhost"`
+}
+
+// Waitlist is the struct that holds the configuration of the waitlist
+// emails, PublicURL is where the collector is reached at to build the
+// confirmation and unsubscribe links, and InviteURL the page the invited
+// emails are sent to.
+type Waitlist struct {
+	PublicURL       string        `envconfig:"WAITLIST_PUBLIC_URL" default:"http://localhost:3001"`
+	InviteURL       string        `envconfig:"WAITLIST_INVITE_URL" default:"http://localhost:3000"`
+	ConfirmationTTL time.Duration `envconfig:"WAITLIST_CONFIRMATION_TTL" default:"72h"`
+	ResendInterval  time.Duration `envconfig:"WAITLIST_RESEND_INTERVAL" default:"5m"`
+}
+
+// Digests is the struct that holds the configuration of the weekly program
+// digests, sent every Weekday at Hour UTC. The scheduler checks for due
+// digests every CheckInterval and PublicURL is where the collector is
+// reached at to build the unsubscribe links.
+type Digests struct {
+	Weekday       string        `envconfig:"DIGESTS_WEEKDAY" default:"monday"`
+	Hour          int           `envconfig:"DIGESTS_HOUR" default:"9"`
+	CheckInterval time.Duration `envconfig:"DIGESTS_CHECK_INTERVAL" default:"1h"`
+	PublicURL     string        `envconfig:"DIGESTS_PUBLIC_URL" default:"http://localhost:3001"`
+}
+
+// AgentEvents is the struct that holds the configuration of the agent events
+// stored in Redis, namespaced with KeyPrefix. The sendTransaction calls are
+// kept for PendingTTL until they're joined with their transactions and for
+// JoinedTTL after, and the stored events are reported every ReportInterval.
+type AgentEvents struct {
+	KeyPrefix      string        `envconfig:"AGENT_EVENTS_KEY_PREFIX" default:"encinitas"`
+	PendingTTL     time.Duration `envconfig:"AGENT_EVENTS_PENDING_TTL" default:"72h"`
+	JoinedTTL      time.Duration `envconfig:"AGENT_EVENTS_JOINED_TTL" default:"6h"`
+	ReportInterval time.Duration `envconfig:"AGENT_EVENTS_REPORT_INTERVAL" default:"5m"`
+}
+
+// Lifecycles is the struct that holds the configuration of the transaction
+// lifecycles, the slot statuses are polled every SlotTrackingInterval, which
+// bounds how accurate the times to confirmed and finalized are.
+type Lifecycles struct {
+	SlotTrackingInterval time.Duration `envconfig:"LIFECYCLES_SLOT_TRACKING_INTERVAL" default:"500ms"`
+}
+
+// Reconciler is the struct that holds the configuration of the reconciler of
+// the transactions sent through the agents, which runs every Interval. The
+// transactions whose blockhash never shows up on chain are considered
+// expired after ExpiryTimeout.
+type Reconciler struct {
+	Interval      time.Duration `envconfig:"RECONCILER_INTERVAL" default:"15s"`
+	ExpiryTimeout time.Duration `envconfig:"RECONCILER_EXPIRY_TIMEOUT" default:"5m"`
+}
+
+// TransactionsCollector is the struct that holds the configuration of the
+// transactions collector, notified of every transaction inserted. The
+// database is also polled every PollInterval for the missed notifications,
+// and the lost listener connections are re-established between
+// MinReconnectInterval and MaxReconnectInterval. The transactions claimed
+// by a replica are claimed again by any after VisibilityTimeout when they
+// weren't processed, which must be longer than publishing a batch takes.
+type TransactionsCollector struct {
+	PollInterval         time.Duration `envconfig:"TRANSACTIONS_COLLECTOR_POLL_INTERVAL" default:"30s"`
+	MinReconnectInterval time.Duration `envconfig:"TRANSACTIONS_COLLECTOR_MIN_RECONNECT_INTERVAL" default:"1s"`
+	MaxReconnectInterval time.Duration `envconfig:"TRANSACTIONS_COLLECTOR_MAX_RECONNECT_INTERVAL" default:"1m"`
+	VisibilityTimeout    time.Duration `envconfig:"TRANSACTIONS_COLLECTOR_VISIBILITY_TIMEOUT" default:"5m"`
+}
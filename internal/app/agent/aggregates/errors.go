@@ -0,0 +1,9 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrInvalidEvent        = errors.New("invalid event")
+	ErrEventNotFound       = errors.New("event not found")
+	ErrInvalidEventsFilter = errors.New("invalid events filter")
+)
@@ -1,22 +1,176 @@
 package aggregates
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// CurrentSchemaVersion is the version of the agent events schema the
+	// collector expects, older versions are upgraded on ingestion.
+	CurrentSchemaVersion = 2
+
+	// EventTypeRPCCall is the type of the events of an RPC call made by a
+	// dApp, the only type of event so far.
+	EventTypeRPCCall = "rpc_call"
+
+	// ErrorTypeTimeout and ErrorTypeNetwork are the errors the agent reports
+	// when a call didn't get a response, either because it timed out or
+	// because of a network error, e.g. a CORS or DNS failure.
+	ErrorTypeTimeout = "timeout"
+	ErrorTypeNetwork = "network"
+
+	maxIDLength          = 128
+	maxAgentLength       = 64
+	maxURLLength         = 4096
+	maxHeaders           = 100
+	maxHeaderLength      = 8 << 10
+	maxBodyLength        = 1 << 20
+	maxShortFieldLength  = 256
+	maxErrorLength       = 1024
+	maxEventTimeInFuture = 24 * time.Hour
+)
 
 // Event represents an event coming from browser/mobile, including both
-// request and response data.
+// request and response data, ProjectID is the project whose credentials the
+// agent sent the event with.
+//
+// SchemaVersion is the version the agent sent the event with, events of
+// older versions are upgraded to this aggregate, and Agent and AgentVersion
+// identify the agent, e.g. "browser" and "1.4.0". Region is the region of
+// the client that made the call, e.g. a country code, if known. Truncated is
+// set when a body of the call was dropped for being too large.
 type Event struct {
 	ID                string
+	ProjectID         int64
+	SchemaVersion     int
+	Agent             string
+	AgentVersion      string
+	EventType         string
+	Region            string
 	BrowserID         string
 	ClientID          string
-	Handled           interface{}
+	Handled           bool
 	ReplacesClientID  *string
 	ResultingClientID string
 	EventTime         time.Time
 	Request           *Request
 	Response          *Response
+	Error             *EventError
+	Truncated         bool
 
 	// Processed Information
-	ProgramIDs []string
+	ProgramIDs  []string
+	Transaction *SentTransaction
+}
+
+// DropLargeBodies returns the event without the request and response bodies
+// larger than maxBodyLength, marked as truncated, so the rest of the call
+// is kept instead of rejecting the whole event.
+func (e Event) DropLargeBodies() Event {
+	if e.Request != nil && e.Request.Body != nil &&
+		len(*e.Request.Body) > maxBodyLength {
+		request := *e.Request
+		request.Body = nil
+		e.Request = &request
+		e.Truncated = true
+	}
+
+	if e.Response != nil && e.Response.Body != nil &&
+		len(*e.Response.Body) > maxBodyLength {
+		response := *e.Response
+		response.Body = nil
+		e.Response = &response
+		e.Truncated = true
+	}
+
+	return e
+}
+
+// Validate validates the event, every event needs an ID, a known type and
+// the time it happened at, and its fields are limited in size so a single
+// agent can't flood the collector.
+func (e Event) Validate() error {
+	if e.ID == "" || len(e.ID) > maxIDLength {
+		return fmt.Errorf("event id must have between 1 and %d characters: %w",
+			maxIDLength, ErrInvalidEvent)
+	}
+
+	if e.SchemaVersion < 1 || e.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema version %d is not supported: %w",
+			e.SchemaVersion, ErrInvalidEvent)
+	}
+
+	if e.Agent == "" || len(e.Agent) > maxAgentLength ||
+		len(e.AgentVersion) > maxAgentLength {
+		return fmt.Errorf("agent and agent version must have up to %d characters: %w",
+			maxAgentLength, ErrInvalidEvent)
+	}
+
+	if len(e.Region) > maxAgentLength {
+		return fmt.Errorf("region must have up to %d characters: %w",
+			maxAgentLength, ErrInvalidEvent)
+	}
+
+	if e.EventType != EventTypeRPCCall {
+		return fmt.Errorf("event type %q is not supported: %w",
+			e.EventType, ErrInvalidEvent)
+	}
+
+	for _, field := range []string{e.BrowserID, e.ClientID, e.ResultingClientID} {
+		if len(field) > maxIDLength {
+			return fmt.Errorf("client ids must have up to %d characters: %w",
+				maxIDLength, ErrInvalidEvent)
+		}
+	}
+
+	if e.EventTime.UnixMilli() <= 0 ||
+		e.EventTime.After(time.Now().Add(maxEventTimeInFuture)) {
+		return fmt.Errorf("event time is required and can't be in the future: %w",
+			ErrInvalidEvent)
+	}
+
+	if e.Response != nil && e.Request == nil {
+		return fmt.Errorf("event with a response but no request: %w",
+			ErrInvalidEvent)
+	}
+
+	if e.Error != nil {
+		if e.Error.Type != ErrorTypeTimeout && e.Error.Type != ErrorTypeNetwork {
+			return fmt.Errorf("error type %q is not supported: %w",
+				e.Error.Type, ErrInvalidEvent)
+		}
+
+		if len(e.Error.Message) > maxErrorLength {
+			return fmt.Errorf("error message must have up to %d characters: %w",
+				maxErrorLength, ErrInvalidEvent)
+		}
+	}
+
+	if e.Request != nil {
+		if err := e.Request.validate(); err != nil {
+			return fmt.Errorf("request: %w", err)
+		}
+	}
+
+	if e.Response != nil {
+		if err := e.Response.validate(); err != nil {
+			return fmt.Errorf("response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// EventError represents an error the agent reported for a call that didn't
+// get a response.
+type EventError struct {
+	Type    string
+	Message string
 }
 
 // Request struct represents a browser/mobile request.
@@ -27,7 +181,7 @@ type Request struct {
 	Cache          string
 	Credentials    string
 	Destination    string
-	Headers        interface{}
+	Headers        map[string]string
 	Integrity      string
 	Method         string
 	Mode           string
@@ -35,7 +189,90 @@ type Request struct {
 	Referrer       string
 	ReferrerPolicy string
 	URL            string
-	Signal         interface{}
+}
+
+// Endpoint returns the host of the RPC node the request was sent to, empty
+// if the URL can't be parsed or its host isn't valid.
+//
+// The endpoint and the origin tag the metrics, so they are normalized to
+// keep the agents from blowing up their cardinality.
+func (r Request) Endpoint() string {
+	parsed, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+
+	return normalizeHost(parsed)
+}
+
+// Origin returns the origin of the page that sent the request, e.g.
+// https://app.example.com, empty if the referrer can't be parsed, isn't an
+// HTTP one or its host isn't valid.
+func (r Request) Origin() string {
+	parsed, err := url.Parse(r.Referrer)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+
+	host := normalizeHost(parsed)
+	if host == "" {
+		return ""
+	}
+
+	return parsed.Scheme + "://" + host
+}
+
+// hostnamePattern matches the DNS host names.
+var hostnamePattern = regexp.MustCompile(
+	`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// maxHostnameLength is the maximum length of a DNS host name.
+const maxHostnameLength = 253
+
+// normalizeHost returns the lower cased host of the URL, with its port
+// unless it's the default one of the scheme, empty if it's neither a host
+// name nor an IP address.
+func normalizeHost(u *url.URL) string {
+	hostname := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if net.ParseIP(hostname) == nil && (len(hostname) > maxHostnameLength ||
+		!hostnamePattern.MatchString(hostname)) {
+		return ""
+	}
+
+	switch port := u.Port(); {
+	case port == "",
+		port == "80" && u.Scheme == "http",
+		port == "443" && u.Scheme == "https":
+		if strings.Contains(hostname, ":") {
+			return "[" + hostname + "]"
+		}
+
+		return hostname
+
+	default:
+		return net.JoinHostPort(hostname, port)
+	}
+}
+
+func (r Request) validate() error {
+	if err := validateMessage(r.URL, r.Headers, r.Body); err != nil {
+		return err
+	}
+
+	for _, field := range []string{r.Cache, r.Credentials, r.Destination,
+		r.Integrity, r.Method, r.Mode, r.Redirect, r.ReferrerPolicy} {
+		if len(field) > maxShortFieldLength {
+			return fmt.Errorf("fields must have up to %d characters: %w",
+				maxShortFieldLength, ErrInvalidEvent)
+		}
+	}
+
+	if len(r.Referrer) > maxURLLength {
+		return fmt.Errorf("referrer must have up to %d characters: %w",
+			maxURLLength, ErrInvalidEvent)
+	}
+
+	return nil
 }
 
 // Response struct represents a browser/mobile response.
@@ -43,7 +280,7 @@ type Response struct {
 	ResponseTime time.Time
 	Body         *string
 	BodyUsed     bool
-	Headers      interface{}
+	Headers      map[string]string
 	Ok           bool
 	Redirected   bool
 	Status       uint16
@@ -51,3 +288,44 @@ type Response struct {
 	ResponseType string
 	URL          string
 }
+
+func (r Response) validate() error {
+	if err := validateMessage(r.URL, r.Headers, r.Body); err != nil {
+		return err
+	}
+
+	if len(r.StatusText) > maxShortFieldLength ||
+		len(r.ResponseType) > maxShortFieldLength {
+		return fmt.Errorf("fields must have up to %d characters: %w",
+			maxShortFieldLength, ErrInvalidEvent)
+	}
+
+	return nil
+}
+
+// validateMessage validates the fields requests and responses share.
+func validateMessage(url string, headers map[string]string, body *string) error {
+	if len(url) > maxURLLength {
+		return fmt.Errorf("url must have up to %d characters: %w",
+			maxURLLength, ErrInvalidEvent)
+	}
+
+	if len(headers) > maxHeaders {
+		return fmt.Errorf("up to %d headers are allowed: %w",
+			maxHeaders, ErrInvalidEvent)
+	}
+
+	for name, value := range headers {
+		if len(name)+len(value) > maxHeaderLength {
+			return fmt.Errorf("header %q must have up to %d characters: %w",
+				name, maxHeaderLength, ErrInvalidEvent)
+		}
+	}
+
+	if body != nil && len(*body) > maxBodyLength {
+		return fmt.Errorf("body must have up to %d bytes: %w",
+			maxBodyLength, ErrInvalidEvent)
+	}
+
+	return nil
+}
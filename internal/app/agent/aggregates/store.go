@@ -0,0 +1,78 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventClass is the class of a stored event, which sets how long the event
+// is kept.
+type EventClass string
+
+const (
+	// EventClassPending is a sendTransaction call whose transaction hasn't
+	// landed yet, it's kept until it's joined with its transaction.
+	EventClassPending EventClass = "pending"
+	// EventClassJoined is a sendTransaction call already joined with its
+	// transaction, it's only kept for a while to look into it.
+	EventClassJoined EventClass = "joined"
+)
+
+// EventClasses are every class of stored events.
+var EventClasses = []EventClass{EventClassPending, EventClassJoined}
+
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+)
+
+// EventsFilter represents the filters to look up the stored events, by
+// ClientID or BrowserID when set and by time otherwise.
+type EventsFilter struct {
+	ClientID  string
+	BrowserID string
+	Start     *time.Time
+	End       *time.Time
+	Limit     int
+}
+
+// Validate validates the filter, setting the default limit when it's not
+// set.
+func (f *EventsFilter) Validate() error {
+	if f.ClientID != "" && f.BrowserID != "" {
+		return fmt.Errorf("events can be looked up by client or browser, not both: %w",
+			ErrInvalidEventsFilter)
+	}
+
+	if f.Limit < 0 || f.Limit > maxEventsLimit {
+		return fmt.Errorf("limit must be between 1 and %d: %w",
+			maxEventsLimit, ErrInvalidEventsFilter)
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultEventsLimit
+	}
+
+	if f.Start != nil && f.End != nil && !f.End.After(*f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidEventsFilter)
+	}
+
+	return nil
+}
+
+// StoreReport represents the events stored per class and the memory of the
+// store, EvictionPolicy is the maxmemory-policy of the store.
+type StoreReport struct {
+	Classes        []ClassReport
+	UsedMemory     int64
+	MaxMemory      int64
+	EvictionPolicy string
+}
+
+// ClassReport represents the events stored of a class, Memory is estimated
+// from a sample of the events.
+type ClassReport struct {
+	Class  EventClass
+	Count  int64
+	Memory int64
+}
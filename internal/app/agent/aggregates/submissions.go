@@ -0,0 +1,67 @@
+package aggregates
+
+import "time"
+
+// Submissions represents every sendTransaction call that sent the same
+// signed transaction, as wallets and frontends resend it until it lands.
+// Events are sorted by request time and LandedAt is set once the
+// transaction landed.
+type Submissions struct {
+	Signature string
+	Events    []Event
+	LandedAt  *time.Time
+}
+
+// Count returns the amount of submissions.
+func (s Submissions) Count() int {
+	return len(s.Events)
+}
+
+// First returns the first submission.
+func (s Submissions) First() Event {
+	return s.Events[0]
+}
+
+// Intervals returns the time between every submission and the previous one.
+func (s Submissions) Intervals() []time.Duration {
+	intervals := make([]time.Duration, 0, max(len(s.Events)-1, 0))
+	for i := 1; i < len(s.Events); i++ {
+		intervals = append(intervals,
+			s.Events[i].Request.RequestTime.Sub(s.Events[i-1].Request.RequestTime))
+	}
+
+	return intervals
+}
+
+// Endpoints returns the RPC endpoints the transaction was sent to, in the
+// order they were first used.
+func (s Submissions) Endpoints() []string {
+	seen := make(map[string]bool, len(s.Events))
+	endpoints := make([]string, 0, len(s.Events))
+	for _, event := range s.Events {
+		endpoint := event.Request.Endpoint()
+		if seen[endpoint] {
+			continue
+		}
+
+		seen[endpoint] = true
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// Landing returns the index of the submission followed by the landing of
+// the transaction landed at the given time, the last one sent before it.
+func (s Submissions) Landing(landedAt time.Time) int {
+	landing := 0
+	for i, event := range s.Events {
+		if event.Request.RequestTime.After(landedAt) {
+			break
+		}
+
+		landing = i
+	}
+
+	return landing
+}
@@ -0,0 +1,26 @@
+package aggregates
+
+// Transaction versions of the sent transactions.
+const (
+	TransactionVersionLegacy = "legacy"
+	TransactionVersionV0     = "0"
+)
+
+// SentTransaction represents the transaction a sendTransaction call sent,
+// as decoded from the call params.
+//
+// The Signature is the first signature of the transaction, which is the one
+// the RPC nodes return. ComputeUnitLimit and ComputeUnitPrice, in
+// micro-lamports per compute unit, are set when the transaction has the
+// compute budget instructions, Size is the size of the wire transaction in
+// bytes and LookupTables the address lookup tables of v0 transactions.
+type SentTransaction struct {
+	Signature        string
+	Version          string
+	FeePayer         string
+	RecentBlockhash  string
+	ComputeUnitLimit *uint32
+	ComputeUnitPrice *uint64
+	Size             int
+	LookupTables     []string
+}
@@ -2,31 +2,52 @@ package services
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 
-	bin "github.com/gagliardetto/binary"
-	solana "github.com/gagliardetto/solana-go"
-
 	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	failuresAggregates "github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	sessionsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 )
 
 type eventsRedisRepository interface {
 	SubscribeToEvents(context.Context) (chan aggregates.Event, chan error)
-	SetEvent(context.Context, string, aggregates.Event) error
+	SetEvent(context.Context, string, aggregates.EventClass, aggregates.Event) error
+}
+
+type rpcMetricsRepository interface {
+	WriteRPCCall(context.Context, metricsAggregates.RPCCallMetric) error
+}
+
+type failuresRepository interface {
+	InsertFailure(context.Context, failuresAggregates.Failure) error
+}
+
+type attemptsRepository interface {
+	UpsertAttempt(context.Context, sessionsAggregates.Attempt) error
 }
 
 // EventCollector define the dependencies to collect events.
 type EventCollector struct {
-	repository eventsRedisRepository
+	repository           eventsRedisRepository
+	rpcMetricsRepository rpcMetricsRepository
+	failuresRepository   failuresRepository
+	attemptsRepository   attemptsRepository
 }
 
 // NewEventCollector creates a new EventCollector.
-func NewEventCollector(repository eventsRedisRepository) *EventCollector {
+func NewEventCollector(repository eventsRedisRepository,
+	rpcMetricsRepository rpcMetricsRepository,
+	failuresRepository failuresRepository,
+	attemptsRepository attemptsRepository) *EventCollector {
 	return &EventCollector{
-		repository: repository,
+		repository:           repository,
+		rpcMetricsRepository: rpcMetricsRepository,
+		failuresRepository:   failuresRepository,
+		attemptsRepository:   attemptsRepository,
 	}
 }
 
@@ -40,84 +61,140 @@ func (ec *EventCollector) Collect(ctx context.Context) {
 			return
 
 		case event := <-eventChan:
-			// For the moment we are only interested in successful responses
-			// so we can ignore the rest.
-			//
-			// But failure responses are event more important than successful
-			// ones, so we should handle them as well.
-			if event.Response == nil || event.Response.Status != 200 || event.Response.Body == nil {
-				continue
-			}
+			ec.collect(ctx, event)
 
-			if event.Request == nil || event.Request.Body == nil {
-				continue
-			}
+		case err := <-errChan:
+			slog.Error("error in the agent events redis repository: ", slog.Any("error", err))
+		}
+	}
+}
 
-			solanaRequestBody := struct {
-				Method  string        `json:"method"`
-				JsonRPC string        `json:"jsonrpc"`
-				Params  []interface{} `json:"params"`
-			}{}
+// collect processes every JSON-RPC call of an event, a single call or a
+// batch. Every call is measured, whatever its method, the failed ones are
+// recorded to be searched later and the sendTransaction ones are recorded
+// as attempts of the browser that sent them and, when they succeeded,
+// stored on their own to be matched with their transactions.
+func (ec *EventCollector) collect(ctx context.Context, event aggregates.Event) {
+	calls, batch, err := rpcCallsFromEvent(event)
+	if err != nil {
+		// Collect doesn't return an error, so we should log it.
+		slog.Error("can't unmarshal solana request body: ", slog.Any("error", err))
+		return
+	}
 
-			if err := json.Unmarshal([]byte(*event.Request.Body), &solanaRequestBody); err != nil {
-				// Collect doesn't return an error, so we should log it.
-				slog.Error("can't unmarshal solana request body: ", err)
-				continue
-			}
+	// Events published before the multi-tenancy don't have a project.
+	if event.ProjectID == 0 {
+		event.ProjectID = tenantsAggregates.DefaultProjectID
+	}
 
-			if solanaRequestBody.Method != "sendTransaction" {
-				continue
+	for _, call := range calls {
+		if metric, ok := call.metric(event, batch); ok {
+			if err := ec.rpcMetricsRepository.WriteRPCCall(ctx, metric); err != nil {
+				slog.Error("can't write rpc call metric: ", slog.Any("error", err))
 			}
+		}
 
-			if len(solanaRequestBody.Params) > 0 {
-				if transactionData, ok := solanaRequestBody.Params[0].(string); ok {
-					// We don't need to decode the transaction signature, we just
-					// need to store it.
-
-					data, err := base64.StdEncoding.DecodeString(transactionData)
-					if err != nil {
-						slog.Error(
-							"can't decode transaction base64: ",
-							slog.String("transactionData", transactionData),
-							err)
-					}
-
-					decodedTx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(data))
-					if err != nil {
-						slog.Error("can't decode transaction: ", err)
-					}
-
-					programIDs := make([]string, 0)
-
-					for _, instruction := range decodedTx.Message.Instructions {
-						programID, err := decodedTx.ResolveProgramIDIndex(instruction.ProgramIDIndex)
-						if err != nil {
-							slog.Error("can't resolve program ID index: ", err)
-						}
-
-						programIDs = append(programIDs, programID.String())
-					}
-
-					event.ProgramIDs = programIDs
-				}
+		failure := call.failure(event, batch)
+		if failure != nil {
+			if err := ec.failuresRepository.InsertFailure(ctx, *failure); err != nil {
+				slog.Error("can't insert rpc call failure: ", slog.Any("error", err))
 			}
+		}
 
-			solanaResponseBody := struct {
-				Result string `json:"result"`
-			}{}
+		if call.Request.Method != "sendTransaction" {
+			continue
+		}
 
-			if err := json.Unmarshal([]byte(*event.Response.Body), &solanaResponseBody); err != nil {
-				slog.Error("can't unmarshal solana response body: ", err)
-				continue
+		transaction, programIDs, err := decodeSendTransaction(call.Request.Params)
+		if err != nil {
+			// A failed call may have failed for sending an invalid transaction.
+			if failure == nil {
+				slog.Error("can't decode sendTransaction call: ", slog.Any("error", err))
 			}
 
-			ec.repository.SetEvent(ctx,
-				fmt.Sprintf("%s.%s", solanaRequestBody.Method, solanaResponseBody.Result),
-				event,
-			)
+			continue
+		}
 
-		case err := <-errChan:
-			slog.Error("error in the agent events redis repository: ", err)
+		if err := ec.recordAttempt(ctx, event, transaction, programIDs,
+			failure); err != nil {
+			slog.Error("can't record sendTransaction attempt: ", slog.Any("error", err))
 		}
+
+		if err := ec.storeTransaction(ctx, event, call,
+			transaction, programIDs); err != nil {
+			slog.Error("can't store sendTransaction call: ", slog.Any("error", err))
+		}
+	}
+}
+
+// recordAttempt records a sendTransaction call as an attempt of the browser
+// and client that sent it, the navigations only carry the client they
+// result in.
+func (ec *EventCollector) recordAttempt(ctx context.Context,
+	event aggregates.Event, transaction aggregates.SentTransaction,
+	programIDs []string, failure *failuresAggregates.Failure) error {
+	attempt := sessionsAggregates.Attempt{
+		ProjectID:        event.ProjectID,
+		Signature:        transaction.Signature,
+		BrowserID:        event.BrowserID,
+		ClientID:         event.ClientID,
+		FeePayer:         transaction.FeePayer,
+		ProgramAddresses: programIDs,
+		SubmittedAt:      event.Request.RequestTime,
+	}
+
+	if attempt.ClientID == "" {
+		attempt.ClientID = event.ResultingClientID
+	}
+
+	if failure != nil {
+		attempt.Failed = true
+		attempt.Error = failure.Message
+	}
+
+	if err := ec.attemptsRepository.UpsertAttempt(ctx, attempt); err != nil {
+		return fmt.Errorf("ec.attemptsRepository.UpsertAttempt: %w", err)
+	}
+
+	return nil
+}
+
+// storeTransaction stores a successful sendTransaction call keyed by the
+// signature of the transaction it sent, along with the decoded transaction
+// and its programs. The transactions of the failed calls never reach the
+// chain, they are only recorded as failures and attempts.
+//
+// The signature is derived from the transaction rather than taken from the
+// response, a misbehaving RPC node can't make us track another transaction.
+func (ec *EventCollector) storeTransaction(ctx context.Context,
+	event aggregates.Event, call rpcCall,
+	transaction aggregates.SentTransaction, programIDs []string) error {
+	if event.Response == nil || event.Response.Status != 200 ||
+		call.Response == nil || call.Response.failed() {
+		return nil
 	}
+
+	var result string
+	if err := json.Unmarshal(call.Response.Result, &result); err == nil &&
+		result != transaction.Signature {
+		slog.Warn("sendTransaction result doesn't match the transaction signature",
+			slog.String("endpoint", event.Request.Endpoint()),
+			slog.String("result", result),
+			slog.String("signature", transaction.Signature))
+	}
+
+	callEvent := call.event(event)
+	callEvent.ProgramIDs = programIDs
+	callEvent.Transaction = &transaction
+
+	if err := ec.repository.SetEvent(ctx,
+		fmt.Sprintf("%d.%s.%s", event.ProjectID, call.Request.Method,
+			transaction.Signature),
+		aggregates.EventClassPending,
+		callEvent,
+	); err != nil {
+		return fmt.Errorf("ec.repository.SetEvent: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type eventGetterRepository interface {
+	SelectEvents(context.Context, aggregates.EventsFilter) ([]aggregates.Event, error)
+	GetSubmissions(context.Context, string) (aggregates.Submissions, error)
+}
+
+// EventGetter defines the methods needed to look up the stored events.
+type EventGetter struct {
+	eventGetterRepository eventGetterRepository
+}
+
+// NewEventGetter initializes a new EventGetter.
+func NewEventGetter(eventGetterRepository eventGetterRepository) *EventGetter {
+	return &EventGetter{
+		eventGetterRepository: eventGetterRepository,
+	}
+}
+
+// GetEvents gets the stored events matching the filter, newest first.
+func (eg *EventGetter) GetEvents(ctx context.Context,
+	filter aggregates.EventsFilter) ([]aggregates.Event, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("filter.Validate, err: %w", err)
+	}
+
+	events, err := eg.eventGetterRepository.SelectEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("eg.eventGetterRepository.SelectEvents, err: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetSubmissions gets every sendTransaction call of the project that sent
+// the transaction of the signature.
+func (eg *EventGetter) GetSubmissions(ctx context.Context,
+	signature string) (aggregates.Submissions, error) {
+	submissions, err := eg.eventGetterRepository.GetSubmissions(ctx,
+		fmt.Sprintf("%d.sendTransaction.%s",
+			tenantsAggregates.ProjectIDOrDefault(ctx), signature))
+	if err != nil {
+		return aggregates.Submissions{}, fmt.Errorf(
+			"eg.eventGetterRepository.GetSubmissions, err: %w", err)
+	}
+
+	return submissions, nil
+}
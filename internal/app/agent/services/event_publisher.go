@@ -8,7 +8,7 @@ import (
 )
 
 type eventPublisher interface {
-	PublishEvent(context.Context, aggregates.Event) error
+	PublishEvents(context.Context, []aggregates.Event) error
 }
 
 // EventPublisher defines the dependencies to publish events.
@@ -24,11 +24,15 @@ func NewEventPublisher(
 	}
 }
 
-// Publish publishes an event.
-func (ep *EventPublisher) Publish(
-	ctx context.Context, event aggregates.Event) error {
-	if err := ep.eventPublisher.PublishEvent(ctx, event); err != nil {
-		return fmt.Errorf("eventPublisher.PublishEvent: %w", err)
+// PublishBatch publishes a batch of events at once.
+func (ep *EventPublisher) PublishBatch(
+	ctx context.Context, events []aggregates.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := ep.eventPublisher.PublishEvents(ctx, events); err != nil {
+		return fmt.Errorf("eventPublisher.PublishEvents: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	failuresAggregates "github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// rpcMethodPattern is the pattern of the JSON-RPC method names the metrics
+// are tagged by, any other method is tagged as invalid so agents can't
+// blow up the metrics cardinality.
+var rpcMethodPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
+
+// invalidRPCMethod is the method the calls with invalid method names are
+// tagged by.
+const invalidRPCMethod = "invalid"
+
+// rpcRequest represents a JSON-RPC request, Params are decoded by the
+// methods that need them as they are either an array or an object.
+type rpcRequest struct {
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	JsonRPC string          `json:"jsonrpc"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// rpcResponse represents a JSON-RPC response, Error is null on success.
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// failed returns true when the JSON-RPC response carries an error.
+func (rr rpcResponse) failed() bool {
+	return len(rr.Error) > 0 && string(rr.Error) != "null"
+}
+
+// rpcCall represents a single JSON-RPC call of an event, along with the raw
+// request and response so they can be stored on their own. Response is nil
+// when the call didn't get a response, e.g. a batch response missing it.
+type rpcCall struct {
+	Request     rpcRequest
+	RawRequest  json.RawMessage
+	Response    *rpcResponse
+	RawResponse json.RawMessage
+}
+
+// rpcCallsFromEvent returns the JSON-RPC calls of an event, either a single
+// call or every call of a batch, whose responses are matched by id. It
+// returns no calls when the event isn't a JSON-RPC call.
+func rpcCallsFromEvent(event aggregates.Event) ([]rpcCall, bool, error) {
+	if event.Request == nil || event.Request.Body == nil {
+		return nil, false, nil
+	}
+
+	rawRequests, batch, err := splitRPCBody(*event.Request.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("splitRPCBody(request): %w", err)
+	}
+
+	calls := make([]rpcCall, 0, len(rawRequests))
+	for _, rawRequest := range rawRequests {
+		// Invalid calls within a batch don't take the rest with them.
+		var request rpcRequest
+		if err := json.Unmarshal(rawRequest, &request); err != nil ||
+			request.JsonRPC == "" || request.Method == "" {
+			continue
+		}
+
+		calls = append(calls, rpcCall{Request: request, RawRequest: rawRequest})
+	}
+
+	if len(calls) == 0 || event.Response == nil || event.Response.Body == nil {
+		return calls, batch, nil
+	}
+
+	// Responses that aren't JSON-RPC, e.g. an HTML error page, leave the
+	// calls without response.
+	rawResponses, _, err := splitRPCBody(*event.Response.Body)
+	if err != nil {
+		return calls, batch, nil
+	}
+
+	responses := make(map[string]int, len(rawResponses))
+	parsed := make([]rpcResponse, len(rawResponses))
+	for i, rawResponse := range rawResponses {
+		if err := json.Unmarshal(rawResponse, &parsed[i]); err != nil {
+			continue
+		}
+
+		responses[string(parsed[i].ID)] = i
+	}
+
+	for i := range calls {
+		index, ok := responses[string(calls[i].Request.ID)]
+
+		// A single call is matched with a single response whatever their
+		// ids, some nodes don't echo them back.
+		if !batch && len(calls) == 1 && len(rawResponses) == 1 {
+			index, ok = 0, true
+		}
+
+		if !ok {
+			continue
+		}
+
+		calls[i].Response = &parsed[index]
+		calls[i].RawResponse = rawResponses[index]
+	}
+
+	return calls, batch, nil
+}
+
+// splitRPCBody splits a JSON-RPC body into its messages, batch is true when
+// the body is an array.
+func splitRPCBody(body string) ([]json.RawMessage, bool, error) {
+	trimmed := bytes.TrimSpace([]byte(body))
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(trimmed, &messages); err != nil {
+			return nil, true, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		return messages, true, nil
+	}
+
+	if !json.Valid(trimmed) {
+		return nil, false, fmt.Errorf("invalid json body")
+	}
+
+	return []json.RawMessage{trimmed}, false, nil
+}
+
+// metric returns the RPC call metric of a call, the calls of a batch share
+// the round trip latency of the batch as there's no way to tell them apart,
+// while their response size is the size of their own response. The calls
+// without response are only measured when the agent reported why, their
+// latency is the time until the agent gave up.
+func (rc rpcCall) metric(event aggregates.Event,
+	batch bool) (metricsAggregates.RPCCallMetric, bool) {
+	if event.Response == nil && event.Error == nil {
+		return metricsAggregates.RPCCallMetric{}, false
+	}
+
+	method := rc.Request.Method
+	if !rpcMethodPattern.MatchString(method) {
+		method = invalidRPCMethod
+	}
+
+	end := event.EventTime
+	var rateLimitRemaining *int64
+	if event.Response != nil {
+		end = event.Response.ResponseTime
+		rateLimitRemaining = remainingRateLimit(event.Response.Headers)
+	}
+
+	latency := end.Sub(event.Request.RequestTime)
+	if latency < 0 {
+		latency = 0
+	}
+
+	var failureKind string
+	if failure := rc.failure(event, batch); failure != nil {
+		failureKind = string(failure.Kind)
+	}
+
+	return metricsAggregates.RPCCallMetric{
+		ProjectID:          event.ProjectID,
+		Method:             method,
+		Endpoint:           event.Request.Endpoint(),
+		Origin:             event.Request.Origin(),
+		Region:             event.Region,
+		Batch:              batch,
+		Error:              failureKind != "",
+		FailureKind:        failureKind,
+		Latency:            latency,
+		ResponseSize:       int64(len(rc.RawResponse)),
+		RateLimitRemaining: rateLimitRemaining,
+		Time:               event.Request.RequestTime,
+	}, true
+}
+
+// failure returns the failure of a call, nil when the call succeeded or
+// there's no way to tell, e.g. a successful response without the JSON-RPC
+// response of the call, which some proxies answer notifications with.
+func (rc rpcCall) failure(event aggregates.Event,
+	batch bool) *failuresAggregates.Failure {
+	failure := failuresAggregates.Failure{
+		ProjectID:  event.ProjectID,
+		EventID:    event.ID,
+		Endpoint:   event.Request.Endpoint(),
+		Origin:     event.Request.Origin(),
+		Method:     rc.Request.Method,
+		Batch:      batch,
+		OccurredAt: event.Request.RequestTime,
+	}
+
+	if !rpcMethodPattern.MatchString(failure.Method) {
+		failure.Method = invalidRPCMethod
+	}
+
+	if event.Response == nil {
+		if event.Error == nil {
+			return nil
+		}
+
+		failure.Kind = failuresAggregates.KindNetwork
+		if event.Error.Type == aggregates.ErrorTypeTimeout {
+			failure.Kind = failuresAggregates.KindTimeout
+		}
+		failure.Message = truncate(event.Error.Message)
+
+		return &failure
+	}
+
+	failure.HTTPStatus = int(event.Response.Status)
+	failure.RateLimit = rateLimitHeaders(event.Response.Headers)
+
+	switch {
+	case event.Response.Status == http.StatusTooManyRequests:
+		failure.Kind = failuresAggregates.KindRateLimited
+		failure.Message = truncate(event.Response.StatusText)
+
+	case event.Response.Status < 200 || event.Response.Status > 299:
+		failure.Kind = failuresAggregates.KindHTTP
+		failure.Message = event.Response.StatusText
+		if event.Response.Body != nil {
+			failure.Message = strings.TrimSpace(failure.Message + " " + *event.Response.Body)
+		}
+		failure.Message = truncate(failure.Message)
+
+	case rc.Response != nil && rc.Response.failed():
+		var rpcError struct {
+			Code    *int64          `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(rc.Response.Error, &rpcError); err != nil {
+			rpcError.Message = string(rc.Response.Error)
+		}
+
+		failure.Kind = failuresAggregates.KindRPC
+		if rateLimitedRPCError(rpcError.Code, rpcError.Message) {
+			failure.Kind = failuresAggregates.KindRateLimited
+		}
+		failure.RPCCode = rpcError.Code
+		failure.Message = truncate(rpcError.Message)
+		failure.Logs = simulationLogs(rpcError.Data)
+
+	default:
+		return nil
+	}
+
+	return &failure
+}
+
+// maxFailureMessageLength is the maximum length of the failure messages,
+// the bodies of HTTP errors are often whole HTML pages.
+const maxFailureMessageLength = 1024
+
+// truncate cuts the message to maxFailureMessageLength bytes, backing up to
+// the start of the rune so it stays valid UTF-8.
+func truncate(message string) string {
+	if len(message) <= maxFailureMessageLength {
+		return message
+	}
+
+	end := maxFailureMessageLength
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+
+	return message[:end]
+}
+
+// rateLimitedRPCError returns true for the JSON-RPC errors the RPC providers
+// rate limit with.
+func rateLimitedRPCError(code *int64, message string) bool {
+	if code != nil && (*code == http.StatusTooManyRequests || *code == -32005) {
+		return true
+	}
+
+	message = strings.ToLower(message)
+	return strings.Contains(message, "rate limit") ||
+		strings.Contains(message, "too many requests")
+}
+
+// rateLimitHeaders returns the rate limit headers of a response, either
+// the x-ratelimit-* or the ratelimit-* ones and retry-after.
+func rateLimitHeaders(headers map[string]string) map[string]string {
+	rateLimit := make(map[string]string)
+	for name, value := range headers {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-ratelimit-") ||
+			strings.HasPrefix(name, "ratelimit-") || name == "retry-after" {
+			rateLimit[name] = value
+		}
+	}
+
+	return rateLimit
+}
+
+// remainingRateLimit returns the requests left of the rate limit of the RPC
+// node from the rate limit headers of a response, nil when it's not sent.
+func remainingRateLimit(headers map[string]string) *int64 {
+	rateLimit := rateLimitHeaders(headers)
+
+	for _, name := range []string{"x-ratelimit-remaining", "ratelimit-remaining"} {
+		value, ok := rateLimit[name]
+		if !ok {
+			continue
+		}
+
+		remaining, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		return &remaining
+	}
+
+	return nil
+}
+
+// simulationLogs returns the program logs of a failed preflight simulation,
+// which the RPC nodes send in the data of the JSON-RPC error.
+func simulationLogs(data json.RawMessage) []string {
+	var simulation struct {
+		Logs []string `json:"logs"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &simulation) != nil {
+		return nil
+	}
+
+	return simulation.Logs
+}
+
+// event returns a copy of the event holding only this call, so the calls
+// of a batch are stored as if they were sent on their own.
+func (rc rpcCall) event(event aggregates.Event) aggregates.Event {
+	request := *event.Request
+	requestBody := string(rc.RawRequest)
+	request.Body = &requestBody
+	event.Request = &request
+
+	if event.Response != nil {
+		response := *event.Response
+		response.Body = nil
+		if rc.RawResponse != nil {
+			responseBody := string(rc.RawResponse)
+			response.Body = &responseBody
+		}
+		event.Response = &response
+	}
+
+	return event
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	failuresAggregates "github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+)
+
+// rpcEvent returns an RPC call event with the given response, no response
+// when status is 0.
+func rpcEvent(status uint16, body string,
+	headers map[string]string) aggregates.Event {
+	requestBody := `{"jsonrpc":"2.0","id":1,"method":"getBalance","params":[]}`
+	requestTime := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	event := aggregates.Event{
+		ID:        "event",
+		EventTime: requestTime.Add(time.Second),
+		Request: &aggregates.Request{
+			RequestTime: requestTime,
+			Body:        &requestBody,
+			URL:         "https://rpc.example.com",
+		},
+	}
+
+	if status != 0 {
+		event.Response = &aggregates.Response{
+			ResponseTime: requestTime.Add(100 * time.Millisecond),
+			Body:         &body,
+			Headers:      headers,
+			Status:       status,
+		}
+	}
+
+	return event
+}
+
+func TestRPCCallFailure(t *testing.T) {
+	timeout := rpcEvent(0, "", nil)
+	timeout.Error = &aggregates.EventError{
+		Type: aggregates.ErrorTypeTimeout, Message: "aborted",
+	}
+
+	tests := []struct {
+		name  string
+		event aggregates.Event
+		kind  failuresAggregates.Kind
+	}{
+		{
+			name:  "succeeded",
+			event: rpcEvent(200, `{"jsonrpc":"2.0","id":1,"result":1}`, nil),
+		},
+		{
+			name:  "successful response without the json-rpc response",
+			event: rpcEvent(200, `OK`, nil),
+		},
+		{
+			name:  "successful response without body",
+			event: rpcEvent(204, ``, nil),
+		},
+		{
+			name:  "http error",
+			event: rpcEvent(502, `<html>Bad Gateway</html>`, nil),
+			kind:  failuresAggregates.KindHTTP,
+		},
+		{
+			name:  "http rate limited",
+			event: rpcEvent(429, ``, map[string]string{"Retry-After": "1"}),
+			kind:  failuresAggregates.KindRateLimited,
+		},
+		{
+			name: "json-rpc error",
+			event: rpcEvent(200,
+				`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid params"}}`, nil),
+			kind: failuresAggregates.KindRPC,
+		},
+		{
+			name: "json-rpc rate limited",
+			event: rpcEvent(200,
+				`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"slow down"}}`, nil),
+			kind: failuresAggregates.KindRateLimited,
+		},
+		{
+			name:  "timeout",
+			event: timeout,
+			kind:  failuresAggregates.KindTimeout,
+		},
+		{
+			name:  "no response",
+			event: rpcEvent(0, "", nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls, batch, err := rpcCallsFromEvent(test.event)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if len(calls) != 1 {
+				t.Fatalf("calls = %d, want 1", len(calls))
+			}
+
+			failure := calls[0].failure(test.event, batch)
+			if test.kind == "" {
+				if failure != nil {
+					t.Fatalf("failure = %+v, want nil", failure)
+				}
+
+				return
+			}
+
+			if failure == nil || failure.Kind != test.kind {
+				t.Fatalf("failure = %+v, want kind %s", failure, test.kind)
+			}
+		})
+	}
+}
+
+func TestRPCCallMetricRateLimitRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     aggregates.Event
+		remaining *int64
+	}{
+		{
+			name: "successful call",
+			event: rpcEvent(200, `{"jsonrpc":"2.0","id":1,"result":1}`,
+				map[string]string{"X-RateLimit-Remaining": "42"}),
+			remaining: func() *int64 { remaining := int64(42); return &remaining }(),
+		},
+		{
+			name: "rate limited call",
+			event: rpcEvent(429, ``,
+				map[string]string{"RateLimit-Remaining": "0"}),
+			remaining: func() *int64 { remaining := int64(0); return &remaining }(),
+		},
+		{
+			name:  "without headers",
+			event: rpcEvent(200, `{"jsonrpc":"2.0","id":1,"result":1}`, nil),
+		},
+		{
+			name: "invalid header",
+			event: rpcEvent(200, `{"jsonrpc":"2.0","id":1,"result":1}`,
+				map[string]string{"X-RateLimit-Remaining": "many"}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls, batch, err := rpcCallsFromEvent(test.event)
+			if err != nil || len(calls) != 1 {
+				t.Fatalf("calls = %d, err = %v", len(calls), err)
+			}
+
+			metric, ok := calls[0].metric(test.event, batch)
+			if !ok {
+				t.Fatal("the call wasn't measured")
+			}
+
+			got := metric.RateLimitRemaining
+			if (got == nil) != (test.remaining == nil) ||
+				(got != nil && *got != *test.remaining) {
+				t.Errorf("RateLimitRemaining = %v, want %v", got, test.remaining)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "short",
+			message: "rate limited",
+			want:    "rate limited",
+		},
+		{
+			name:    "ascii",
+			message: strings.Repeat("a", maxFailureMessageLength+1),
+			want:    strings.Repeat("a", maxFailureMessageLength),
+		},
+		{
+			name:    "rune on the limit",
+			message: strings.Repeat("a", maxFailureMessageLength-1) + "é",
+			want:    strings.Repeat("a", maxFailureMessageLength-1),
+		},
+		{
+			name:    "four byte rune on the limit",
+			message: strings.Repeat("a", maxFailureMessageLength-2) + "🙂",
+			want:    strings.Repeat("a", maxFailureMessageLength-2),
+		},
+		{
+			name:    "rune ending on the limit",
+			message: strings.Repeat("a", maxFailureMessageLength-2) + "é" + "a",
+			want:    strings.Repeat("a", maxFailureMessageLength-2) + "é",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := truncate(test.message)
+			if got != test.want {
+				t.Errorf("truncate() = %q, want %q", got, test.want)
+			}
+
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate() = %q, isn't valid UTF-8", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// Encodings of the sendTransaction transactions.
+const (
+	encodingBase58 = "base58"
+	encodingBase64 = "base64"
+)
+
+// Compute budget program instructions, the first byte of their data.
+const (
+	setComputeUnitLimit = 2
+	setComputeUnitPrice = 3
+)
+
+// errInvalidSendTransaction is returned when the params of a
+// sendTransaction call don't carry a transaction.
+var errInvalidSendTransaction = errors.New("invalid sendTransaction params")
+
+// decodeSendTransaction decodes the transaction a sendTransaction call sent,
+// whose first param is the encoded transaction and second the optional
+// config with its encoding, and returns it along with the programs it
+// invokes.
+func decodeSendTransaction(
+	rawParams json.RawMessage) (aggregates.SentTransaction, []string, error) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(rawParams, &params); err != nil || len(params) == 0 {
+		return aggregates.SentTransaction{}, nil, errInvalidSendTransaction
+	}
+
+	var encoded string
+	if err := json.Unmarshal(params[0], &encoded); err != nil || encoded == "" {
+		return aggregates.SentTransaction{}, nil, errInvalidSendTransaction
+	}
+
+	var config struct {
+		Encoding string `json:"encoding"`
+	}
+	if len(params) > 1 {
+		if err := json.Unmarshal(params[1], &config); err != nil {
+			return aggregates.SentTransaction{}, nil,
+				fmt.Errorf("json.Unmarshal(config), err: %w", err)
+		}
+	}
+
+	// The RPC nodes default to base58 but some clients send base64 without
+	// saying so, both are tried when the encoding isn't set.
+	encodings := []string{config.Encoding}
+	if config.Encoding == "" {
+		encodings = []string{encodingBase58, encodingBase64}
+	}
+
+	var (
+		data []byte
+		tx   *solana.Transaction
+		err  error
+	)
+	for _, encoding := range encodings {
+		data, err = decodeTransactionData(encoded, encoding)
+		if err != nil {
+			err = fmt.Errorf("decodeTransactionData, err: %w", err)
+			continue
+		}
+
+		decoder := bin.NewBinDecoder(data)
+		tx, err = solana.TransactionFromDecoder(decoder)
+		if err != nil {
+			err = fmt.Errorf("solana.TransactionFromDecoder, err: %w", err)
+			continue
+		}
+
+		// Data decoded with the wrong encoding may still look like the start
+		// of a transaction.
+		if decoder.HasRemaining() {
+			err = fmt.Errorf("trailing transaction data: %w", errInvalidSendTransaction)
+			continue
+		}
+
+		break
+	}
+	if err != nil {
+		return aggregates.SentTransaction{}, nil, err
+	}
+
+	if len(tx.Signatures) == 0 || len(tx.Message.AccountKeys) == 0 {
+		return aggregates.SentTransaction{}, nil,
+			fmt.Errorf("transaction without signatures: %w", errInvalidSendTransaction)
+	}
+
+	transaction := aggregates.SentTransaction{
+		Signature:       tx.Signatures[0].String(),
+		Version:         aggregates.TransactionVersionLegacy,
+		FeePayer:        tx.Message.AccountKeys[0].String(),
+		RecentBlockhash: tx.Message.RecentBlockhash.String(),
+		Size:            len(data),
+	}
+
+	if tx.Message.IsVersioned() {
+		transaction.Version = aggregates.TransactionVersionV0
+		for _, lookup := range tx.Message.AddressTableLookups {
+			transaction.LookupTables = append(
+				transaction.LookupTables, lookup.AccountKey.String())
+		}
+	}
+
+	programIDs := make([]string, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		// The programs must be static keys, an index within the addresses
+		// loaded from the lookup tables can't be resolved without fetching
+		// the tables and the runtime rejects it anyway.
+		if int(instruction.ProgramIDIndex) >= len(tx.Message.AccountKeys) {
+			continue
+		}
+
+		programID := tx.Message.AccountKeys[instruction.ProgramIDIndex]
+		programIDs = append(programIDs, programID.String())
+
+		if !programID.Equals(solana.ComputeBudget) || len(instruction.Data) == 0 {
+			continue
+		}
+
+		switch data := instruction.Data; data[0] {
+		case setComputeUnitLimit:
+			if len(data) >= 5 {
+				limit := binary.LittleEndian.Uint32(data[1:5])
+				transaction.ComputeUnitLimit = &limit
+			}
+
+		case setComputeUnitPrice:
+			if len(data) >= 9 {
+				price := binary.LittleEndian.Uint64(data[1:9])
+				transaction.ComputeUnitPrice = &price
+			}
+		}
+	}
+
+	return transaction, programIDs, nil
+}
+
+// decodeTransactionData decodes a wire transaction with the encoding of the
+// call.
+func decodeTransactionData(encoded, encoding string) ([]byte, error) {
+	switch encoding {
+	case encodingBase64:
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("base64.StdEncoding.DecodeString, err: %w", err)
+		}
+
+		return data, nil
+
+	case encodingBase58:
+		data := base58.Decode(encoded)
+		if len(data) == 0 {
+			return nil, fmt.Errorf("invalid base58 transaction: %w",
+				errInvalidSendTransaction)
+		}
+
+		return data, nil
+
+	default:
+		return nil, fmt.Errorf("encoding %q is not supported: %w",
+			encoding, errInvalidSendTransaction)
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	solana "github.com/gagliardetto/solana-go"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// signedTransaction returns the wire format of a transaction of the given
+// instructions, paid and signed by payer.
+func signedTransaction(t *testing.T, payer solana.PrivateKey,
+	tables map[solana.PublicKey]solana.PublicKeySlice,
+	instructions ...solana.Instruction) (*solana.Transaction, []byte) {
+	t.Helper()
+
+	tx, err := solana.NewTransaction(instructions, solana.Hash{1},
+		solana.TransactionPayer(payer.PublicKey()),
+		solana.TransactionAddressTables(tables))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(payer.PublicKey()) {
+			return &payer
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tx, data
+}
+
+// sendTransactionParams returns the params of a sendTransaction call, with
+// the config only when the encoding is set.
+func sendTransactionParams(t *testing.T, encoded, encoding string) json.RawMessage {
+	t.Helper()
+
+	params := []interface{}{encoded}
+	if encoding != "" {
+		params = append(params, map[string]string{"encoding": encoding})
+	}
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rawParams
+}
+
+func TestDecodeSendTransaction(t *testing.T) {
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := solana.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	account := solana.PublicKey{2}
+	table := solana.PublicKey{3}
+
+	transfer := solana.NewInstruction(program, solana.AccountMetaSlice{
+		solana.Meta(payer.PublicKey()).WRITE().SIGNER(),
+		solana.Meta(account).WRITE(),
+	}, []byte{1})
+
+	limitData := binary.LittleEndian.AppendUint32(
+		[]byte{setComputeUnitLimit}, 200_000)
+	priceData := binary.LittleEndian.AppendUint64(
+		[]byte{setComputeUnitPrice}, 5_000)
+
+	legacyTx, legacy := signedTransaction(t, payer, nil, transfer)
+	v0Tx, v0 := signedTransaction(t, payer,
+		map[solana.PublicKey]solana.PublicKeySlice{table: {account}}, transfer)
+	budgetTx, budget := signedTransaction(t, payer, nil,
+		solana.NewInstruction(solana.ComputeBudget, nil, limitData),
+		solana.NewInstruction(solana.ComputeBudget, nil, priceData),
+		transfer)
+
+	limit, price := uint32(200_000), uint64(5_000)
+
+	tests := []struct {
+		name        string
+		params      json.RawMessage
+		transaction aggregates.SentTransaction
+		programIDs  []string
+		fails       bool
+		err         error
+	}{
+		{
+			name:   "legacy base58",
+			params: sendTransactionParams(t, base58.Encode(legacy), encodingBase58),
+			transaction: aggregates.SentTransaction{
+				Signature:       legacyTx.Signatures[0].String(),
+				Version:         aggregates.TransactionVersionLegacy,
+				FeePayer:        payer.PublicKey().String(),
+				RecentBlockhash: solana.Hash{1}.String(),
+				Size:            len(legacy),
+			},
+			programIDs: []string{program.String()},
+		},
+		{
+			name:   "v0 base64 with lookup tables",
+			params: sendTransactionParams(t, base64.StdEncoding.EncodeToString(v0), encodingBase64),
+			transaction: aggregates.SentTransaction{
+				Signature:       v0Tx.Signatures[0].String(),
+				Version:         aggregates.TransactionVersionV0,
+				FeePayer:        payer.PublicKey().String(),
+				RecentBlockhash: solana.Hash{1}.String(),
+				Size:            len(v0),
+				LookupTables:    []string{table.String()},
+			},
+			programIDs: []string{program.String()},
+		},
+		{
+			name:   "base64 without encoding",
+			params: sendTransactionParams(t, base64.StdEncoding.EncodeToString(legacy), ""),
+			transaction: aggregates.SentTransaction{
+				Signature:       legacyTx.Signatures[0].String(),
+				Version:         aggregates.TransactionVersionLegacy,
+				FeePayer:        payer.PublicKey().String(),
+				RecentBlockhash: solana.Hash{1}.String(),
+				Size:            len(legacy),
+			},
+			programIDs: []string{program.String()},
+		},
+		{
+			name:   "compute budget instructions",
+			params: sendTransactionParams(t, base64.StdEncoding.EncodeToString(budget), encodingBase64),
+			transaction: aggregates.SentTransaction{
+				Signature:        budgetTx.Signatures[0].String(),
+				Version:          aggregates.TransactionVersionLegacy,
+				FeePayer:         payer.PublicKey().String(),
+				RecentBlockhash:  solana.Hash{1}.String(),
+				ComputeUnitLimit: &limit,
+				ComputeUnitPrice: &price,
+				Size:             len(budget),
+			},
+			programIDs: []string{
+				solana.ComputeBudget.String(),
+				solana.ComputeBudget.String(),
+				program.String(),
+			},
+		},
+		{
+			name: "truncated transaction",
+			params: sendTransactionParams(t,
+				base64.StdEncoding.EncodeToString(legacy[:len(legacy)-10]), encodingBase64),
+			fails: true,
+		},
+		{
+			name: "trailing data",
+			params: sendTransactionParams(t,
+				base64.StdEncoding.EncodeToString(append(legacy, 0, 0)), encodingBase64),
+			err: errInvalidSendTransaction,
+		},
+		{
+			name:   "wrong encoding",
+			params: sendTransactionParams(t, base58.Encode(legacy), encodingBase64),
+			fails:  true,
+		},
+		{
+			name:   "unsupported encoding",
+			params: sendTransactionParams(t, base58.Encode(legacy), "jsonParsed"),
+			err:    errInvalidSendTransaction,
+		},
+		{
+			name:   "no params",
+			params: json.RawMessage(`[]`),
+			err:    errInvalidSendTransaction,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transaction, programIDs, err := decodeSendTransaction(test.params)
+			if test.fails || test.err != nil {
+				if err == nil || (test.err != nil && !errors.Is(err, test.err)) {
+					t.Fatalf("err = %v, want %v", err, test.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if !reflect.DeepEqual(transaction, test.transaction) {
+				t.Errorf("transaction = %+v, want %+v", transaction, test.transaction)
+			}
+
+			if !reflect.DeepEqual(programIDs, test.programIDs) {
+				t.Errorf("programIDs = %v, want %v", programIDs, test.programIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+type storeRepository interface {
+	PruneIndexes(context.Context) error
+	ExpireLegacyEvents(context.Context) (int64, error)
+	Report(context.Context) (aggregates.StoreReport, error)
+}
+
+// StoreReporter is a service that periodically prunes the indexes of the
+// stored events and reports how many of them are stored and the memory
+// they use.
+type StoreReporter struct {
+	repository storeRepository
+	interval   time.Duration
+}
+
+// NewStoreReporter creates a new instance of the StoreReporter service.
+func NewStoreReporter(
+	repository storeRepository,
+	interval time.Duration,
+) *StoreReporter {
+	return &StoreReporter{
+		repository: repository,
+		interval:   interval,
+	}
+}
+
+// Report starts reporting the stored events every interval.
+func (sr *StoreReporter) Report(ctx context.Context) {
+	ticker := time.NewTicker(sr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := sr.report(ctx); err != nil {
+				slog.Error("error while reporting the stored events", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (sr *StoreReporter) report(ctx context.Context) error {
+	if err := sr.repository.PruneIndexes(ctx); err != nil {
+		return fmt.Errorf("sr.repository.PruneIndexes: %w", err)
+	}
+
+	legacy, err := sr.repository.ExpireLegacyEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("sr.repository.ExpireLegacyEvents: %w", err)
+	}
+
+	if legacy > 0 {
+		slog.Info("legacy stored events set to expire", slog.Int64("events", legacy))
+	}
+
+	report, err := sr.repository.Report(ctx)
+	if err != nil {
+		return fmt.Errorf("sr.repository.Report: %w", err)
+	}
+
+	attrs := []any{
+		slog.Int64("used_memory", report.UsedMemory),
+		slog.Int64("max_memory", report.MaxMemory),
+		slog.String("eviction_policy", report.EvictionPolicy),
+	}
+	for _, class := range report.Classes {
+		attrs = append(attrs, slog.Group(string(class.Class),
+			slog.Int64("events", class.Count),
+			slog.Int64("memory", class.Memory),
+		))
+	}
+
+	slog.Info("stored events report", attrs...)
+
+	// The pending events are lost if the store evicts them before they're
+	// joined, only the volatile policies evict the events closest to expire.
+	if report.MaxMemory > 0 && report.UsedMemory > report.MaxMemory*9/10 {
+		slog.Warn("the stored events are close to the memory limit",
+			slog.Int64("used_memory", report.UsedMemory),
+			slog.Int64("max_memory", report.MaxMemory),
+			slog.String("eviction_policy", report.EvictionPolicy))
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidRule  = errors.New("invalid alert rule")
+)
@@ -0,0 +1,182 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// publicKeyLength is the length in bytes of a Solana public key.
+const publicKeyLength = 32
+
+// Metric represents the metric an alert rule is evaluated against.
+type Metric string
+
+const (
+	// MetricLatencyP50 is the 50th percentile of the solana time.
+	MetricLatencyP50 Metric = "latency_p50"
+	// MetricLatencyP95 is the 95th percentile of the solana time.
+	MetricLatencyP95 Metric = "latency_p95"
+	// MetricLatencyP99 is the 99th percentile of the solana time.
+	MetricLatencyP99 Metric = "latency_p99"
+	// MetricErrorRate is the ratio of failed transactions.
+	MetricErrorRate Metric = "error_rate"
+	// MetricThroughput is the amount of transactions per minute.
+	MetricThroughput Metric = "throughput"
+	// MetricApdex is the Apdex score.
+	MetricApdex Metric = "apdex"
+)
+
+// Value returns the value of the metric from the given window stats.
+func (m Metric) Value(stats metricsAggregates.WindowStats) float64 {
+	switch m {
+	case MetricLatencyP50:
+		return stats.LatencyP50
+	case MetricLatencyP95:
+		return stats.LatencyP95
+	case MetricLatencyP99:
+		return stats.LatencyP99
+	case MetricErrorRate:
+		return stats.ErrorRate()
+	case MetricThroughput:
+		return stats.Throughput()
+	case MetricApdex:
+		return stats.Apdex
+	}
+
+	return 0
+}
+
+func (m Metric) valid() bool {
+	switch m {
+	case MetricLatencyP50, MetricLatencyP95, MetricLatencyP99,
+		MetricErrorRate, MetricThroughput, MetricApdex:
+		return true
+	}
+
+	return false
+}
+
+// Comparison represents how the metric value is compared to the threshold.
+type Comparison string
+
+const (
+	ComparisonGreaterThan        Comparison = "gt"
+	ComparisonGreaterThanOrEqual Comparison = "gte"
+	ComparisonLessThan           Comparison = "lt"
+	ComparisonLessThanOrEqual    Comparison = "lte"
+)
+
+// Breached checks if the value breaches the threshold.
+func (c Comparison) Breached(value, threshold float64) bool {
+	switch c {
+	case ComparisonGreaterThan:
+		return value > threshold
+	case ComparisonGreaterThanOrEqual:
+		return value >= threshold
+	case ComparisonLessThan:
+		return value < threshold
+	case ComparisonLessThanOrEqual:
+		return value <= threshold
+	}
+
+	return false
+}
+
+// Recovered checks if the value went back past the threshold by at least the
+// hysteresis margin, so a value hovering around the threshold doesn't make
+// the alert flap between firing and resolved.
+func (c Comparison) Recovered(value, threshold, hysteresis float64) bool {
+	switch c {
+	case ComparisonGreaterThan, ComparisonGreaterThanOrEqual:
+		return value <= threshold-hysteresis
+	case ComparisonLessThan, ComparisonLessThanOrEqual:
+		return value >= threshold+hysteresis
+	}
+
+	return true
+}
+
+func (c Comparison) valid() bool {
+	switch c {
+	case ComparisonGreaterThan, ComparisonGreaterThanOrEqual,
+		ComparisonLessThan, ComparisonLessThanOrEqual:
+		return true
+	}
+
+	return false
+}
+
+// Severity represents the severity of an alert.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	}
+
+	return false
+}
+
+// Rule represents an alert rule, an empty ProgramAddress means the rule is
+// evaluated against every transaction (global scope). State changes are sent
+// to the WebhookURL and queued for every notification channel in ChannelIDs.
+type Rule struct {
+	ID             int64
+	ProjectID      int64
+	Name           string
+	Metric         Metric
+	ProgramAddress string
+	Comparison     Comparison
+	Threshold      float64
+	Hysteresis     float64
+	Window         time.Duration
+	For            time.Duration
+	Severity       Severity
+	WebhookURL     string
+	ChannelIDs     []int64
+	Enabled        bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Global returns true when the rule is not scoped to a program.
+func (r Rule) Global() bool {
+	return r.ProgramAddress == ""
+}
+
+// Validate checks that the rule is well formed and its program address, when
+// scoped to a program, is a base58 encoded 32 bytes public key.
+func (r Rule) Validate() error {
+	switch {
+	case r.Name == "":
+		return fmt.Errorf("name is required: %w", ErrInvalidRule)
+	case !r.Global() && len(base58.Decode(r.ProgramAddress)) != publicKeyLength:
+		return fmt.Errorf("program address %q is not a base58 public key: %w",
+			r.ProgramAddress, ErrInvalidRule)
+	case !r.Metric.valid():
+		return fmt.Errorf("unknown metric %q: %w", r.Metric, ErrInvalidRule)
+	case !r.Comparison.valid():
+		return fmt.Errorf("unknown comparison %q: %w", r.Comparison, ErrInvalidRule)
+	case !r.Severity.valid():
+		return fmt.Errorf("unknown severity %q: %w", r.Severity, ErrInvalidRule)
+	case r.Window <= 0:
+		return fmt.Errorf("window must be positive: %w", ErrInvalidRule)
+	case r.For < 0:
+		return fmt.Errorf("for can't be negative: %w", ErrInvalidRule)
+	case r.Hysteresis < 0:
+		return fmt.Errorf("hysteresis can't be negative: %w", ErrInvalidRule)
+	}
+
+	return nil
+}
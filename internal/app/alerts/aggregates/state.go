@@ -0,0 +1,129 @@
+package aggregates
+
+import "time"
+
+// State represents the state of an alert rule.
+type State string
+
+const (
+	// StateOK means the rule's condition is not breached.
+	StateOK State = "ok"
+	// StatePending means the rule's condition is breached but not for long
+	// enough to fire.
+	StatePending State = "pending"
+	// StateFiring means the rule's condition has been breached for at least
+	// the rule's for-duration.
+	StateFiring State = "firing"
+	// StateResolved is only used in the alert history, it's recorded when a
+	// firing alert recovers.
+	StateResolved State = "resolved"
+)
+
+// Status represents the current evaluation status of an alert rule.
+type Status struct {
+	RuleID          int64
+	State           State
+	PendingSince    *time.Time
+	FiringSince     *time.Time
+	LastValue       float64
+	LastEvaluatedAt time.Time
+}
+
+// Transition evaluates the new value of the rule's metric and returns the new
+// status, the returned bool is true when the alert started firing or got
+// resolved, which are the state changes worth notifying.
+func (s Status) Transition(
+	rule Rule, value float64, now time.Time) (Status, bool) {
+	next := s
+	next.RuleID = rule.ID
+	next.LastValue = value
+	next.LastEvaluatedAt = now
+
+	breached := rule.Comparison.Breached(value, rule.Threshold)
+
+	switch s.State {
+	case StateFiring:
+		if !rule.Comparison.Recovered(value, rule.Threshold, rule.Hysteresis) {
+			return next, false
+		}
+
+		next.State = StateOK
+		next.PendingSince = nil
+		next.FiringSince = nil
+		return next, true
+
+	case StatePending:
+		if !breached {
+			next.State = StateOK
+			next.PendingSince = nil
+			return next, false
+		}
+
+		if s.PendingSince != nil && now.Sub(*s.PendingSince) >= rule.For {
+			next.State = StateFiring
+			next.FiringSince = &now
+			return next, true
+		}
+
+		return next, false
+
+	default:
+		if !breached {
+			next.State = StateOK
+			return next, false
+		}
+
+		if rule.For == 0 {
+			next.State = StateFiring
+			next.FiringSince = &now
+			return next, true
+		}
+
+		next.State = StatePending
+		next.PendingSince = &now
+		return next, false
+	}
+}
+
+// Event represents a state change of an alert rule, the alert history.
+type Event struct {
+	ID             int64
+	ProjectID      int64
+	RuleID         int64
+	RuleName       string
+	Metric         Metric
+	ProgramAddress string
+	Severity       Severity
+	State          State
+	Value          float64
+	Threshold      float64
+	CreatedAt      time.Time
+}
+
+// NewEvent creates the event for a rule transition to the given status.
+func NewEvent(rule Rule, status Status) Event {
+	state := StateResolved
+	if status.State == StateFiring {
+		state = StateFiring
+	}
+
+	return Event{
+		ProjectID:      rule.ProjectID,
+		RuleID:         rule.ID,
+		RuleName:       rule.Name,
+		Metric:         rule.Metric,
+		ProgramAddress: rule.ProgramAddress,
+		Severity:       rule.Severity,
+		State:          state,
+		Value:          status.LastValue,
+		Threshold:      rule.Threshold,
+		CreatedAt:      status.LastEvaluatedAt,
+	}
+}
+
+// EventsFilter represents the filters to query the alert history.
+type EventsFilter struct {
+	RuleID *int64
+	Since  *time.Time
+	Limit  int
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	notificationsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type evaluatorSQLRepository interface {
+	SelectEnabledRules(context.Context) ([]aggregates.Rule, error)
+	SelectStatuses(context.Context) ([]aggregates.Status, error)
+	UpsertStatus(context.Context, aggregates.Status) error
+	InsertEvent(context.Context, aggregates.Event) (aggregates.Event, error)
+}
+
+type evaluatorMetricsRepository interface {
+	QueryWindowStats(
+		context.Context, string, time.Duration) (metricsAggregates.WindowStats, error)
+}
+
+type evaluatorNotifier interface {
+	Notify(context.Context, aggregates.Rule, aggregates.Event) error
+}
+
+type evaluatorEnqueuer interface {
+	Enqueue(context.Context, []int64, notificationsAggregates.Message) error
+}
+
+// Evaluator is a service that periodically evaluates the alert rules against
+// the ingested metrics, tracking their state and notifying the state changes.
+type Evaluator struct {
+	sqlRepository     evaluatorSQLRepository
+	metricsRepository evaluatorMetricsRepository
+	notifier          evaluatorNotifier
+	enqueuer          evaluatorEnqueuer
+	interval          time.Duration
+}
+
+// NewEvaluator creates a new instance of the Evaluator service.
+func NewEvaluator(
+	sqlRepository evaluatorSQLRepository,
+	metricsRepository evaluatorMetricsRepository,
+	notifier evaluatorNotifier,
+	enqueuer evaluatorEnqueuer,
+	interval time.Duration,
+) *Evaluator {
+	return &Evaluator{
+		sqlRepository:     sqlRepository,
+		metricsRepository: metricsRepository,
+		notifier:          notifier,
+		enqueuer:          enqueuer,
+		interval:          interval,
+	}
+}
+
+// Evaluate starts evaluating the alert rules every interval.
+func (e *Evaluator) Evaluate(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := e.evaluate(ctx, time.Now().UTC()); err != nil {
+				slog.Error("error while evaluating alert rules", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, now time.Time) error {
+	// Rules are evaluated for every project, each one in its own tenant.
+	rules, err := e.sqlRepository.SelectEnabledRules(
+		tenantsAggregates.WithAllProjects(ctx))
+	if err != nil {
+		return fmt.Errorf("e.sqlRepository.SelectEnabledRules: %w", err)
+	}
+
+	statuses, err := e.sqlRepository.SelectStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("e.sqlRepository.SelectStatuses: %w", err)
+	}
+
+	statusesByRule := make(map[int64]aggregates.Status, len(statuses))
+	for _, status := range statuses {
+		statusesByRule[status.RuleID] = status
+	}
+
+	// Several rules usually share the same scope and window, so we only
+	// query the metrics once per project, scope and window on every
+	// evaluation.
+	windowStats := make(map[string]metricsAggregates.WindowStats)
+
+	for _, rule := range rules {
+		key := fmt.Sprintf("%d/%s/%s", rule.ProjectID, rule.ProgramAddress, rule.Window)
+
+		stats, ok := windowStats[key]
+		if !ok {
+			stats, err = e.metricsRepository.QueryWindowStats(
+				tenantsAggregates.WithProjectID(ctx, rule.ProjectID),
+				rule.ProgramAddress, rule.Window)
+			if err != nil {
+				slog.Error("error while querying alert rule metrics",
+					slog.Int64("rule_id", rule.ID), slog.Any("error", err))
+				continue
+			}
+
+			windowStats[key] = stats
+		}
+
+		// Without transactions in the window the metrics are zeroed, which
+		// would fire every "less than" rule, so the rules keep their state
+		// until there's traffic again.
+		if stats.Count == 0 {
+			continue
+		}
+
+		status, changed := statusesByRule[rule.ID].Transition(
+			rule, rule.Metric.Value(stats), now)
+
+		if err := e.sqlRepository.UpsertStatus(ctx, status); err != nil {
+			slog.Error("error while storing alert rule status",
+				slog.Int64("rule_id", rule.ID), slog.Any("error", err))
+			continue
+		}
+
+		if !changed {
+			continue
+		}
+
+		event, err := e.sqlRepository.InsertEvent(ctx, aggregates.NewEvent(rule, status))
+		if err != nil {
+			slog.Error("error while storing alert event",
+				slog.Int64("rule_id", rule.ID), slog.Any("error", err))
+			continue
+		}
+
+		if err := e.notifier.Notify(ctx, rule, event); err != nil {
+			slog.Error("error while notifying alert event",
+				slog.Int64("rule_id", rule.ID), slog.Any("error", err))
+		}
+
+		if err := e.enqueuer.Enqueue(
+			tenantsAggregates.WithProjectID(ctx, rule.ProjectID),
+			rule.ChannelIDs, notificationMessage(rule, event)); err != nil {
+			slog.Error("error while queueing alert notifications",
+				slog.Int64("rule_id", rule.ID), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
+
+// notificationMessage builds the notification of an alert event, every event
+// of the same rule shares the dedup key so a resolved notification resolves
+// the triggered one.
+func notificationMessage(
+	rule aggregates.Rule, event aggregates.Event) notificationsAggregates.Message {
+	state := notificationsAggregates.StateTriggered
+	if event.State == aggregates.StateResolved {
+		state = notificationsAggregates.StateResolved
+	}
+
+	scope := "all programs"
+	if !rule.Global() {
+		scope = rule.ProgramAddress
+	}
+
+	return notificationsAggregates.Message{
+		Title: fmt.Sprintf("%s is %s", rule.Name, event.State),
+		Text: fmt.Sprintf("%s for %s is %.2f (%s %.2f over the last %s)",
+			rule.Metric, scope, event.Value, rule.Comparison, rule.Threshold, rule.Window),
+		Severity: notificationsAggregates.Severity(rule.Severity),
+		State:    state,
+		DedupKey: fmt.Sprintf("encinitas-alert-%d", rule.ID),
+		Source:   "encinitas",
+		Fields: []notificationsAggregates.Field{
+			{Name: "Metric", Value: string(rule.Metric)},
+			{Name: "Scope", Value: scope},
+			{Name: "Value", Value: fmt.Sprintf("%.2f", event.Value)},
+			{Name: "Threshold", Value: fmt.Sprintf("%s %.2f", rule.Comparison, rule.Threshold)},
+			{Name: "Severity", Value: string(rule.Severity)},
+		},
+		CreatedAt: event.CreatedAt,
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	notificationsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// statusStore keeps the rules, their statuses and the alert history in memory.
+type statusStore struct {
+	rules    []aggregates.Rule
+	statuses map[int64]aggregates.Status
+	events   []aggregates.Event
+}
+
+func (ss *statusStore) SelectEnabledRules(context.Context) ([]aggregates.Rule, error) {
+	return ss.rules, nil
+}
+
+func (ss *statusStore) SelectStatuses(context.Context) ([]aggregates.Status, error) {
+	statuses := make([]aggregates.Status, 0, len(ss.statuses))
+	for _, status := range ss.statuses {
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (ss *statusStore) UpsertStatus(_ context.Context, status aggregates.Status) error {
+	ss.statuses[status.RuleID] = status
+	return nil
+}
+
+func (ss *statusStore) InsertEvent(
+	_ context.Context, event aggregates.Event) (aggregates.Event, error) {
+	event.ID = int64(len(ss.events) + 1)
+	ss.events = append(ss.events, event)
+	return event, nil
+}
+
+// windowStatsStub returns the same stats for every window.
+type windowStatsStub struct {
+	stats metricsAggregates.WindowStats
+}
+
+func (ws *windowStatsStub) QueryWindowStats(context.Context, string,
+	time.Duration) (metricsAggregates.WindowStats, error) {
+	return ws.stats, nil
+}
+
+// notificationsRecorder records the notified events and the queued messages.
+type notificationsRecorder struct {
+	events   []aggregates.Event
+	messages []notificationsAggregates.Message
+}
+
+func (nr *notificationsRecorder) Notify(
+	_ context.Context, _ aggregates.Rule, event aggregates.Event) error {
+	nr.events = append(nr.events, event)
+	return nil
+}
+
+func (nr *notificationsRecorder) Enqueue(_ context.Context, _ []int64,
+	message notificationsAggregates.Message) error {
+	nr.messages = append(nr.messages, message)
+	return nil
+}
+
+func TestEvaluatorEvaluate(t *testing.T) {
+	errorRate := aggregates.Rule{
+		ID:         1,
+		ProjectID:  7,
+		Name:       "error rate",
+		Metric:     aggregates.MetricErrorRate,
+		Comparison: aggregates.ComparisonGreaterThan,
+		Threshold:  0.1,
+		Hysteresis: 0.05,
+		Window:     5 * time.Minute,
+		For:        2 * time.Minute,
+	}
+
+	immediate := errorRate
+	immediate.For = 0
+
+	throughput := aggregates.Rule{
+		ID:         2,
+		ProjectID:  7,
+		Name:       "throughput",
+		Metric:     aggregates.MetricThroughput,
+		Comparison: aggregates.ComparisonLessThan,
+		Threshold:  10,
+		Window:     5 * time.Minute,
+	}
+
+	// errorsOf returns the stats of 100 transactions with the given errors,
+	// a negative amount of errors returns an empty window.
+	errorsOf := func(errors int64) metricsAggregates.WindowStats {
+		if errors < 0 {
+			return metricsAggregates.WindowStats{}
+		}
+
+		return metricsAggregates.WindowStats{
+			Start:  time.Date(2024, 3, 4, 9, 55, 0, 0, time.UTC),
+			End:    time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			Count:  100,
+			Errors: errors,
+		}
+	}
+
+	const empty = -1
+
+	tests := []struct {
+		name   string
+		rule   aggregates.Rule
+		errors []int64
+		state  aggregates.State
+		events []aggregates.State
+	}{
+		{
+			name:   "breach fires after the for duration",
+			rule:   errorRate,
+			errors: []int64{20, 20, 20, 20},
+			state:  aggregates.StateFiring,
+			events: []aggregates.State{aggregates.StateFiring},
+		},
+		{
+			name:   "breach shorter than the for duration",
+			rule:   errorRate,
+			errors: []int64{20, 0, 20},
+			state:  aggregates.StatePending,
+		},
+		{
+			name:   "breach without for duration fires at once",
+			rule:   immediate,
+			errors: []int64{20},
+			state:  aggregates.StateFiring,
+			events: []aggregates.State{aggregates.StateFiring},
+		},
+		{
+			name:   "recovery within the hysteresis keeps firing",
+			rule:   immediate,
+			errors: []int64{20, 8},
+			state:  aggregates.StateFiring,
+			events: []aggregates.State{aggregates.StateFiring},
+		},
+		{
+			name:   "recovery past the hysteresis resolves",
+			rule:   immediate,
+			errors: []int64{20, 8, 4},
+			state:  aggregates.StateOK,
+			events: []aggregates.State{
+				aggregates.StateFiring, aggregates.StateResolved,
+			},
+		},
+		{
+			name:   "empty window keeps firing",
+			rule:   immediate,
+			errors: []int64{20, empty, empty},
+			state:  aggregates.StateFiring,
+			events: []aggregates.State{aggregates.StateFiring},
+		},
+		{
+			name:   "empty window keeps pending",
+			rule:   errorRate,
+			errors: []int64{20, empty, 20},
+			state:  aggregates.StateFiring,
+			events: []aggregates.State{aggregates.StateFiring},
+		},
+		{
+			name:   "empty windows don't fire less than rules",
+			rule:   throughput,
+			errors: []int64{empty, empty},
+		},
+		{
+			name:   "traffic after empty windows",
+			rule:   throughput,
+			errors: []int64{empty, 0},
+			state:  aggregates.StateOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &statusStore{
+				rules:    []aggregates.Rule{test.rule},
+				statuses: map[int64]aggregates.Status{},
+			}
+			stats := &windowStatsStub{}
+			recorder := &notificationsRecorder{}
+
+			evaluator := NewEvaluator(store, stats, recorder, recorder, time.Minute)
+
+			now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+			for _, errors := range test.errors {
+				stats.stats = errorsOf(errors)
+
+				if err := evaluator.evaluate(context.Background(), now); err != nil {
+					t.Fatalf("err = %v", err)
+				}
+
+				now = now.Add(time.Minute)
+			}
+
+			if state := store.statuses[test.rule.ID].State; state != test.state {
+				t.Errorf("state = %q, want %q", state, test.state)
+			}
+
+			if len(store.events) != len(test.events) {
+				t.Fatalf("events = %+v, want %v", store.events, test.events)
+			}
+
+			for i, state := range test.events {
+				if store.events[i].State != state {
+					t.Errorf("events[%d].State = %q, want %q",
+						i, store.events[i].State, state)
+				}
+			}
+
+			if len(recorder.events) != len(test.events) {
+				t.Errorf("notified events = %d, want %d",
+					len(recorder.events), len(test.events))
+			}
+
+			if len(recorder.messages) != len(test.events) {
+				t.Errorf("queued messages = %d, want %d",
+					len(recorder.messages), len(test.events))
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+type historyGetterRepository interface {
+	SelectEvents(context.Context, aggregates.EventsFilter) ([]aggregates.Event, error)
+}
+
+// HistoryGetter defines the methods needed to get the alert history.
+type HistoryGetter struct {
+	historyGetterRepository historyGetterRepository
+}
+
+// NewHistoryGetter initializes a new HistoryGetter.
+func NewHistoryGetter(
+	historyGetterRepository historyGetterRepository) *HistoryGetter {
+	return &HistoryGetter{
+		historyGetterRepository: historyGetterRepository,
+	}
+}
+
+// GetHistory gets the alert state changes matching the filter, newest first.
+func (hg *HistoryGetter) GetHistory(ctx context.Context,
+	filter aggregates.EventsFilter) ([]aggregates.Event, error) {
+	events, err := hg.historyGetterRepository.SelectEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"hg.historyGetterRepository.SelectEvents, err: %w", err)
+	}
+
+	return events, nil
+}
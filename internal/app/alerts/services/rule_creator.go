@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+type ruleCreatorRepository interface {
+	InsertRule(context.Context, aggregates.Rule) (aggregates.Rule, error)
+}
+
+// RuleCreator defines the methods needed to create alert rules.
+type RuleCreator struct {
+	ruleCreatorRepository ruleCreatorRepository
+}
+
+// NewRuleCreator initializes a new RuleCreator.
+func NewRuleCreator(
+	ruleCreatorRepository ruleCreatorRepository) *RuleCreator {
+	return &RuleCreator{
+		ruleCreatorRepository: ruleCreatorRepository,
+	}
+}
+
+// Create validates and creates a new alert rule.
+func (rc *RuleCreator) Create(
+	ctx context.Context, rule aggregates.Rule) (aggregates.Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return aggregates.Rule{}, fmt.Errorf("rule.Validate, err: %w", err)
+	}
+
+	rule, err := rc.ruleCreatorRepository.InsertRule(ctx, rule)
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf(
+			"rc.ruleCreatorRepository.InsertRule, err: %w", err)
+	}
+
+	return rule, nil
+}
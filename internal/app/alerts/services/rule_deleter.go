@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type ruleDeleterRepository interface {
+	DeleteRule(context.Context, int64) error
+}
+
+// RuleDeleter defines the methods needed to delete alert rules.
+type RuleDeleter struct {
+	ruleDeleterRepository ruleDeleterRepository
+}
+
+// NewRuleDeleter initializes a new RuleDeleter.
+func NewRuleDeleter(
+	ruleDeleterRepository ruleDeleterRepository) *RuleDeleter {
+	return &RuleDeleter{
+		ruleDeleterRepository: ruleDeleterRepository,
+	}
+}
+
+// Delete deletes an alert rule, its history is kept.
+func (rd *RuleDeleter) Delete(ctx context.Context, id int64) error {
+	if err := rd.ruleDeleterRepository.DeleteRule(ctx, id); err != nil {
+		return fmt.Errorf("rd.ruleDeleterRepository.DeleteRule, err: %w", err)
+	}
+
+	return nil
+}
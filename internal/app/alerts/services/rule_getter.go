@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+type ruleGetterRepository interface {
+	SelectAllRules(context.Context) ([]aggregates.Rule, error)
+	SelectRuleByID(context.Context, int64) (aggregates.Rule, error)
+}
+
+// RuleGetter defines the methods needed to get alert rules.
+type RuleGetter struct {
+	ruleGetterRepository ruleGetterRepository
+}
+
+// NewRuleGetter initializes a new RuleGetter.
+func NewRuleGetter(
+	ruleGetterRepository ruleGetterRepository) *RuleGetter {
+	return &RuleGetter{
+		ruleGetterRepository: ruleGetterRepository,
+	}
+}
+
+// GetRules gets all alert rules.
+func (rg *RuleGetter) GetRules(
+	ctx context.Context) ([]aggregates.Rule, error) {
+	rules, err := rg.ruleGetterRepository.SelectAllRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"rg.ruleGetterRepository.SelectAllRules, err: %w", err)
+	}
+
+	return rules, nil
+}
+
+// GetRule gets an alert rule by its ID.
+func (rg *RuleGetter) GetRule(
+	ctx context.Context, id int64) (aggregates.Rule, error) {
+	rule, err := rg.ruleGetterRepository.SelectRuleByID(ctx, id)
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf(
+			"rg.ruleGetterRepository.SelectRuleByID, err: %w", err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+type ruleUpdaterRepository interface {
+	UpdateRule(context.Context, aggregates.Rule) (aggregates.Rule, error)
+}
+
+// RuleUpdater defines the methods needed to update alert rules.
+type RuleUpdater struct {
+	ruleUpdaterRepository ruleUpdaterRepository
+}
+
+// NewRuleUpdater initializes a new RuleUpdater.
+func NewRuleUpdater(
+	ruleUpdaterRepository ruleUpdaterRepository) *RuleUpdater {
+	return &RuleUpdater{
+		ruleUpdaterRepository: ruleUpdaterRepository,
+	}
+}
+
+// Update validates and updates an existing alert rule.
+func (ru *RuleUpdater) Update(
+	ctx context.Context, rule aggregates.Rule) (aggregates.Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return aggregates.Rule{}, fmt.Errorf("rule.Validate, err: %w", err)
+	}
+
+	rule, err := ru.ruleUpdaterRepository.UpdateRule(ctx, rule)
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf(
+			"ru.ruleUpdaterRepository.UpdateRule, err: %w", err)
+	}
+
+	return rule, nil
+}
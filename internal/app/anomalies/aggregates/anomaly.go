@@ -0,0 +1,48 @@
+package aggregates
+
+import "time"
+
+// Anomaly represents a window whose metric value deviated from its baseline
+// beyond the detector sensitivity, Lower and Upper are the expected band.
+type Anomaly struct {
+	ID             int64
+	ProjectID      int64
+	ProgramAddress string
+	Metric         Metric
+	WindowStart    time.Time
+	WindowEnd      time.Time
+	Value          float64
+	Expected       float64
+	Lower          float64
+	Upper          float64
+	ZScore         float64
+	CreatedAt      time.Time
+}
+
+// NewAnomaly creates the anomaly of a window value against its baseline.
+func NewAnomaly(baseline Baseline, value, sensitivity float64,
+	windowStart, windowEnd time.Time) Anomaly {
+	lower, upper := baseline.Band(sensitivity)
+
+	return Anomaly{
+		ProjectID:      baseline.ProjectID,
+		ProgramAddress: baseline.ProgramAddress,
+		Metric:         baseline.Metric,
+		WindowStart:    windowStart,
+		WindowEnd:      windowEnd,
+		Value:          value,
+		Expected:       baseline.Mean,
+		Lower:          lower,
+		Upper:          upper,
+		ZScore:         baseline.ZScore(value),
+	}
+}
+
+// AnomaliesFilter represents the filters to query the anomalies.
+type AnomaliesFilter struct {
+	ProgramAddress string
+	Metric         Metric
+	Since          *time.Time
+	Until          *time.Time
+	Limit          int
+}
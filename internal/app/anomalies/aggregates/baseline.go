@@ -0,0 +1,143 @@
+package aggregates
+
+import (
+	"math"
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// Metric represents the metric an anomaly baseline is kept for.
+type Metric string
+
+const (
+	// MetricSolanaTime is the median solana time of the window.
+	MetricSolanaTime Metric = "solana_time"
+	// MetricErrorRate is the ratio of failed transactions of the window.
+	MetricErrorRate Metric = "error_rate"
+)
+
+// Metrics are the metrics watched by the anomaly detector.
+var Metrics = []Metric{MetricSolanaTime, MetricErrorRate}
+
+const (
+	// minSolanaTimeStdDev is the least standard deviation of the solana
+	// time baselines, in milliseconds.
+	minSolanaTimeStdDev = 10
+	// minErrorRateStdDev is the least standard deviation of the error rate
+	// baselines, half a percentage point.
+	minErrorRateStdDev = 0.005
+	// minRelativeStdDev is the least standard deviation of every baseline
+	// as a fraction of its mean.
+	minRelativeStdDev = 0.05
+)
+
+// Value returns the value of the metric from the given window stats.
+func (m Metric) Value(stats metricsAggregates.WindowStats) float64 {
+	switch m {
+	case MetricSolanaTime:
+		return stats.LatencyP50
+	case MetricErrorRate:
+		return stats.ErrorRate()
+	}
+
+	return 0
+}
+
+// MinStdDev returns the least standard deviation of a baseline of the
+// metric with the given mean. A baseline that barely varied, e.g. no errors
+// for weeks, still flags the first burst instead of dividing by zero, while
+// the noise around its mean isn't flagged.
+func (m Metric) MinStdDev(mean float64) float64 {
+	minStdDev := minRelativeStdDev * math.Abs(mean)
+
+	switch m {
+	case MetricSolanaTime:
+		return math.Max(minSolanaTimeStdDev, minStdDev)
+	case MetricErrorRate:
+		return math.Max(minErrorRateStdDev, minStdDev)
+	}
+
+	return minStdDev
+}
+
+// Valid returns true for the known metrics.
+func (m Metric) Valid() bool {
+	switch m {
+	case MetricSolanaTime, MetricErrorRate:
+		return true
+	}
+
+	return false
+}
+
+// HourOfWeek returns the hour of the week of t in UTC, from 0 (Sunday 00:00)
+// to 167 (Saturday 23:00). Baselines are kept per hour of week to follow the
+// daily and weekly seasonality of the traffic.
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// Baseline represents the expected value of a program metric of a project at
+// an hour of the week, as an exponentially weighted mean and variance, so it
+// keeps adapting to slow trends while older weeks fade out.
+type Baseline struct {
+	ProjectID      int64
+	ProgramAddress string
+	Metric         Metric
+	HourOfWeek     int
+	Samples        int64
+	Mean           float64
+	Variance       float64
+	LastWindowEnd  time.Time
+	UpdatedAt      time.Time
+}
+
+// StdDev returns the standard deviation of the baseline, never less than
+// the least one of its metric.
+func (b Baseline) StdDev() float64 {
+	return math.Max(math.Sqrt(b.Variance), b.Metric.MinStdDev(b.Mean))
+}
+
+// ZScore returns how many standard deviations the value is away from the
+// baseline mean, 0 for the metrics without a least standard deviation
+// while the baseline has no variance.
+func (b Baseline) ZScore(value float64) float64 {
+	stdDev := b.StdDev()
+	if stdDev == 0 {
+		return 0
+	}
+
+	return (value - b.Mean) / stdDev
+}
+
+// Band returns the expected band of the baseline for the given sensitivity,
+// the mean +/- sensitivity standard deviations, the lower bound is never
+// negative as none of the metrics can be.
+func (b Baseline) Band(sensitivity float64) (float64, float64) {
+	margin := sensitivity * b.StdDev()
+
+	return math.Max(0, b.Mean-margin), b.Mean + margin
+}
+
+// Update returns the baseline including the value, alpha is the weight of the
+// new value. The first value seeds the mean, the following ones update the
+// exponentially weighted mean and variance.
+func (b Baseline) Update(value, alpha float64, windowEnd time.Time) Baseline {
+	if b.Samples == 0 {
+		b.Mean = value
+		b.Variance = 0
+	} else {
+		diff := value - b.Mean
+		increment := alpha * diff
+		b.Mean += increment
+		b.Variance = (1 - alpha) * (b.Variance + diff*increment)
+	}
+
+	b.Samples++
+	b.LastWindowEnd = windowEnd
+
+	return b
+}
@@ -0,0 +1,156 @@
+package aggregates
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBaselineUpdate(t *testing.T) {
+	windowEnd := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		baseline Baseline
+		value    float64
+		alpha    float64
+		want     Baseline
+	}{
+		{
+			name:     "first value seeds the mean",
+			baseline: Baseline{Metric: MetricSolanaTime},
+			value:    400,
+			alpha:    0.1,
+			want:     Baseline{Metric: MetricSolanaTime, Samples: 1, Mean: 400},
+		},
+		{
+			name: "constant value keeps the variance",
+			baseline: Baseline{
+				Metric: MetricSolanaTime, Samples: 5, Mean: 400,
+			},
+			value: 400,
+			alpha: 0.1,
+			want: Baseline{
+				Metric: MetricSolanaTime, Samples: 6, Mean: 400,
+			},
+		},
+		{
+			name: "value above the mean",
+			baseline: Baseline{
+				Metric: MetricSolanaTime, Samples: 5, Mean: 400, Variance: 100,
+			},
+			value: 500,
+			alpha: 0.1,
+			// diff 100, increment 10, variance 0.9 * (100 + 100*10).
+			want: Baseline{
+				Metric: MetricSolanaTime, Samples: 6, Mean: 410, Variance: 990,
+			},
+		},
+		{
+			name: "value below the mean",
+			baseline: Baseline{
+				Metric: MetricErrorRate, Samples: 2, Mean: 0.1, Variance: 0,
+			},
+			value: 0,
+			alpha: 0.5,
+			// diff -0.1, increment -0.05, variance 0.5 * (0 + 0.005).
+			want: Baseline{
+				Metric: MetricErrorRate, Samples: 3, Mean: 0.05, Variance: 0.0025,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.baseline.Update(test.value, test.alpha, windowEnd)
+
+			if got.Samples != test.want.Samples {
+				t.Errorf("Samples = %d, want %d", got.Samples, test.want.Samples)
+			}
+
+			if math.Abs(got.Mean-test.want.Mean) > 1e-9 {
+				t.Errorf("Mean = %v, want %v", got.Mean, test.want.Mean)
+			}
+
+			if math.Abs(got.Variance-test.want.Variance) > 1e-9 {
+				t.Errorf("Variance = %v, want %v", got.Variance, test.want.Variance)
+			}
+
+			if !got.LastWindowEnd.Equal(windowEnd) {
+				t.Errorf("LastWindowEnd = %s, want %s", got.LastWindowEnd, windowEnd)
+			}
+		})
+	}
+}
+
+func TestBaselineZScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline Baseline
+		value    float64
+		want     float64
+	}{
+		{
+			name:     "value at the mean",
+			baseline: Baseline{Metric: MetricSolanaTime, Mean: 400, Variance: 2500},
+			value:    400,
+			want:     0,
+		},
+		{
+			name:     "value above the mean",
+			baseline: Baseline{Metric: MetricSolanaTime, Mean: 400, Variance: 2500},
+			value:    550,
+			want:     3,
+		},
+		{
+			name:     "value below the mean",
+			baseline: Baseline{Metric: MetricSolanaTime, Mean: 400, Variance: 2500},
+			value:    300,
+			want:     -2,
+		},
+		{
+			name:     "constant solana time, relative least deviation",
+			baseline: Baseline{Metric: MetricSolanaTime, Mean: 400},
+			value:    480,
+			want:     4,
+		},
+		{
+			name:     "constant fast solana time, absolute least deviation",
+			baseline: Baseline{Metric: MetricSolanaTime, Mean: 100},
+			value:    130,
+			want:     3,
+		},
+		{
+			name:     "errors burst after no errors",
+			baseline: Baseline{Metric: MetricErrorRate, Samples: 20},
+			value:    0.05,
+			want:     10,
+		},
+		{
+			name:     "constant error rate, relative least deviation",
+			baseline: Baseline{Metric: MetricErrorRate, Mean: 0.2},
+			value:    0.25,
+			want:     5,
+		},
+		{
+			name:     "no errors after no errors",
+			baseline: Baseline{Metric: MetricErrorRate},
+			value:    0,
+			want:     0,
+		},
+		{
+			name:     "unknown metric without variance",
+			baseline: Baseline{Metric: "unknown"},
+			value:    1,
+			want:     0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.baseline.ZScore(test.value); math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("ZScore = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+)
+
+type anomaliesGetterRepository interface {
+	SelectAnomalies(context.Context,
+		aggregates.AnomaliesFilter) ([]aggregates.Anomaly, error)
+}
+
+// AnomaliesGetter defines the methods needed to get the detected anomalies.
+type AnomaliesGetter struct {
+	anomaliesGetterRepository anomaliesGetterRepository
+}
+
+// NewAnomaliesGetter initializes a new AnomaliesGetter.
+func NewAnomaliesGetter(
+	anomaliesGetterRepository anomaliesGetterRepository) *AnomaliesGetter {
+	return &AnomaliesGetter{
+		anomaliesGetterRepository: anomaliesGetterRepository,
+	}
+}
+
+// GetAnomalies gets the anomalies matching the filter, newest first.
+func (ag *AnomaliesGetter) GetAnomalies(ctx context.Context,
+	filter aggregates.AnomaliesFilter) ([]aggregates.Anomaly, error) {
+	if filter.Metric != "" && !filter.Metric.Valid() {
+		return nil, fmt.Errorf("unknown metric %q: %w",
+			filter.Metric, aggregates.ErrInvalidFilter)
+	}
+
+	anomalies, err := ag.anomaliesGetterRepository.SelectAnomalies(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"ag.anomaliesGetterRepository.SelectAnomalies, err: %w", err)
+	}
+
+	return anomalies, nil
+}
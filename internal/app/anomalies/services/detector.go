@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type detectorSQLRepository interface {
+	SelectBaseline(context.Context, string,
+		aggregates.Metric, int) (aggregates.Baseline, error)
+	UpsertBaseline(context.Context, aggregates.Baseline) error
+	InsertAnomaly(context.Context, aggregates.Anomaly) (aggregates.Anomaly, error)
+}
+
+type detectorProgramsRepository interface {
+	SelectAllPrograms(context.Context) ([]managerAggregates.Program, error)
+}
+
+type detectorMetricsRepository interface {
+	QueryWindowStatsBetween(context.Context,
+		string, time.Time, time.Time) (metricsAggregates.WindowStats, error)
+}
+
+// DetectorConfig holds the anomaly detector settings.
+type DetectorConfig struct {
+	// Window is the length of the evaluated windows, windows are aligned to
+	// it, e.g. a 5m window evaluates 10:00-10:05, 10:05-10:10...
+	Window time.Duration
+	// Sensitivity is the z-score beyond which a window is an anomaly.
+	Sensitivity float64
+	// Alpha is the weight of every new window in its baseline.
+	Alpha float64
+	// MinSamples is the amount of windows a baseline needs before its
+	// anomalies are flagged.
+	MinSamples int64
+}
+
+// Detector is a service that keeps per program baselines of the solana time
+// and error rate by hour of week, flagging the windows that deviate from them.
+type Detector struct {
+	sqlRepository      detectorSQLRepository
+	programsRepository detectorProgramsRepository
+	metricsRepository  detectorMetricsRepository
+	config             DetectorConfig
+}
+
+// NewDetector creates a new instance of the Detector service.
+func NewDetector(
+	sqlRepository detectorSQLRepository,
+	programsRepository detectorProgramsRepository,
+	metricsRepository detectorMetricsRepository,
+	config DetectorConfig,
+) *Detector {
+	return &Detector{
+		sqlRepository:      sqlRepository,
+		programsRepository: programsRepository,
+		metricsRepository:  metricsRepository,
+		config:             config,
+	}
+}
+
+// Detect starts evaluating every completed window.
+func (d *Detector) Detect(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := d.detect(ctx, time.Now().UTC()); err != nil {
+				slog.Error("error while detecting anomalies", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (d *Detector) detect(ctx context.Context, now time.Time) error {
+	programs, err := d.programsRepository.SelectAllPrograms(
+		tenantsAggregates.WithAllProjects(ctx))
+	if err != nil {
+		return fmt.Errorf("d.programsRepository.SelectAllPrograms: %w", err)
+	}
+
+	end := now.Truncate(d.config.Window)
+	start := end.Add(-d.config.Window)
+
+	for _, program := range programs {
+		programCtx := tenantsAggregates.WithProjectID(ctx, program.ProjectID)
+
+		stats, err := d.metricsRepository.QueryWindowStatsBetween(
+			programCtx, program.ProgramAddress, start, end)
+		if err != nil {
+			slog.Error("error while querying anomaly window metrics",
+				slog.String("program_address", program.ProgramAddress),
+				slog.Any("error", err))
+			continue
+		}
+
+		// Windows without transactions don't say anything about the
+		// latency or the error rate, so they are neither flagged nor
+		// included in the baselines.
+		if stats.Count == 0 {
+			continue
+		}
+
+		for _, metric := range aggregates.Metrics {
+			if err := d.detectMetric(programCtx, program.ProgramAddress,
+				metric, metric.Value(stats), start, end); err != nil {
+				slog.Error("error while detecting program anomalies",
+					slog.String("program_address", program.ProgramAddress),
+					slog.String("metric", string(metric)),
+					slog.Any("error", err))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Detector) detectMetric(ctx context.Context, programAddress string,
+	metric aggregates.Metric, value float64, start, end time.Time) error {
+	hourOfWeek := aggregates.HourOfWeek(start)
+
+	baseline, err := d.sqlRepository.SelectBaseline(
+		ctx, programAddress, metric, hourOfWeek)
+	if err != nil {
+		return fmt.Errorf("d.sqlRepository.SelectBaseline: %w", err)
+	}
+
+	// The window was already evaluated, e.g. right after a restart.
+	if !baseline.LastWindowEnd.Before(end) {
+		return nil
+	}
+
+	baseline.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	baseline.ProgramAddress = programAddress
+	baseline.Metric = metric
+	baseline.HourOfWeek = hourOfWeek
+
+	if baseline.Samples >= d.config.MinSamples &&
+		math.Abs(baseline.ZScore(value)) > d.config.Sensitivity {
+		anomaly := aggregates.NewAnomaly(
+			baseline, value, d.config.Sensitivity, start, end)
+		anomaly.CreatedAt = time.Now().UTC()
+
+		if _, err := d.sqlRepository.InsertAnomaly(ctx, anomaly); err != nil {
+			return fmt.Errorf("d.sqlRepository.InsertAnomaly: %w", err)
+		}
+	}
+
+	baseline = baseline.Update(value, d.config.Alpha, end)
+	baseline.UpdatedAt = time.Now().UTC()
+
+	if err := d.sqlRepository.UpsertBaseline(ctx, baseline); err != nil {
+		return fmt.Errorf("d.sqlRepository.UpsertBaseline: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrForbidden       = errors.New("forbidden")
+)
@@ -0,0 +1,46 @@
+package aggregates
+
+import "context"
+
+// Principal represents who a request is made by. A principal with a
+// ProjectID is bound to that project, the admins without one, such as the
+// static admin tokens, can act on any project.
+//
+// Anonymous principals are only used when the authentication is disabled,
+// they have every permission.
+type Principal struct {
+	Subject        string
+	Role           Role
+	OrganizationID int64
+	ProjectID      int64
+	Anonymous      bool
+}
+
+// AnonymousPrincipal returns the principal of the requests when the
+// authentication is disabled.
+func AnonymousPrincipal() Principal {
+	return Principal{
+		Subject:   "anonymous",
+		Role:      RoleAdmin,
+		Anonymous: true,
+	}
+}
+
+// Allows returns whether the principal has the permissions of the required
+// role.
+func (p Principal) Allows(required Role) bool {
+	return p.Role.Includes(required)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal carried by ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,49 @@
+package aggregates
+
+// Role is the level of access of a principal, every role includes the
+// permissions of the roles below it.
+type Role string
+
+const (
+	// RoleViewer can query the metrics, transactions and configuration.
+	RoleViewer Role = "viewer"
+
+	// RoleEditor can also change the programs, alerts, SLOs and channels.
+	RoleEditor Role = "editor"
+
+	// RoleAdmin can also manage the API keys and the tenants.
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels sorts the roles by their level of access.
+var roleLevels = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Valid returns whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleLevels[r]
+	return ok
+}
+
+// Includes returns whether the role grants the permissions of the required
+// role.
+func (r Role) Includes(required Role) bool {
+	return r.Valid() && roleLevels[r] >= roleLevels[required]
+}
+
+// HighestRole returns the highest of the known roles, it returns an empty
+// role when none of them is known.
+func HighestRole(roles ...string) Role {
+	var highest Role
+	for _, name := range roles {
+		role := Role(name)
+		if role.Valid() && roleLevels[role] > roleLevels[highest] {
+			highest = role
+		}
+	}
+
+	return highest
+}
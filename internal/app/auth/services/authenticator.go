@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// keysProvider provides the public keys the JWTs are signed with, by their
+// key ID.
+type keysProvider interface {
+	Key(context.Context, string) (crypto.PublicKey, error)
+}
+
+// projectResolver resolves the project tokens, which authenticate as
+// editors of their project.
+type projectResolver interface {
+	Resolve(context.Context, string) (tenantsAggregates.Tenant, error)
+}
+
+// AuthenticatorConfig defines how the JWTs are validated. Issuer and
+// Audience are only checked when set, RoleClaim and ProjectClaim are the
+// paths of the claims holding the role and the project of the principal.
+type AuthenticatorConfig struct {
+	Issuer       string
+	Audience     string
+	RoleClaim    string
+	ProjectClaim string
+	AdminTokens  []string
+	ClockSkew    time.Duration
+}
+
+// Authenticator authenticates the principals from their bearer tokens, which
+// can be JWTs, static admin tokens or project tokens.
+type Authenticator struct {
+	keysProvider    keysProvider
+	projectResolver projectResolver
+	config          AuthenticatorConfig
+}
+
+// NewAuthenticator initializes a new Authenticator.
+func NewAuthenticator(
+	keysProvider keysProvider,
+	projectResolver projectResolver,
+	config AuthenticatorConfig,
+) *Authenticator {
+	adminTokens := make([]string, 0, len(config.AdminTokens))
+	for _, token := range config.AdminTokens {
+		if token = strings.TrimSpace(token); token != "" {
+			adminTokens = append(adminTokens, token)
+		}
+	}
+	config.AdminTokens = adminTokens
+
+	return &Authenticator{
+		keysProvider:    keysProvider,
+		projectResolver: projectResolver,
+		config:          config,
+	}
+}
+
+// Authenticate returns the principal the token identifies, it returns
+// aggregates.ErrUnauthenticated when there's no token and
+// aggregates.ErrInvalidToken when it can't be verified.
+func (a *Authenticator) Authenticate(
+	ctx context.Context, token string) (aggregates.Principal, error) {
+	if token == "" {
+		return aggregates.Principal{}, aggregates.ErrUnauthenticated
+	}
+
+	for _, adminToken := range a.config.AdminTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+			return aggregates.Principal{
+				Subject: "admin-token",
+				Role:    aggregates.RoleAdmin,
+			}, nil
+		}
+	}
+
+	if strings.Count(token, ".") == 2 {
+		principal, err := a.authenticateJWT(ctx, token)
+		if err != nil {
+			return aggregates.Principal{}, fmt.Errorf("a.authenticateJWT, err: %w", err)
+		}
+
+		return principal, nil
+	}
+
+	tenant, err := a.projectResolver.Resolve(ctx, token)
+	if err != nil {
+		if errors.Is(err, tenantsAggregates.ErrUnauthorized) {
+			return aggregates.Principal{}, aggregates.ErrInvalidToken
+		}
+
+		return aggregates.Principal{}, fmt.Errorf(
+			"a.projectResolver.Resolve, err: %w", err)
+	}
+
+	return aggregates.Principal{
+		Subject:        fmt.Sprintf("project:%d", tenant.ProjectID),
+		Role:           aggregates.RoleEditor,
+		OrganizationID: tenant.OrganizationID,
+		ProjectID:      tenant.ProjectID,
+	}, nil
+}
+
+// authenticateJWT verifies the JWT signature and its registered claims, and
+// returns the principal of its subject.
+func (a *Authenticator) authenticateJWT(
+	ctx context.Context, token string) (aggregates.Principal, error) {
+	jwt, err := parseJWT(token)
+	if err != nil {
+		return aggregates.Principal{}, fmt.Errorf("parseJWT, err: %w", err)
+	}
+
+	key, err := a.keysProvider.Key(ctx, jwt.header.Kid)
+	if err != nil {
+		return aggregates.Principal{}, fmt.Errorf("a.keysProvider.Key, err: %w", err)
+	}
+
+	if err := jwt.verify(key); err != nil {
+		return aggregates.Principal{}, fmt.Errorf("jwt.verify, err: %w", err)
+	}
+
+	if err := a.validateClaims(jwt, time.Now()); err != nil {
+		return aggregates.Principal{}, fmt.Errorf("a.validateClaims, err: %w", err)
+	}
+
+	role := aggregates.HighestRole(jwt.stringsClaim(a.config.RoleClaim)...)
+	if role == "" {
+		return aggregates.Principal{}, fmt.Errorf("jwt has no known role: %w",
+			aggregates.ErrForbidden)
+	}
+
+	projectID, err := a.projectID(jwt)
+	if err != nil {
+		return aggregates.Principal{}, fmt.Errorf("a.projectID, err: %w", err)
+	}
+
+	subject, _ := jwt.claims["sub"].(string)
+
+	return aggregates.Principal{
+		Subject:   subject,
+		Role:      role,
+		ProjectID: projectID,
+	}, nil
+}
+
+// validateClaims checks the expiration, not before, issuer and audience of
+// the JWT, the expiration is required.
+func (a *Authenticator) validateClaims(jwt jwt, now time.Time) error {
+	skew := a.config.ClockSkew.Seconds()
+	unix := float64(now.Unix())
+
+	expiresAt, ok := jwt.numericClaim("exp")
+	if !ok {
+		return fmt.Errorf("jwt has no expiration: %w", aggregates.ErrInvalidToken)
+	}
+
+	if unix > expiresAt+skew {
+		return fmt.Errorf("jwt expired: %w", aggregates.ErrInvalidToken)
+	}
+
+	if notBefore, ok := jwt.numericClaim("nbf"); ok && unix+skew < notBefore {
+		return fmt.Errorf("jwt not valid yet: %w", aggregates.ErrInvalidToken)
+	}
+
+	if a.config.Issuer != "" {
+		if issuer, _ := jwt.claims["iss"].(string); issuer != a.config.Issuer {
+			return fmt.Errorf("jwt issuer %q: %w", issuer, aggregates.ErrInvalidToken)
+		}
+	}
+
+	if a.config.Audience != "" &&
+		!slices.Contains(jwt.stringsClaim("aud"), a.config.Audience) {
+		return fmt.Errorf("jwt audience: %w", aggregates.ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// projectID returns the project the JWT is bound to, 0 when it has no
+// project claim.
+func (a *Authenticator) projectID(jwt jwt) (int64, error) {
+	if a.config.ProjectClaim == "" {
+		return 0, nil
+	}
+
+	value, ok := jwt.claim(a.config.ProjectClaim)
+	if !ok {
+		return 0, nil
+	}
+
+	var raw string
+	switch value := value.(type) {
+	case json.Number:
+		raw = value.String()
+	case string:
+		raw = value
+	}
+
+	projectID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || projectID <= 0 {
+		return 0, fmt.Errorf("jwt project claim %v: %w", value, aggregates.ErrInvalidToken)
+	}
+
+	return projectID, nil
+}
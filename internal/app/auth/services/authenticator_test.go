@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+// testKeys provides the public keys by their key ID.
+type testKeys map[string]crypto.PublicKey
+
+func (tk testKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := tk[kid]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found: %w", kid, aggregates.ErrInvalidToken)
+	}
+
+	return key, nil
+}
+
+// signer signs the JWT signing input.
+type signer func(t *testing.T, signingInput []byte) []byte
+
+func signRSA(key *rsa.PrivateKey, pss bool) signer {
+	return func(t *testing.T, signingInput []byte) []byte {
+		digest := sha256.Sum256(signingInput)
+
+		var (
+			signature []byte
+			err       error
+		)
+		if pss {
+			signature, err = rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest[:],
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signature
+	}
+}
+
+// signECDSA signs with the JWS encoding, r and s padded to the curve size.
+func signECDSA(key *ecdsa.PrivateKey) signer {
+	return func(t *testing.T, signingInput []byte) []byte {
+		digest := sha256.Sum256(signingInput)
+		size := (key.Curve.Params().BitSize + 7) / 8
+
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+
+		return signature
+	}
+}
+
+// signECDSAASN1 signs with the ASN.1 encoding, which isn't the JWS one.
+func signECDSAASN1(key *ecdsa.PrivateKey) signer {
+	return func(t *testing.T, signingInput []byte) []byte {
+		digest := sha256.Sum256(signingInput)
+
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signature
+	}
+}
+
+// resized returns the signature of sign truncated or zero padded to length.
+func resized(sign signer, length int) signer {
+	return func(t *testing.T, signingInput []byte) []byte {
+		signature := make([]byte, length)
+		copy(signature, sign(t, signingInput))
+		return signature
+	}
+}
+
+func signEd25519(key ed25519.PrivateKey) signer {
+	return func(_ *testing.T, signingInput []byte) []byte {
+		return ed25519.Sign(key, signingInput)
+	}
+}
+
+func signHMAC(secret []byte) signer {
+	return func(_ *testing.T, signingInput []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signingInput)
+		return mac.Sum(nil)
+	}
+}
+
+func unsigned(*testing.T, []byte) []byte {
+	return nil
+}
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	t.Helper()
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func newToken(t *testing.T, alg, kid string,
+	claims map[string]interface{}, sign signer) string {
+	t.Helper()
+
+	signingInput := encodeSegment(t, jwtHeader{Alg: alg, Kid: kid}) + "." +
+		encodeSegment(t, claims)
+
+	return signingInput + "." +
+		base64.RawURLEncoding.EncodeToString(sign(t, []byte(signingInput)))
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPublicKeyDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator := NewAuthenticator(
+		testKeys{
+			"rsa": &rsaKey.PublicKey,
+			"ec":  &ecKey.PublicKey,
+			"ed":  edPublicKey,
+		},
+		nil,
+		AuthenticatorConfig{
+			Issuer:       "https://issuer.example.com",
+			Audience:     "collector",
+			RoleClaim:    "roles",
+			ProjectClaim: "project_id",
+			ClockSkew:    30 * time.Second,
+		},
+	)
+
+	now := time.Now()
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"sub":        "user",
+			"iss":        "https://issuer.example.com",
+			"aud":        "collector",
+			"exp":        now.Add(time.Hour).Unix(),
+			"roles":      []string{"viewer", "editor"},
+			"project_id": 7,
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(claims, name)
+				continue
+			}
+
+			claims[name] = value
+		}
+
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{
+			name:  "RS256",
+			token: newToken(t, "RS256", "rsa", claims(nil), signRSA(rsaKey, false)),
+		},
+		{
+			name:  "PS256",
+			token: newToken(t, "PS256", "rsa", claims(nil), signRSA(rsaKey, true)),
+		},
+		{
+			name:  "ES256",
+			token: newToken(t, "ES256", "ec", claims(nil), signECDSA(ecKey)),
+		},
+		{
+			name:  "EdDSA",
+			token: newToken(t, "EdDSA", "ed", claims(nil), signEd25519(edKey)),
+		},
+		{
+			name:  "none algorithm",
+			token: newToken(t, "none", "rsa", claims(nil), unsigned),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "empty algorithm",
+			token: newToken(t, "", "rsa", claims(nil), unsigned),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name: "HS256 with the RSA public key as secret",
+			token: newToken(t, "HS256", "rsa", claims(nil),
+				signHMAC(rsaPublicKeyDER)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "HS256 with the RSA modulus as secret",
+			token: newToken(t, "HS256", "rsa", claims(nil),
+				signHMAC(rsaKey.PublicKey.N.Bytes())),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "RS256 signed with another key",
+			token: newToken(t, "RS256", "rsa", claims(nil), signRSA(otherRSAKey, false)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "RS256 verified with an EC key",
+			token: newToken(t, "RS256", "ec", claims(nil), signRSA(rsaKey, false)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "ES256 verified with an RSA key",
+			token: newToken(t, "ES256", "rsa", claims(nil), signECDSA(ecKey)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "EdDSA verified with an RSA key",
+			token: newToken(t, "EdDSA", "rsa", claims(nil), signEd25519(edKey)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "ES256 signature too short",
+			token: newToken(t, "ES256", "ec", claims(nil), resized(signECDSA(ecKey), 63)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "ES256 signature too long",
+			token: newToken(t, "ES256", "ec", claims(nil), resized(signECDSA(ecKey), 65)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "ES256 ASN.1 signature",
+			token: newToken(t, "ES256", "ec", claims(nil), signECDSAASN1(ecKey)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name:  "unknown kid",
+			token: newToken(t, "RS256", "unknown", claims(nil), signRSA(rsaKey, false)),
+			err:   aggregates.ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"exp": now.Add(-time.Minute).Unix(),
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "expired within the clock skew",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"exp": now.Add(-10 * time.Second).Unix(),
+			}), signRSA(rsaKey, false)),
+		},
+		{
+			name: "without expiration",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"exp": nil,
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "not valid yet",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"nbf": now.Add(time.Minute).Unix(),
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "not valid yet within the clock skew",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"nbf": now.Add(10 * time.Second).Unix(),
+			}), signRSA(rsaKey, false)),
+		},
+		{
+			name: "issuer mismatch",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"iss": "https://attacker.example.com",
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "without issuer",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"iss": nil,
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "audience mismatch",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"aud": "another-service",
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+		{
+			name: "audience list",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"aud": []string{"another-service", "collector"},
+			}), signRSA(rsaKey, false)),
+		},
+		{
+			name: "without a known role",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"roles": []string{"owner"},
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrForbidden,
+		},
+		{
+			name: "invalid project",
+			token: newToken(t, "RS256", "rsa", claims(map[string]interface{}{
+				"project_id": "project",
+			}), signRSA(rsaKey, false)),
+			err: aggregates.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), test.token)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("err = %v, want %v", err, test.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			want := aggregates.Principal{
+				Subject:   "user",
+				Role:      aggregates.RoleEditor,
+				ProjectID: 7,
+			}
+			if principal != want {
+				t.Errorf("principal = %+v, want %+v", principal, want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateTamperedJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator := NewAuthenticator(testKeys{"rsa": &rsaKey.PublicKey}, nil,
+		AuthenticatorConfig{RoleClaim: "roles"})
+
+	token := newToken(t, "RS256", "rsa", map[string]interface{}{
+		"sub":   "user",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": "viewer",
+	}, signRSA(rsaKey, false))
+
+	signature := token[len(token)-len(base64.RawURLEncoding.EncodeToString(
+		make([]byte, rsaKey.Size()))):]
+	tampered := newToken(t, "RS256", "rsa", map[string]interface{}{
+		"sub":   "user",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": "admin",
+	}, unsigned) + signature
+
+	if _, err := authenticator.Authenticate(context.Background(), token); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	if _, err := authenticator.Authenticate(
+		context.Background(), tampered); !errors.Is(err, aggregates.ErrInvalidToken) {
+		t.Fatalf("err = %v, want %v", err, aggregates.ErrInvalidToken)
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	// Register the hashes used by the JWT algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+// jwtHeader is the JOSE header of a JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwt is a parsed, not yet verified, JSON Web Token.
+type jwt struct {
+	header       jwtHeader
+	claims       map[string]interface{}
+	signingInput []byte
+	signature    []byte
+}
+
+// parseJWT parses a compact serialized JWT, the numeric claims are kept as
+// json.Number.
+func parseJWT(token string) (jwt, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwt{}, fmt.Errorf("jwt must have 3 parts: %w", aggregates.ErrInvalidToken)
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return jwt{}, fmt.Errorf("jwt header encoding: %w", aggregates.ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return jwt{}, fmt.Errorf("jwt header: %w", aggregates.ErrInvalidToken)
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return jwt{}, fmt.Errorf("jwt claims encoding: %w", aggregates.ErrInvalidToken)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawClaims))
+	decoder.UseNumber()
+
+	var claims map[string]interface{}
+	if err := decoder.Decode(&claims); err != nil {
+		return jwt{}, fmt.Errorf("jwt claims: %w", aggregates.ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return jwt{}, fmt.Errorf("jwt signature encoding: %w", aggregates.ErrInvalidToken)
+	}
+
+	return jwt{
+		header:       header,
+		claims:       claims,
+		signingInput: []byte(parts[0] + "." + parts[1]),
+		signature:    signature,
+	}, nil
+}
+
+// jwtHashes are the hashes of the supported JWT algorithms, the symmetric
+// and "none" algorithms are deliberately not supported.
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// verify checks the signature of the JWT with the given key, the key type
+// must match the algorithm of the JWT.
+func (t jwt) verify(key crypto.PublicKey) error {
+	alg := t.header.Alg
+
+	if alg == "EdDSA" {
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(edKey, t.signingInput, t.signature) {
+			return fmt.Errorf("jwt signature: %w", aggregates.ErrInvalidToken)
+		}
+
+		return nil
+	}
+
+	hash, ok := jwtHashes[alg]
+	if !ok {
+		return fmt.Errorf("jwt algorithm %q not supported: %w",
+			alg, aggregates.ErrInvalidToken)
+	}
+
+	hasher := hash.New()
+	hasher.Write(t.signingInput)
+	digest := hasher.Sum(nil)
+
+	var valid bool
+	switch alg[:2] {
+	case "RS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		valid = ok && rsa.VerifyPKCS1v15(rsaKey, hash, digest, t.signature) == nil
+
+	case "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		valid = ok && rsa.VerifyPSS(rsaKey, hash, digest, t.signature,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		valid = ok && verifyECDSA(ecKey, digest, t.signature)
+	}
+
+	if !valid {
+		return fmt.Errorf("jwt signature: %w", aggregates.ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// verifyECDSA verifies a JWS ECDSA signature, which is the concatenation of
+// r and s padded to the curve size.
+func verifyECDSA(key *ecdsa.PublicKey, digest, signature []byte) bool {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+
+	return ecdsa.Verify(key, digest, r, s)
+}
+
+// claim returns the claim at the given path, nested claims are separated by
+// dots, e.g. "realm_access.roles".
+func (t jwt) claim(path string) (interface{}, bool) {
+	var value interface{} = t.claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = object[name]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// numericClaim returns a NumericDate or numeric claim.
+func (t jwt) numericClaim(path string) (float64, bool) {
+	value, ok := t.claim(path)
+	if !ok {
+		return 0, false
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	float, err := number.Float64()
+	return float, err == nil
+}
+
+// stringsClaim returns a claim that can either be a string or an array of
+// strings, such as the audience or the roles.
+func (t jwt) stringsClaim(path string) []string {
+	value, ok := t.claim(path)
+	if !ok {
+		return nil
+	}
+
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				values = append(values, item)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package aggregates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// digestPeriod is the period every digest summarizes.
+const digestPeriod = 7 * 24 * time.Hour
+
+// Schedule represents when the weekly digests are sent, every Weekday at
+// Hour UTC.
+type Schedule struct {
+	Weekday time.Weekday
+	Hour    int
+}
+
+// PeriodEnd returns the end of the last period that can be sent at the given
+// time, which is the last scheduled time.
+func (s Schedule) PeriodEnd(now time.Time) time.Time {
+	now = now.UTC()
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, time.UTC)
+	end = end.AddDate(0, 0, -int((7+now.Weekday()-s.Weekday)%7))
+
+	if end.After(now) {
+		end = end.AddDate(0, 0, -7)
+	}
+
+	return end
+}
+
+// NewSchedule returns the schedule of the digests sent every weekday, e.g.
+// "monday", at hour UTC.
+func NewSchedule(weekday string, hour int) (Schedule, error) {
+	if hour < 0 || hour > 23 {
+		return Schedule{}, fmt.Errorf("hour must be between 0 and 23: %w",
+			ErrInvalidSchedule)
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(weekday, day.String()) {
+			return Schedule{Weekday: day, Hour: hour}, nil
+		}
+	}
+
+	return Schedule{}, fmt.Errorf("unknown weekday %q: %w", weekday,
+		ErrInvalidSchedule)
+}
+
+// WeekStats represents the performance of a program within a week, the
+// latencies are in milliseconds.
+type WeekStats struct {
+	Start        time.Time
+	End          time.Time
+	Transactions int64
+	Errors       int64
+	LatencyP50   float64
+	LatencyP95   float64
+}
+
+// Throughput returns the average amount of transactions per minute.
+func (ws WeekStats) Throughput() float64 {
+	minutes := ws.End.Sub(ws.Start).Minutes()
+	if minutes <= 0 {
+		return 0
+	}
+
+	return float64(ws.Transactions) / minutes
+}
+
+// SuccessRate returns the ratio of successful transactions, 1 when there
+// were no transactions.
+func (ws WeekStats) SuccessRate() float64 {
+	if ws.Transactions == 0 {
+		return 1
+	}
+
+	return 1 - float64(ws.Errors)/float64(ws.Transactions)
+}
+
+// Digest represents the weekly performance summary of a program along with
+// the previous week, for the week over week deltas.
+type Digest struct {
+	ProjectID      int64
+	ProgramAddress string
+	ProgramName    string
+	Current        WeekStats
+	Previous       WeekStats
+}
+
+// Periods returns the current and previous week of the digest ending at the
+// given time.
+func Periods(end time.Time) (WeekStats, WeekStats) {
+	return WeekStats{Start: end.Add(-digestPeriod), End: end},
+		WeekStats{Start: end.Add(-2 * digestPeriod), End: end.Add(-digestPeriod)}
+}
+
+// ThroughputDelta returns the relative change of the throughput, nil when
+// there's no previous throughput to compare with.
+func (d Digest) ThroughputDelta() *float64 {
+	return relativeDelta(d.Current.Throughput(), d.Previous.Throughput())
+}
+
+// SuccessRateDelta returns the change of the success rate in percentage
+// points, nil when there were no transactions in either week.
+func (d Digest) SuccessRateDelta() *float64 {
+	if d.Current.Transactions == 0 || d.Previous.Transactions == 0 {
+		return nil
+	}
+
+	delta := (d.Current.SuccessRate() - d.Previous.SuccessRate()) * 100
+	return &delta
+}
+
+// LatencyP50Delta returns the relative change of the median latency.
+func (d Digest) LatencyP50Delta() *float64 {
+	return relativeDelta(d.Current.LatencyP50, d.Previous.LatencyP50)
+}
+
+// LatencyP95Delta returns the relative change of the 95th percentile
+// latency.
+func (d Digest) LatencyP95Delta() *float64 {
+	return relativeDelta(d.Current.LatencyP95, d.Previous.LatencyP95)
+}
+
+func relativeDelta(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+
+	delta := (current - previous) / previous
+	return &delta
+}
@@ -0,0 +1,9 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
+	ErrInvalidSchedule           = errors.New("invalid schedule")
+)
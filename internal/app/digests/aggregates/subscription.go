@@ -0,0 +1,35 @@
+package aggregates
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// unsubscribeTokenLength is the number of random bytes of the unsubscribe
+// tokens.
+const unsubscribeTokenLength = 24
+
+// Subscription represents an email subscribed to the weekly digest of a
+// program. LastSentAt is the end of the last period the digest was sent for,
+// the UnsubscribeToken is linked from every digest.
+type Subscription struct {
+	ID               int64
+	ProjectID        int64
+	ProgramAddress   string
+	Email            string
+	UnsubscribeToken string
+	LastSentAt       *time.Time
+	CreatedAt        time.Time
+}
+
+// NewUnsubscribeToken generates a new random unsubscribe token.
+func NewUnsubscribeToken() (string, error) {
+	bytes := make([]byte, unsubscribeTokenLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("rand.Read, err: %w", err)
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	mailAggregates "github.com/jcleira/encinitas-collector-go/internal/app/mail/aggregates"
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type schedulerRepository interface {
+	SelectDueSubscriptions(context.Context, time.Time) ([]aggregates.Subscription, error)
+	ClaimSubscription(context.Context, int64, time.Time) (bool, error)
+	ReleaseSubscription(context.Context, int64, *time.Time) error
+}
+
+type schedulerMetricsRepository interface {
+	QueryWindowStatsBetween(context.Context, string, time.Time,
+		time.Time) (metricsAggregates.WindowStats, error)
+}
+
+// mailer sends the digests, e.g. through SMTP.
+type mailer interface {
+	Send(context.Context, mailAggregates.Mail) error
+}
+
+// SchedulerConfig defines when the digests are sent, how often the scheduler
+// checks for due digests and the URL the collector is reached at, for the
+// unsubscribe links.
+type SchedulerConfig struct {
+	Schedule      aggregates.Schedule
+	CheckInterval time.Duration
+	PublicURL     string
+}
+
+// Scheduler is a service that periodically sends the weekly performance
+// digest of every program to its subscribers.
+type Scheduler struct {
+	schedulerRepository schedulerRepository
+	programsRepository  programsRepository
+	metricsRepository   schedulerMetricsRepository
+	mailer              mailer
+	config              SchedulerConfig
+}
+
+// NewScheduler creates a new instance of the Scheduler service.
+func NewScheduler(
+	schedulerRepository schedulerRepository,
+	programsRepository programsRepository,
+	metricsRepository schedulerMetricsRepository,
+	mailer mailer,
+	config SchedulerConfig,
+) *Scheduler {
+	return &Scheduler{
+		schedulerRepository: schedulerRepository,
+		programsRepository:  programsRepository,
+		metricsRepository:   metricsRepository,
+		mailer:              mailer,
+		config:              config,
+	}
+}
+
+// Schedule starts checking for due digests every check interval.
+func (s *Scheduler) Schedule(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := s.send(ctx, time.Now().UTC()); err != nil {
+				slog.Error("error while sending digests", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// send sends the digest of the last period to every subscription that
+// hasn't received it yet. Subscriptions are claimed before sending so that
+// several collectors don't send the same digest twice, and released if the
+// digest can't be sent so it's retried on the next check.
+func (s *Scheduler) send(ctx context.Context, now time.Time) error {
+	periodEnd := s.config.Schedule.PeriodEnd(now)
+
+	subscriptions, err := s.schedulerRepository.SelectDueSubscriptions(
+		tenantsAggregates.WithAllProjects(ctx), periodEnd)
+	if err != nil {
+		return fmt.Errorf("s.schedulerRepository.SelectDueSubscriptions: %w", err)
+	}
+
+	type programKey struct {
+		projectID      int64
+		programAddress string
+	}
+
+	digests := make(map[programKey]*aggregates.Digest)
+	for _, subscription := range subscriptions {
+		key := programKey{subscription.ProjectID, subscription.ProgramAddress}
+
+		digest, ok := digests[key]
+		if !ok {
+			compiled, err := s.compile(ctx, subscription.ProjectID,
+				subscription.ProgramAddress, periodEnd)
+			if err != nil {
+				slog.Error("error while compiling digest",
+					slog.String("program_address", subscription.ProgramAddress),
+					slog.Any("error", err))
+			}
+
+			digest = compiled
+			digests[key] = digest
+		}
+
+		if digest == nil {
+			continue
+		}
+
+		if err := s.deliver(ctx, subscription, *digest, periodEnd); err != nil {
+			slog.Error("error while sending digest",
+				slog.Int64("subscription_id", subscription.ID), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
+
+// compile builds the digest of a program for the week ending at periodEnd,
+// nil when the program doesn't exist anymore.
+func (s *Scheduler) compile(ctx context.Context, projectID int64,
+	programAddress string, periodEnd time.Time) (*aggregates.Digest, error) {
+	ctx = tenantsAggregates.WithProjectID(ctx, projectID)
+
+	program, err := s.programsRepository.SelectProgramByAddress(ctx, programAddress)
+	if err != nil {
+		if errors.Is(err, managerAggregates.ErrProgramNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("s.programsRepository.SelectProgramByAddress: %w", err)
+	}
+
+	digest := aggregates.Digest{
+		ProjectID:      projectID,
+		ProgramAddress: programAddress,
+		ProgramName:    program.ProgramName,
+	}
+	digest.Current, digest.Previous = aggregates.Periods(periodEnd)
+
+	for _, week := range []*aggregates.WeekStats{&digest.Current, &digest.Previous} {
+		stats, err := s.metricsRepository.QueryWindowStatsBetween(ctx,
+			programAddress, week.Start, week.End)
+		if err != nil {
+			return nil, fmt.Errorf("s.metricsRepository.QueryWindowStatsBetween: %w", err)
+		}
+
+		week.Transactions = stats.Count
+		week.Errors = stats.Errors
+		week.LatencyP50 = stats.LatencyP50
+		week.LatencyP95 = stats.LatencyP95
+	}
+
+	return &digest, nil
+}
+
+// deliver claims a subscription for the period and sends it the digest.
+func (s *Scheduler) deliver(ctx context.Context,
+	subscription aggregates.Subscription, digest aggregates.Digest,
+	periodEnd time.Time) error {
+	claimed, err := s.schedulerRepository.ClaimSubscription(
+		ctx, subscription.ID, periodEnd)
+	if err != nil {
+		return fmt.Errorf("s.schedulerRepository.ClaimSubscription: %w", err)
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	mail, err := renderDigest(digest, strings.TrimSuffix(s.config.PublicURL, "/")+
+		"/manager/digests/unsubscribe?token="+url.QueryEscape(subscription.UnsubscribeToken))
+	if err == nil {
+		mail.To = []string{subscription.Email}
+		err = s.mailer.Send(ctx, mail)
+	}
+
+	if err != nil {
+		if releaseErr := s.schedulerRepository.ReleaseSubscription(
+			ctx, subscription.ID, subscription.LastSentAt); releaseErr != nil {
+			slog.Error("error while releasing digest subscription",
+				slog.Int64("subscription_id", subscription.ID),
+				slog.Any("error", releaseErr))
+		}
+
+		return fmt.Errorf("s.mailer.Send: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type subscriptionCreatorRepository interface {
+	InsertSubscription(context.Context,
+		aggregates.Subscription) (aggregates.Subscription, error)
+}
+
+type programsRepository interface {
+	SelectProgramByAddress(context.Context, string) (managerAggregates.Program, error)
+}
+
+// SubscriptionCreator defines the methods needed to subscribe emails to the
+// weekly digest of a program.
+type SubscriptionCreator struct {
+	subscriptionCreatorRepository subscriptionCreatorRepository
+	programsRepository            programsRepository
+}
+
+// NewSubscriptionCreator initializes a new SubscriptionCreator.
+func NewSubscriptionCreator(
+	subscriptionCreatorRepository subscriptionCreatorRepository,
+	programsRepository programsRepository) *SubscriptionCreator {
+	return &SubscriptionCreator{
+		subscriptionCreatorRepository: subscriptionCreatorRepository,
+		programsRepository:            programsRepository,
+	}
+}
+
+// Create subscribes an email to the weekly digest of a program of the
+// tenant in ctx.
+func (sc *SubscriptionCreator) Create(ctx context.Context,
+	programAddress, email string) (aggregates.Subscription, error) {
+	address, err := managerAggregates.NormalizeEmail(email)
+	if err != nil {
+		return aggregates.Subscription{}, fmt.Errorf(
+			"managerAggregates.NormalizeEmail, err: %w", err)
+	}
+
+	program, err := sc.programsRepository.SelectProgramByAddress(ctx, programAddress)
+	if err != nil {
+		return aggregates.Subscription{}, fmt.Errorf(
+			"sc.programsRepository.SelectProgramByAddress, err: %w", err)
+	}
+
+	token, err := aggregates.NewUnsubscribeToken()
+	if err != nil {
+		return aggregates.Subscription{}, fmt.Errorf(
+			"aggregates.NewUnsubscribeToken, err: %w", err)
+	}
+
+	subscription, err := sc.subscriptionCreatorRepository.InsertSubscription(ctx,
+		aggregates.Subscription{
+			ProjectID:        program.ProjectID,
+			ProgramAddress:   program.ProgramAddress,
+			Email:            address,
+			UnsubscribeToken: token,
+		})
+	if err != nil {
+		return aggregates.Subscription{}, fmt.Errorf(
+			"sc.subscriptionCreatorRepository.InsertSubscription, err: %w", err)
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type subscriptionDeleterRepository interface {
+	DeleteSubscription(context.Context, string, int64) error
+	DeleteSubscriptionByToken(context.Context, string) error
+}
+
+// SubscriptionDeleter defines the methods needed to delete digest
+// subscriptions, either by a project member or through the unsubscribe link
+// of the digests.
+type SubscriptionDeleter struct {
+	subscriptionDeleterRepository subscriptionDeleterRepository
+}
+
+// NewSubscriptionDeleter initializes a new SubscriptionDeleter.
+func NewSubscriptionDeleter(
+	subscriptionDeleterRepository subscriptionDeleterRepository) *SubscriptionDeleter {
+	return &SubscriptionDeleter{
+		subscriptionDeleterRepository: subscriptionDeleterRepository,
+	}
+}
+
+// Delete deletes a digest subscription of a program.
+func (sd *SubscriptionDeleter) Delete(ctx context.Context,
+	programAddress string, id int64) error {
+	if err := sd.subscriptionDeleterRepository.DeleteSubscription(
+		ctx, programAddress, id); err != nil {
+		return fmt.Errorf(
+			"sd.subscriptionDeleterRepository.DeleteSubscription, err: %w", err)
+	}
+
+	return nil
+}
+
+// Unsubscribe deletes the digest subscription with the given unsubscribe
+// token.
+func (sd *SubscriptionDeleter) Unsubscribe(ctx context.Context, token string) error {
+	if err := sd.subscriptionDeleterRepository.DeleteSubscriptionByToken(
+		ctx, token); err != nil {
+		return fmt.Errorf(
+			"sd.subscriptionDeleterRepository.DeleteSubscriptionByToken, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+)
+
+type subscriptionGetterRepository interface {
+	SelectSubscriptions(context.Context, string) ([]aggregates.Subscription, error)
+}
+
+// SubscriptionGetter defines the methods needed to get the digest
+// subscriptions.
+type SubscriptionGetter struct {
+	subscriptionGetterRepository subscriptionGetterRepository
+}
+
+// NewSubscriptionGetter initializes a new SubscriptionGetter.
+func NewSubscriptionGetter(
+	subscriptionGetterRepository subscriptionGetterRepository) *SubscriptionGetter {
+	return &SubscriptionGetter{
+		subscriptionGetterRepository: subscriptionGetterRepository,
+	}
+}
+
+// GetSubscriptions gets the digest subscriptions of a program.
+func (sg *SubscriptionGetter) GetSubscriptions(ctx context.Context,
+	programAddress string) ([]aggregates.Subscription, error) {
+	subscriptions, err := sg.subscriptionGetterRepository.SelectSubscriptions(
+		ctx, programAddress)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"sg.subscriptionGetterRepository.SelectSubscriptions, err: %w", err)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	mailAggregates "github.com/jcleira/encinitas-collector-go/internal/app/mail/aggregates"
+)
+
+const (
+	digestSubjectTemplate = `Weekly digest for {{.Name}}: {{.Current.Transactions}} transactions, {{percent .Current.SuccessRate}} success`
+
+	digestTextTemplate = `Weekly performance digest for {{.Name}}
+{{.Digest.ProgramAddress}}
+{{date .Current.Start}} - {{date .Current.End}}
+
+Transactions:  {{.Current.Transactions}}
+Throughput:    {{number .Current.Throughput}} tx/min ({{delta .ThroughputDelta}} week over week)
+Success rate:  {{percent .Current.SuccessRate}} ({{points .SuccessRateDelta}} week over week)
+Latency p50:   {{ms .Current.LatencyP50}} ({{delta .LatencyP50Delta}} week over week)
+Latency p95:   {{ms .Current.LatencyP95}} ({{delta .LatencyP95Delta}} week over week)
+
+To stop receiving this digest: {{.UnsubscribeURL}}
+`
+
+	digestHTMLTemplate = `<h2>Weekly performance digest for {{.Name}}</h2>
+<p><code>{{.Digest.ProgramAddress}}</code><br>{{date .Current.Start}} - {{date .Current.End}}</p>
+<table cellpadding="6" style="border-collapse:collapse">
+<tr><th align="left">Metric</th><th align="right">This week</th><th align="right">Week over week</th></tr>
+<tr><td>Transactions</td><td align="right">{{.Current.Transactions}}</td><td align="right"></td></tr>
+<tr><td>Throughput</td><td align="right">{{number .Current.Throughput}} tx/min</td><td align="right">{{delta .ThroughputDelta}}</td></tr>
+<tr><td>Success rate</td><td align="right">{{percent .Current.SuccessRate}}</td><td align="right">{{points .SuccessRateDelta}}</td></tr>
+<tr><td>Latency p50</td><td align="right">{{ms .Current.LatencyP50}}</td><td align="right">{{delta .LatencyP50Delta}}</td></tr>
+<tr><td>Latency p95</td><td align="right">{{ms .Current.LatencyP95}}</td><td align="right">{{delta .LatencyP95Delta}}</td></tr>
+</table>
+<p style="font-size:12px"><a href="{{.UnsubscribeURL}}">Unsubscribe</a> from this digest.</p>
+`
+)
+
+// digestFuncs are the formatting functions shared by the digest templates.
+var digestFuncs = map[string]any{
+	"date":    func(t time.Time) string { return t.Format("Jan 2, 2006") },
+	"number":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"ms":      func(v float64) string { return fmt.Sprintf("%.0f ms", v) },
+	"percent": func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) },
+	"delta": func(v *float64) string {
+		if v == nil {
+			return "n/a"
+		}
+		return fmt.Sprintf("%+.1f%%", *v*100)
+	},
+	"points": func(v *float64) string {
+		if v == nil {
+			return "n/a"
+		}
+		return fmt.Sprintf("%+.2f pp", *v)
+	},
+}
+
+var (
+	digestSubject = textTemplate.Must(textTemplate.New("subject").
+			Funcs(digestFuncs).Parse(digestSubjectTemplate))
+	digestText = textTemplate.Must(textTemplate.New("text").
+			Funcs(digestFuncs).Parse(digestTextTemplate))
+	digestHTML = htmlTemplate.Must(htmlTemplate.New("html").
+			Funcs(digestFuncs).Parse(digestHTMLTemplate))
+)
+
+// digestData is the data the digest templates are rendered with.
+type digestData struct {
+	aggregates.Digest
+	Name           string
+	UnsubscribeURL string
+}
+
+// renderDigest renders the digest mail of a subscription.
+func renderDigest(digest aggregates.Digest,
+	unsubscribeURL string) (mailAggregates.Mail, error) {
+	data := digestData{
+		Digest:         digest,
+		Name:           digest.ProgramName,
+		UnsubscribeURL: unsubscribeURL,
+	}
+	if data.Name == "" {
+		data.Name = digest.ProgramAddress
+	}
+
+	var subject, text, body strings.Builder
+	if err := digestSubject.Execute(&subject, data); err != nil {
+		return mailAggregates.Mail{}, fmt.Errorf("digestSubject.Execute, err: %w", err)
+	}
+
+	if err := digestText.Execute(&text, data); err != nil {
+		return mailAggregates.Mail{}, fmt.Errorf("digestText.Execute, err: %w", err)
+	}
+
+	if err := digestHTML.Execute(&body, data); err != nil {
+		return mailAggregates.Mail{}, fmt.Errorf("digestHTML.Execute, err: %w", err)
+	}
+
+	return mailAggregates.Mail{
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
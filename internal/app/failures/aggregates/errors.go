@@ -0,0 +1,7 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrInvalidFilter = errors.New("invalid failures filter")
+)
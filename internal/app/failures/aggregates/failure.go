@@ -0,0 +1,96 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind is the kind of a failed RPC call.
+type Kind string
+
+const (
+	// KindHTTP is a call answered with an HTTP error status.
+	KindHTTP Kind = "http_error"
+	// KindRPC is a call answered with a JSON-RPC error object, e.g. a
+	// preflight simulation failure.
+	KindRPC Kind = "rpc_error"
+	// KindRateLimited is a call the RPC provider rate limited.
+	KindRateLimited Kind = "rate_limited"
+	// KindTimeout is a call the agent reported as timed out.
+	KindTimeout Kind = "timeout"
+	// KindNetwork is a call the agent reported as failed before getting a
+	// response, e.g. a CORS or DNS failure.
+	KindNetwork Kind = "network_error"
+)
+
+// Valid returns true when the kind is a known one.
+func (k Kind) Valid() bool {
+	switch k {
+	case KindHTTP, KindRPC, KindRateLimited, KindTimeout, KindNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	defaultFailuresLimit = 100
+	maxFailuresLimit     = 1000
+)
+
+// Failure represents a failed RPC call, searchable by endpoint and method.
+// RPCCode is the code of the JSON-RPC error object, Logs the program logs
+// of the failed simulations and RateLimit the rate limit headers of the
+// response, e.g. x-ratelimit-remaining or retry-after.
+type Failure struct {
+	ID         int64
+	ProjectID  int64
+	EventID    string
+	Endpoint   string
+	Origin     string
+	Method     string
+	Kind       Kind
+	Batch      bool
+	HTTPStatus int
+	RPCCode    *int64
+	Message    string
+	Logs       []string
+	RateLimit  map[string]string
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
+
+// Filter represents the filters to search the failures, Query matches the
+// failure messages.
+type Filter struct {
+	Endpoint string
+	Method   string
+	Kind     Kind
+	Query    string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+}
+
+// Validate validates the filter, setting the default limit when it's not
+// set.
+func (f *Filter) Validate() error {
+	if f.Kind != "" && !f.Kind.Valid() {
+		return fmt.Errorf("kind %q is not supported: %w", f.Kind, ErrInvalidFilter)
+	}
+
+	if f.Limit < 0 || f.Limit > maxFailuresLimit {
+		return fmt.Errorf("limit must be between 1 and %d: %w",
+			maxFailuresLimit, ErrInvalidFilter)
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultFailuresLimit
+	}
+
+	if f.Since != nil && f.Until != nil && !f.Until.After(*f.Since) {
+		return fmt.Errorf("until must be after since: %w", ErrInvalidFilter)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+)
+
+type failureGetterRepository interface {
+	SelectFailures(context.Context, aggregates.Filter) ([]aggregates.Failure, error)
+}
+
+// FailureGetter defines the methods needed to search the failed RPC calls.
+type FailureGetter struct {
+	failureGetterRepository failureGetterRepository
+}
+
+// NewFailureGetter initializes a new FailureGetter.
+func NewFailureGetter(
+	failureGetterRepository failureGetterRepository) *FailureGetter {
+	return &FailureGetter{
+		failureGetterRepository: failureGetterRepository,
+	}
+}
+
+// GetFailures gets the failed RPC calls matching the filter, newest first.
+func (fg *FailureGetter) GetFailures(ctx context.Context,
+	filter aggregates.Filter) ([]aggregates.Failure, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("filter.Validate, err: %w", err)
+	}
+
+	failures, err := fg.failureGetterRepository.SelectFailures(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"fg.failureGetterRepository.SelectFailures, err: %w", err)
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,12 @@
+package aggregates
+
+// Mail represents an email, either Text or HTML can be empty but not both.
+// Headers are extra headers such as List-Unsubscribe.
+type Mail struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
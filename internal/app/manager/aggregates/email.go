@@ -0,0 +1,118 @@
+package aggregates
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	// maxEmailLength is the maximum length of an address, RFC 5321 limits
+	// the forward paths to 256 characters including the angle brackets.
+	maxEmailLength = 254
+
+	// emailTokenLength is the number of random bytes of the waitlist tokens.
+	emailTokenLength = 24
+)
+
+// EmailStatus is the stage of a waitlist email lifecycle.
+type EmailStatus string
+
+const (
+	// EmailStatusPending emails haven't confirmed their address yet.
+	EmailStatusPending EmailStatus = "pending"
+
+	// EmailStatusConfirmed emails confirmed their address and can be invited.
+	EmailStatusConfirmed EmailStatus = "confirmed"
+
+	// EmailStatusInvited emails have been invited to the product.
+	EmailStatusInvited EmailStatus = "invited"
+
+	// EmailStatusUnsubscribed emails don't want to get any more emails.
+	EmailStatusUnsubscribed EmailStatus = "unsubscribed"
+)
+
+// Valid returns whether the status is one of the known statuses.
+func (s EmailStatus) Valid() bool {
+	switch s {
+	case EmailStatusPending, EmailStatusConfirmed,
+		EmailStatusInvited, EmailStatusUnsubscribed:
+		return true
+	}
+
+	return false
+}
+
+// Email represents a waitlist entry. The addresses are confirmed with a
+// double opt-in, only the hash of the confirmation token is kept while the
+// unsubscribe token, which only allows to unsubscribe, is kept to link it
+// from every email.
+type Email struct {
+	ID                    int64
+	Address               string
+	Name                  string
+	Status                EmailStatus
+	ConfirmationTokenHash string
+	ConfirmationSentAt    *time.Time
+	UnsubscribeToken      string
+	ConfirmedAt           *time.Time
+	InvitedAt             *time.Time
+	UnsubscribedAt        *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// ConfirmationExpired returns whether the confirmation token sent to the
+// email is older than the given TTL.
+func (e Email) ConfirmationExpired(now time.Time, ttl time.Duration) bool {
+	return e.ConfirmationSentAt == nil || now.Sub(*e.ConfirmationSentAt) > ttl
+}
+
+// NormalizeEmail validates an address and returns it in its canonical form,
+// trimmed and lowercased, so the same address is never stored twice.
+func NormalizeEmail(address string) (string, error) {
+	address = strings.ToLower(strings.TrimSpace(address))
+
+	if address == "" || len(address) > maxEmailLength {
+		return "", fmt.Errorf("email must have between 1 and %d characters: %w",
+			maxEmailLength, ErrInvalidEmail)
+	}
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return "", fmt.Errorf("email %q is not a valid address: %w",
+			address, ErrInvalidEmail)
+	}
+
+	_, domain, _ := strings.Cut(address, "@")
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") ||
+		strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", fmt.Errorf("email %q domain is not valid: %w",
+			address, ErrInvalidEmail)
+	}
+
+	return address, nil
+}
+
+// NewEmailToken generates a new random waitlist token, it returns the token,
+// that is only sent by email, and its hash to store.
+func NewEmailToken() (string, string, error) {
+	bytes := make([]byte, emailTokenLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("rand.Read, err: %w", err)
+	}
+
+	token := hex.EncodeToString(bytes)
+
+	return token, HashEmailToken(token), nil
+}
+
+// HashEmailToken returns the hex encoded SHA-256 of a waitlist token.
+func HashEmailToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
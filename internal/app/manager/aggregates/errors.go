@@ -3,5 +3,12 @@ package aggregates
 import "errors"
 
 var (
-	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrEmailAlreadyExists   = errors.New("email already exists")
+	ErrEmailNotFound        = errors.New("email not found")
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrInvalidEmailToken    = errors.New("invalid or expired email token")
+	ErrInvalidEmailStatus   = errors.New("invalid email status")
+	ErrProgramNotFound      = errors.New("program not found")
+	ErrProgramAlreadyExists = errors.New("program already exists")
+	ErrInvalidProgram       = errors.New("invalid program")
 )
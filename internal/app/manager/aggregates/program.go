@@ -1,7 +1,114 @@
 package aggregates
 
-// Program represents a Solana program.
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// publicKeyLength is the length in bytes of a Solana public key.
+const publicKeyLength = 32
+
+// Program represents a Solana program monitored by a project.
 type Program struct {
+	ProjectID      int64
 	ProgramAddress string
 	ProgramName    string
+	Description    string
+	Priority       int
+	Tags           []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time
+}
+
+// ValidateProgramAddress checks that the address is a base58 encoded 32
+// bytes public key.
+func ValidateProgramAddress(address string) error {
+	if len(base58.Decode(address)) != publicKeyLength {
+		return fmt.Errorf("program address %q is not a base58 public key: %w",
+			address, ErrInvalidProgram)
+	}
+
+	return nil
+}
+
+// Deleted returns true when the program has been soft deleted.
+func (p Program) Deleted() bool {
+	return p.DeletedAt != nil
+}
+
+// Validate checks that the program address is a base58 encoded 32 bytes
+// public key and that the program is named.
+func (p Program) Validate() error {
+	if err := ValidateProgramAddress(p.ProgramAddress); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(p.ProgramName) == "" {
+		return fmt.Errorf("program name can't be empty: %w", ErrInvalidProgram)
+	}
+
+	return nil
+}
+
+// Apply returns the program with the update applied.
+func (p Program) Apply(update ProgramUpdate) Program {
+	if update.ProgramName != nil {
+		p.ProgramName = strings.TrimSpace(*update.ProgramName)
+	}
+
+	if update.Description != nil {
+		p.Description = *update.Description
+	}
+
+	if update.Priority != nil {
+		p.Priority = *update.Priority
+	}
+
+	if update.Tags != nil {
+		p.Tags = NormalizeTags(*update.Tags)
+	}
+
+	return p
+}
+
+// ProgramUpdate represents a partial update of a program, nil fields are
+// left untouched.
+type ProgramUpdate struct {
+	ProgramName *string
+	Description *string
+	Priority    *int
+	Tags        *[]string
+}
+
+// ProgramsFilter represents the filters to list programs, Search matches the
+// beginning of the program name (case insensitive) or address.
+type ProgramsFilter struct {
+	Search string
+	Limit  int
+}
+
+// NormalizeTags trims the tags, dropping the empty and duplicated ones.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
 }
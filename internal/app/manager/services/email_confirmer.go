@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type emailConfirmerRepository interface {
+	SelectEmailByConfirmationTokenHash(context.Context, string) (aggregates.Email, error)
+	UpdateEmail(context.Context, aggregates.Email) (aggregates.Email, error)
+}
+
+// EmailConfirmer defines the methods needed to confirm waitlist emails.
+type EmailConfirmer struct {
+	emailConfirmerRepository emailConfirmerRepository
+	config                   WaitlistConfig
+}
+
+// NewEmailConfirmer initializes a new EmailConfirmer.
+func NewEmailConfirmer(
+	emailConfirmerRepository emailConfirmerRepository,
+	config WaitlistConfig,
+) *EmailConfirmer {
+	return &EmailConfirmer{
+		emailConfirmerRepository: emailConfirmerRepository,
+		config:                   config,
+	}
+}
+
+// Confirm confirms the waitlist email the token was sent to, the tokens can
+// only be used once and expire after the confirmation TTL.
+func (ec *EmailConfirmer) Confirm(
+	ctx context.Context, token string) (aggregates.Email, error) {
+	if token == "" {
+		return aggregates.Email{}, aggregates.ErrInvalidEmailToken
+	}
+
+	email, err := ec.emailConfirmerRepository.SelectEmailByConfirmationTokenHash(
+		ctx, aggregates.HashEmailToken(token))
+	if err != nil {
+		if errors.Is(err, aggregates.ErrEmailNotFound) {
+			return aggregates.Email{}, aggregates.ErrInvalidEmailToken
+		}
+
+		return aggregates.Email{}, fmt.Errorf(
+			"ec.emailConfirmerRepository.SelectEmailByConfirmationTokenHash, err: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if email.Status != aggregates.EmailStatusPending ||
+		email.ConfirmationExpired(now, ec.config.ConfirmationTTL) {
+		return aggregates.Email{}, aggregates.ErrInvalidEmailToken
+	}
+
+	email.Status = aggregates.EmailStatusConfirmed
+	email.ConfirmedAt = &now
+	email.ConfirmationTokenHash = ""
+
+	email, err = ec.emailConfirmerRepository.UpdateEmail(ctx, email)
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf(
+			"ec.emailConfirmerRepository.UpdateEmail, err: %w", err)
+	}
+
+	return email, nil
+}
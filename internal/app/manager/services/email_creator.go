@@ -2,35 +2,134 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
 )
 
+// maxEmailNameLength is the maximum length of the name of a waitlist email.
+const maxEmailNameLength = 200
+
 type emailCreatorRepository interface {
-	CheckEmailExists(context.Context, string) error
-	InsertEmail(context.Context, string) error
+	SelectEmailByAddress(context.Context, string) (aggregates.Email, error)
+	InsertEmail(context.Context, aggregates.Email) (aggregates.Email, error)
+	UpdateEmail(context.Context, aggregates.Email) (aggregates.Email, error)
 }
 
 // EmailCreator defines the methods needed to create emails.
 type EmailCreator struct {
-	emailCreatorRepository
+	emailCreatorRepository emailCreatorRepository
+	mailer                 mailer
+	config                 WaitlistConfig
 }
 
 // NewEmailCreator initializes a new EmailCreator.
 func NewEmailCreator(
-	emailCreatorRepository emailCreatorRepository) *EmailCreator {
+	emailCreatorRepository emailCreatorRepository,
+	mailer mailer,
+	config WaitlistConfig,
+) *EmailCreator {
 	return &EmailCreator{
 		emailCreatorRepository: emailCreatorRepository,
+		mailer:                 mailer,
+		config:                 config,
+	}
+}
+
+// Create adds an address to the waitlist and emails it a confirmation link.
+// Pending addresses get a new confirmation link, at most once per resend
+// interval, while the confirmed ones are rejected with
+// aggregates.ErrEmailAlreadyExists. Unsubscribed addresses are never emailed
+// again, their request succeeds without sending anything so it can't be
+// used to tell them apart or to mail them against their will.
+func (ec *EmailCreator) Create(ctx context.Context, address, name string) error {
+	address, err := aggregates.NormalizeEmail(address)
+	if err != nil {
+		return fmt.Errorf("aggregates.NormalizeEmail, err: %w", err)
+	}
+
+	if len(name) > maxEmailNameLength {
+		return fmt.Errorf("email name can't be longer than %d characters: %w",
+			maxEmailNameLength, aggregates.ErrInvalidEmail)
+	}
+
+	email, err := ec.emailCreatorRepository.SelectEmailByAddress(ctx, address)
+	switch {
+	case errors.Is(err, aggregates.ErrEmailNotFound):
+		return ec.insert(ctx, address, strings.TrimSpace(name))
+
+	case err != nil:
+		return fmt.Errorf(
+			"ec.emailCreatorRepository.SelectEmailByAddress, err: %w", err)
+	}
+
+	switch email.Status {
+	case aggregates.EmailStatusConfirmed, aggregates.EmailStatusInvited:
+		return aggregates.ErrEmailAlreadyExists
+
+	case aggregates.EmailStatusUnsubscribed:
+		return nil
+
+	case aggregates.EmailStatusPending:
+		if !email.ConfirmationExpired(time.Now(), ec.config.ResendInterval) {
+			return nil
+		}
+	}
+
+	if name = strings.TrimSpace(name); name != "" {
+		email.Name = name
 	}
+
+	email.Status = aggregates.EmailStatusPending
+
+	return ec.sendConfirmation(ctx, email, ec.emailCreatorRepository.UpdateEmail)
 }
 
-// Create creates a new email.
-func (pc *EmailCreator) Create(ctx context.Context, email string) error {
-	if err := pc.emailCreatorRepository.CheckEmailExists(ctx, email); err != nil {
-		return fmt.Errorf("pc.emailCreatorRepository.CheckEmailExists, err: %w", err)
+func (ec *EmailCreator) insert(ctx context.Context, address, name string) error {
+	unsubscribeToken, _, err := aggregates.NewEmailToken()
+	if err != nil {
+		return fmt.Errorf("aggregates.NewEmailToken, err: %w", err)
 	}
 
-	if err := pc.emailCreatorRepository.InsertEmail(ctx, email); err != nil {
-		return fmt.Errorf("pc.emailCreatorRepository.InsertEmail, err: %w", err)
+	return ec.sendConfirmation(ctx, aggregates.Email{
+		Address:          address,
+		Name:             name,
+		Status:           aggregates.EmailStatusPending,
+		UnsubscribeToken: unsubscribeToken,
+	}, ec.emailCreatorRepository.InsertEmail)
+}
+
+// sendConfirmation stores the email with a new confirmation token and sends
+// it, the email is stored first so the link works as soon as it's received.
+func (ec *EmailCreator) sendConfirmation(ctx context.Context, email aggregates.Email,
+	store func(context.Context, aggregates.Email) (aggregates.Email, error)) error {
+	token, tokenHash, err := aggregates.NewEmailToken()
+	if err != nil {
+		return fmt.Errorf("aggregates.NewEmailToken, err: %w", err)
+	}
+
+	now := time.Now().UTC()
+	email.ConfirmationTokenHash = tokenHash
+	email.ConfirmationSentAt = &now
+
+	email, err = store(ctx, email)
+	if err != nil {
+		return fmt.Errorf("store, err: %w", err)
+	}
+
+	if err := ec.mailer.Send(ctx, ec.config.confirmationMail(email, token)); err != nil {
+		// Allow the address to ask for a new confirmation right away.
+		email.ConfirmationSentAt = nil
+		if _, updateErr := ec.emailCreatorRepository.UpdateEmail(ctx, email); updateErr != nil {
+			slog.Error("error while resetting the email confirmation",
+				slog.Int64("email_id", email.ID), slog.Any("error", updateErr))
+		}
+
+		return fmt.Errorf("ec.mailer.Send, err: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type emailGetterRepository interface {
+	SelectEmails(context.Context, aggregates.EmailStatus) ([]aggregates.Email, error)
+}
+
+// EmailGetter defines the methods needed to get the waitlist emails.
+type EmailGetter struct {
+	emailGetterRepository emailGetterRepository
+}
+
+// NewEmailGetter initializes a new EmailGetter.
+func NewEmailGetter(emailGetterRepository emailGetterRepository) *EmailGetter {
+	return &EmailGetter{
+		emailGetterRepository: emailGetterRepository,
+	}
+}
+
+// GetEmails gets the waitlist emails with the given status, every email when
+// the status is empty.
+func (eg *EmailGetter) GetEmails(ctx context.Context,
+	status aggregates.EmailStatus) ([]aggregates.Email, error) {
+	if status != "" && !status.Valid() {
+		return nil, fmt.Errorf("unknown email status %q: %w",
+			status, aggregates.ErrInvalidEmail)
+	}
+
+	emails, err := eg.emailGetterRepository.SelectEmails(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("eg.emailGetterRepository.SelectEmails, err: %w", err)
+	}
+
+	return emails, nil
+}
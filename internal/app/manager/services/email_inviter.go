@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type emailInviterRepository interface {
+	SelectEmailByID(context.Context, int64) (aggregates.Email, error)
+	SelectEmails(context.Context, aggregates.EmailStatus) ([]aggregates.Email, error)
+	UpdateEmail(context.Context, aggregates.Email) (aggregates.Email, error)
+}
+
+// EmailInviter defines the methods needed to invite the confirmed waitlist
+// emails.
+type EmailInviter struct {
+	emailInviterRepository emailInviterRepository
+	mailer                 mailer
+	config                 WaitlistConfig
+}
+
+// NewEmailInviter initializes a new EmailInviter.
+func NewEmailInviter(
+	emailInviterRepository emailInviterRepository,
+	mailer mailer,
+	config WaitlistConfig,
+) *EmailInviter {
+	return &EmailInviter{
+		emailInviterRepository: emailInviterRepository,
+		mailer:                 mailer,
+		config:                 config,
+	}
+}
+
+// Invite invites a confirmed waitlist email, it returns
+// aggregates.ErrInvalidEmailStatus for the emails that aren't confirmed.
+func (ei *EmailInviter) Invite(
+	ctx context.Context, id int64) (aggregates.Email, error) {
+	email, err := ei.emailInviterRepository.SelectEmailByID(ctx, id)
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf(
+			"ei.emailInviterRepository.SelectEmailByID, err: %w", err)
+	}
+
+	if email.Status != aggregates.EmailStatusConfirmed {
+		return aggregates.Email{}, fmt.Errorf("email %d is %s: %w",
+			id, email.Status, aggregates.ErrInvalidEmailStatus)
+	}
+
+	email, err = ei.invite(ctx, email)
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf("ei.invite, err: %w", err)
+	}
+
+	return email, nil
+}
+
+// InviteNext invites up to limit confirmed waitlist emails, the ones that
+// confirmed first are invited first.
+func (ei *EmailInviter) InviteNext(
+	ctx context.Context, limit int) ([]aggregates.Email, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("invite limit must be positive: %w",
+			aggregates.ErrInvalidEmail)
+	}
+
+	emails, err := ei.emailInviterRepository.SelectEmails(
+		ctx, aggregates.EmailStatusConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("ei.emailInviterRepository.SelectEmails, err: %w", err)
+	}
+
+	sortByConfirmation(emails)
+	if len(emails) > limit {
+		emails = emails[:limit]
+	}
+
+	invited := make([]aggregates.Email, 0, len(emails))
+	for _, email := range emails {
+		email, err := ei.invite(ctx, email)
+		if err != nil {
+			return invited, fmt.Errorf("ei.invite, err: %w", err)
+		}
+
+		invited = append(invited, email)
+	}
+
+	return invited, nil
+}
+
+// invite sends the invitation and marks the email as invited once sent.
+func (ei *EmailInviter) invite(ctx context.Context,
+	email aggregates.Email) (aggregates.Email, error) {
+	if err := ei.mailer.Send(ctx, ei.config.inviteMail(email)); err != nil {
+		return aggregates.Email{}, fmt.Errorf("ei.mailer.Send, err: %w", err)
+	}
+
+	now := time.Now().UTC()
+	email.Status = aggregates.EmailStatusInvited
+	email.InvitedAt = &now
+
+	email, err := ei.emailInviterRepository.UpdateEmail(ctx, email)
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf(
+			"ei.emailInviterRepository.UpdateEmail, err: %w", err)
+	}
+
+	return email, nil
+}
+
+func sortByConfirmation(emails []aggregates.Email) {
+	sort.SliceStable(emails, func(i, j int) bool {
+		return confirmedAt(emails[i]).Before(confirmedAt(emails[j]))
+	})
+}
+
+func confirmedAt(email aggregates.Email) time.Time {
+	if email.ConfirmedAt == nil {
+		return email.CreatedAt
+	}
+
+	return *email.ConfirmedAt
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type emailUnsubscriberRepository interface {
+	SelectEmailByUnsubscribeToken(context.Context, string) (aggregates.Email, error)
+	UpdateEmail(context.Context, aggregates.Email) (aggregates.Email, error)
+}
+
+// EmailUnsubscriber defines the methods needed to unsubscribe waitlist
+// emails.
+type EmailUnsubscriber struct {
+	emailUnsubscriberRepository emailUnsubscriberRepository
+}
+
+// NewEmailUnsubscriber initializes a new EmailUnsubscriber.
+func NewEmailUnsubscriber(
+	emailUnsubscriberRepository emailUnsubscriberRepository) *EmailUnsubscriber {
+	return &EmailUnsubscriber{
+		emailUnsubscriberRepository: emailUnsubscriberRepository,
+	}
+}
+
+// Unsubscribe unsubscribes the waitlist email the token belongs to, it's
+// idempotent so the unsubscribe links keep working.
+func (eu *EmailUnsubscriber) Unsubscribe(ctx context.Context, token string) error {
+	if token == "" {
+		return aggregates.ErrInvalidEmailToken
+	}
+
+	email, err := eu.emailUnsubscriberRepository.SelectEmailByUnsubscribeToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, aggregates.ErrEmailNotFound) {
+			return aggregates.ErrInvalidEmailToken
+		}
+
+		return fmt.Errorf(
+			"eu.emailUnsubscriberRepository.SelectEmailByUnsubscribeToken, err: %w", err)
+	}
+
+	if email.Status == aggregates.EmailStatusUnsubscribed {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	email.Status = aggregates.EmailStatusUnsubscribed
+	email.UnsubscribedAt = &now
+	email.ConfirmationTokenHash = ""
+
+	if _, err := eu.emailUnsubscriberRepository.UpdateEmail(ctx, email); err != nil {
+		return fmt.Errorf("eu.emailUnsubscriberRepository.UpdateEmail, err: %w", err)
+	}
+
+	return nil
+}
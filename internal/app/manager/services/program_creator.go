@@ -2,13 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
 )
 
 type programCreatorRepository interface {
-	InsertProgram(context.Context, aggregates.Program) error
+	SelectProgramByAddress(context.Context, string) (aggregates.Program, error)
+	InsertProgram(context.Context, aggregates.Program) (aggregates.Program, error)
 }
 
 // ProgramCreator defines the methods needed to create programs.
@@ -24,12 +27,46 @@ func NewProgramCreator(
 	}
 }
 
-// Create creates a new program.
-func (pc *ProgramCreator) Create(ctx context.Context, program aggregates.Program) error {
-	program.ProgramName = program.ProgramAddress
-	if err := pc.programCreatorRepository.InsertProgram(ctx, program); err != nil {
-		return fmt.Errorf("pc.programCreatorRepository.InsertProgram, err: %w", err)
+// Create validates and creates a new program, programs without name are
+// named after their address. Creating a program that already exists, even
+// if it was deleted, returns aggregates.ErrProgramAlreadyExists, deleted
+// programs have to be restored instead.
+func (pc *ProgramCreator) Create(ctx context.Context,
+	program aggregates.Program) (aggregates.Program, error) {
+	program.ProgramAddress = strings.TrimSpace(program.ProgramAddress)
+	program.ProgramName = strings.TrimSpace(program.ProgramName)
+	if program.ProgramName == "" {
+		program.ProgramName = program.ProgramAddress
 	}
+	program.Tags = aggregates.NormalizeTags(program.Tags)
 
-	return nil
+	if err := program.Validate(); err != nil {
+		return aggregates.Program{}, fmt.Errorf("program.Validate, err: %w", err)
+	}
+
+	existing, err := pc.programCreatorRepository.SelectProgramByAddress(
+		ctx, program.ProgramAddress)
+	switch {
+	case err == nil:
+		if existing.Deleted() {
+			return aggregates.Program{}, fmt.Errorf(
+				"program %s was deleted, restore it instead: %w",
+				program.ProgramAddress, aggregates.ErrProgramAlreadyExists)
+		}
+
+		return aggregates.Program{}, fmt.Errorf("program %s: %w",
+			program.ProgramAddress, aggregates.ErrProgramAlreadyExists)
+
+	case !errors.Is(err, aggregates.ErrProgramNotFound):
+		return aggregates.Program{}, fmt.Errorf(
+			"pc.programCreatorRepository.SelectProgramByAddress, err: %w", err)
+	}
+
+	program, err = pc.programCreatorRepository.InsertProgram(ctx, program)
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf(
+			"pc.programCreatorRepository.InsertProgram, err: %w", err)
+	}
+
+	return program, nil
 }
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type programDeleterRepository interface {
+	DeleteProgram(context.Context, string) error
+	RestoreProgram(context.Context, string) error
+}
+
+// ProgramDeleter defines the methods needed to delete and restore programs.
+type ProgramDeleter struct {
+	programDeleterRepository programDeleterRepository
+}
+
+// NewProgramDeleter initializes a new ProgramDeleter.
+func NewProgramDeleter(
+	programDeleterRepository programDeleterRepository) *ProgramDeleter {
+	return &ProgramDeleter{
+		programDeleterRepository: programDeleterRepository,
+	}
+}
+
+// Delete soft deletes a program, its metrics are kept.
+func (pd *ProgramDeleter) Delete(ctx context.Context, address string) error {
+	if err := pd.programDeleterRepository.DeleteProgram(ctx, address); err != nil {
+		return fmt.Errorf("pd.programDeleterRepository.DeleteProgram, err: %w", err)
+	}
+
+	return nil
+}
+
+// Restore restores a soft deleted program.
+func (pd *ProgramDeleter) Restore(ctx context.Context, address string) error {
+	if err := pd.programDeleterRepository.RestoreProgram(ctx, address); err != nil {
+		return fmt.Errorf("pd.programDeleterRepository.RestoreProgram, err: %w", err)
+	}
+
+	return nil
+}
@@ -8,7 +8,8 @@ import (
 )
 
 type programGetterRepository interface {
-	SelectAllPrograms(context.Context) ([]aggregates.Program, error)
+	SelectPrograms(context.Context, aggregates.ProgramsFilter) ([]aggregates.Program, error)
+	SelectProgramByAddress(context.Context, string) (aggregates.Program, error)
 }
 
 // ProgramGetter defines the methods needed to get programs.
@@ -24,14 +25,30 @@ func NewProgramGetter(
 	}
 }
 
-// GetPrograms gets all programs.
-func (pg *ProgramGetter) GetPrograms(
-	ctx context.Context) ([]aggregates.Program, error) {
-	programs, err := pg.programGetterRepository.SelectAllPrograms(ctx)
+// GetPrograms gets the programs matching the filter.
+func (pg *ProgramGetter) GetPrograms(ctx context.Context,
+	filter aggregates.ProgramsFilter) ([]aggregates.Program, error) {
+	programs, err := pg.programGetterRepository.SelectPrograms(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"pg.programGetterRepository.SelectAllPrograms, err: %w", err)
+			"pg.programGetterRepository.SelectPrograms, err: %w", err)
 	}
 
 	return programs, nil
 }
+
+// GetProgram gets a program by its address, deleted programs are not found.
+func (pg *ProgramGetter) GetProgram(
+	ctx context.Context, address string) (aggregates.Program, error) {
+	program, err := pg.programGetterRepository.SelectProgramByAddress(ctx, address)
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf(
+			"pg.programGetterRepository.SelectProgramByAddress, err: %w", err)
+	}
+
+	if program.Deleted() {
+		return aggregates.Program{}, aggregates.ErrProgramNotFound
+	}
+
+	return program, nil
+}
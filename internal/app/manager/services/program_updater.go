@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+type programUpdaterRepository interface {
+	SelectProgramByAddress(context.Context, string) (aggregates.Program, error)
+	UpdateProgram(context.Context, aggregates.Program) (aggregates.Program, error)
+}
+
+// ProgramUpdater defines the methods needed to update programs.
+type ProgramUpdater struct {
+	programUpdaterRepository programUpdaterRepository
+}
+
+// NewProgramUpdater initializes a new ProgramUpdater.
+func NewProgramUpdater(
+	programUpdaterRepository programUpdaterRepository) *ProgramUpdater {
+	return &ProgramUpdater{
+		programUpdaterRepository: programUpdaterRepository,
+	}
+}
+
+// Update applies the partial update to the program.
+func (pu *ProgramUpdater) Update(ctx context.Context, address string,
+	update aggregates.ProgramUpdate) (aggregates.Program, error) {
+	program, err := pu.programUpdaterRepository.SelectProgramByAddress(ctx, address)
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf(
+			"pu.programUpdaterRepository.SelectProgramByAddress, err: %w", err)
+	}
+
+	if program.Deleted() {
+		return aggregates.Program{}, aggregates.ErrProgramNotFound
+	}
+
+	program = program.Apply(update)
+	if err := program.Validate(); err != nil {
+		return aggregates.Program{}, fmt.Errorf("program.Validate, err: %w", err)
+	}
+
+	program, err = pu.programUpdaterRepository.UpdateProgram(ctx, program)
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf(
+			"pu.programUpdaterRepository.UpdateProgram, err: %w", err)
+	}
+
+	return program, nil
+}
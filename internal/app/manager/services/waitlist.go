@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	mailAggregates "github.com/jcleira/encinitas-collector-go/internal/app/mail/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// mailer sends the waitlist emails, e.g. through SMTP.
+type mailer interface {
+	Send(context.Context, mailAggregates.Mail) error
+}
+
+// WaitlistConfig defines the links of the waitlist emails and how long the
+// confirmations are valid for. PublicURL is the URL the collector is reached
+// at, InviteURL the page the invited emails are sent to and ResendInterval
+// the minimum time between two confirmation emails to the same address.
+type WaitlistConfig struct {
+	PublicURL       string
+	InviteURL       string
+	ConfirmationTTL time.Duration
+	ResendInterval  time.Duration
+}
+
+// confirmationMail returns the mail asking to confirm a waitlist address.
+func (wc WaitlistConfig) confirmationMail(
+	email aggregates.Email, token string) mailAggregates.Mail {
+	confirmURL := wc.link("/manager/emails/confirm", token)
+
+	return wc.mail(email,
+		"Confirm your email for the Encinitas waitlist",
+		"Please confirm your email to join the Encinitas waitlist.",
+		"Confirm email", confirmURL)
+}
+
+// inviteMail returns the mail inviting a confirmed waitlist email.
+func (wc WaitlistConfig) inviteMail(email aggregates.Email) mailAggregates.Mail {
+	return wc.mail(email,
+		"You're invited to Encinitas",
+		"Your spot is ready, thanks for waiting!",
+		"Get started", wc.InviteURL)
+}
+
+// mail builds a waitlist mail with a call to action, a footer to unsubscribe
+// and the List-Unsubscribe headers for one click unsubscribes.
+func (wc WaitlistConfig) mail(email aggregates.Email,
+	subject, message, action, actionURL string) mailAggregates.Mail {
+	unsubscribeURL := wc.link("/manager/emails/unsubscribe", email.UnsubscribeToken)
+
+	greeting := "Hi,"
+	if email.Name != "" {
+		greeting = fmt.Sprintf("Hi %s,", email.Name)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n\n%s\n\n%s: %s\n\n", greeting, message, action, actionURL)
+	fmt.Fprintf(&text, "To stop receiving these emails: %s\n", unsubscribeURL)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "<p>%s</p>\n<p>%s</p>\n", html.EscapeString(greeting),
+		html.EscapeString(message))
+	fmt.Fprintf(&body, "<p><a href=\"%s\">%s</a></p>\n", html.EscapeString(actionURL),
+		html.EscapeString(action))
+	fmt.Fprintf(&body, "<p style=\"font-size:12px\"><a href=\"%s\">Unsubscribe</a></p>\n",
+		html.EscapeString(unsubscribeURL))
+
+	return mailAggregates.Mail{
+		To:      []string{email.Address},
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+}
+
+func (wc WaitlistConfig) link(path, token string) string {
+	return strings.TrimSuffix(wc.PublicURL, "/") + path +
+		"?token=" + url.QueryEscape(token)
+}
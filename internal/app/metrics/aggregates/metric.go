@@ -5,18 +5,22 @@ import "time"
 // TransactionMetric represents a metric event which aggregates information coming from
 // both the Solana blockchain and agents (browser/mobile).
 type TransactionMetric struct {
-	EventID    string
-	Signature  string
-	UpdatedOn  time.Time
-	SolanaTime int64
-	Error      bool
+	ProjectID        int64
+	EventID          string
+	Signature        string
+	UpdatedOn        time.Time
+	SolanaTime       int64
+	Error            bool
+	ProgramAddresses []string
 }
 
 // ProgramMetric represents a metric event which aggregates information for each instruction within the Solana Transaction.
 type ProgramMetric struct {
+	ProjectID      int64
 	ProgramAddress string
 	UpdatedOn      time.Time
 	SolanaTime     int64
+	Error          bool
 }
 
 // Type represents the type of metric.
@@ -73,3 +77,45 @@ type ErrorResult struct {
 
 // ErrorResults represents a slice of ErrorResult.
 type ErrorResults []ErrorResult
+
+// WindowStats represents the aggregated metrics of a time window, either for
+// every transaction or for a single program.
+type WindowStats struct {
+	Start      time.Time
+	End        time.Time
+	Count      int64
+	Errors     int64
+	LatencyP50 float64
+	LatencyP95 float64
+	LatencyP99 float64
+	Apdex      float64
+}
+
+// ErrorRate returns the ratio of failed transactions within the window.
+func (ws WindowStats) ErrorRate() float64 {
+	if ws.Count == 0 {
+		return 0
+	}
+
+	return float64(ws.Errors) / float64(ws.Count)
+}
+
+// Throughput returns the amount of transactions per minute within the window.
+func (ws WindowStats) Throughput() float64 {
+	minutes := ws.End.Sub(ws.Start).Minutes()
+	if minutes <= 0 {
+		return 0
+	}
+
+	return float64(ws.Count) / minutes
+}
+
+// WindowCounts represents the transaction counts of a time window, Slow is
+// the amount of transactions slower than the requested latency threshold.
+type WindowCounts struct {
+	Start  time.Time
+	End    time.Time
+	Total  int64
+	Errors int64
+	Slow   int64
+}
@@ -0,0 +1,101 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// Outcome is how a transaction sent with sendTransaction ended up once the
+// validity window of its recent blockhash passed.
+type Outcome string
+
+const (
+	// OutcomeLanded is a transaction that landed on chain.
+	OutcomeLanded Outcome = "landed"
+	// OutcomeDropped is a transaction sent with a valid blockhash that
+	// never landed, it was dropped by the RPC provider or the leaders.
+	OutcomeDropped Outcome = "dropped"
+	// OutcomeExpired is a transaction whose blockhash had already expired
+	// when it was sent, or was never seen on chain.
+	OutcomeExpired Outcome = "expired"
+)
+
+// OutcomeMetric represents the outcome of a transaction sent with
+// sendTransaction for one of the programs it invokes. Endpoint, Origin and
+// Region are the ones of the sendTransaction call and Time when the outcome
+// was known.
+type OutcomeMetric struct {
+	ProjectID      int64
+	ProgramAddress string
+	Endpoint       string
+	Origin         string
+	Region         string
+	Outcome        Outcome
+	Time           time.Time
+}
+
+// OutcomeBreakdown is the dimension the drop rates are broken down by.
+type OutcomeBreakdown string
+
+const (
+	OutcomeBreakdownProgram  OutcomeBreakdown = "program"
+	OutcomeBreakdownEndpoint OutcomeBreakdown = "endpoint"
+	OutcomeBreakdownOrigin   OutcomeBreakdown = "origin"
+)
+
+// DropRateFilter represents the filter of the drop rates, the empty
+// ProgramAddress and Endpoint match every program and RPC provider.
+type DropRateFilter struct {
+	Start          time.Time
+	End            time.Time
+	Breakdown      OutcomeBreakdown
+	ProgramAddress string
+	Endpoint       string
+}
+
+// Validate validates the drop rates filter.
+func (f DropRateFilter) Validate() error {
+	switch f.Breakdown {
+	case OutcomeBreakdownProgram, OutcomeBreakdownEndpoint, OutcomeBreakdownOrigin:
+	default:
+		return fmt.Errorf("breakdown must be program, endpoint or origin: %w",
+			ErrInvalidRPCQuery)
+	}
+
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidRPCQuery)
+	}
+
+	if f.End.Sub(f.Start) > maxRPCQueryRange {
+		return fmt.Errorf("the range can't be longer than %s: %w",
+			maxRPCQueryRange, ErrInvalidRPCQuery)
+	}
+
+	return nil
+}
+
+// DropRate represents the outcomes of the transactions sent for a program,
+// through an RPC provider or from an origin between Start and End.
+//
+// The outcomes are counted once per program a transaction invokes, so the
+// counts by RPC provider and origin weight every transaction by its
+// programs.
+type DropRate struct {
+	Breakdown string
+	Start     time.Time
+	End       time.Time
+	Landed    int64
+	Dropped   int64
+	Expired   int64
+}
+
+// Total returns the amount of transactions with a known outcome.
+func (dr DropRate) Total() int64 {
+	return dr.Landed + dr.Dropped + dr.Expired
+}
+
+// DropRate returns the ratio of transactions that never landed, either
+// dropped or expired.
+func (dr DropRate) DropRate() float64 {
+	return ratio(dr.Dropped+dr.Expired, dr.Total())
+}
@@ -0,0 +1,127 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProviderBreakdown is the client dimension the provider benchmarks are
+// broken down by, none when it's empty.
+type ProviderBreakdown string
+
+const (
+	ProviderBreakdownNone   ProviderBreakdown = ""
+	ProviderBreakdownRegion ProviderBreakdown = "region"
+	ProviderBreakdownOrigin ProviderBreakdown = "origin"
+)
+
+// LandingMetric represents a transaction sent with sendTransaction that
+// landed on chain. Endpoint, Origin and Region are the ones of the
+// sendTransaction call it landed through, TimeToLand is the time between
+// the call and the transaction landing and BlockhashStaleness the age of
+// the recent blockhash of the transaction when it was sent. Submissions is
+// the amount of calls that sent the transaction and LandingSubmission which
+// of them, from 1, it landed through.
+type LandingMetric struct {
+	ProjectID          int64
+	Endpoint           string
+	Origin             string
+	Region             string
+	TimeToLand         time.Duration
+	BlockhashStaleness time.Duration
+	Submissions        int
+	LandingSubmission  int
+	Time               time.Time
+}
+
+// ProviderBenchmarkFilter represents the filter of the provider benchmarks,
+// the empty Endpoint matches every RPC provider.
+type ProviderBenchmarkFilter struct {
+	Start     time.Time
+	End       time.Time
+	Breakdown ProviderBreakdown
+	Endpoint  string
+}
+
+// Validate validates the provider benchmarks filter.
+func (f ProviderBenchmarkFilter) Validate() error {
+	switch f.Breakdown {
+	case ProviderBreakdownNone, ProviderBreakdownRegion, ProviderBreakdownOrigin:
+	default:
+		return fmt.Errorf("breakdown must be region or origin: %w",
+			ErrInvalidRPCQuery)
+	}
+
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidRPCQuery)
+	}
+
+	if f.End.Sub(f.Start) > maxRPCQueryRange {
+		return fmt.Errorf("the range can't be longer than %s: %w",
+			maxRPCQueryRange, ErrInvalidRPCQuery)
+	}
+
+	return nil
+}
+
+// ProviderBenchmark represents how an RPC provider performed between Start
+// and End, for the clients of a region or origin when the benchmarks are
+// broken down by any.
+//
+// Calls, Errors and RateLimited count the RPC calls, Sent the successful
+// sendTransaction calls and Landed the transactions they sent that landed
+// on chain. ResendsPerLanded is the average amount of times the landed
+// transactions were resent and LandingSubmission the average submission
+// they landed through, from 1. The latencies and times are in milliseconds.
+type ProviderBenchmark struct {
+	Endpoint              string
+	Breakdown             string
+	Start                 time.Time
+	End                   time.Time
+	Calls                 int64
+	Errors                int64
+	RateLimited           int64
+	Methods               []MethodLatency
+	Sent                  int64
+	Landed                int64
+	TimeToLandP50         float64
+	TimeToLandP95         float64
+	BlockhashStalenessP50 float64
+	BlockhashStalenessP95 float64
+	ResendsPerLanded      float64
+	LandingSubmission     float64
+}
+
+// MethodLatency represents the latency percentiles of a JSON-RPC method of
+// a provider, in milliseconds.
+type MethodLatency struct {
+	Method     string
+	Count      int64
+	LatencyP50 float64
+	LatencyP95 float64
+	LatencyP99 float64
+}
+
+// ErrorRate returns the ratio of failed calls.
+func (pb ProviderBenchmark) ErrorRate() float64 {
+	return ratio(pb.Errors, pb.Calls)
+}
+
+// RateLimitRate returns the ratio of rate limited calls.
+func (pb ProviderBenchmark) RateLimitRate() float64 {
+	return ratio(pb.RateLimited, pb.Calls)
+}
+
+// LandingRate returns the ratio of sent transactions that landed, capped
+// at 1 as both counts are written separately and either may be lost.
+func (pb ProviderBenchmark) LandingRate() float64 {
+	return min(ratio(pb.Landed, pb.Sent), 1)
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part) / float64(total)
+}
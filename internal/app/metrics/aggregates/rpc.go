@@ -0,0 +1,114 @@
+package aggregates
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RPCGroupBy is the tag the RPC call stats are grouped by.
+type RPCGroupBy string
+
+const (
+	RPCGroupByMethod   RPCGroupBy = "method"
+	RPCGroupByEndpoint RPCGroupBy = "endpoint"
+	RPCGroupByOrigin   RPCGroupBy = "origin"
+)
+
+// ErrInvalidRPCQuery is returned when the RPC stats are queried with an
+// invalid filter.
+var ErrInvalidRPCQuery = errors.New("invalid rpc query")
+
+// maxRPCQueryRange is the longest period the RPC stats can be queried for.
+const maxRPCQueryRange = 31 * 24 * time.Hour
+
+// RPCCallMetric represents a JSON-RPC call made by a dApp, as captured by the
+// agent. Endpoint is the host of the RPC node, Origin the origin of the page
+// that made the call and Region the region of the client, Batch is set for
+// the calls sent within a JSON-RPC batch, which share the latency of the
+// batch, and FailureKind is the kind of failure of the failed calls, e.g.
+// rate_limited. Latency is the time between the request and its response
+// and ResponseSize the size of the response body in bytes.
+//
+// RateLimitRemaining is the amount of requests the RPC node said were left
+// of its rate limit, nil when it didn't send it.
+type RPCCallMetric struct {
+	ProjectID          int64
+	Method             string
+	Endpoint           string
+	Origin             string
+	Region             string
+	Batch              bool
+	Error              bool
+	FailureKind        string
+	Latency            time.Duration
+	ResponseSize       int64
+	RateLimitRemaining *int64
+	Time               time.Time
+}
+
+// RPCStatsFilter represents the filter of the RPC call stats, the empty
+// Method, Endpoint and Origin match every call.
+type RPCStatsFilter struct {
+	Start    time.Time
+	End      time.Time
+	GroupBy  RPCGroupBy
+	Method   string
+	Endpoint string
+	Origin   string
+}
+
+// Validate validates the RPC stats filter.
+func (f RPCStatsFilter) Validate() error {
+	switch f.GroupBy {
+	case RPCGroupByMethod, RPCGroupByEndpoint, RPCGroupByOrigin:
+	default:
+		return fmt.Errorf("group by must be method, endpoint or origin: %w",
+			ErrInvalidRPCQuery)
+	}
+
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidRPCQuery)
+	}
+
+	if f.End.Sub(f.Start) > maxRPCQueryRange {
+		return fmt.Errorf("the range can't be longer than %s: %w",
+			maxRPCQueryRange, ErrInvalidRPCQuery)
+	}
+
+	return nil
+}
+
+// RPCStats represents the aggregated RPC calls of a group, e.g. of a method,
+// between Start and End. The latencies are in milliseconds and the
+// ResponseSize is the mean response size in bytes.
+type RPCStats struct {
+	Group        string
+	Start        time.Time
+	End          time.Time
+	Count        int64
+	Errors       int64
+	LatencyP50   float64
+	LatencyP95   float64
+	LatencyP99   float64
+	ResponseSize float64
+}
+
+// ErrorRate returns the ratio of failed calls.
+func (rs RPCStats) ErrorRate() float64 {
+	if rs.Count == 0 {
+		return 0
+	}
+
+	return float64(rs.Errors) / float64(rs.Count)
+}
+
+// Rate returns the amount of calls per minute.
+func (rs RPCStats) Rate() float64 {
+	minutes := rs.End.Sub(rs.Start).Minutes()
+	if minutes <= 0 {
+		return 0
+	}
+
+	return float64(rs.Count) / minutes
+}
@@ -0,0 +1,90 @@
+package aggregates
+
+// StreamEventType represents the type of event sent through the live stream.
+type StreamEventType string
+
+const (
+	// StreamEventTransaction represents an ingested transaction.
+	StreamEventTransaction StreamEventType = "transaction"
+	// StreamEventProgram represents a per-program metric point.
+	StreamEventProgram StreamEventType = "program"
+)
+
+// StreamEvent represents an event published to the live stream subscribers,
+// only one of Transaction or Program is set depending on the Type.
+type StreamEvent struct {
+	Type        StreamEventType
+	Transaction *TransactionMetric
+	Program     *ProgramMetric
+}
+
+// StreamFilter represents the filters a stream subscriber can set to only
+// receive the events it's interested in, ProjectID is set from the tenant of
+// the subscriber so it only receives the events of its project.
+type StreamFilter struct {
+	ProjectID        int64
+	ProgramAddresses []string
+	Error            *bool
+	MinSolanaTime    int64
+}
+
+// Matches checks if the given event passes the filter.
+func (sf StreamFilter) Matches(event StreamEvent) bool {
+	switch event.Type {
+	case StreamEventTransaction:
+		if event.Transaction == nil {
+			return false
+		}
+
+		if sf.ProjectID != 0 && sf.ProjectID != event.Transaction.ProjectID {
+			return false
+		}
+
+		if sf.Error != nil && *sf.Error != event.Transaction.Error {
+			return false
+		}
+
+		if event.Transaction.SolanaTime < sf.MinSolanaTime {
+			return false
+		}
+
+		return sf.matchesAnyProgram(event.Transaction.ProgramAddresses...)
+
+	case StreamEventProgram:
+		if event.Program == nil {
+			return false
+		}
+
+		if sf.ProjectID != 0 && sf.ProjectID != event.Program.ProjectID {
+			return false
+		}
+
+		if sf.Error != nil && *sf.Error != event.Program.Error {
+			return false
+		}
+
+		if event.Program.SolanaTime < sf.MinSolanaTime {
+			return false
+		}
+
+		return sf.matchesAnyProgram(event.Program.ProgramAddress)
+	}
+
+	return false
+}
+
+func (sf StreamFilter) matchesAnyProgram(programAddresses ...string) bool {
+	if len(sf.ProgramAddresses) == 0 {
+		return true
+	}
+
+	for _, filterAddress := range sf.ProgramAddresses {
+		for _, programAddress := range programAddresses {
+			if filterAddress == programAddress {
+				return true
+			}
+		}
+	}
+
+	return false
+}
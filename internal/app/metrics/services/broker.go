@@ -0,0 +1,97 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+
+	aggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// brokerSubscriber represents a single live stream client, it has its own
+// buffered channel so a slow client doesn't block the ingester.
+type brokerSubscriber struct {
+	filter aggregates.StreamFilter
+	events chan aggregates.StreamEvent
+}
+
+// Broker is a fan-out broker that delivers the ingested metrics to every
+// live stream subscriber.
+//
+// Every subscriber gets a buffered channel, whenever a subscriber's buffer is
+// full we consider it a slow consumer and we disconnect it by closing its
+// channel, so the ingester is never blocked by a client.
+type Broker struct {
+	bufferSize int
+
+	mu          sync.RWMutex
+	subscribers map[*brokerSubscriber]struct{}
+}
+
+// NewBroker creates a new Broker, bufferSize sets the amount of events each
+// subscriber can have pending before being disconnected.
+func NewBroker(bufferSize int) *Broker {
+	return &Broker{
+		bufferSize:  bufferSize,
+		subscribers: make(map[*brokerSubscriber]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber with the given filter, it returns the
+// channel where the events will be delivered and a function to unsubscribe.
+//
+// The events channel is closed on unsubscribe or whenever the subscriber is
+// disconnected for being a slow consumer.
+func (b *Broker) Subscribe(
+	filter aggregates.StreamFilter) (<-chan aggregates.StreamEvent, func()) {
+	subscriber := &brokerSubscriber{
+		filter: filter,
+		events: make(chan aggregates.StreamEvent, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	return subscriber.events, func() {
+		b.remove(subscriber)
+	}
+}
+
+// Publish delivers the event to every subscriber whose filter matches it.
+func (b *Broker) Publish(event aggregates.StreamEvent) {
+	slowSubscribers := make([]*brokerSubscriber, 0)
+
+	b.mu.RLock()
+	for subscriber := range b.subscribers {
+		if !subscriber.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case subscriber.events <- event:
+		default:
+			slowSubscribers = append(slowSubscribers, subscriber)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, subscriber := range slowSubscribers {
+		slog.Warn("disconnecting slow stream subscriber",
+			slog.Int("buffer_size", b.bufferSize))
+		b.remove(subscriber)
+	}
+}
+
+// remove unregisters the subscriber and closes its channel, it's safe to call
+// it several times for the same subscriber.
+func (b *Broker) remove(subscriber *brokerSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[subscriber]; !ok {
+		return
+	}
+
+	delete(b.subscribers, subscriber)
+	close(subscriber.events)
+}
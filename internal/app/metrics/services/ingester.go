@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"strings"
@@ -22,17 +24,24 @@ type solanaRedisRepository interface {
 }
 
 type agentRedisRepository interface {
-	GetEvent(context.Context, string) (agentAggregates.Event, error)
+	GetSubmissions(context.Context, string) (agentAggregates.Submissions, error)
+	JoinEvent(context.Context, string, time.Time) error
 }
 
 type influxTelegrafRepository interface {
 	WriteTransaction(context.Context, aggregates.TransactionMetric) error
 	WriteProgram(context.Context, aggregates.ProgramMetric) error
+	WriteLanding(context.Context, aggregates.LandingMetric) error
 }
 
 type solanaSQLRepository interface {
 	InsertTransactionDetail(context.Context, solanaAggregates.TransactionDetail) error
 	GetBlockTimeByBlockHash(context.Context, string) (time.Time, error)
+	UpsertLifecycle(context.Context, solanaAggregates.Lifecycle) error
+}
+
+type streamPublisher interface {
+	Publish(aggregates.StreamEvent)
 }
 
 // Ingester is a service that ingests information coming from both the Solana
@@ -42,6 +51,8 @@ type Ingester struct {
 	agentRedisRepository     agentRedisRepository
 	influxTelegrafRepository influxTelegrafRepository
 	solanaSQLRepository      solanaSQLRepository
+	streamPublisher          streamPublisher
+	programOwners            *programOwners
 }
 
 // NewIngester creates a new instance of the Ingester service.
@@ -50,12 +61,16 @@ func NewIngester(
 	agentRedisRepository agentRedisRepository,
 	influxTelegrafRepository influxTelegrafRepository,
 	solanaSQLRepository solanaSQLRepository,
+	streamPublisher streamPublisher,
+	programsRepository programsRepository,
 ) *Ingester {
 	return &Ingester{
 		solanaRedisRepository:    solanaRedisRepository,
 		agentRedisRepository:     agentRedisRepository,
 		influxTelegrafRepository: influxTelegrafRepository,
 		solanaSQLRepository:      solanaSQLRepository,
+		streamPublisher:          streamPublisher,
+		programOwners:            newProgramOwners(programsRepository),
 	}
 }
 
@@ -69,24 +84,23 @@ func (i *Ingester) Ingest(ctx context.Context) {
 			return
 
 		case transaction := <-transactions:
-			if rand.Intn(100) < 5 {
+			bytes, err := hex.DecodeString(transaction.Signature[2:])
+			if err != nil {
+				slog.Error("error while decoding transaction signature", slog.Any("error", err))
 				continue
 			}
+			signature := base58.Encode(bytes)
 
-			bytes, err := hex.DecodeString(transaction.Signature[2:])
+			failed, err := transaction.Failed()
 			if err != nil {
-				slog.Error("error while decoding transaction signature", err)
+				slog.Error("error while reading transaction status meta", slog.Any("error", err))
 				continue
 			}
 
 			metric := aggregates.TransactionMetric{
 				UpdatedOn: transaction.UpdatedOn,
 				Signature: transaction.Signature,
-				// TODO: We are setting the error rate to keep some randomness around
-				// 0.18% for now,but we should be using the error rate from the
-				// transactions, but the selected transactions for the demo do not
-				// fail frequently.
-				Error: rand.Float64() < 0.018,
+				Error:     failed,
 			}
 
 			metric.EventID = transaction.Signature
@@ -97,7 +111,7 @@ func (i *Ingester) Ingest(ctx context.Context) {
 
 			if json.Unmarshal(
 				[]byte(transaction.LegacyMessage), &transactionLegacyMessage); err != nil {
-				slog.Error("error while unmarshalling transaction legacy message", err)
+				slog.Error("error while unmarshalling transaction legacy message", slog.Any("error", err))
 				continue
 			}
 
@@ -109,14 +123,14 @@ func (i *Ingester) Ingest(ctx context.Context) {
 			bytes, err = hex.DecodeString(
 				transactionLegacyMessage.RecentBlockhash[2:])
 			if err != nil {
-				slog.Error("error while decoding program account", err)
+				slog.Error("error while decoding program account", slog.Any("error", err))
 				continue
 			}
 
 			blockTime, err := i.solanaSQLRepository.GetBlockTimeByBlockHash(
 				ctx, base58.Encode(bytes))
 			if err != nil {
-				slog.Error("error while getting block time by block hash", err)
+				slog.Error("error while getting block time by block hash", slog.Any("error", err))
 				continue
 			}
 
@@ -124,13 +138,7 @@ func (i *Ingester) Ingest(ctx context.Context) {
 
 			if err := json.Unmarshal(
 				[]byte(transaction.LegacyMessage), &transactionLegacyMessage); err != nil {
-				slog.Error("error while unmarshalling transaction legacy message", err)
-				continue
-			}
-
-			if err := i.influxTelegrafRepository.WriteTransaction(
-				ctx, metric); err != nil {
-				slog.Error("error while writing transaction metric", err)
+				slog.Error("error while unmarshalling transaction legacy message", slog.Any("error", err))
 				continue
 			}
 
@@ -141,7 +149,7 @@ func (i *Ingester) Ingest(ctx context.Context) {
 
 			transactionData := solanaAggregates.TransactionData{}
 			if err := json.Unmarshal([]byte(transaction.LegacyMessage), &transactionData); err != nil {
-				slog.Error("error while unmarshalling transaction data", err)
+				slog.Error("error while unmarshalling transaction data", slog.Any("error", err))
 				continue
 			}
 
@@ -149,35 +157,147 @@ func (i *Ingester) Ingest(ctx context.Context) {
 				programAccountKey := transactionData.AccountKeys[instruction.ProgramIDIndex]
 				bytes, err := hex.DecodeString(programAccountKey[2:])
 				if err != nil {
-					slog.Error("error while decoding program account", err)
+					slog.Error("error while decoding program account", slog.Any("error", err))
 					continue
 				}
 
-				transactionDetail := solanaAggregates.TransactionDetail{
-					ProgramAddress: base58.Encode(bytes),
-					UpdatedOn:      transaction.UpdatedOn,
-					SolanaTime:     metric.SolanaTime,
-				}
+				metric.ProgramAddresses = append(
+					metric.ProgramAddresses, base58.Encode(bytes))
+			}
+
+			i.recordLifecycles(ctx, signature, transaction.Slot,
+				metric.ProgramAddresses, transaction.UpdatedOn, blockTime)
+
+			// The lifecycles and landings are recorded for every transaction so
+			// the landing rates are accurate, only the metrics below are
+			// sampled.
+			if rand.Intn(100) < 5 {
+				continue
+			}
 
-				if err := i.solanaSQLRepository.InsertTransactionDetail(
-					ctx, transactionDetail); err != nil {
-					slog.Error("error while inserting transaction detail", err)
+			// The transaction metrics are written once for every project
+			// monitoring any of its programs, and the program metrics once
+			// for every project monitoring the program.
+			for _, projectID := range i.programOwners.anyProjects(
+				ctx, metric.ProgramAddresses) {
+				projectMetric := metric
+				projectMetric.ProjectID = projectID
+
+				if err := i.influxTelegrafRepository.WriteTransaction(
+					ctx, projectMetric); err != nil {
+					slog.Error("error while writing transaction metric", slog.Any("error", err))
 					continue
 				}
 
-				if err := i.influxTelegrafRepository.WriteProgram(ctx,
-					aggregates.ProgramMetric{
-						ProgramAddress: base58.Encode(bytes),
+				i.streamPublisher.Publish(aggregates.StreamEvent{
+					Type:        aggregates.StreamEventTransaction,
+					Transaction: &projectMetric,
+				})
+			}
+
+			for _, programAddress := range metric.ProgramAddresses {
+				for _, projectID := range i.programOwners.projects(ctx, programAddress) {
+					transactionDetail := solanaAggregates.TransactionDetail{
+						ProjectID:      projectID,
+						ProgramAddress: programAddress,
+						UpdatedOn:      transaction.UpdatedOn,
 						SolanaTime:     metric.SolanaTime,
-					}); err != nil {
-					slog.Error("error while writing program metric", err)
-					continue
+					}
+
+					if err := i.solanaSQLRepository.InsertTransactionDetail(
+						ctx, transactionDetail); err != nil {
+						slog.Error("error while inserting transaction detail", slog.Any("error", err))
+						continue
+					}
+
+					programMetric := aggregates.ProgramMetric{
+						ProjectID:      projectID,
+						ProgramAddress: programAddress,
+						UpdatedOn:      transaction.UpdatedOn,
+						SolanaTime:     metric.SolanaTime,
+						Error:          metric.Error,
+					}
+
+					if err := i.influxTelegrafRepository.WriteProgram(
+						ctx, programMetric); err != nil {
+						slog.Error("error while writing program metric", slog.Any("error", err))
+						continue
+					}
+
+					i.streamPublisher.Publish(aggregates.StreamEvent{
+						Type:    aggregates.StreamEventProgram,
+						Program: &programMetric,
+					})
 				}
 			}
 
 		case err := <-errors:
-			slog.Error("error while ingesting a transaction", err)
+			slog.Error("error while ingesting a transaction", slog.Any("error", err))
+
+		}
+	}
+}
+
+// recordLifecycles records the lifecycle of a transaction for every project
+// monitoring any of its programs and, when it was sent through an agent, its
+// landing, matching it with the sendTransaction calls stored for the
+// project. Transactions sent outside the agents have no call to match.
+//
+// A transaction resent several times was submitted with the first call, and
+// landed through the last one sent before it landed.
+func (i *Ingester) recordLifecycles(ctx context.Context, signature string,
+	slot int64, programAddresses []string, landedAt, blockTime time.Time) {
+	for _, projectID := range i.programOwners.anyProjects(ctx, programAddresses) {
+		lifecycle := solanaAggregates.Lifecycle{
+			ProjectID:        projectID,
+			Signature:        signature,
+			Slot:             slot,
+			ProgramAddresses: programAddresses,
+			ProcessedAt:      &landedAt,
+		}
+
+		key := fmt.Sprintf("%d.sendTransaction.%s", projectID, signature)
+
+		submissions, err := i.agentRedisRepository.GetSubmissions(ctx, key)
+		if err != nil && !errors.Is(err, agentAggregates.ErrEventNotFound) {
+			slog.Error("error while getting sendTransaction submissions", slog.Any("error", err))
+		}
+
+		if err == nil {
+			lifecycle.SubmittedAt = &submissions.First().Request.RequestTime
+		}
+
+		if err := i.solanaSQLRepository.UpsertLifecycle(ctx, lifecycle); err != nil {
+			slog.Error("error while upserting transaction lifecycle", slog.Any("error", err))
+		}
+
+		if lifecycle.SubmittedAt == nil {
+			continue
+		}
+
+		landing := submissions.Landing(landedAt)
+		event := submissions.Events[landing]
+
+		landingMetric := aggregates.LandingMetric{
+			ProjectID:          projectID,
+			Endpoint:           event.Request.Endpoint(),
+			Origin:             event.Request.Origin(),
+			Region:             event.Region,
+			TimeToLand:         max(landedAt.Sub(event.Request.RequestTime), 0),
+			BlockhashStaleness: max(event.Request.RequestTime.Sub(blockTime), 0),
+			Submissions:        submissions.Count(),
+			LandingSubmission:  landing + 1,
+			Time:               event.Request.RequestTime,
+		}
+
+		if err := i.influxTelegrafRepository.WriteLanding(ctx, landingMetric); err != nil {
+			slog.Error("error while writing landing metric", slog.Any("error", err))
+		}
 
+		// The joined events are kept for a shorter while than the pending
+		// ones.
+		if err := i.agentRedisRepository.JoinEvent(ctx, key, landedAt); err != nil {
+			slog.Error("error while joining sendTransaction event", slog.Any("error", err))
 		}
 	}
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// programOwnersRefreshInterval is how often the projects monitoring every
+// program are reloaded, a new program takes up to this long to be ingested
+// for its project.
+const programOwnersRefreshInterval = time.Minute
+
+type programsRepository interface {
+	SelectAllPrograms(context.Context) ([]managerAggregates.Program, error)
+}
+
+// programOwners keeps the projects monitoring every program, the ingested
+// metrics are written once per project so every tenant has its own series.
+//
+// It's not safe for concurrent use, the ingester uses it from its loop.
+type programOwners struct {
+	programsRepository programsRepository
+	owners             map[string][]int64
+	refreshedAt        time.Time
+}
+
+func newProgramOwners(programsRepository programsRepository) *programOwners {
+	return &programOwners{
+		programsRepository: programsRepository,
+		owners:             make(map[string][]int64),
+	}
+}
+
+// projects returns the projects monitoring the program, the default project
+// when no project monitors it.
+func (po *programOwners) projects(
+	ctx context.Context, programAddress string) []int64 {
+	po.refresh(ctx)
+
+	if projects, ok := po.owners[programAddress]; ok {
+		return projects
+	}
+
+	return []int64{tenantsAggregates.DefaultProjectID}
+}
+
+// anyProjects returns the projects monitoring any of the programs, in the
+// order they are first found.
+func (po *programOwners) anyProjects(
+	ctx context.Context, programAddresses []string) []int64 {
+	projects := make([]int64, 0, 1)
+	seen := make(map[int64]struct{})
+
+	for _, programAddress := range programAddresses {
+		for _, projectID := range po.projects(ctx, programAddress) {
+			if _, ok := seen[projectID]; ok {
+				continue
+			}
+
+			seen[projectID] = struct{}{}
+			projects = append(projects, projectID)
+		}
+	}
+
+	if len(projects) == 0 {
+		projects = append(projects, tenantsAggregates.DefaultProjectID)
+	}
+
+	return projects
+}
+
+// refresh reloads the owners once the refresh interval has elapsed, the
+// previous owners are kept when they can't be reloaded.
+func (po *programOwners) refresh(ctx context.Context) {
+	if time.Since(po.refreshedAt) < programOwnersRefreshInterval {
+		return
+	}
+	po.refreshedAt = time.Now()
+
+	if err := po.load(ctx); err != nil {
+		slog.Error("error while loading the programs owners", slog.Any("error", err))
+	}
+}
+
+func (po *programOwners) load(ctx context.Context) error {
+	programs, err := po.programsRepository.SelectAllPrograms(
+		tenantsAggregates.WithAllProjects(ctx))
+	if err != nil {
+		return fmt.Errorf("po.programsRepository.SelectAllPrograms: %w", err)
+	}
+
+	owners := make(map[string][]int64, len(programs))
+	for _, program := range programs {
+		owners[program.ProgramAddress] = append(
+			owners[program.ProgramAddress], program.ProjectID)
+	}
+
+	po.owners = owners
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+
+	agentAggregates "github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	aggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	sessionsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+	solanaAggregates "github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// blockhashValidity is the amount of blocks a transaction can land
+	// after the block of its recent blockhash.
+	blockhashValidity = 150
+
+	// blockhashValidityTime is the shortest time a blockhash is valid for,
+	// the events younger than it can't be reconciled yet.
+	blockhashValidityTime = 60 * time.Second
+
+	// reconcileBatchSize is the amount of events reconciled at once.
+	reconcileBatchSize = 500
+)
+
+type reconcilerAgentRepository interface {
+	SelectUnreconciledEvents(context.Context, time.Time, int64) ([]string, error)
+	GetEvent(context.Context, string) (agentAggregates.Event, error)
+	MarkEventReconciled(context.Context, string) error
+}
+
+type reconcilerSolanaRepository interface {
+	SelectLandedSignatures(context.Context, []string) (map[string]bool, error)
+	GetBlockHeightByBlockHash(context.Context, string) (int64, error)
+	GetBlockHeight(context.Context, time.Time) (int64, error)
+}
+
+type reconcilerMetricsRepository interface {
+	WriteOutcome(context.Context, aggregates.OutcomeMetric) error
+}
+
+type reconcilerAttemptsRepository interface {
+	UpdateAttemptOutcome(context.Context, int64, string, sessionsAggregates.Outcome) error
+}
+
+// ReconcilerConfig holds the reconciler settings.
+type ReconcilerConfig struct {
+	// Interval is how often the sent transactions are reconciled.
+	Interval time.Duration
+	// ExpiryTimeout is how long a transaction whose blockhash isn't on
+	// chain is waited for before it's considered expired.
+	ExpiryTimeout time.Duration
+}
+
+// Reconciler is a service that reconciles the transactions sent through
+// the agents with the transactions that landed on chain, once the validity
+// window of their blockhash passed, writing whether they landed, were
+// dropped or expired and recording it as the outcome of their attempts.
+type Reconciler struct {
+	agentRepository    reconcilerAgentRepository
+	solanaRepository   reconcilerSolanaRepository
+	metricsRepository  reconcilerMetricsRepository
+	attemptsRepository reconcilerAttemptsRepository
+	config             ReconcilerConfig
+}
+
+// NewReconciler creates a new instance of the Reconciler service.
+func NewReconciler(
+	agentRepository reconcilerAgentRepository,
+	solanaRepository reconcilerSolanaRepository,
+	metricsRepository reconcilerMetricsRepository,
+	attemptsRepository reconcilerAttemptsRepository,
+	config ReconcilerConfig,
+) *Reconciler {
+	return &Reconciler{
+		agentRepository:    agentRepository,
+		solanaRepository:   solanaRepository,
+		metricsRepository:  metricsRepository,
+		attemptsRepository: attemptsRepository,
+		config:             config,
+	}
+}
+
+// Reconcile starts reconciling the sent transactions every interval.
+func (r *Reconciler) Reconcile(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := r.reconcile(ctx, time.Now().UTC()); err != nil {
+				slog.Error("error while reconciling sent transactions", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, now time.Time) error {
+	// The sent transactions of every project are reconciled, each one is
+	// written to the project of its event.
+	ctx = tenantsAggregates.WithAllProjects(ctx)
+
+	keys, err := r.agentRepository.SelectUnreconciledEvents(
+		ctx, now.Add(-blockhashValidityTime), reconcileBatchSize)
+	if err != nil {
+		return fmt.Errorf("r.agentRepository.SelectUnreconciledEvents: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	events := make(map[string]agentAggregates.Event, len(keys))
+	signatures := make([]string, 0, len(keys))
+	for _, key := range keys {
+		event, err := r.agentRepository.GetEvent(ctx, key)
+		if err != nil && !errors.Is(err, agentAggregates.ErrEventNotFound) {
+			return fmt.Errorf("r.agentRepository.GetEvent: %w", err)
+		}
+
+		// The events expired or without a decoded transaction can't be
+		// reconciled.
+		if err != nil || event.Request == nil || event.Transaction == nil {
+			if err := r.agentRepository.MarkEventReconciled(ctx, key); err != nil {
+				return fmt.Errorf("r.agentRepository.MarkEventReconciled: %w", err)
+			}
+
+			continue
+		}
+
+		events[key] = event
+		signatures = append(signatures, event.Transaction.Signature)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	landed, err := r.solanaRepository.SelectLandedSignatures(ctx, signatures)
+	if err != nil {
+		return fmt.Errorf("r.solanaRepository.SelectLandedSignatures: %w", err)
+	}
+
+	height, err := r.solanaRepository.GetBlockHeight(ctx, now)
+	if err != nil {
+		return fmt.Errorf("r.solanaRepository.GetBlockHeight: %w", err)
+	}
+
+	for key, event := range events {
+		outcome := aggregates.OutcomeLanded
+		if !landed[event.Transaction.Signature] {
+			var ok bool
+			outcome, ok, err = r.unlandedOutcome(ctx, event, height, now)
+			if err != nil {
+				slog.Error("error while reconciling sent transaction",
+					slog.String("signature", event.Transaction.Signature),
+					slog.Any("error", err))
+				continue
+			}
+
+			// The transaction may still land.
+			if !ok {
+				continue
+			}
+		}
+
+		programAddresses := make([]string, 0, len(event.ProgramIDs))
+		for _, programID := range event.ProgramIDs {
+			if programID != solana.ComputeBudget.String() {
+				programAddresses = append(programAddresses, programID)
+			}
+		}
+
+		if len(programAddresses) == 0 {
+			programAddresses = append(programAddresses, "")
+		}
+
+		for _, programAddress := range programAddresses {
+			if err := r.metricsRepository.WriteOutcome(ctx, aggregates.OutcomeMetric{
+				ProjectID:      event.ProjectID,
+				ProgramAddress: programAddress,
+				Endpoint:       event.Request.Endpoint(),
+				Origin:         event.Request.Origin(),
+				Region:         event.Region,
+				Outcome:        outcome,
+				Time:           now,
+			}); err != nil {
+				slog.Error("error while writing outcome metric", slog.Any("error", err))
+			}
+		}
+
+		if err := r.attemptsRepository.UpdateAttemptOutcome(ctx, event.ProjectID,
+			event.Transaction.Signature, sessionsAggregates.Outcome(outcome)); err != nil {
+			slog.Error("error while updating attempt outcome", slog.Any("error", err))
+		}
+
+		if err := r.agentRepository.MarkEventReconciled(ctx, key); err != nil {
+			return fmt.Errorf("r.agentRepository.MarkEventReconciled: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// unlandedOutcome returns whether a transaction that didn't land was
+// dropped or expired, or false while its blockhash is still valid.
+//
+// A transaction sent with a blockhash past its validity window, or one that
+// never made it on chain, expired. One sent with a valid blockhash that
+// didn't land within the window was dropped.
+func (r *Reconciler) unlandedOutcome(ctx context.Context,
+	event agentAggregates.Event, height int64, now time.Time) (aggregates.Outcome, bool, error) {
+	blockhashHeight, err := r.solanaRepository.GetBlockHeightByBlockHash(
+		ctx, event.Transaction.RecentBlockhash)
+	if err != nil {
+		if errors.Is(err, solanaAggregates.ErrBlockNotFound) {
+			if now.Sub(event.Request.RequestTime) < r.config.ExpiryTimeout {
+				return "", false, nil
+			}
+
+			return aggregates.OutcomeExpired, true, nil
+		}
+
+		return "", false, fmt.Errorf("r.solanaRepository.GetBlockHeightByBlockHash: %w", err)
+	}
+
+	if height <= blockhashHeight+blockhashValidity {
+		return "", false, nil
+	}
+
+	sentHeight, err := r.solanaRepository.GetBlockHeight(ctx, event.Request.RequestTime)
+	if err != nil && !errors.Is(err, solanaAggregates.ErrBlockNotFound) {
+		return "", false, fmt.Errorf("r.solanaRepository.GetBlockHeight: %w", err)
+	}
+
+	if err == nil && sentHeight > blockhashHeight+blockhashValidity {
+		return aggregates.OutcomeExpired, true, nil
+	}
+
+	return aggregates.OutcomeDropped, true, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	agentAggregates "github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	aggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	solanaAggregates "github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+// blockHeights is a chain of the given block heights by blockhash and by
+// time, the missing ones aren't found.
+type blockHeights struct {
+	byBlockHash map[string]int64
+	byTime      map[time.Time]int64
+	err         error
+}
+
+func (bh *blockHeights) SelectLandedSignatures(
+	context.Context, []string) (map[string]bool, error) {
+	return nil, nil
+}
+
+func (bh *blockHeights) GetBlockHeightByBlockHash(
+	_ context.Context, blockHash string) (int64, error) {
+	if bh.err != nil {
+		return 0, bh.err
+	}
+
+	height, ok := bh.byBlockHash[blockHash]
+	if !ok {
+		return 0, solanaAggregates.ErrBlockNotFound
+	}
+
+	return height, nil
+}
+
+func (bh *blockHeights) GetBlockHeight(_ context.Context, at time.Time) (int64, error) {
+	height, ok := bh.byTime[at]
+	if !ok {
+		return 0, solanaAggregates.ErrBlockNotFound
+	}
+
+	return height, nil
+}
+
+func TestReconcilerUnlandedOutcome(t *testing.T) {
+	const blockhashHeight = 1000
+
+	sentAt := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	expiryTimeout := 5 * time.Minute
+
+	tests := []struct {
+		name       string
+		blockHash  string
+		sentHeight int64
+		height     int64
+		now        time.Time
+		err        error
+		outcome    aggregates.Outcome
+		ok         bool
+	}{
+		{
+			name:       "blockhash still valid",
+			blockHash:  "known",
+			sentHeight: blockhashHeight + 10,
+			height:     blockhashHeight + 100,
+			now:        sentAt.Add(time.Minute),
+		},
+		{
+			name:       "last valid block height",
+			blockHash:  "known",
+			sentHeight: blockhashHeight + 10,
+			height:     blockhashHeight + blockhashValidity,
+			now:        sentAt.Add(time.Minute),
+		},
+		{
+			name:       "first block height past the window",
+			blockHash:  "known",
+			sentHeight: blockhashHeight + 10,
+			height:     blockhashHeight + blockhashValidity + 1,
+			now:        sentAt.Add(time.Minute),
+			outcome:    aggregates.OutcomeDropped,
+			ok:         true,
+		},
+		{
+			name:       "sent at the last valid block height",
+			blockHash:  "known",
+			sentHeight: blockhashHeight + blockhashValidity,
+			height:     blockhashHeight + blockhashValidity + 1,
+			now:        sentAt.Add(time.Minute),
+			outcome:    aggregates.OutcomeDropped,
+			ok:         true,
+		},
+		{
+			name:       "sent past the window",
+			blockHash:  "known",
+			sentHeight: blockhashHeight + blockhashValidity + 1,
+			height:     blockhashHeight + blockhashValidity + 1,
+			now:        sentAt.Add(time.Minute),
+			outcome:    aggregates.OutcomeExpired,
+			ok:         true,
+		},
+		{
+			name:      "sent at an unknown block height",
+			blockHash: "known",
+			height:    blockhashHeight + blockhashValidity + 1,
+			now:       sentAt.Add(time.Minute),
+			outcome:   aggregates.OutcomeDropped,
+			ok:        true,
+		},
+		{
+			name:      "unknown blockhash within the expiry timeout",
+			blockHash: "unknown",
+			height:    blockhashHeight + blockhashValidity + 1,
+			now:       sentAt.Add(expiryTimeout - time.Second),
+		},
+		{
+			name:      "unknown blockhash at the expiry timeout",
+			blockHash: "unknown",
+			height:    blockhashHeight + blockhashValidity + 1,
+			now:       sentAt.Add(expiryTimeout),
+			outcome:   aggregates.OutcomeExpired,
+			ok:        true,
+		},
+		{
+			name:      "blocks error",
+			blockHash: "known",
+			now:       sentAt.Add(time.Minute),
+			err:       errors.New("postgres is down"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := &blockHeights{
+				byBlockHash: map[string]int64{"known": blockhashHeight},
+				byTime:      map[time.Time]int64{},
+				err:         test.err,
+			}
+			if test.sentHeight != 0 {
+				chain.byTime[sentAt] = test.sentHeight
+			}
+
+			reconciler := NewReconciler(nil, chain, nil, nil,
+				ReconcilerConfig{ExpiryTimeout: expiryTimeout})
+
+			outcome, ok, err := reconciler.unlandedOutcome(context.Background(),
+				agentAggregates.Event{
+					Request: &agentAggregates.Request{RequestTime: sentAt},
+					Transaction: &agentAggregates.SentTransaction{
+						RecentBlockhash: test.blockHash,
+					},
+				}, test.height, test.now)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+
+			if ok != test.ok {
+				t.Errorf("ok = %t, want %t", ok, test.ok)
+			}
+
+			if outcome != test.outcome {
+				t.Errorf("outcome = %q, want %q", outcome, test.outcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChannelType represents the kind of destination of a notification channel.
+type ChannelType string
+
+const (
+	ChannelTypeSlack     ChannelType = "slack"
+	ChannelTypeDiscord   ChannelType = "discord"
+	ChannelTypeTelegram  ChannelType = "telegram"
+	ChannelTypePagerDuty ChannelType = "pagerduty"
+	ChannelTypeSMTP      ChannelType = "smtp"
+	ChannelTypeWebhook   ChannelType = "webhook"
+)
+
+// RequiredKeys returns the configuration keys every channel of the type must
+// define.
+func (ct ChannelType) RequiredKeys() []string {
+	switch ct {
+	case ChannelTypeSlack, ChannelTypeDiscord:
+		return []string{"webhook_url"}
+	case ChannelTypeTelegram:
+		return []string{"bot_token", "chat_id"}
+	case ChannelTypePagerDuty:
+		return []string{"routing_key"}
+	case ChannelTypeSMTP:
+		return []string{"host", "port", "from", "to"}
+	case ChannelTypeWebhook:
+		return []string{"url"}
+	}
+
+	return nil
+}
+
+// SecretKeys returns the configuration keys holding credentials, which must
+// never be returned by the API.
+func (ct ChannelType) SecretKeys() []string {
+	switch ct {
+	case ChannelTypeSlack, ChannelTypeDiscord:
+		return []string{"webhook_url"}
+	case ChannelTypeTelegram:
+		return []string{"bot_token"}
+	case ChannelTypePagerDuty:
+		return []string{"routing_key"}
+	case ChannelTypeSMTP:
+		return []string{"password"}
+	case ChannelTypeWebhook:
+		return []string{"secret"}
+	}
+
+	return nil
+}
+
+func (ct ChannelType) valid() bool {
+	return ct.RequiredKeys() != nil
+}
+
+// Channel represents a notification channel, its Config holds the type
+// specific settings and credentials, and its Template optionally overrides
+// the default text of the rendered notifications.
+type Channel struct {
+	ID        int64
+	ProjectID int64
+	Name      string
+	Type      ChannelType
+	Config    map[string]string
+	Template  string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks that the channel is well formed.
+func (c Channel) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required: %w", ErrInvalidChannel)
+	}
+
+	if !c.Type.valid() {
+		return fmt.Errorf("unknown channel type %q: %w", c.Type, ErrInvalidChannel)
+	}
+
+	for _, key := range c.Type.RequiredKeys() {
+		if c.Config[key] == "" {
+			return fmt.Errorf("config %q is required for %s channels: %w",
+				key, c.Type, ErrInvalidChannel)
+		}
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the channel without its credentials.
+func (c Channel) Redacted() Channel {
+	config := make(map[string]string, len(c.Config))
+	for key, value := range c.Config {
+		config[key] = value
+	}
+
+	for _, key := range c.Type.SecretKeys() {
+		if config[key] != "" {
+			config[key] = redactedValue
+		}
+	}
+
+	c.Config = config
+
+	return c
+}
+
+// KeepSecrets copies the credentials of the existing channel whenever the
+// channel still holds the redacted placeholder, so clients can update a
+// channel with the payload they got from the API.
+func (c Channel) KeepSecrets(existing Channel) Channel {
+	for _, key := range c.Type.SecretKeys() {
+		if c.Config[key] == redactedValue {
+			c.Config[key] = existing.Config[key]
+		}
+	}
+
+	return c
+}
+
+const redactedValue = "********"
@@ -0,0 +1,29 @@
+package aggregates
+
+import "time"
+
+// DeliveryStatus represents the status of a notification in the outbox.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending is a notification waiting to be sent or retried.
+	DeliveryStatusPending DeliveryStatus = "pending"
+	// DeliveryStatusSent is a notification successfully sent.
+	DeliveryStatusSent DeliveryStatus = "sent"
+	// DeliveryStatusFailed is a notification that failed every attempt.
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery represents a notification to be sent through a channel, stored in
+// the outbox table until it's sent or it runs out of attempts.
+type Delivery struct {
+	ID            int64
+	ChannelID     int64
+	Message       Message
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
@@ -0,0 +1,9 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrChannelNotFound = errors.New("notification channel not found")
+	ErrInvalidChannel  = errors.New("invalid notification channel")
+	ErrDeliveryFailed  = errors.New("notification delivery failed")
+)
@@ -0,0 +1,45 @@
+package aggregates
+
+import "time"
+
+// Severity represents the severity of a notification.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// State represents whether the notified condition started or ended.
+type State string
+
+const (
+	StateTriggered State = "triggered"
+	StateResolved  State = "resolved"
+)
+
+// Field represents a name/value detail of a notification.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Message represents a notification, it's rendered by every channel type
+// into its own payload. DedupKey identifies the notified condition, so a
+// resolved message can be matched to its triggered one (e.g. PagerDuty).
+type Message struct {
+	Title     string
+	Text      string
+	Severity  Severity
+	State     State
+	DedupKey  string
+	Source    string
+	Fields    []Field
+	CreatedAt time.Time
+}
+
+// Resolved returns true when the message notifies the end of a condition.
+func (m Message) Resolved() bool {
+	return m.State == StateResolved
+}
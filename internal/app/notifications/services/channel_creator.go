@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+type channelCreatorRepository interface {
+	InsertChannel(context.Context, aggregates.Channel) (aggregates.Channel, error)
+}
+
+// ChannelCreator defines the methods needed to create notification channels.
+type ChannelCreator struct {
+	channelCreatorRepository channelCreatorRepository
+}
+
+// NewChannelCreator initializes a new ChannelCreator.
+func NewChannelCreator(
+	channelCreatorRepository channelCreatorRepository) *ChannelCreator {
+	return &ChannelCreator{
+		channelCreatorRepository: channelCreatorRepository,
+	}
+}
+
+// Create validates and creates a new notification channel.
+func (cc *ChannelCreator) Create(ctx context.Context,
+	channel aggregates.Channel) (aggregates.Channel, error) {
+	if err := channel.Validate(); err != nil {
+		return aggregates.Channel{}, fmt.Errorf("channel.Validate, err: %w", err)
+	}
+
+	channel, err := cc.channelCreatorRepository.InsertChannel(ctx, channel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf(
+			"cc.channelCreatorRepository.InsertChannel, err: %w", err)
+	}
+
+	return channel, nil
+}
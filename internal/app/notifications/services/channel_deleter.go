@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type channelDeleterRepository interface {
+	DeleteChannel(context.Context, int64) error
+}
+
+// ChannelDeleter defines the methods needed to delete notification channels.
+type ChannelDeleter struct {
+	channelDeleterRepository channelDeleterRepository
+}
+
+// NewChannelDeleter initializes a new ChannelDeleter.
+func NewChannelDeleter(
+	channelDeleterRepository channelDeleterRepository) *ChannelDeleter {
+	return &ChannelDeleter{
+		channelDeleterRepository: channelDeleterRepository,
+	}
+}
+
+// Delete deletes a notification channel.
+func (cd *ChannelDeleter) Delete(ctx context.Context, id int64) error {
+	if err := cd.channelDeleterRepository.DeleteChannel(ctx, id); err != nil {
+		return fmt.Errorf(
+			"cd.channelDeleterRepository.DeleteChannel, err: %w", err)
+	}
+
+	return nil
+}
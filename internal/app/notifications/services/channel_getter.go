@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+type channelGetterRepository interface {
+	SelectAllChannels(context.Context) ([]aggregates.Channel, error)
+	SelectChannelByID(context.Context, int64) (aggregates.Channel, error)
+}
+
+// ChannelGetter defines the methods needed to get notification channels.
+type ChannelGetter struct {
+	channelGetterRepository channelGetterRepository
+}
+
+// NewChannelGetter initializes a new ChannelGetter.
+func NewChannelGetter(
+	channelGetterRepository channelGetterRepository) *ChannelGetter {
+	return &ChannelGetter{
+		channelGetterRepository: channelGetterRepository,
+	}
+}
+
+// GetChannels gets all notification channels.
+func (cg *ChannelGetter) GetChannels(
+	ctx context.Context) ([]aggregates.Channel, error) {
+	channels, err := cg.channelGetterRepository.SelectAllChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cg.channelGetterRepository.SelectAllChannels, err: %w", err)
+	}
+
+	return channels, nil
+}
+
+// GetChannel gets a notification channel by its ID.
+func (cg *ChannelGetter) GetChannel(
+	ctx context.Context, id int64) (aggregates.Channel, error) {
+	channel, err := cg.channelGetterRepository.SelectChannelByID(ctx, id)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf(
+			"cg.channelGetterRepository.SelectChannelByID, err: %w", err)
+	}
+
+	return channel, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+type channelTesterRepository interface {
+	SelectChannelByID(context.Context, int64) (aggregates.Channel, error)
+}
+
+type channelSender interface {
+	Send(context.Context, aggregates.Channel, aggregates.Message) error
+}
+
+// ChannelTester defines the methods needed to send test notifications.
+type ChannelTester struct {
+	channelTesterRepository channelTesterRepository
+	channelSender           channelSender
+}
+
+// NewChannelTester initializes a new ChannelTester.
+func NewChannelTester(
+	channelTesterRepository channelTesterRepository,
+	channelSender channelSender) *ChannelTester {
+	return &ChannelTester{
+		channelTesterRepository: channelTesterRepository,
+		channelSender:           channelSender,
+	}
+}
+
+// Test sends a test notification through the channel right away, skipping
+// the outbox, so the caller gets the delivery error if any.
+func (ct *ChannelTester) Test(ctx context.Context, id int64) error {
+	channel, err := ct.channelTesterRepository.SelectChannelByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf(
+			"ct.channelTesterRepository.SelectChannelByID, err: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	message := aggregates.Message{
+		Title:    "Encinitas test notification",
+		Text:     fmt.Sprintf("This is a test notification for the %q channel.", channel.Name),
+		Severity: aggregates.SeverityInfo,
+		State:    aggregates.StateTriggered,
+		DedupKey: fmt.Sprintf("encinitas-test-%d-%d", channel.ID, now.Unix()),
+		Source:   "encinitas",
+		Fields: []aggregates.Field{
+			{Name: "Channel", Value: channel.Name},
+			{Name: "Type", Value: string(channel.Type)},
+		},
+		CreatedAt: now,
+	}
+
+	if err := ct.channelSender.Send(ctx, channel, message); err != nil {
+		return fmt.Errorf("ct.channelSender.Send, err: %v: %w",
+			err, aggregates.ErrDeliveryFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+type channelUpdaterRepository interface {
+	SelectChannelByID(context.Context, int64) (aggregates.Channel, error)
+	UpdateChannel(context.Context, aggregates.Channel) (aggregates.Channel, error)
+}
+
+// ChannelUpdater defines the methods needed to update notification channels.
+type ChannelUpdater struct {
+	channelUpdaterRepository channelUpdaterRepository
+}
+
+// NewChannelUpdater initializes a new ChannelUpdater.
+func NewChannelUpdater(
+	channelUpdaterRepository channelUpdaterRepository) *ChannelUpdater {
+	return &ChannelUpdater{
+		channelUpdaterRepository: channelUpdaterRepository,
+	}
+}
+
+// Update validates and updates an existing notification channel, redacted
+// credentials are kept from the stored channel.
+func (cu *ChannelUpdater) Update(ctx context.Context,
+	channel aggregates.Channel) (aggregates.Channel, error) {
+	existing, err := cu.channelUpdaterRepository.SelectChannelByID(ctx, channel.ID)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf(
+			"cu.channelUpdaterRepository.SelectChannelByID, err: %w", err)
+	}
+
+	channel = channel.KeepSecrets(existing)
+
+	if err := channel.Validate(); err != nil {
+		return aggregates.Channel{}, fmt.Errorf("channel.Validate, err: %w", err)
+	}
+
+	channel, err = cu.channelUpdaterRepository.UpdateChannel(ctx, channel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf(
+			"cu.channelUpdaterRepository.UpdateChannel, err: %w", err)
+	}
+
+	return channel, nil
+}
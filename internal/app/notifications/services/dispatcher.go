@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	dispatchBatchSize = 100
+
+	// dispatchLease is the time a claimed delivery is hidden from other
+	// dispatchers, a crashed dispatcher's deliveries are retried after it.
+	dispatchLease = 5 * time.Minute
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+type dispatcherRepository interface {
+	ClaimDueDeliveries(
+		context.Context, time.Time, time.Duration, int) ([]aggregates.Delivery, error)
+	SelectChannelByID(context.Context, int64) (aggregates.Channel, error)
+	MarkDeliverySent(context.Context, int64, time.Time) error
+	MarkDeliveryFailed(context.Context, aggregates.Delivery) error
+}
+
+// Dispatcher is a service that sends the notifications queued in the outbox,
+// retrying the failed ones with an exponential backoff.
+type Dispatcher struct {
+	dispatcherRepository dispatcherRepository
+	channelSender        channelSender
+	interval             time.Duration
+	maxAttempts          int
+}
+
+// NewDispatcher creates a new instance of the Dispatcher service.
+func NewDispatcher(
+	dispatcherRepository dispatcherRepository,
+	channelSender channelSender,
+	interval time.Duration,
+	maxAttempts int,
+) *Dispatcher {
+	return &Dispatcher{
+		dispatcherRepository: dispatcherRepository,
+		channelSender:        channelSender,
+		interval:             interval,
+		maxAttempts:          maxAttempts,
+	}
+}
+
+// Dispatch starts sending the due notifications every interval.
+func (d *Dispatcher) Dispatch(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := d.dispatch(ctx); err != nil {
+				slog.Error("error while dispatching notifications", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	deliveries, err := d.dispatcherRepository.ClaimDueDeliveries(
+		ctx, time.Now().UTC(), dispatchLease, dispatchBatchSize)
+	if err != nil {
+		return fmt.Errorf("d.dispatcherRepository.ClaimDueDeliveries: %w", err)
+	}
+
+	channels := make(map[int64]aggregates.Channel)
+
+	for _, delivery := range deliveries {
+		channel, err := d.channel(ctx, channels, delivery.ChannelID)
+		if err != nil {
+			// The delivery is retried once its lease expires.
+			slog.Error("error while getting notification channel",
+				slog.Int64("channel_id", delivery.ChannelID), slog.Any("error", err))
+			continue
+		}
+
+		var sendErr error
+		switch {
+		case channel.ID == 0:
+			sendErr = aggregates.ErrChannelNotFound
+		case !channel.Enabled:
+			sendErr = errors.New("notification channel is disabled")
+		default:
+			sendErr = d.channelSender.Send(ctx, channel, delivery.Message)
+		}
+
+		if sendErr == nil {
+			if err := d.dispatcherRepository.MarkDeliverySent(
+				ctx, delivery.ID, time.Now().UTC()); err != nil {
+				slog.Error("error while marking notification as sent",
+					slog.Int64("delivery_id", delivery.ID), slog.Any("error", err))
+			}
+
+			continue
+		}
+
+		delivery = d.retry(delivery, sendErr)
+
+		if err := d.dispatcherRepository.MarkDeliveryFailed(ctx, delivery); err != nil {
+			slog.Error("error while marking notification as failed",
+				slog.Int64("delivery_id", delivery.ID), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
+
+// channel returns the delivery's channel, caching it for the whole batch, a
+// deleted channel is returned as an empty channel.
+func (d *Dispatcher) channel(ctx context.Context,
+	channels map[int64]aggregates.Channel, id int64) (aggregates.Channel, error) {
+	if channel, ok := channels[id]; ok {
+		return channel, nil
+	}
+
+	// Deliveries of every project are dispatched, so the channel is looked
+	// up across them.
+	channel, err := d.dispatcherRepository.SelectChannelByID(
+		tenantsAggregates.WithAllProjects(ctx), id)
+	if err != nil && !errors.Is(err, aggregates.ErrChannelNotFound) {
+		return aggregates.Channel{}, fmt.Errorf(
+			"d.dispatcherRepository.SelectChannelByID: %w", err)
+	}
+
+	channels[id] = channel
+
+	return channel, nil
+}
+
+// retry schedules the next attempt of a failed delivery, giving up once it
+// reaches the maximum attempts.
+func (d *Dispatcher) retry(
+	delivery aggregates.Delivery, sendErr error) aggregates.Delivery {
+	delivery.Attempts++
+	delivery.LastError = sendErr.Error()
+
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = aggregates.DeliveryStatusFailed
+		slog.Error("notification delivery failed, giving up",
+			slog.Int64("delivery_id", delivery.ID),
+			slog.Int("attempts", delivery.Attempts),
+			slog.Any("error", sendErr))
+
+		return delivery
+	}
+
+	delay := retryBaseDelay << (delivery.Attempts - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+
+	delivery.Status = aggregates.DeliveryStatusPending
+	delivery.NextAttemptAt = time.Now().UTC().Add(delay)
+
+	return delivery
+}
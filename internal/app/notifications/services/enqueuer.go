@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+type enqueuerRepository interface {
+	InsertDeliveries(context.Context, []int64, aggregates.Message) error
+}
+
+// Enqueuer defines the methods needed to queue notifications in the outbox.
+type Enqueuer struct {
+	enqueuerRepository enqueuerRepository
+}
+
+// NewEnqueuer initializes a new Enqueuer.
+func NewEnqueuer(enqueuerRepository enqueuerRepository) *Enqueuer {
+	return &Enqueuer{
+		enqueuerRepository: enqueuerRepository,
+	}
+}
+
+// Enqueue queues the message to be sent through every given channel.
+func (e *Enqueuer) Enqueue(ctx context.Context,
+	channelIDs []int64, message aggregates.Message) error {
+	if len(channelIDs) == 0 {
+		return nil
+	}
+
+	if err := e.enqueuerRepository.InsertDeliveries(
+		ctx, channelIDs, message); err != nil {
+		return fmt.Errorf("e.enqueuerRepository.InsertDeliveries, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+)
+
+// Outcome is how a transaction sent by an agent ended up.
+type Outcome string
+
+const (
+	OutcomeLanded  Outcome = "landed"
+	OutcomeDropped Outcome = "dropped"
+	OutcomeExpired Outcome = "expired"
+	// OutcomeFailed is a transaction whose every submission was rejected
+	// by the RPC providers, e.g. failing its preflight simulation.
+	OutcomeFailed Outcome = "failed"
+)
+
+// Attempt represents a submission of a transaction sent by an agent, from
+// the browser and client that sent it. Failed submissions carry the Error
+// the RPC provider answered with.
+type Attempt struct {
+	ProjectID        int64
+	Signature        string
+	BrowserID        string
+	ClientID         string
+	FeePayer         string
+	ProgramAddresses []string
+	SubmittedAt      time.Time
+	Failed           bool
+	Error            string
+}
+
+// SessionGap is the inactivity after which the next transaction of a
+// browser starts a new session.
+const SessionGap = 30 * time.Minute
+
+const (
+	defaultSessionsLimit = 20
+	maxSessionsLimit     = 100
+	maxSessionsRange     = 31 * 24 * time.Hour
+)
+
+// Filter represents the filter of the sessions and wallets of a program,
+// optionally narrowed down to a fee payer Wallet or a BrowserID.
+type Filter struct {
+	ProgramAddress string
+	Wallet         string
+	BrowserID      string
+	Start          time.Time
+	End            time.Time
+	Limit          int
+}
+
+// Validate validates the filter, setting the default limit when it's not
+// set.
+func (f *Filter) Validate() error {
+	if f.ProgramAddress == "" {
+		return fmt.Errorf("program is required: %w", ErrInvalidFilter)
+	}
+
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidFilter)
+	}
+
+	if f.End.Sub(f.Start) > maxSessionsRange {
+		return fmt.Errorf("the range can't be longer than %s: %w",
+			maxSessionsRange, ErrInvalidFilter)
+	}
+
+	if f.Limit < 0 || f.Limit > maxSessionsLimit {
+		return fmt.Errorf("limit must be between 1 and %d: %w",
+			maxSessionsLimit, ErrInvalidFilter)
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultSessionsLimit
+	}
+
+	return nil
+}
+
+// Stats represents how the transactions of a session or wallet went.
+// Dropped counts the dropped and expired transactions and MedianWait is the
+// median time the landed ones took to land, in milliseconds.
+type Stats struct {
+	Attempted  int64
+	Landed     int64
+	Failed     int64
+	Dropped    int64
+	MedianWait float64
+	LastError  string
+}
+
+// Session represents the transactions a browser, or a client when the
+// browser isn't known, sent without a pause longer than SessionGap.
+type Session struct {
+	BrowserID string
+	ClientIDs []string
+	Wallets   []string
+	StartedAt time.Time
+	EndedAt   time.Time
+	Stats
+}
+
+// Wallet represents the transactions a fee payer wallet sent, over
+// Sessions sessions.
+type Wallet struct {
+	Address  string
+	Sessions int64
+	Stats
+}
+
+// Report represents the worst affected sessions and wallets of a program.
+type Report struct {
+	Sessions []Session
+	Wallets  []Wallet
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+)
+
+type reportGetterRepository interface {
+	SelectSessions(context.Context, aggregates.Filter) ([]aggregates.Session, error)
+	SelectWallets(context.Context, aggregates.Filter) ([]aggregates.Wallet, error)
+}
+
+// ReportGetter defines the methods needed to get the worst affected
+// sessions and wallets.
+type ReportGetter struct {
+	reportGetterRepository reportGetterRepository
+}
+
+// NewReportGetter initializes a new ReportGetter.
+func NewReportGetter(reportGetterRepository reportGetterRepository) *ReportGetter {
+	return &ReportGetter{
+		reportGetterRepository: reportGetterRepository,
+	}
+}
+
+// GetReport gets the worst affected sessions and wallets of the
+// transactions matching the filter.
+func (rg *ReportGetter) GetReport(ctx context.Context,
+	filter aggregates.Filter) (aggregates.Report, error) {
+	if err := filter.Validate(); err != nil {
+		return aggregates.Report{}, fmt.Errorf("filter.Validate, err: %w", err)
+	}
+
+	sessions, err := rg.reportGetterRepository.SelectSessions(ctx, filter)
+	if err != nil {
+		return aggregates.Report{}, fmt.Errorf(
+			"rg.reportGetterRepository.SelectSessions, err: %w", err)
+	}
+
+	wallets, err := rg.reportGetterRepository.SelectWallets(ctx, filter)
+	if err != nil {
+		return aggregates.Report{}, fmt.Errorf(
+			"rg.reportGetterRepository.SelectWallets, err: %w", err)
+	}
+
+	return aggregates.Report{Sessions: sessions, Wallets: wallets}, nil
+}
@@ -0,0 +1,8 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrSLONotFound = errors.New("slo not found")
+	ErrInvalidSLO  = errors.New("invalid slo")
+)
@@ -0,0 +1,85 @@
+package aggregates
+
+import (
+	"fmt"
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// Indicator represents what an SLO measures, the service level indicator.
+type Indicator string
+
+const (
+	// IndicatorSuccessRate counts the successful transactions as good.
+	IndicatorSuccessRate Indicator = "success_rate"
+	// IndicatorLatency counts the transactions landing within the SLO
+	// latency threshold as good.
+	IndicatorLatency Indicator = "latency"
+)
+
+// Good returns the amount of good transactions of the window.
+func (i Indicator) Good(counts metricsAggregates.WindowCounts) int64 {
+	switch i {
+	case IndicatorSuccessRate:
+		return counts.Total - counts.Errors
+	case IndicatorLatency:
+		return counts.Total - counts.Slow
+	}
+
+	return 0
+}
+
+func (i Indicator) valid() bool {
+	switch i {
+	case IndicatorSuccessRate, IndicatorLatency:
+		return true
+	}
+
+	return false
+}
+
+// SLO represents a service level objective, e.g. "99.5% of the transactions
+// to program X succeed over 30 days". Objective is a percentage and an empty
+// ProgramAddress means the SLO covers every transaction.
+type SLO struct {
+	ID               int64
+	ProjectID        int64
+	Name             string
+	ProgramAddress   string
+	Indicator        Indicator
+	Objective        float64
+	LatencyThreshold time.Duration
+	Window           time.Duration
+	Enabled          bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Target returns the objective as a ratio.
+func (s SLO) Target() float64 {
+	return s.Objective / 100
+}
+
+// ErrorBudget returns the ratio of bad transactions the SLO allows.
+func (s SLO) ErrorBudget() float64 {
+	return 1 - s.Target()
+}
+
+// Validate checks that the SLO is well formed.
+func (s SLO) Validate() error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("name is required: %w", ErrInvalidSLO)
+	case !s.Indicator.valid():
+		return fmt.Errorf("unknown indicator %q: %w", s.Indicator, ErrInvalidSLO)
+	case s.Objective <= 0 || s.Objective >= 100:
+		return fmt.Errorf("objective must be between 0 and 100: %w", ErrInvalidSLO)
+	case s.Indicator == IndicatorLatency && s.LatencyThreshold <= 0:
+		return fmt.Errorf("latency threshold must be positive: %w", ErrInvalidSLO)
+	case s.Window <= 0:
+		return fmt.Errorf("window must be positive: %w", ErrInvalidSLO)
+	}
+
+	return nil
+}
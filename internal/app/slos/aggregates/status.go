@@ -0,0 +1,98 @@
+package aggregates
+
+import (
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// BurnRateWindows are the windows the burn rates are computed for, a short
+// one to catch fast burns and longer ones to catch slow but steady burns.
+var BurnRateWindows = []time.Duration{
+	time.Hour,
+	6 * time.Hour,
+	3 * 24 * time.Hour,
+}
+
+// BurnRate represents how fast the error budget is consumed within a window,
+// 1 means the budget would be exactly exhausted at the end of the SLO window.
+type BurnRate struct {
+	Window time.Duration
+	Rate   float64
+}
+
+// Status represents the compliance of an SLO over its rolling window.
+type Status struct {
+	SLOID           int64
+	Total           int64
+	Good            int64
+	Compliance      float64
+	BudgetRemaining float64
+	BurnRates       []BurnRate
+	ComputedAt      time.Time
+}
+
+// Met returns true when the SLO compliance is at or above its objective.
+func (s Status) Met(slo SLO) bool {
+	return s.Compliance >= slo.Target()
+}
+
+// NewStatus computes the status of the SLO from the counts of its window and
+// the counts of every burn rate window, in BurnRateWindows order.
+//
+// Windows without transactions are fully compliant and don't burn budget.
+// An SLO without error budget, a 100% objective, has it exhausted by a single
+// bad transaction.
+func NewStatus(slo SLO, counts metricsAggregates.WindowCounts,
+	burnRateCounts []metricsAggregates.WindowCounts, now time.Time) Status {
+	status := Status{
+		SLOID:           slo.ID,
+		Total:           counts.Total,
+		Good:            slo.Indicator.Good(counts),
+		Compliance:      1,
+		BudgetRemaining: 1,
+		ComputedAt:      now,
+	}
+
+	budget := slo.ErrorBudget()
+
+	if status.Total > 0 {
+		status.Compliance = float64(status.Good) / float64(status.Total)
+		status.BudgetRemaining = 1 - budgetsConsumed(1-status.Compliance, budget)
+	}
+
+	for i, window := range BurnRateWindows {
+		burnRate := BurnRate{Window: window}
+
+		if i < len(burnRateCounts) && burnRateCounts[i].Total > 0 {
+			total := burnRateCounts[i].Total
+			bad := total - slo.Indicator.Good(burnRateCounts[i])
+			burnRate.Rate = budgetsConsumed(float64(bad)/float64(total), budget)
+		}
+
+		status.BurnRates = append(status.BurnRates, burnRate)
+	}
+
+	return status
+}
+
+// budgetsConsumed returns how many error budgets the ratio of bad
+// transactions amounts to.
+func budgetsConsumed(badRatio, budget float64) float64 {
+	switch {
+	case badRatio <= 0:
+		return 0
+	case budget <= 0:
+		return 1
+	}
+
+	return badRatio / budget
+}
+
+// StatusesFilter represents the filters to query the SLO status history.
+type StatusesFilter struct {
+	SLOID int64
+	Since *time.Time
+	Until *time.Time
+	Limit int
+}
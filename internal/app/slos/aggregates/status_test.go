@@ -0,0 +1,179 @@
+package aggregates
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+func TestNewStatus(t *testing.T) {
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	successRate := SLO{
+		ID:        1,
+		Indicator: IndicatorSuccessRate,
+		Objective: 99,
+		Window:    30 * 24 * time.Hour,
+	}
+
+	latency := successRate
+	latency.Indicator = IndicatorLatency
+	latency.LatencyThreshold = time.Second
+
+	noBudget := successRate
+	noBudget.Objective = 100
+
+	tests := []struct {
+		name            string
+		slo             SLO
+		counts          metricsAggregates.WindowCounts
+		burnRateCounts  []metricsAggregates.WindowCounts
+		compliance      float64
+		budgetRemaining float64
+		burnRates       []float64
+		met             bool
+	}{
+		{
+			name:            "empty windows",
+			slo:             successRate,
+			burnRateCounts:  make([]metricsAggregates.WindowCounts, 3),
+			compliance:      1,
+			budgetRemaining: 1,
+			burnRates:       []float64{0, 0, 0},
+			met:             true,
+		},
+		{
+			name:            "without burn rate counts",
+			slo:             successRate,
+			counts:          metricsAggregates.WindowCounts{Total: 1000},
+			compliance:      1,
+			budgetRemaining: 1,
+			burnRates:       []float64{0, 0, 0},
+			met:             true,
+		},
+		{
+			name:   "half the budget consumed",
+			slo:    successRate,
+			counts: metricsAggregates.WindowCounts{Total: 1000, Errors: 5},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100, Errors: 2},
+				{},
+				{Total: 1000, Errors: 5},
+			},
+			compliance:      0.995,
+			budgetRemaining: 0.5,
+			burnRates:       []float64{2, 0, 0.5},
+			met:             true,
+		},
+		{
+			name:   "budget exactly exhausted",
+			slo:    successRate,
+			counts: metricsAggregates.WindowCounts{Total: 1000, Errors: 10},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100, Errors: 1},
+				{Total: 500, Errors: 5},
+				{Total: 1000, Errors: 10},
+			},
+			compliance:      0.99,
+			budgetRemaining: 0,
+			burnRates:       []float64{1, 1, 1},
+			met:             true,
+		},
+		{
+			name:   "budget overspent",
+			slo:    successRate,
+			counts: metricsAggregates.WindowCounts{Total: 1000, Errors: 20},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100, Errors: 10},
+				{Total: 500, Errors: 10},
+				{Total: 1000, Errors: 20},
+			},
+			compliance:      0.98,
+			budgetRemaining: -1,
+			burnRates:       []float64{10, 2, 2},
+		},
+		{
+			name:   "latency counts the slow transactions",
+			slo:    latency,
+			counts: metricsAggregates.WindowCounts{Total: 1000, Errors: 100, Slow: 5},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100, Errors: 100, Slow: 1},
+				{},
+				{},
+			},
+			compliance:      0.995,
+			budgetRemaining: 0.5,
+			burnRates:       []float64{1, 0, 0},
+			met:             true,
+		},
+		{
+			name:   "no budget without bad transactions",
+			slo:    noBudget,
+			counts: metricsAggregates.WindowCounts{Total: 1000},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100},
+				{},
+				{Total: 1000},
+			},
+			compliance:      1,
+			budgetRemaining: 1,
+			burnRates:       []float64{0, 0, 0},
+			met:             true,
+		},
+		{
+			name:   "no budget with bad transactions",
+			slo:    noBudget,
+			counts: metricsAggregates.WindowCounts{Total: 1000, Errors: 1},
+			burnRateCounts: []metricsAggregates.WindowCounts{
+				{Total: 100},
+				{},
+				{Total: 1000, Errors: 1},
+			},
+			compliance:      0.999,
+			budgetRemaining: 0,
+			burnRates:       []float64{0, 0, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := NewStatus(test.slo, test.counts, test.burnRateCounts, now)
+
+			if status.SLOID != test.slo.ID || !status.ComputedAt.Equal(now) {
+				t.Errorf("status = %+v, want SLO %d computed at %s",
+					status, test.slo.ID, now)
+			}
+
+			if math.Abs(status.Compliance-test.compliance) > 1e-9 {
+				t.Errorf("Compliance = %v, want %v", status.Compliance, test.compliance)
+			}
+
+			if math.Abs(status.BudgetRemaining-test.budgetRemaining) > 1e-9 {
+				t.Errorf("BudgetRemaining = %v, want %v",
+					status.BudgetRemaining, test.budgetRemaining)
+			}
+
+			if len(status.BurnRates) != len(BurnRateWindows) {
+				t.Fatalf("BurnRates = %+v, want %d", status.BurnRates, len(BurnRateWindows))
+			}
+
+			for i, burnRate := range status.BurnRates {
+				if burnRate.Window != BurnRateWindows[i] {
+					t.Errorf("BurnRates[%d].Window = %s, want %s",
+						i, burnRate.Window, BurnRateWindows[i])
+				}
+
+				if math.Abs(burnRate.Rate-test.burnRates[i]) > 1e-9 {
+					t.Errorf("BurnRates[%d].Rate = %v, want %v",
+						i, burnRate.Rate, test.burnRates[i])
+				}
+			}
+
+			if met := status.Met(test.slo); met != test.met {
+				t.Errorf("Met = %t, want %t", met, test.met)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type calculatorMetricsRepository interface {
+	QueryWindowCountsBetween(context.Context, string, time.Time, time.Time,
+		time.Duration) (metricsAggregates.WindowCounts, error)
+}
+
+// calculateStatus computes the status of the SLO over its rolling window and
+// its burn rates, ending now, from the metrics of the project owning it.
+func calculateStatus(ctx context.Context,
+	metricsRepository calculatorMetricsRepository,
+	slo aggregates.SLO, now time.Time) (aggregates.Status, error) {
+	ctx = tenantsAggregates.WithProjectID(ctx, slo.ProjectID)
+
+	var latencyThreshold time.Duration
+	if slo.Indicator == aggregates.IndicatorLatency {
+		latencyThreshold = slo.LatencyThreshold
+	}
+
+	counts, err := metricsRepository.QueryWindowCountsBetween(ctx,
+		slo.ProgramAddress, now.Add(-slo.Window), now, latencyThreshold)
+	if err != nil {
+		return aggregates.Status{}, fmt.Errorf(
+			"metricsRepository.QueryWindowCountsBetween: %w", err)
+	}
+
+	burnRateCounts := make(
+		[]metricsAggregates.WindowCounts, len(aggregates.BurnRateWindows))
+	for i, window := range aggregates.BurnRateWindows {
+		burnRateCounts[i], err = metricsRepository.QueryWindowCountsBetween(ctx,
+			slo.ProgramAddress, now.Add(-window), now, latencyThreshold)
+		if err != nil {
+			return aggregates.Status{}, fmt.Errorf(
+				"metricsRepository.QueryWindowCountsBetween(%s): %w", window, err)
+		}
+	}
+
+	return aggregates.NewStatus(slo, counts, burnRateCounts, now), nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	metricsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// windowCountsStub returns the counts of every window by its length, and
+// records the latency thresholds it's queried with.
+type windowCountsStub struct {
+	counts     map[time.Duration]metricsAggregates.WindowCounts
+	thresholds []time.Duration
+	err        error
+}
+
+func (wc *windowCountsStub) QueryWindowCountsBetween(ctx context.Context,
+	_ string, start, end time.Time,
+	latencyThreshold time.Duration) (metricsAggregates.WindowCounts, error) {
+	if projectID, err := tenantsAggregates.ProjectIDFromContext(ctx); err != nil || projectID != 7 {
+		return metricsAggregates.WindowCounts{}, fmt.Errorf("project %d, err: %w", projectID, err)
+	}
+
+	wc.thresholds = append(wc.thresholds, latencyThreshold)
+
+	return wc.counts[end.Sub(start)], wc.err
+}
+
+func TestCalculateStatus(t *testing.T) {
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	slo := aggregates.SLO{
+		ID:               1,
+		ProjectID:        7,
+		Indicator:        aggregates.IndicatorSuccessRate,
+		Objective:        99,
+		LatencyThreshold: time.Second,
+		Window:           7 * 24 * time.Hour,
+	}
+
+	latency := slo
+	latency.Indicator = aggregates.IndicatorLatency
+
+	counts := map[time.Duration]metricsAggregates.WindowCounts{
+		7 * 24 * time.Hour: {Total: 1000, Errors: 5, Slow: 10},
+		time.Hour:          {Total: 100, Errors: 2, Slow: 1},
+	}
+
+	tests := []struct {
+		name            string
+		slo             aggregates.SLO
+		err             error
+		budgetRemaining float64
+		burnRates       []float64
+		threshold       time.Duration
+	}{
+		{
+			name:            "success rate",
+			slo:             slo,
+			budgetRemaining: 0.5,
+			burnRates:       []float64{2, 0, 0},
+		},
+		{
+			name:            "latency",
+			slo:             latency,
+			budgetRemaining: 0,
+			burnRates:       []float64{1, 0, 0},
+			threshold:       time.Second,
+		},
+		{
+			name: "metrics error",
+			slo:  slo,
+			err:  errors.New("influx is down"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stub := &windowCountsStub{counts: counts, err: test.err}
+
+			status, err := calculateStatus(context.Background(), stub, test.slo, now)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("err = %v, want %v", err, test.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if status.Total != 1000 {
+				t.Errorf("Total = %d, want 1000", status.Total)
+			}
+
+			if diff := status.BudgetRemaining - test.budgetRemaining; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("BudgetRemaining = %v, want %v",
+					status.BudgetRemaining, test.budgetRemaining)
+			}
+
+			for i, burnRate := range status.BurnRates {
+				if diff := burnRate.Rate - test.burnRates[i]; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("BurnRates[%d].Rate = %v, want %v",
+						i, burnRate.Rate, test.burnRates[i])
+				}
+			}
+
+			if len(stub.thresholds) != 1+len(aggregates.BurnRateWindows) {
+				t.Fatalf("queries = %d, want %d",
+					len(stub.thresholds), 1+len(aggregates.BurnRateWindows))
+			}
+
+			for i, threshold := range stub.thresholds {
+				if threshold != test.threshold {
+					t.Errorf("thresholds[%d] = %s, want %s", i, threshold, test.threshold)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+type historyGetterRepository interface {
+	SelectSLOByID(context.Context, int64) (aggregates.SLO, error)
+	SelectStatuses(context.Context,
+		aggregates.StatusesFilter) ([]aggregates.Status, error)
+}
+
+// HistoryGetter defines the methods needed to get the SLO status history.
+type HistoryGetter struct {
+	historyGetterRepository historyGetterRepository
+}
+
+// NewHistoryGetter initializes a new HistoryGetter.
+func NewHistoryGetter(
+	historyGetterRepository historyGetterRepository) *HistoryGetter {
+	return &HistoryGetter{
+		historyGetterRepository: historyGetterRepository,
+	}
+}
+
+// GetHistory gets the recorded statuses of an SLO matching the filter,
+// newest first.
+func (hg *HistoryGetter) GetHistory(ctx context.Context,
+	filter aggregates.StatusesFilter) (aggregates.SLO, []aggregates.Status, error) {
+	slo, err := hg.historyGetterRepository.SelectSLOByID(ctx, filter.SLOID)
+	if err != nil {
+		return aggregates.SLO{}, nil, fmt.Errorf(
+			"hg.historyGetterRepository.SelectSLOByID, err: %w", err)
+	}
+
+	statuses, err := hg.historyGetterRepository.SelectStatuses(ctx, filter)
+	if err != nil {
+		return aggregates.SLO{}, nil, fmt.Errorf(
+			"hg.historyGetterRepository.SelectStatuses, err: %w", err)
+	}
+
+	return slo, statuses, nil
+}
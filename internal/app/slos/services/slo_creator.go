@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+type sloCreatorRepository interface {
+	InsertSLO(context.Context, aggregates.SLO) (aggregates.SLO, error)
+}
+
+// SLOCreator defines the methods needed to create SLOs.
+type SLOCreator struct {
+	sloCreatorRepository sloCreatorRepository
+}
+
+// NewSLOCreator initializes a new SLOCreator.
+func NewSLOCreator(
+	sloCreatorRepository sloCreatorRepository) *SLOCreator {
+	return &SLOCreator{
+		sloCreatorRepository: sloCreatorRepository,
+	}
+}
+
+// Create validates and creates a new SLO.
+func (sc *SLOCreator) Create(
+	ctx context.Context, slo aggregates.SLO) (aggregates.SLO, error) {
+	if err := slo.Validate(); err != nil {
+		return aggregates.SLO{}, fmt.Errorf("slo.Validate, err: %w", err)
+	}
+
+	slo, err := sc.sloCreatorRepository.InsertSLO(ctx, slo)
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf(
+			"sc.sloCreatorRepository.InsertSLO, err: %w", err)
+	}
+
+	return slo, nil
+}
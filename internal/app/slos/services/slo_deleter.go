@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type sloDeleterRepository interface {
+	DeleteSLO(context.Context, int64) error
+}
+
+// SLODeleter defines the methods needed to delete SLOs.
+type SLODeleter struct {
+	sloDeleterRepository sloDeleterRepository
+}
+
+// NewSLODeleter initializes a new SLODeleter.
+func NewSLODeleter(
+	sloDeleterRepository sloDeleterRepository) *SLODeleter {
+	return &SLODeleter{
+		sloDeleterRepository: sloDeleterRepository,
+	}
+}
+
+// Delete deletes an SLO, its status history is kept.
+func (sd *SLODeleter) Delete(ctx context.Context, id int64) error {
+	if err := sd.sloDeleterRepository.DeleteSLO(ctx, id); err != nil {
+		return fmt.Errorf("sd.sloDeleterRepository.DeleteSLO, err: %w", err)
+	}
+
+	return nil
+}
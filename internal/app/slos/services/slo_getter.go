@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+type sloGetterRepository interface {
+	SelectAllSLOs(context.Context) ([]aggregates.SLO, error)
+	SelectSLOByID(context.Context, int64) (aggregates.SLO, error)
+}
+
+// SLOGetter defines the methods needed to get SLOs.
+type SLOGetter struct {
+	sloGetterRepository sloGetterRepository
+}
+
+// NewSLOGetter initializes a new SLOGetter.
+func NewSLOGetter(
+	sloGetterRepository sloGetterRepository) *SLOGetter {
+	return &SLOGetter{
+		sloGetterRepository: sloGetterRepository,
+	}
+}
+
+// GetSLOs gets all SLOs.
+func (sg *SLOGetter) GetSLOs(
+	ctx context.Context) ([]aggregates.SLO, error) {
+	slos, err := sg.sloGetterRepository.SelectAllSLOs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"sg.sloGetterRepository.SelectAllSLOs, err: %w", err)
+	}
+
+	return slos, nil
+}
+
+// GetSLO gets an SLO by its ID.
+func (sg *SLOGetter) GetSLO(
+	ctx context.Context, id int64) (aggregates.SLO, error) {
+	slo, err := sg.sloGetterRepository.SelectSLOByID(ctx, id)
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf(
+			"sg.sloGetterRepository.SelectSLOByID, err: %w", err)
+	}
+
+	return slo, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+type sloUpdaterRepository interface {
+	UpdateSLO(context.Context, aggregates.SLO) (aggregates.SLO, error)
+}
+
+// SLOUpdater defines the methods needed to update SLOs.
+type SLOUpdater struct {
+	sloUpdaterRepository sloUpdaterRepository
+}
+
+// NewSLOUpdater initializes a new SLOUpdater.
+func NewSLOUpdater(
+	sloUpdaterRepository sloUpdaterRepository) *SLOUpdater {
+	return &SLOUpdater{
+		sloUpdaterRepository: sloUpdaterRepository,
+	}
+}
+
+// Update validates and updates an existing SLO.
+func (su *SLOUpdater) Update(
+	ctx context.Context, slo aggregates.SLO) (aggregates.SLO, error) {
+	if err := slo.Validate(); err != nil {
+		return aggregates.SLO{}, fmt.Errorf("slo.Validate, err: %w", err)
+	}
+
+	slo, err := su.sloUpdaterRepository.UpdateSLO(ctx, slo)
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf(
+			"su.sloUpdaterRepository.UpdateSLO, err: %w", err)
+	}
+
+	return slo, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+type statusGetterRepository interface {
+	SelectAllSLOs(context.Context) ([]aggregates.SLO, error)
+	SelectSLOByID(context.Context, int64) (aggregates.SLO, error)
+}
+
+// StatusGetter defines the methods needed to get the current SLO status.
+type StatusGetter struct {
+	statusGetterRepository statusGetterRepository
+	metricsRepository      calculatorMetricsRepository
+}
+
+// NewStatusGetter initializes a new StatusGetter.
+func NewStatusGetter(
+	statusGetterRepository statusGetterRepository,
+	metricsRepository calculatorMetricsRepository) *StatusGetter {
+	return &StatusGetter{
+		statusGetterRepository: statusGetterRepository,
+		metricsRepository:      metricsRepository,
+	}
+}
+
+// GetStatuses computes the current status of every enabled SLO.
+func (sg *StatusGetter) GetStatuses(ctx context.Context) (
+	[]aggregates.SLO, []aggregates.Status, error) {
+	slos, err := sg.statusGetterRepository.SelectAllSLOs(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"sg.statusGetterRepository.SelectAllSLOs, err: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	var (
+		enabledSLOs []aggregates.SLO
+		statuses    []aggregates.Status
+	)
+
+	for _, slo := range slos {
+		if !slo.Enabled {
+			continue
+		}
+
+		status, err := calculateStatus(ctx, sg.metricsRepository, slo, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("calculateStatus, err: %w", err)
+		}
+
+		enabledSLOs = append(enabledSLOs, slo)
+		statuses = append(statuses, status)
+	}
+
+	return enabledSLOs, statuses, nil
+}
+
+// GetStatus computes the current status of an SLO.
+func (sg *StatusGetter) GetStatus(ctx context.Context,
+	id int64) (aggregates.SLO, aggregates.Status, error) {
+	slo, err := sg.statusGetterRepository.SelectSLOByID(ctx, id)
+	if err != nil {
+		return aggregates.SLO{}, aggregates.Status{}, fmt.Errorf(
+			"sg.statusGetterRepository.SelectSLOByID, err: %w", err)
+	}
+
+	status, err := calculateStatus(
+		ctx, sg.metricsRepository, slo, time.Now().UTC())
+	if err != nil {
+		return aggregates.SLO{}, aggregates.Status{}, fmt.Errorf(
+			"calculateStatus, err: %w", err)
+	}
+
+	return slo, status, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type trackerSQLRepository interface {
+	SelectEnabledSLOs(context.Context) ([]aggregates.SLO, error)
+	InsertStatus(context.Context, aggregates.Status) error
+}
+
+// Tracker is a service that periodically records the status of every enabled
+// SLO, building the history used for the reliability reviews.
+type Tracker struct {
+	sqlRepository     trackerSQLRepository
+	metricsRepository calculatorMetricsRepository
+	interval          time.Duration
+}
+
+// NewTracker creates a new instance of the Tracker service.
+func NewTracker(
+	sqlRepository trackerSQLRepository,
+	metricsRepository calculatorMetricsRepository,
+	interval time.Duration,
+) *Tracker {
+	return &Tracker{
+		sqlRepository:     sqlRepository,
+		metricsRepository: metricsRepository,
+		interval:          interval,
+	}
+}
+
+// Track starts recording the SLO statuses every interval.
+func (t *Tracker) Track(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := t.track(ctx, time.Now().UTC()); err != nil {
+				slog.Error("error while tracking slos", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (t *Tracker) track(ctx context.Context, now time.Time) error {
+	slos, err := t.sqlRepository.SelectEnabledSLOs(
+		tenantsAggregates.WithAllProjects(ctx))
+	if err != nil {
+		return fmt.Errorf("t.sqlRepository.SelectEnabledSLOs: %w", err)
+	}
+
+	for _, slo := range slos {
+		status, err := calculateStatus(ctx, t.metricsRepository, slo, now)
+		if err != nil {
+			slog.Error("error while calculating slo status",
+				slog.Int64("slo_id", slo.ID), slog.Any("error", err))
+			continue
+		}
+
+		if err := t.sqlRepository.InsertStatus(ctx, status); err != nil {
+			slog.Error("error while storing slo status",
+				slog.Int64("slo_id", slo.ID), slog.Any("error", err))
+		}
+	}
+
+	return nil
+}
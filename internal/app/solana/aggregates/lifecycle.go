@@ -0,0 +1,121 @@
+package aggregates
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrLifecycleNotFound     = errors.New("transaction lifecycle not found")
+	ErrInvalidLifecycleQuery = errors.New("invalid transaction lifecycle query")
+)
+
+// Commitment is the commitment level a slot, and so its transactions,
+// reached.
+type Commitment string
+
+const (
+	CommitmentProcessed Commitment = "processed"
+	CommitmentConfirmed Commitment = "confirmed"
+	CommitmentFinalized Commitment = "finalized"
+)
+
+// CommitmentFromSlotStatus returns the commitment of a status of the
+// accountsdb plugin slot table, which names the finalized slots rooted.
+func CommitmentFromSlotStatus(status string) (Commitment, bool) {
+	switch status {
+	case "processed":
+		return CommitmentProcessed, true
+	case "confirmed":
+		return CommitmentConfirmed, true
+	case "rooted":
+		return CommitmentFinalized, true
+	default:
+		return "", false
+	}
+}
+
+// Lifecycle represents the lifecycle of a transaction for a project, from
+// its submission by an agent, if it was sent through one, to its slot being
+// finalized. The times of the commitment levels not reached yet are nil.
+type Lifecycle struct {
+	ProjectID        int64
+	Signature        string
+	Slot             int64
+	ProgramAddresses []string
+	SubmittedAt      *time.Time
+	ProcessedAt      *time.Time
+	ConfirmedAt      *time.Time
+	FinalizedAt      *time.Time
+}
+
+// Commitment returns the highest commitment level the transaction reached.
+func (l Lifecycle) Commitment() Commitment {
+	switch {
+	case l.FinalizedAt != nil:
+		return CommitmentFinalized
+	case l.ConfirmedAt != nil:
+		return CommitmentConfirmed
+	default:
+		return CommitmentProcessed
+	}
+}
+
+// LifecycleFrom is the lifecycle event the times to confirmed and finalized
+// are measured from.
+type LifecycleFrom string
+
+const (
+	// LifecycleFromSubmitted measures from the agent submission, only the
+	// transactions sent through an agent are measured.
+	LifecycleFromSubmitted LifecycleFrom = "submitted"
+	// LifecycleFromProcessed measures from the transaction being processed.
+	LifecycleFromProcessed LifecycleFrom = "processed"
+)
+
+// maxLifecycleQueryRange is the longest period the lifecycle stats can be
+// queried for.
+const maxLifecycleQueryRange = 31 * 24 * time.Hour
+
+// LifecycleStatsFilter represents the filter of the lifecycle stats, the
+// empty ProgramAddress matches every program.
+type LifecycleStatsFilter struct {
+	Start          time.Time
+	End            time.Time
+	ProgramAddress string
+	From           LifecycleFrom
+}
+
+// Validate validates the lifecycle stats filter.
+func (f LifecycleStatsFilter) Validate() error {
+	if f.From != LifecycleFromSubmitted && f.From != LifecycleFromProcessed {
+		return fmt.Errorf("from must be submitted or processed: %w",
+			ErrInvalidLifecycleQuery)
+	}
+
+	if !f.End.After(f.Start) {
+		return fmt.Errorf("end must be after start: %w", ErrInvalidLifecycleQuery)
+	}
+
+	if f.End.Sub(f.Start) > maxLifecycleQueryRange {
+		return fmt.Errorf("the range can't be longer than %s: %w",
+			maxLifecycleQueryRange, ErrInvalidLifecycleQuery)
+	}
+
+	return nil
+}
+
+// LifecycleStats represents the percentiles of the times to confirmed and
+// finalized of the transactions of a program, in milliseconds. Count is the
+// amount of transactions measured.
+type LifecycleStats struct {
+	ProgramAddress string
+	Count          int64
+	ConfirmedP50   float64
+	ConfirmedP95   float64
+	ConfirmedP99   float64
+	FinalizedP50   float64
+	FinalizedP95   float64
+	FinalizedP99   float64
+}
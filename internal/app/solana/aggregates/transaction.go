@@ -1,6 +1,15 @@
 package aggregates
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBlockNotFound is returned when a block isn't stored by the geyser
+// plugin, either because it's too old or it isn't on chain.
+var ErrBlockNotFound = errors.New("block not found")
 
 // Transaction is the domain representation of a solana transaction.
 type Transaction struct {
@@ -22,8 +31,23 @@ type Transaction struct {
 	ProcessedAt *time.Time
 }
 
+// Failed returns whether the transaction failed, that's whether the error of
+// its status meta is set. The geyser plugin stores it as the code and detail
+// of the error, null for the transactions that succeeded.
+func (t Transaction) Failed() (bool, error) {
+	var meta struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(t.Meta), &meta); err != nil {
+		return false, fmt.Errorf("json.Unmarshal, err: %w", err)
+	}
+
+	return len(meta.Error) > 0 && string(meta.Error) != "null", nil
+}
+
 // TransactionDetail is the domain representation of a solana transaction detail.
 type TransactionDetail struct {
+	ProjectID      int64
 	ProgramAddress string
 	UpdatedOn      time.Time
 	RPCTime        int64
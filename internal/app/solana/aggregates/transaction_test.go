@@ -0,0 +1,49 @@
+package aggregates
+
+import "testing"
+
+func TestTransactionFailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		meta   string
+		failed bool
+		err    bool
+	}{
+		{
+			name: "succeeded",
+			meta: `{"error":null,"fee":5000}`,
+		},
+		{
+			name: "without error",
+			meta: `{"fee":5000}`,
+		},
+		{
+			name:   "failed",
+			meta:   `{"error":{"error_code":"InstructionError","error_detail":"custom program error: 0x1"},"fee":5000}`,
+			failed: true,
+		},
+		{
+			name:   "failed with the error code",
+			meta:   `{"error":"InsufficientFundsForFee","fee":5000}`,
+			failed: true,
+		},
+		{
+			name: "invalid meta",
+			meta: `(,5000,,,,,,,)`,
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			failed, err := Transaction{Meta: test.meta}.Failed()
+			if (err != nil) != test.err {
+				t.Fatalf("err = %v, want error %t", err, test.err)
+			}
+
+			if failed != test.failed {
+				t.Errorf("failed = %t, want %t", failed, test.failed)
+			}
+		})
+	}
+}
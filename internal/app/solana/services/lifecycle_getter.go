@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+type lifecycleGetterRepository interface {
+	SelectLifecycle(context.Context, string) (aggregates.Lifecycle, error)
+	SelectLifecycleStats(context.Context,
+		aggregates.LifecycleStatsFilter) ([]aggregates.LifecycleStats, error)
+}
+
+// LifecycleGetter defines the methods needed to get the transaction
+// lifecycles.
+type LifecycleGetter struct {
+	lifecycleGetterRepository lifecycleGetterRepository
+}
+
+// NewLifecycleGetter initializes a new LifecycleGetter.
+func NewLifecycleGetter(
+	lifecycleGetterRepository lifecycleGetterRepository) *LifecycleGetter {
+	return &LifecycleGetter{
+		lifecycleGetterRepository: lifecycleGetterRepository,
+	}
+}
+
+// GetLifecycle gets the lifecycle of a transaction by its signature.
+func (lg *LifecycleGetter) GetLifecycle(ctx context.Context,
+	signature string) (aggregates.Lifecycle, error) {
+	lifecycle, err := lg.lifecycleGetterRepository.SelectLifecycle(ctx, signature)
+	if err != nil {
+		return aggregates.Lifecycle{}, fmt.Errorf(
+			"lg.lifecycleGetterRepository.SelectLifecycle, err: %w", err)
+	}
+
+	return lifecycle, nil
+}
+
+// GetLifecycleStats gets the percentiles of the times to confirmed and
+// finalized per program.
+func (lg *LifecycleGetter) GetLifecycleStats(ctx context.Context,
+	filter aggregates.LifecycleStatsFilter) ([]aggregates.LifecycleStats, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("filter.Validate, err: %w", err)
+	}
+
+	stats, err := lg.lifecycleGetterRepository.SelectLifecycleStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"lg.lifecycleGetterRepository.SelectLifecycleStats, err: %w", err)
+	}
+
+	return stats, nil
+}
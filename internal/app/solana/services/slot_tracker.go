@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type slotTrackerSQLRepository interface {
+	InsertSlotStatuses(context.Context) (int64, error)
+}
+
+// SlotTracker is a service that records when every slot reached every
+// commitment level. The accountsdb plugin slot table only keeps the latest
+// status of every slot, so its updates are polled every interval, a slot
+// confirmed and rooted within the same interval is recorded as confirmed
+// when it was rooted.
+type SlotTracker struct {
+	sqlRepository slotTrackerSQLRepository
+	interval      time.Duration
+}
+
+// NewSlotTracker creates a new instance of the SlotTracker service.
+func NewSlotTracker(
+	sqlRepository slotTrackerSQLRepository,
+	interval time.Duration,
+) *SlotTracker {
+	return &SlotTracker{
+		sqlRepository: sqlRepository,
+		interval:      interval,
+	}
+}
+
+// Track starts recording the slot statuses every interval.
+func (st *SlotTracker) Track(ctx context.Context) {
+	ticker := time.NewTicker(st.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if _, err := st.sqlRepository.InsertSlotStatuses(ctx); err != nil {
+				slog.Error("error while tracking slot statuses", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"log/slog"
+	"math/rand"
 	"time"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
@@ -11,62 +12,121 @@ import (
 // TransactionsSQLRepository define the methods to become a transaction's
 // SQL sqlRepository.
 type TransactionsSQLRepository interface {
-	SelectTransactionsByProcessedAt(
-		context.Context) ([]aggregates.Transaction, error)
-	UpdateTransactionProcessedAt(
-		context.Context, string, time.Time) error
+	ClaimTransactions(
+		context.Context, time.Duration) ([]aggregates.Transaction, error)
+	UpdateTransactionsProcessedAt(
+		context.Context, []string, time.Time) error
 }
 
 type TransactionsRedisRepository interface {
 	PublishTransaction(context.Context, aggregates.Transaction) error
 }
 
+// TransactionsListener define the methods to be notified of the inserted
+// transactions.
+type TransactionsListener interface {
+	ListenTransactions(context.Context) <-chan struct{}
+}
+
 // TransactionsCollector  define the dependencies needed to perform the
 // transaction collection.
 type TransactionsCollector struct {
-	sqlRepository   TransactionsSQLRepository
-	redisRepository TransactionsRedisRepository
+	sqlRepository     TransactionsSQLRepository
+	redisRepository   TransactionsRedisRepository
+	listener          TransactionsListener
+	pollInterval      time.Duration
+	visibilityTimeout time.Duration
 }
 
 // NewTransactionsCollector creates a new transaction collector.
 func NewTransactionsCollector(
 	sqlRepository TransactionsSQLRepository,
 	redisRepository TransactionsRedisRepository,
+	listener TransactionsListener,
+	pollInterval time.Duration,
+	visibilityTimeout time.Duration,
 ) *TransactionsCollector {
 	return &TransactionsCollector{
-		sqlRepository:   sqlRepository,
-		redisRepository: redisRepository,
+		sqlRepository:     sqlRepository,
+		redisRepository:   redisRepository,
+		listener:          listener,
+		pollInterval:      pollInterval,
+		visibilityTimeout: visibilityTimeout,
 	}
 }
 
-// CollectTransactions collects transactions from the database.
+// Collect collects the transactions from the database as soon as they're
+// notified. The database is also polled every pollInterval, so the
+// transactions whose notifications were missed are collected too.
 func (tc *TransactionsCollector) Collect(ctx context.Context) {
-	for {
-		// Sleep for 1 second before collecting transactions.
-		// We should use a better approach to avoid busy waiting.
-		time.Sleep(1 * time.Second)
+	notifications := tc.listener.ListenTransactions(ctx)
+
+	// The transactions inserted while stopped are collected right away.
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
+	for {
 		select {
 		case <-ctx.Done():
 			return
 
-		default:
-			transactions, err := tc.sqlRepository.SelectTransactionsByProcessedAt(ctx)
-			if err != nil {
-				slog.Error("tc.sqlRepository.SelectTransactionsByProcessedAt", err)
-				continue
+		case <-notifications:
+		case <-timer.C:
+		}
+
+		tc.collect(ctx)
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
 			}
+		}
+		timer.Reset(tc.nextPoll())
+	}
+}
 
-			for _, transaction := range transactions {
-				if err := tc.redisRepository.PublishTransaction(ctx, transaction); err != nil {
-					slog.Error("tc.redisRepository.PublishTransaction", err)
-				}
+// collect claims and publishes the unprocessed transactions, batch after
+// batch until there are none left. The transactions that failed to be
+// published aren't marked as processed, so they're claimed again after the
+// visibility timeout, and it stops on errors so the rest of the batches
+// wait for the next notification or poll.
+func (tc *TransactionsCollector) collect(ctx context.Context) {
+	for ctx.Err() == nil {
+		transactions, err := tc.sqlRepository.ClaimTransactions(ctx, tc.visibilityTimeout)
+		if err != nil {
+			slog.Error("tc.sqlRepository.ClaimTransactions", slog.Any("error", err))
+			return
+		}
 
-				if err := tc.sqlRepository.UpdateTransactionProcessedAt(
-					ctx, transaction.Signature, time.Now()); err != nil {
-					slog.Error("tc.sqlRepository.SetUpdatedOn", err)
-				}
+		if len(transactions) == 0 {
+			return
+		}
+
+		published := make([]string, 0, len(transactions))
+		for _, transaction := range transactions {
+			if err := tc.redisRepository.PublishTransaction(ctx, transaction); err != nil {
+				slog.Error("tc.redisRepository.PublishTransaction", slog.Any("error", err))
+				continue
 			}
+
+			published = append(published, transaction.Signature)
+		}
+
+		if err := tc.sqlRepository.UpdateTransactionsProcessedAt(
+			ctx, published, time.Now()); err != nil {
+			slog.Error("tc.sqlRepository.UpdateTransactionsProcessedAt", slog.Any("error", err))
+			return
+		}
+
+		if len(published) < len(transactions) {
+			return
 		}
 	}
 }
+
+// nextPoll returns when to poll next, the poll interval plus up to a fifth
+// of it so the replicas don't poll in lockstep.
+func (tc *TransactionsCollector) nextPoll() time.Duration {
+	return tc.pollInterval + time.Duration(rand.Int63n(int64(tc.pollInterval)/5+1))
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+// transactionsQueue claims the unprocessed transactions in batches, the
+// claimed ones aren't claimed again as they would until the visibility
+// timeout.
+type transactionsQueue struct {
+	unclaimed []aggregates.Transaction
+	batchSize int
+	claims    int
+	processed []string
+	claimErr  error
+	updateErr error
+}
+
+func (tq *transactionsQueue) ClaimTransactions(
+	context.Context, time.Duration) ([]aggregates.Transaction, error) {
+	tq.claims++
+	if tq.claimErr != nil {
+		return nil, tq.claimErr
+	}
+
+	batch := tq.unclaimed[:min(tq.batchSize, len(tq.unclaimed))]
+	tq.unclaimed = tq.unclaimed[len(batch):]
+
+	return batch, nil
+}
+
+func (tq *transactionsQueue) UpdateTransactionsProcessedAt(
+	_ context.Context, signatures []string, _ time.Time) error {
+	if tq.updateErr != nil {
+		return tq.updateErr
+	}
+
+	tq.processed = append(tq.processed, signatures...)
+	return nil
+}
+
+// transactionsPublisher publishes every transaction but the failing ones.
+type transactionsPublisher struct {
+	failing map[string]bool
+}
+
+func (tp transactionsPublisher) PublishTransaction(
+	_ context.Context, transaction aggregates.Transaction) error {
+	if tp.failing[transaction.Signature] {
+		return errors.New("redis is down")
+	}
+
+	return nil
+}
+
+func TestTransactionsCollectorCollect(t *testing.T) {
+	transactions := func(count int) []aggregates.Transaction {
+		transactions := make([]aggregates.Transaction, count)
+		for i := range transactions {
+			transactions[i].Signature = fmt.Sprintf("signature-%d", i)
+		}
+
+		return transactions
+	}
+
+	tests := []struct {
+		name         string
+		transactions int
+		failing      map[string]bool
+		claimErr     error
+		updateErr    error
+		claims       int
+		processed    []string
+		unclaimed    int
+	}{
+		{
+			name:   "no transactions",
+			claims: 1,
+		},
+		{
+			name:         "single batch",
+			transactions: 1,
+			claims:       2,
+			processed:    []string{"signature-0"},
+		},
+		{
+			name:         "batch after batch until there are none left",
+			transactions: 5,
+			claims:       4,
+			processed: []string{
+				"signature-0", "signature-1", "signature-2",
+				"signature-3", "signature-4",
+			},
+		},
+		{
+			name:         "exactly full batches",
+			transactions: 4,
+			claims:       3,
+			processed: []string{
+				"signature-0", "signature-1", "signature-2", "signature-3",
+			},
+		},
+		{
+			name:         "publish failure stops after the batch",
+			transactions: 5,
+			failing:      map[string]bool{"signature-1": true},
+			claims:       1,
+			processed:    []string{"signature-0"},
+			unclaimed:    3,
+		},
+		{
+			name:         "whole batch failing to publish",
+			transactions: 2,
+			failing:      map[string]bool{"signature-0": true, "signature-1": true},
+			claims:       1,
+		},
+		{
+			name:         "claim error",
+			transactions: 2,
+			claimErr:     errors.New("postgres is down"),
+			claims:       1,
+			unclaimed:    2,
+		},
+		{
+			name:         "update error stops after the batch",
+			transactions: 5,
+			updateErr:    errors.New("postgres is down"),
+			claims:       1,
+			unclaimed:    3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := &transactionsQueue{
+				unclaimed: transactions(test.transactions),
+				batchSize: 2,
+				claimErr:  test.claimErr,
+				updateErr: test.updateErr,
+			}
+
+			collector := NewTransactionsCollector(queue,
+				transactionsPublisher{failing: test.failing}, nil, time.Second, time.Minute)
+			collector.collect(context.Background())
+
+			if queue.claims != test.claims {
+				t.Errorf("claims = %d, want %d", queue.claims, test.claims)
+			}
+
+			if len(queue.processed) != len(test.processed) ||
+				(len(test.processed) > 0 && !reflect.DeepEqual(queue.processed, test.processed)) {
+				t.Errorf("processed = %v, want %v", queue.processed, test.processed)
+			}
+
+			if len(queue.unclaimed) != test.unclaimed {
+				t.Errorf("unclaimed = %d, want %d", len(queue.unclaimed), test.unclaimed)
+			}
+		})
+	}
+}
+
+func TestTransactionsCollectorNextPoll(t *testing.T) {
+	collector := NewTransactionsCollector(nil, nil, nil, 10*time.Second, time.Minute)
+
+	for i := 0; i < 100; i++ {
+		if next := collector.nextPoll(); next < 10*time.Second || next > 12*time.Second {
+			t.Fatalf("nextPoll = %s, want between 10s and 12s", next)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package aggregates
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// apiKeyPrefix tells the publishable ingestion keys apart from the secret
+	// project tokens.
+	apiKeyPrefix = "pk_"
+
+	// apiKeyLength is the number of random bytes of an API key.
+	apiKeyLength = 24
+
+	// apiKeyHintLength is the number of characters of the key kept to help
+	// users identify it.
+	apiKeyHintLength = len(apiKeyPrefix) + 6
+
+	// DefaultAPIKeyRateLimit is the number of events per second an API key is
+	// allowed to send when not configured.
+	DefaultAPIKeyRateLimit = 50
+
+	// DefaultAPIKeyBurst is the number of events an API key is allowed to
+	// send at once when not configured.
+	DefaultAPIKeyBurst = 100
+)
+
+// APIKey represents a publishable key the agents use to send their events
+// to the project it belongs to. Only the hash of the key is kept, along with
+// a hint to identify it.
+//
+// AllowedOrigins restricts the web pages the agents can send events from, an
+// empty list allows any origin. The origins can use a wildcard for the
+// subdomains, e.g. "https://*.example.com".
+//
+// RateLimit and Burst configure the token bucket limiting the events of the
+// key, a key with an ExpiresAt is being rotated and stops working then.
+type APIKey struct {
+	ID             int64
+	OrganizationID int64
+	ProjectID      int64
+	Name           string
+	Hint           string
+	KeyHash        string
+	AllowedOrigins []string
+	RateLimit      float64
+	Burst          int64
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Validate checks that the API key is named, its origins are valid and its
+// rate limit is positive.
+func (k APIKey) Validate() error {
+	if strings.TrimSpace(k.Name) == "" {
+		return fmt.Errorf("api key name can't be empty: %w", ErrInvalidAPIKey)
+	}
+
+	for _, origin := range k.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			return err
+		}
+	}
+
+	if k.RateLimit <= 0 {
+		return fmt.Errorf("api key rate limit must be positive: %w", ErrInvalidAPIKey)
+	}
+
+	if k.Burst < 1 {
+		return fmt.Errorf("api key burst must be at least 1: %w", ErrInvalidAPIKey)
+	}
+
+	return nil
+}
+
+// Active returns whether the API key can be used at the given time.
+func (k APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// AllowsOrigin returns whether the API key allows events sent from the given
+// origin, requests without origin are only allowed when every origin is.
+func (k APIKey) AllowsOrigin(origin string) bool {
+	if len(k.AllowedOrigins) == 0 {
+		return true
+	}
+
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	if origin == "" {
+		return false
+	}
+
+	for _, allowed := range k.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		scheme, host, found := strings.Cut(allowed, "://*.")
+		if found && strings.HasPrefix(origin, scheme+"://") &&
+			strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tenant returns the tenant the API key belongs to.
+func (k APIKey) Tenant() Tenant {
+	return Tenant{
+		OrganizationID: k.OrganizationID,
+		ProjectID:      k.ProjectID,
+	}
+}
+
+type apiKeyContextKey struct{}
+
+// NewAPIKeyContext returns a copy of ctx carrying the API key the request
+// was authorized with.
+func NewAPIKeyContext(ctx context.Context, apiKey APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+// APIKeyFromContext returns the API key carried by ctx, if any.
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	apiKey, ok := ctx.Value(apiKeyContextKey{}).(APIKey)
+	return apiKey, ok
+}
+
+// NewAPIKey generates a new random API key, it returns the key, that has to
+// be handed to the user, its hint and its hash to store.
+func NewAPIKey() (string, string, string, error) {
+	bytes := make([]byte, apiKeyLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", "", fmt.Errorf("rand.Read, err: %w", err)
+	}
+
+	key := apiKeyPrefix + hex.EncodeToString(bytes)
+
+	return key, key[:apiKeyHintLength], HashToken(key), nil
+}
+
+// validateOrigin checks that the origin is either "*" or a scheme and host,
+// optionally with a wildcard subdomain.
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+
+	parsed, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" ||
+		(parsed.Path != "" && parsed.Path != "/") ||
+		parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("api key origin %q must be a scheme and host: %w",
+			origin, ErrInvalidAPIKey)
+	}
+
+	return nil
+}
+
+// APIKeyOutcome is the result of an event sent with an API key, kept in the
+// API key usage counters.
+type APIKeyOutcome string
+
+const (
+	APIKeyOutcomeAccepted         APIKeyOutcome = "accepted"
+	APIKeyOutcomeRateLimited      APIKeyOutcome = "rate_limited"
+	APIKeyOutcomeOriginNotAllowed APIKeyOutcome = "origin_not_allowed"
+)
+
+// APIKeyUsage represents the number of events sent with an API key in a day,
+// by their outcome.
+type APIKeyUsage struct {
+	Day              time.Time
+	Accepted         int64
+	RateLimited      int64
+	OriginNotAllowed int64
+}
+
+// RateLimitDecision is the result of taking the tokens of some events from
+// the bucket of an API key, RetryAfter is how long to wait for the tokens
+// when the events are not allowed.
+type RateLimitDecision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
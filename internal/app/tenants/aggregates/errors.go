@@ -0,0 +1,18 @@
+package aggregates
+
+import "errors"
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrOrganizationAlreadyExists = errors.New("organization already exists")
+	ErrInvalidOrganization       = errors.New("invalid organization")
+	ErrProjectNotFound           = errors.New("project not found")
+	ErrProjectAlreadyExists      = errors.New("project already exists")
+	ErrInvalidProject            = errors.New("invalid project")
+	ErrUnauthorized              = errors.New("unauthorized")
+	ErrAPIKeyNotFound            = errors.New("api key not found")
+	ErrInvalidAPIKey             = errors.New("invalid api key")
+	ErrOriginNotAllowed          = errors.New("origin not allowed")
+	ErrRateLimited               = errors.New("rate limited")
+	ErrMissingTenant             = errors.New("missing tenant")
+)
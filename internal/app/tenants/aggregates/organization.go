@@ -0,0 +1,36 @@
+package aggregates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slugPattern is the format of the organizations and projects slugs, they
+// are used in URLs and bucket names so they are kept lowercase ASCII.
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Organization represents a customer of the collector, it owns projects.
+type Organization struct {
+	ID        int64
+	Name      string
+	Slug      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks that the organization is named and has a valid slug.
+func (o Organization) Validate() error {
+	if strings.TrimSpace(o.Name) == "" {
+		return fmt.Errorf("organization name can't be empty: %w",
+			ErrInvalidOrganization)
+	}
+
+	if !slugPattern.MatchString(o.Slug) {
+		return fmt.Errorf("organization slug %q must be lowercase letters, digits or dashes: %w",
+			o.Slug, ErrInvalidOrganization)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package aggregates
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// projectTokenPrefix makes the project tokens easy to spot in configs and
+	// secret scanners.
+	projectTokenPrefix = "enc_"
+
+	// projectTokenLength is the number of random bytes of a project token.
+	projectTokenLength = 32
+)
+
+// Project represents a tenant of the collector, every program, agent event,
+// metric and configuration belongs to a project. Only the hash of the
+// project token is kept.
+type Project struct {
+	ID             int64
+	OrganizationID int64
+	Name           string
+	Slug           string
+	TokenHash      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Validate checks that the project belongs to an organization, is named and
+// has a valid slug.
+func (p Project) Validate() error {
+	if p.OrganizationID <= 0 {
+		return fmt.Errorf("project organization is required: %w", ErrInvalidProject)
+	}
+
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("project name can't be empty: %w", ErrInvalidProject)
+	}
+
+	if !slugPattern.MatchString(p.Slug) {
+		return fmt.Errorf("project slug %q must be lowercase letters, digits or dashes: %w",
+			p.Slug, ErrInvalidProject)
+	}
+
+	return nil
+}
+
+// Tenant returns the tenant the project identifies.
+func (p Project) Tenant() Tenant {
+	return Tenant{
+		OrganizationID: p.OrganizationID,
+		ProjectID:      p.ID,
+	}
+}
+
+// NewProjectToken generates a new random project token, it returns the
+// token, that has to be handed to the user, and its hash to store.
+func NewProjectToken() (string, string, error) {
+	bytes := make([]byte, projectTokenLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("rand.Read, err: %w", err)
+	}
+
+	token := projectTokenPrefix + hex.EncodeToString(bytes)
+
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of a token, tokens are random
+// enough to not need a salt.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package aggregates
+
+import "context"
+
+const (
+	// DefaultOrganizationID is the organization created by the migrations,
+	// it owns every row that predates the multi-tenancy.
+	DefaultOrganizationID int64 = 1
+
+	// DefaultProjectID is the project created by the migrations, it's the
+	// tenant of every request when tenancy is disabled.
+	DefaultProjectID int64 = 1
+)
+
+// Tenant represents the project, and its organization, a request or a
+// background job is acting on behalf of.
+type Tenant struct {
+	OrganizationID int64
+	ProjectID      int64
+}
+
+// DefaultTenant returns the tenant created by the migrations.
+func DefaultTenant() Tenant {
+	return Tenant{
+		OrganizationID: DefaultOrganizationID,
+		ProjectID:      DefaultProjectID,
+	}
+}
+
+type tenantContextKey struct{}
+
+// allProjects is carried by the contexts acting on every project instead of
+// a tenant, see WithAllProjects.
+type allProjects struct{}
+
+// NewContext returns a copy of ctx carrying the tenant.
+func NewContext(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// FromContext returns the tenant carried by ctx, if any.
+func FromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// ProjectIDFromContext returns the project ID of the tenant carried by ctx,
+// or 0 when ctx acts on every project, which repositories don't scope their
+// queries for. Any other context fails with ErrMissingTenant, so a request
+// or a job that forgot to set its tenant can't read every project's data.
+func ProjectIDFromContext(ctx context.Context) (int64, error) {
+	switch value := ctx.Value(tenantContextKey{}).(type) {
+	case allProjects:
+		return 0, nil
+
+	case Tenant:
+		if value.ProjectID != 0 {
+			return value.ProjectID, nil
+		}
+	}
+
+	return 0, ErrMissingTenant
+}
+
+// ProjectIDOrDefault returns the project ID of the tenant carried by ctx,
+// DefaultProjectID when there is none. It's used to own the new rows.
+func ProjectIDOrDefault(ctx context.Context) int64 {
+	if tenant, ok := FromContext(ctx); ok && tenant.ProjectID != 0 {
+		return tenant.ProjectID
+	}
+
+	return DefaultProjectID
+}
+
+// WithAllProjects returns a copy of ctx acting on every project instead of
+// its tenant, if any. The background jobs working across tenants use it to
+// look up what they process, which they then process with WithProjectID.
+func WithAllProjects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, allProjects{})
+}
+
+// WithProjectID returns a copy of ctx scoped to the project, the background
+// jobs use it to act on behalf of the project owning what they process.
+func WithProjectID(ctx context.Context, projectID int64) context.Context {
+	tenant, _ := FromContext(ctx)
+	if tenant.ProjectID != projectID {
+		tenant = Tenant{ProjectID: projectID}
+	}
+
+	return NewContext(ctx, tenant)
+}
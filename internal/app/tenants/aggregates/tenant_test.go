@@ -0,0 +1,96 @@
+package aggregates
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProjectIDFromContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		projectID int64
+		err       error
+	}{
+		{
+			name: "no tenant",
+			ctx:  context.Background(),
+			err:  ErrMissingTenant,
+		},
+		{
+			name: "tenant without project",
+			ctx:  NewContext(context.Background(), Tenant{OrganizationID: 1}),
+			err:  ErrMissingTenant,
+		},
+		{
+			name:      "tenant",
+			ctx:       NewContext(context.Background(), Tenant{ProjectID: 7}),
+			projectID: 7,
+		},
+		{
+			name: "all projects",
+			ctx:  WithAllProjects(context.Background()),
+		},
+		{
+			name: "all projects overriding a tenant",
+			ctx: WithAllProjects(
+				NewContext(context.Background(), Tenant{ProjectID: 7})),
+		},
+		{
+			name:      "project of an all projects context",
+			ctx:       WithProjectID(WithAllProjects(context.Background()), 7),
+			projectID: 7,
+		},
+		{
+			name: "zero project",
+			ctx:  WithProjectID(WithAllProjects(context.Background()), 0),
+			err:  ErrMissingTenant,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projectID, err := ProjectIDFromContext(test.ctx)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+
+			if projectID != test.projectID {
+				t.Errorf("projectID = %d, want %d", projectID, test.projectID)
+			}
+		})
+	}
+}
+
+func TestProjectIDOrDefault(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		projectID int64
+	}{
+		{
+			name:      "no tenant",
+			ctx:       context.Background(),
+			projectID: DefaultProjectID,
+		},
+		{
+			name:      "all projects",
+			ctx:       WithAllProjects(context.Background()),
+			projectID: DefaultProjectID,
+		},
+		{
+			name:      "tenant",
+			ctx:       NewContext(context.Background(), Tenant{ProjectID: 7}),
+			projectID: 7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if projectID := ProjectIDOrDefault(test.ctx); projectID != test.projectID {
+				t.Errorf("projectID = %d, want %d", projectID, test.projectID)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type apiKeyCreatorRepository interface {
+	InsertAPIKey(context.Context, aggregates.APIKey) (aggregates.APIKey, error)
+}
+
+// APIKeyCreator defines the methods needed to create API keys.
+type APIKeyCreator struct {
+	apiKeyCreatorRepository apiKeyCreatorRepository
+}
+
+// NewAPIKeyCreator initializes a new APIKeyCreator.
+func NewAPIKeyCreator(
+	apiKeyCreatorRepository apiKeyCreatorRepository) *APIKeyCreator {
+	return &APIKeyCreator{
+		apiKeyCreatorRepository: apiKeyCreatorRepository,
+	}
+}
+
+// Create validates and creates a new API key for the project in ctx, it
+// returns the API key along with the key, which is not stored and can't be
+// retrieved afterwards.
+func (kc *APIKeyCreator) Create(ctx context.Context,
+	apiKey aggregates.APIKey) (aggregates.APIKey, string, error) {
+	apiKey.ProjectID = aggregates.ProjectIDOrDefault(ctx)
+
+	if apiKey.RateLimit == 0 {
+		apiKey.RateLimit = aggregates.DefaultAPIKeyRateLimit
+	}
+
+	if apiKey.Burst == 0 {
+		apiKey.Burst = aggregates.DefaultAPIKeyBurst
+	}
+
+	if err := apiKey.Validate(); err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf("apiKey.Validate, err: %w", err)
+	}
+
+	key, hint, keyHash, err := aggregates.NewAPIKey()
+	if err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf("aggregates.NewAPIKey, err: %w", err)
+	}
+	apiKey.Hint = hint
+	apiKey.KeyHash = keyHash
+
+	apiKey, err = kc.apiKeyCreatorRepository.InsertAPIKey(ctx, apiKey)
+	if err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf(
+			"kc.apiKeyCreatorRepository.InsertAPIKey, err: %w", err)
+	}
+
+	return apiKey, key, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type apiKeyGetterRepository interface {
+	SelectAPIKeys(context.Context) ([]aggregates.APIKey, error)
+	SelectAPIKeyByID(context.Context, int64) (aggregates.APIKey, error)
+}
+
+// APIKeyGetter defines the methods needed to get API keys.
+type APIKeyGetter struct {
+	apiKeyGetterRepository apiKeyGetterRepository
+}
+
+// NewAPIKeyGetter initializes a new APIKeyGetter.
+func NewAPIKeyGetter(
+	apiKeyGetterRepository apiKeyGetterRepository) *APIKeyGetter {
+	return &APIKeyGetter{
+		apiKeyGetterRepository: apiKeyGetterRepository,
+	}
+}
+
+// GetAPIKeys gets the API keys of the project in ctx.
+func (kg *APIKeyGetter) GetAPIKeys(
+	ctx context.Context) ([]aggregates.APIKey, error) {
+	apiKeys, err := kg.apiKeyGetterRepository.SelectAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"kg.apiKeyGetterRepository.SelectAPIKeys, err: %w", err)
+	}
+
+	return apiKeys, nil
+}
+
+// GetAPIKey gets an API key by its ID.
+func (kg *APIKeyGetter) GetAPIKey(
+	ctx context.Context, id int64) (aggregates.APIKey, error) {
+	apiKey, err := kg.apiKeyGetterRepository.SelectAPIKeyByID(ctx, id)
+	if err != nil {
+		return aggregates.APIKey{}, fmt.Errorf(
+			"kg.apiKeyGetterRepository.SelectAPIKeyByID, err: %w", err)
+	}
+
+	return apiKey, nil
+}
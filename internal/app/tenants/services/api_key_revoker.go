@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+type apiKeyRevokerRepository interface {
+	RevokeAPIKey(context.Context, int64) error
+}
+
+// APIKeyRevoker defines the methods needed to revoke API keys.
+type APIKeyRevoker struct {
+	apiKeyRevokerRepository apiKeyRevokerRepository
+}
+
+// NewAPIKeyRevoker initializes a new APIKeyRevoker.
+func NewAPIKeyRevoker(
+	apiKeyRevokerRepository apiKeyRevokerRepository) *APIKeyRevoker {
+	return &APIKeyRevoker{
+		apiKeyRevokerRepository: apiKeyRevokerRepository,
+	}
+}
+
+// Revoke revokes an API key, the events sent with it are rejected as soon as
+// the authorizers caches expire.
+func (kr *APIKeyRevoker) Revoke(ctx context.Context, id int64) error {
+	if err := kr.apiKeyRevokerRepository.RevokeAPIKey(ctx, id); err != nil {
+		return fmt.Errorf(
+			"kr.apiKeyRevokerRepository.RevokeAPIKey, err: %w", err)
+	}
+
+	return nil
+}
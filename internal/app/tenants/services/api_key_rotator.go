@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type apiKeyRotatorRepository interface {
+	SelectAPIKeyByID(context.Context, int64) (aggregates.APIKey, error)
+	RotateAPIKey(context.Context, int64, time.Time, aggregates.APIKey) (aggregates.APIKey, error)
+}
+
+// APIKeyRotator defines the methods needed to rotate API keys.
+type APIKeyRotator struct {
+	apiKeyRotatorRepository apiKeyRotatorRepository
+}
+
+// NewAPIKeyRotator initializes a new APIKeyRotator.
+func NewAPIKeyRotator(
+	apiKeyRotatorRepository apiKeyRotatorRepository) *APIKeyRotator {
+	return &APIKeyRotator{
+		apiKeyRotatorRepository: apiKeyRotatorRepository,
+	}
+}
+
+// Rotate replaces an API key with a new one with the same settings, the old
+// key keeps working for the grace period so the agents can be updated
+// without dropping events. It returns the new API key along with its key.
+func (kr *APIKeyRotator) Rotate(ctx context.Context,
+	id int64, grace time.Duration) (aggregates.APIKey, string, error) {
+	if grace < 0 {
+		return aggregates.APIKey{}, "", fmt.Errorf(
+			"api key rotation grace period can't be negative: %w",
+			aggregates.ErrInvalidAPIKey)
+	}
+
+	oldAPIKey, err := kr.apiKeyRotatorRepository.SelectAPIKeyByID(ctx, id)
+	if err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf(
+			"kr.apiKeyRotatorRepository.SelectAPIKeyByID, err: %w", err)
+	}
+
+	if !oldAPIKey.Active(time.Now()) {
+		return aggregates.APIKey{}, "", fmt.Errorf(
+			"api key %d is revoked or expired: %w", id, aggregates.ErrInvalidAPIKey)
+	}
+
+	key, hint, keyHash, err := aggregates.NewAPIKey()
+	if err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf("aggregates.NewAPIKey, err: %w", err)
+	}
+
+	apiKey := aggregates.APIKey{
+		OrganizationID: oldAPIKey.OrganizationID,
+		ProjectID:      oldAPIKey.ProjectID,
+		Name:           oldAPIKey.Name,
+		Hint:           hint,
+		KeyHash:        keyHash,
+		AllowedOrigins: oldAPIKey.AllowedOrigins,
+		RateLimit:      oldAPIKey.RateLimit,
+		Burst:          oldAPIKey.Burst,
+	}
+
+	apiKey, err = kr.apiKeyRotatorRepository.RotateAPIKey(
+		ctx, oldAPIKey.ID, time.Now().UTC().Add(grace), apiKey)
+	if err != nil {
+		return aggregates.APIKey{}, "", fmt.Errorf(
+			"kr.apiKeyRotatorRepository.RotateAPIKey, err: %w", err)
+	}
+
+	return apiKey, key, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// maxUsageDays is the number of days the API keys usage counters are kept.
+const maxUsageDays = 31
+
+type apiKeyUsageRepository interface {
+	SelectAPIKeyByID(context.Context, int64) (aggregates.APIKey, error)
+}
+
+type usageCounter interface {
+	SelectUsage(context.Context, int64, []time.Time) ([]aggregates.APIKeyUsage, error)
+}
+
+// APIKeyUsageGetter defines the methods needed to get the usage of API keys.
+type APIKeyUsageGetter struct {
+	apiKeyUsageRepository apiKeyUsageRepository
+	usageCounter          usageCounter
+}
+
+// NewAPIKeyUsageGetter initializes a new APIKeyUsageGetter.
+func NewAPIKeyUsageGetter(
+	apiKeyUsageRepository apiKeyUsageRepository,
+	usageCounter usageCounter,
+) *APIKeyUsageGetter {
+	return &APIKeyUsageGetter{
+		apiKeyUsageRepository: apiKeyUsageRepository,
+		usageCounter:          usageCounter,
+	}
+}
+
+// GetUsage gets the daily usage of an API key of the project in ctx over the
+// last days, the current day included.
+func (kug *APIKeyUsageGetter) GetUsage(ctx context.Context,
+	id int64, days int) ([]aggregates.APIKeyUsage, error) {
+	if days <= 0 || days > maxUsageDays {
+		return nil, fmt.Errorf("usage days must be between 1 and %d: %w",
+			maxUsageDays, aggregates.ErrInvalidAPIKey)
+	}
+
+	if _, err := kug.apiKeyUsageRepository.SelectAPIKeyByID(ctx, id); err != nil {
+		return nil, fmt.Errorf(
+			"kug.apiKeyUsageRepository.SelectAPIKeyByID, err: %w", err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	dates := make([]time.Time, days)
+	for i := range dates {
+		dates[i] = today.AddDate(0, 0, i-days+1)
+	}
+
+	usage, err := kug.usageCounter.SelectUsage(ctx, id, dates)
+	if err != nil {
+		return nil, fmt.Errorf("kug.usageCounter.SelectUsage, err: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// apiKeysCacheTTL is how long the API keys are cached, which is also how
+// long a revoked key can keep sending events.
+const apiKeysCacheTTL = 30 * time.Second
+
+type ingestionAuthorizerRepository interface {
+	SelectAPIKeyByHash(context.Context, string) (aggregates.APIKey, error)
+}
+
+// rateLimiter limits the events of the API keys with a token bucket shared
+// by every collector instance.
+type rateLimiter interface {
+	Take(context.Context, int64, float64, int64, int64) (aggregates.RateLimitDecision, error)
+}
+
+type usageIncrementer interface {
+	IncrementUsage(context.Context, int64, aggregates.APIKeyOutcome, int64) error
+}
+
+// cachedAPIKey is an API key lookup, found is false for unknown keys so
+// they don't hit the database on every event.
+type cachedAPIKey struct {
+	apiKey    aggregates.APIKey
+	found     bool
+	fetchedAt time.Time
+}
+
+// IngestionAuthorizer authorizes the agents events sent with an API key.
+type IngestionAuthorizer struct {
+	ingestionAuthorizerRepository ingestionAuthorizerRepository
+	rateLimiter                   rateLimiter
+	usageIncrementer              usageIncrementer
+
+	mu        sync.Mutex
+	apiKeys   map[string]cachedAPIKey
+	evictedAt time.Time
+}
+
+// NewIngestionAuthorizer initializes a new IngestionAuthorizer.
+func NewIngestionAuthorizer(
+	ingestionAuthorizerRepository ingestionAuthorizerRepository,
+	rateLimiter rateLimiter,
+	usageIncrementer usageIncrementer,
+) *IngestionAuthorizer {
+	return &IngestionAuthorizer{
+		ingestionAuthorizerRepository: ingestionAuthorizerRepository,
+		rateLimiter:                   rateLimiter,
+		usageIncrementer:              usageIncrementer,
+		apiKeys:                       make(map[string]cachedAPIKey),
+	}
+}
+
+// Authorize returns the API key when it's active and allows the origin, it
+// returns aggregates.ErrUnauthorized or aggregates.ErrOriginNotAllowed
+// otherwise. The events are rate limited once they are counted, see
+// TakeEvents.
+func (ia *IngestionAuthorizer) Authorize(ctx context.Context,
+	key string, origin string) (aggregates.APIKey, error) {
+	if key == "" {
+		return aggregates.APIKey{}, aggregates.ErrUnauthorized
+	}
+
+	apiKey, err := ia.apiKey(ctx, key)
+	if err != nil {
+		return aggregates.APIKey{}, fmt.Errorf("ia.apiKey, err: %w", err)
+	}
+
+	if !apiKey.Active(time.Now()) {
+		return aggregates.APIKey{}, aggregates.ErrUnauthorized
+	}
+
+	if !apiKey.AllowsOrigin(origin) {
+		ia.incrementUsage(ctx, apiKey.ID, aggregates.APIKeyOutcomeOriginNotAllowed, 1)
+		return aggregates.APIKey{}, fmt.Errorf("origin %q: %w",
+			origin, aggregates.ErrOriginNotAllowed)
+	}
+
+	return apiKey, nil
+}
+
+// TakeEvents takes a token per event from the rate limit of the API key the
+// request was authorized with, the requests sent without one aren't rate
+// limited. It returns aggregates.ErrRateLimited when the events exceed the
+// tokens left, along with how long to wait before retrying.
+func (ia *IngestionAuthorizer) TakeEvents(
+	ctx context.Context, events int) (time.Duration, error) {
+	apiKey, ok := aggregates.APIKeyFromContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+
+	decision, err := ia.rateLimiter.Take(
+		ctx, apiKey.ID, apiKey.RateLimit, apiKey.Burst, int64(events))
+	if err != nil {
+		return 0, fmt.Errorf("ia.rateLimiter.Take, err: %w", err)
+	}
+
+	if !decision.Allowed {
+		ia.incrementUsage(ctx, apiKey.ID,
+			aggregates.APIKeyOutcomeRateLimited, int64(events))
+		return decision.RetryAfter, aggregates.ErrRateLimited
+	}
+
+	ia.incrementUsage(ctx, apiKey.ID, aggregates.APIKeyOutcomeAccepted, int64(events))
+
+	return 0, nil
+}
+
+// apiKey returns the API key from the cache, looking it up when it's not
+// cached or its entry is stale.
+func (ia *IngestionAuthorizer) apiKey(
+	ctx context.Context, key string) (aggregates.APIKey, error) {
+	keyHash := aggregates.HashToken(key)
+
+	ia.mu.Lock()
+	cached, ok := ia.apiKeys[keyHash]
+	ia.mu.Unlock()
+
+	if !ok || time.Since(cached.fetchedAt) > apiKeysCacheTTL {
+		apiKey, err := ia.ingestionAuthorizerRepository.SelectAPIKeyByHash(ctx, keyHash)
+		if err != nil && !errors.Is(err, aggregates.ErrAPIKeyNotFound) {
+			return aggregates.APIKey{}, fmt.Errorf(
+				"ia.ingestionAuthorizerRepository.SelectAPIKeyByHash, err: %w", err)
+		}
+
+		cached = cachedAPIKey{
+			apiKey:    apiKey,
+			found:     err == nil,
+			fetchedAt: time.Now(),
+		}
+
+		ia.mu.Lock()
+		ia.evictStale()
+		ia.apiKeys[keyHash] = cached
+		ia.mu.Unlock()
+	}
+
+	if !cached.found {
+		return aggregates.APIKey{}, aggregates.ErrUnauthorized
+	}
+
+	return cached.apiKey, nil
+}
+
+// evictStale removes the stale entries of the cache, at most once per TTL,
+// so random keys can't grow it unbounded. It must be called with the mutex
+// held.
+func (ia *IngestionAuthorizer) evictStale() {
+	if time.Since(ia.evictedAt) < apiKeysCacheTTL {
+		return
+	}
+	ia.evictedAt = time.Now()
+
+	for keyHash, cached := range ia.apiKeys {
+		if time.Since(cached.fetchedAt) > apiKeysCacheTTL {
+			delete(ia.apiKeys, keyHash)
+		}
+	}
+}
+
+// incrementUsage increments the usage counters of an API key, the counters
+// are best effort and never reject an event.
+func (ia *IngestionAuthorizer) incrementUsage(ctx context.Context,
+	apiKeyID int64, outcome aggregates.APIKeyOutcome, events int64) {
+	if err := ia.usageIncrementer.IncrementUsage(
+		ctx, apiKeyID, outcome, events); err != nil {
+		slog.Error("error while incrementing the api key usage",
+			slog.Int64("api_key_id", apiKeyID), slog.Any("error", err))
+	}
+}
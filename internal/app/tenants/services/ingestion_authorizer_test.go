@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// tokenBucket is a rate limiter with the given tokens left, never refilled.
+type tokenBucket struct {
+	tokens int64
+}
+
+func (tb *tokenBucket) Take(_ context.Context, _ int64, rate float64,
+	_, tokens int64) (aggregates.RateLimitDecision, error) {
+	if tokens > tb.tokens {
+		return aggregates.RateLimitDecision{
+			RetryAfter: time.Duration(float64(tokens-tb.tokens)/rate) * time.Second,
+		}, nil
+	}
+
+	tb.tokens -= tokens
+
+	return aggregates.RateLimitDecision{Allowed: true}, nil
+}
+
+// usageCounters counts the events of every outcome.
+type usageCounters map[aggregates.APIKeyOutcome]int64
+
+func (uc usageCounters) IncrementUsage(_ context.Context, _ int64,
+	outcome aggregates.APIKeyOutcome, events int64) error {
+	uc[outcome] += events
+	return nil
+}
+
+func TestTakeEvents(t *testing.T) {
+	apiKey := aggregates.APIKey{ID: 1, ProjectID: 7, RateLimit: 1, Burst: 100}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		events     []int
+		err        error
+		retryAfter time.Duration
+		usage      usageCounters
+	}{
+		{
+			name:   "batches within the tokens left",
+			ctx:    aggregates.NewAPIKeyContext(context.Background(), apiKey),
+			events: []int{60, 40},
+			usage:  usageCounters{aggregates.APIKeyOutcomeAccepted: 100},
+		},
+		{
+			name:       "batch exceeding the tokens left",
+			ctx:        aggregates.NewAPIKeyContext(context.Background(), apiKey),
+			events:     []int{60, 50},
+			err:        aggregates.ErrRateLimited,
+			retryAfter: 10 * time.Second,
+			usage: usageCounters{
+				aggregates.APIKeyOutcomeAccepted:    60,
+				aggregates.APIKeyOutcomeRateLimited: 50,
+			},
+		},
+		{
+			name:   "request without api key",
+			ctx:    context.Background(),
+			events: []int{1000},
+			usage:  usageCounters{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usage := usageCounters{}
+			authorizer := NewIngestionAuthorizer(
+				nil, &tokenBucket{tokens: apiKey.Burst}, usage)
+
+			var (
+				retryAfter time.Duration
+				err        error
+			)
+			for _, events := range test.events {
+				retryAfter, err = authorizer.TakeEvents(test.ctx, events)
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("err = %v, want %v", err, test.err)
+			}
+
+			if retryAfter != test.retryAfter {
+				t.Errorf("retryAfter = %s, want %s", retryAfter, test.retryAfter)
+			}
+
+			if len(usage) != len(test.usage) {
+				t.Fatalf("usage = %v, want %v", usage, test.usage)
+			}
+
+			for outcome, events := range test.usage {
+				if usage[outcome] != events {
+					t.Errorf("usage[%s] = %d, want %d", outcome, usage[outcome], events)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type organizationCreatorRepository interface {
+	InsertOrganization(context.Context,
+		aggregates.Organization) (aggregates.Organization, error)
+}
+
+// OrganizationCreator defines the methods needed to create organizations.
+type OrganizationCreator struct {
+	organizationCreatorRepository organizationCreatorRepository
+}
+
+// NewOrganizationCreator initializes a new OrganizationCreator.
+func NewOrganizationCreator(
+	organizationCreatorRepository organizationCreatorRepository) *OrganizationCreator {
+	return &OrganizationCreator{
+		organizationCreatorRepository: organizationCreatorRepository,
+	}
+}
+
+// Create validates and creates a new organization.
+func (oc *OrganizationCreator) Create(ctx context.Context,
+	organization aggregates.Organization) (aggregates.Organization, error) {
+	if err := organization.Validate(); err != nil {
+		return aggregates.Organization{}, fmt.Errorf(
+			"organization.Validate, err: %w", err)
+	}
+
+	organization, err := oc.organizationCreatorRepository.InsertOrganization(
+		ctx, organization)
+	if err != nil {
+		return aggregates.Organization{}, fmt.Errorf(
+			"oc.organizationCreatorRepository.InsertOrganization, err: %w", err)
+	}
+
+	return organization, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type organizationGetterRepository interface {
+	SelectAllOrganizations(context.Context) ([]aggregates.Organization, error)
+	SelectOrganizationByID(context.Context, int64) (aggregates.Organization, error)
+}
+
+// OrganizationGetter defines the methods needed to get organizations.
+type OrganizationGetter struct {
+	organizationGetterRepository organizationGetterRepository
+}
+
+// NewOrganizationGetter initializes a new OrganizationGetter.
+func NewOrganizationGetter(
+	organizationGetterRepository organizationGetterRepository) *OrganizationGetter {
+	return &OrganizationGetter{
+		organizationGetterRepository: organizationGetterRepository,
+	}
+}
+
+// GetOrganizations gets all organizations.
+func (og *OrganizationGetter) GetOrganizations(
+	ctx context.Context) ([]aggregates.Organization, error) {
+	organizations, err := og.organizationGetterRepository.SelectAllOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"og.organizationGetterRepository.SelectAllOrganizations, err: %w", err)
+	}
+
+	return organizations, nil
+}
+
+// GetOrganization gets an organization by its ID.
+func (og *OrganizationGetter) GetOrganization(
+	ctx context.Context, id int64) (aggregates.Organization, error) {
+	organization, err := og.organizationGetterRepository.SelectOrganizationByID(ctx, id)
+	if err != nil {
+		return aggregates.Organization{}, fmt.Errorf(
+			"og.organizationGetterRepository.SelectOrganizationByID, err: %w", err)
+	}
+
+	return organization, nil
+}
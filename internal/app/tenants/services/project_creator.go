@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type projectCreatorRepository interface {
+	SelectOrganizationByID(context.Context, int64) (aggregates.Organization, error)
+	InsertProject(context.Context, aggregates.Project) (aggregates.Project, error)
+}
+
+// bucketsProvisioner creates the storage of a project when the metrics are
+// isolated in a bucket per project.
+type bucketsProvisioner interface {
+	CreateProjectBuckets(context.Context, int64) error
+}
+
+// ProjectCreator defines the methods needed to create projects.
+type ProjectCreator struct {
+	projectCreatorRepository projectCreatorRepository
+	bucketsProvisioner       bucketsProvisioner
+}
+
+// NewProjectCreator initializes a new ProjectCreator.
+func NewProjectCreator(
+	projectCreatorRepository projectCreatorRepository,
+	bucketsProvisioner bucketsProvisioner,
+) *ProjectCreator {
+	return &ProjectCreator{
+		projectCreatorRepository: projectCreatorRepository,
+		bucketsProvisioner:       bucketsProvisioner,
+	}
+}
+
+// Create validates and creates a new project, it returns the project along
+// with its token, which is not stored and can't be retrieved afterwards.
+func (pc *ProjectCreator) Create(ctx context.Context,
+	project aggregates.Project) (aggregates.Project, string, error) {
+	if err := project.Validate(); err != nil {
+		return aggregates.Project{}, "", fmt.Errorf("project.Validate, err: %w", err)
+	}
+
+	if _, err := pc.projectCreatorRepository.SelectOrganizationByID(
+		ctx, project.OrganizationID); err != nil {
+		return aggregates.Project{}, "", fmt.Errorf(
+			"pc.projectCreatorRepository.SelectOrganizationByID, err: %w", err)
+	}
+
+	token, tokenHash, err := aggregates.NewProjectToken()
+	if err != nil {
+		return aggregates.Project{}, "", fmt.Errorf(
+			"aggregates.NewProjectToken, err: %w", err)
+	}
+	project.TokenHash = tokenHash
+
+	project, err = pc.projectCreatorRepository.InsertProject(ctx, project)
+	if err != nil {
+		return aggregates.Project{}, "", fmt.Errorf(
+			"pc.projectCreatorRepository.InsertProject, err: %w", err)
+	}
+
+	if err := pc.bucketsProvisioner.CreateProjectBuckets(ctx, project.ID); err != nil {
+		return aggregates.Project{}, "", fmt.Errorf(
+			"pc.bucketsProvisioner.CreateProjectBuckets, err: %w", err)
+	}
+
+	return project, token, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type projectGetterRepository interface {
+	SelectProjectsByOrganizationID(context.Context, int64) ([]aggregates.Project, error)
+	SelectProjectByID(context.Context, int64) (aggregates.Project, error)
+}
+
+// ProjectGetter defines the methods needed to get projects.
+type ProjectGetter struct {
+	projectGetterRepository projectGetterRepository
+}
+
+// NewProjectGetter initializes a new ProjectGetter.
+func NewProjectGetter(
+	projectGetterRepository projectGetterRepository) *ProjectGetter {
+	return &ProjectGetter{
+		projectGetterRepository: projectGetterRepository,
+	}
+}
+
+// GetProjects gets the projects of an organization.
+func (pg *ProjectGetter) GetProjects(
+	ctx context.Context, organizationID int64) ([]aggregates.Project, error) {
+	projects, err := pg.projectGetterRepository.SelectProjectsByOrganizationID(
+		ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"pg.projectGetterRepository.SelectProjectsByOrganizationID, err: %w", err)
+	}
+
+	return projects, nil
+}
+
+// GetProject gets a project by its ID.
+func (pg *ProjectGetter) GetProject(
+	ctx context.Context, id int64) (aggregates.Project, error) {
+	project, err := pg.projectGetterRepository.SelectProjectByID(ctx, id)
+	if err != nil {
+		return aggregates.Project{}, fmt.Errorf(
+			"pg.projectGetterRepository.SelectProjectByID, err: %w", err)
+	}
+
+	return project, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+type resolverRepository interface {
+	SelectProjectByTokenHash(context.Context, string) (aggregates.Project, error)
+	SelectProjectByID(context.Context, int64) (aggregates.Project, error)
+}
+
+// Resolver resolves the tenant of a request from its credentials.
+type Resolver struct {
+	resolverRepository resolverRepository
+}
+
+// NewResolver initializes a new Resolver.
+func NewResolver(resolverRepository resolverRepository) *Resolver {
+	return &Resolver{
+		resolverRepository: resolverRepository,
+	}
+}
+
+// Resolve returns the tenant of the project the token belongs to, it returns
+// aggregates.ErrUnauthorized when the token doesn't belong to any project.
+func (r *Resolver) Resolve(
+	ctx context.Context, token string) (aggregates.Tenant, error) {
+	if token == "" {
+		return aggregates.Tenant{}, aggregates.ErrUnauthorized
+	}
+
+	project, err := r.resolverRepository.SelectProjectByTokenHash(
+		ctx, aggregates.HashToken(token))
+	if err != nil {
+		if errors.Is(err, aggregates.ErrProjectNotFound) {
+			return aggregates.Tenant{}, aggregates.ErrUnauthorized
+		}
+
+		return aggregates.Tenant{}, fmt.Errorf(
+			"r.resolverRepository.SelectProjectByTokenHash, err: %w", err)
+	}
+
+	return project.Tenant(), nil
+}
+
+// ResolveProject returns the tenant of the project with the given ID, for
+// the principals authenticated by other means than a project token.
+func (r *Resolver) ResolveProject(
+	ctx context.Context, projectID int64) (aggregates.Tenant, error) {
+	project, err := r.resolverRepository.SelectProjectByID(ctx, projectID)
+	if err != nil {
+		return aggregates.Tenant{}, fmt.Errorf(
+			"r.resolverRepository.SelectProjectByID, err: %w", err)
+	}
+
+	return project.Tenant(), nil
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxBodyBytes is the maximum size of a request body once decompressed.
+	maxBodyBytes = 5 << 20
+
+	// maxBatchEvents is the maximum amount of events of a single request.
+	maxBatchEvents = 1000
+)
+
+var (
+	errUnsupportedEncoding = errors.New("unsupported content encoding")
+	errBodyTooLarge        = fmt.Errorf("body larger than %d bytes", maxBodyBytes)
+	errTooManyEvents       = fmt.Errorf("more than %d events", maxBatchEvents)
+	errEmptyBody           = errors.New("no events in the body")
+)
+
+// readBody reads the request body, decompressing it according to its
+// Content-Encoding, gzip and deflate are supported.
+func readBody(c *gin.Context) ([]byte, error) {
+	var reader io.Reader = c.Request.Body
+
+	switch encoding := strings.ToLower(strings.TrimSpace(
+		c.GetHeader("Content-Encoding"))); encoding {
+	case "", "identity":
+
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("gzip.NewReader: %w", err)
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+
+	case "deflate":
+		zlibReader, err := zlib.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("zlib.NewReader: %w", err)
+		}
+		defer zlibReader.Close()
+
+		reader = zlibReader
+
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedEncoding, encoding)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	if len(body) > maxBodyBytes {
+		return nil, errBodyTooLarge
+	}
+
+	return body, nil
+}
+
+// splitEvents splits a body into its raw events, whatever the content type
+// as beacons are sent as text/plain. The body is either a single JSON event,
+// a JSON array of events or NDJSON, one event per line. batch is false for
+// the single event bodies.
+func splitEvents(body []byte) (events []json.RawMessage, batch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, false, errEmptyBody
+	}
+
+	switch {
+	case body[0] == '[':
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, true, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		batch = true
+
+	case json.Valid(body):
+		events = []json.RawMessage{body}
+
+	default:
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			events = append(events, json.RawMessage(line))
+		}
+
+		batch = true
+	}
+
+	if len(events) == 0 {
+		return nil, batch, errEmptyBody
+	}
+
+	if len(events) > maxBatchEvents {
+		return nil, batch, errTooManyEvents
+	}
+
+	return events, batch, nil
+}
+
+// httpStatusFromBodyError maps the body errors to HTTP status codes.
+func httpStatusFromBodyError(err error) int {
+	switch {
+	case errors.Is(err, errUnsupportedEncoding):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, errBodyTooLarge), errors.Is(err, errTooManyEvents):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusBadRequest
+	}
+}
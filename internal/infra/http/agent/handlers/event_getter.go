@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// eventGetter defines the methods needed to look up the stored events.
+type eventGetter interface {
+	GetEvents(context.Context, aggregates.EventsFilter) ([]aggregates.Event, error)
+}
+
+// EventGetterHandler defines the dependencies to look up the stored events.
+type EventGetterHandler struct {
+	eventGetter eventGetter
+}
+
+// NewEventGetterHandler initializes a new EventGetterHandler.
+func NewEventGetterHandler(eventGetter eventGetter) *EventGetterHandler {
+	return &EventGetterHandler{
+		eventGetter: eventGetter,
+	}
+}
+
+// Handle is the handler function to look up the stored events, by the
+// "client_id" or "browser_id" query params, between the "start" and "end"
+// (RFC 3339) query params and up to the "limit" query param.
+func (egh *EventGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.EventsFilter{
+		ClientID:  c.Query("client_id"),
+		BrowserID: c.Query("browser_id"),
+	}
+
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = &start
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = &end
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	events, err := egh.eventGetter.GetEvents(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	httpEvents := make([]httpStoredEvent, len(events))
+	for i, event := range events {
+		httpEvents[i] = httpStoredEventFromAggregate(event)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": httpEvents})
+}
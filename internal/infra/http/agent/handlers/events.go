@@ -2,44 +2,146 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 )
 
 // eventsPublisher defines the methods needed to publish events.
 type eventsPublisher interface {
-	Publish(context.Context, aggregates.Event) error
+	PublishBatch(context.Context, []aggregates.Event) error
+}
+
+// eventsRateLimiter defines the methods needed to rate limit the events of
+// the API key the request was authorized with.
+type eventsRateLimiter interface {
+	TakeEvents(context.Context, int) (time.Duration, error)
 }
 
 // EventsCreatorHandler defines the dependencies to create events.
 type EventsCreatorHandler struct {
-	eventsPublisher eventsPublisher
+	eventsPublisher   eventsPublisher
+	eventsRateLimiter eventsRateLimiter
 }
 
 // NewEventsCreatorHandler initializes a new EventsCreatorHandler.
-func NewEventsCreatorHandler(
-	eventsPublisher eventsPublisher) *EventsCreatorHandler {
+func NewEventsCreatorHandler(eventsPublisher eventsPublisher,
+	eventsRateLimiter eventsRateLimiter) *EventsCreatorHandler {
 	return &EventsCreatorHandler{
-		eventsPublisher: eventsPublisher,
+		eventsPublisher:   eventsPublisher,
+		eventsRateLimiter: eventsRateLimiter,
 	}
 }
 
-// Handle is the handler function to create events
+// Handle is the handler function to create events, either a single JSON
+// event, a JSON array or NDJSON, optionally gzip or deflate compressed and
+// with any content type so navigator.sendBeacon can be used.
+//
+// Every event of a batch takes a token of the API key rate limit, a batch
+// exceeding the tokens left is rejected as a whole with a 429.
+//
+// Every event of a batch is validated on its own, the valid ones are
+// published even if others are rejected: the response is 201 when every
+// event was accepted, 207 when only some were and 400 when none were.
 func (ech *EventsCreatorHandler) Handle(c *gin.Context) {
-	var httpEventRequest httpEventRequest
-	if err := c.ShouldBindJSON(&httpEventRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	body, err := readBody(c)
+	if err != nil {
+		c.JSON(httpStatusFromBodyError(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := ech.eventsPublisher.Publish(
-		c.Request.Context(), httpEventRequest.ToAggregate()); err != nil {
+	rawEvents, batch, err := splitEvents(body)
+	if err != nil {
+		c.JSON(httpStatusFromBodyError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	retryAfter, err := ech.eventsRateLimiter.TakeEvents(
+		c.Request.Context(), len(rawEvents))
+	if err != nil {
+		if errors.Is(err, tenantsAggregates.ErrRateLimited) {
+			c.Header("Retry-After", strconv.FormatInt(
+				int64(math.Ceil(retryAfter.Seconds())), 10))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{})
+	projectID := tenantsAggregates.ProjectIDOrDefault(c.Request.Context())
+
+	response := httpEventsResponse{Errors: []httpEventError{}}
+	events := make([]aggregates.Event, 0, len(rawEvents))
+	for i, rawEvent := range rawEvents {
+		event, err := decodeEvent(rawEvent)
+		if err != nil {
+			response.reject(i, "", err)
+			continue
+		}
+
+		event = event.DropLargeBodies()
+		if err := event.Validate(); err != nil {
+			response.reject(i, event.ID, err)
+			continue
+		}
+
+		event.ProjectID = projectID
+		if event.Region == "" {
+			event.Region = regionFromHeaders(c.Request.Header)
+		}
+
+		events = append(events, event)
+	}
+
+	if !batch && response.Rejected > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": response.Errors[0].Error})
+		return
+	}
+
+	if err := ech.eventsPublisher.PublishBatch(c.Request.Context(), events); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response.Accepted = len(events)
+
+	switch {
+	case response.Rejected == 0:
+		c.JSON(http.StatusCreated, response)
+	case response.Accepted > 0:
+		c.JSON(http.StatusMultiStatus, response)
+	default:
+		c.JSON(http.StatusBadRequest, response)
+	}
+}
+
+// regionHeaders are the headers the CDNs in front of the collector set with
+// the country of the client.
+var regionHeaders = []string{
+	"CF-IPCountry",
+	"CloudFront-Viewer-Country",
+	"X-Vercel-IP-Country",
+}
+
+// regionFromHeaders returns the region of the client that sent the events
+// request, for the events whose agent doesn't know it.
+func regionFromHeaders(header http.Header) string {
+	for _, name := range regionHeaders {
+		// Cloudflare uses XX for unknown countries and T1 for Tor.
+		if value := header.Get(name); value != "" && value != "XX" && value != "T1" {
+			return strings.ToUpper(value)
+		}
+	}
+
+	return ""
 }
@@ -1,63 +1,89 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
 )
 
-// httpEventRequest represents the http version of an event coming from
-// browser/mobile, including both request and response data.
+// httpEventEnvelope represents the fields every version of the agent events
+// shares, to tell which version an event has to be decoded with. Events
+// without schema_version are version 1.
+type httpEventEnvelope struct {
+	SchemaVersion int `json:"schema_version"`
+}
+
+// httpEventRequest represents the current version of the agent events,
+// a versioned envelope identifying the agent around the event data and the
+// request and response of the RPC call. Region is the region of the client,
+// when the agent knows it.
 type httpEventRequest struct {
-	Event    httpEvent     `json:"event"`
-	Request  *httpRequest  `json:"request,omitempty"`
-	Response *httpResponse `json:"response,omitempty"`
+	SchemaVersion int            `json:"schema_version"`
+	Agent         string         `json:"agent"`
+	AgentVersion  string         `json:"agent_version"`
+	EventType     string         `json:"event_type"`
+	Region        string         `json:"region,omitempty"`
+	Event         httpEvent      `json:"event"`
+	Request       *httpRequest   `json:"request,omitempty"`
+	Response      *httpResponse  `json:"response,omitempty"`
+	Error         *httpCallError `json:"error,omitempty"`
 }
 
 // httpEvent represents the http version of an event coming from
-// browser/mobile, including both request and response data.
+// browser/mobile, EventTime is a unix timestamp in milliseconds.
 type httpEvent struct {
-	ID                string      `json:"id"`
-	BrowserID         string      `json:"browserId"`
-	ClientID          string      `json:"clientId"`
-	Handled           interface{} `json:"handled"`
-	ReplacesClientID  *string     `json:"replacesClientId,omitempty"`
-	ResultingClientID string      `json:"resultingClientId"`
-	EventTime         int64       `json:"eventTime"`
+	ID                string  `json:"id"`
+	BrowserID         string  `json:"browserId"`
+	ClientID          string  `json:"clientId"`
+	Handled           bool    `json:"handled"`
+	ReplacesClientID  *string `json:"replacesClientId,omitempty"`
+	ResultingClientID string  `json:"resultingClientId"`
+	EventTime         int64   `json:"eventTime"`
+}
+
+// httpCallError represents the error the agent reports for a call that
+// didn't get a response, a timeout or a network error.
+type httpCallError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
 
 // httpRequest struct represents the http version of a browser/mobile request.
 type httpRequest struct {
-	RequestTime    int64       `json:"requestTime"`
-	Body           *string     `json:"body,omitempty"`
-	BodyUsed       bool        `json:"bodyUsed"`
-	Cache          string      `json:"cache"`
-	Credentials    string      `json:"credentials"`
-	Destination    string      `json:"destination"`
-	Headers        interface{} `json:"headers"`
-	Integrity      string      `json:"integrity"`
-	Method         string      `json:"method"`
-	Mode           string      `json:"mode"`
-	Redirect       string      `json:"redirect"`
-	Referrer       string      `json:"referrer"`
-	ReferrerPolicy string      `json:"referrerPolicy"`
-	URL            string      `json:"url"`
-	Signal         interface{} `json:"signal"`
+	RequestTime    int64             `json:"requestTime"`
+	Body           *string           `json:"body,omitempty"`
+	BodyUsed       bool              `json:"bodyUsed"`
+	Cache          string            `json:"cache"`
+	Credentials    string            `json:"credentials"`
+	Destination    string            `json:"destination"`
+	Headers        map[string]string `json:"headers"`
+	Integrity      string            `json:"integrity"`
+	Method         string            `json:"method"`
+	Mode           string            `json:"mode"`
+	Redirect       string            `json:"redirect"`
+	Referrer       string            `json:"referrer"`
+	ReferrerPolicy string            `json:"referrerPolicy"`
+	URL            string            `json:"url"`
 }
 
 // httpResponse struct represents the http version of a browser/mobile
 // response.
 type httpResponse struct {
-	ResponseTime int64       `json:"responseTime"`
-	Body         *string     `json:"body,omitempty"`
-	BodyUsed     bool        `json:"bodyUsed"`
-	Headers      interface{} `json:"headers"`
-	Ok           bool        `json:"ok"`
-	Redirected   bool        `json:"redirected"`
-	Status       uint16      `json:"status"`
-	StatusText   string      `json:"statusText"`
-	ResponseType string      `json:"responseType"`
-	URL          string      `json:"url"`
+	ResponseTime int64             `json:"responseTime"`
+	Body         *string           `json:"body,omitempty"`
+	BodyUsed     bool              `json:"bodyUsed"`
+	Headers      map[string]string `json:"headers"`
+	Ok           bool              `json:"ok"`
+	Redirected   bool              `json:"redirected"`
+	Status       uint16            `json:"status"`
+	StatusText   string            `json:"statusText"`
+	ResponseType string            `json:"responseType"`
+	URL          string            `json:"url"`
 }
 
 func (her *httpEventRequest) ToAggregate() aggregates.Event {
@@ -71,23 +97,36 @@ func (her *httpEventRequest) ToAggregate() aggregates.Event {
 		response = her.Response.ToAggregate()
 	}
 
+	var eventError *aggregates.EventError
+	if her.Error != nil {
+		eventError = &aggregates.EventError{
+			Type:    her.Error.Type,
+			Message: her.Error.Message,
+		}
+	}
+
 	return aggregates.Event{
 		ID:                her.Event.ID,
+		SchemaVersion:     her.SchemaVersion,
+		Agent:             her.Agent,
+		AgentVersion:      her.AgentVersion,
+		EventType:         her.EventType,
+		Region:            her.Region,
 		BrowserID:         her.Event.BrowserID,
 		ClientID:          her.Event.ClientID,
 		Handled:           her.Event.Handled,
 		ReplacesClientID:  her.Event.ReplacesClientID,
 		ResultingClientID: her.Event.ResultingClientID,
-		EventTime:         time.Unix(0, her.Event.EventTime*int64(time.Millisecond)),
+		EventTime:         time.UnixMilli(her.Event.EventTime),
 		Request:           request,
 		Response:          response,
+		Error:             eventError,
 	}
 }
 
 func (hr *httpRequest) ToAggregate() *aggregates.Request {
-
 	return &aggregates.Request{
-		RequestTime:    time.Unix(0, hr.RequestTime*int64(time.Millisecond)),
+		RequestTime:    time.UnixMilli(hr.RequestTime),
 		Body:           hr.Body,
 		BodyUsed:       hr.BodyUsed,
 		Cache:          hr.Cache,
@@ -101,13 +140,12 @@ func (hr *httpRequest) ToAggregate() *aggregates.Request {
 		Referrer:       hr.Referrer,
 		ReferrerPolicy: hr.ReferrerPolicy,
 		URL:            hr.URL,
-		Signal:         hr.Signal,
 	}
 }
 
 func (hr *httpResponse) ToAggregate() *aggregates.Response {
 	return &aggregates.Response{
-		ResponseTime: time.Unix(0, hr.ResponseTime*int64(time.Millisecond)),
+		ResponseTime: time.UnixMilli(hr.ResponseTime),
 		Body:         hr.Body,
 		BodyUsed:     hr.BodyUsed,
 		Headers:      hr.Headers,
@@ -119,3 +157,205 @@ func (hr *httpResponse) ToAggregate() *aggregates.Response {
 		URL:          hr.URL,
 	}
 }
+
+// decodeEvent decodes a raw event of any supported schema version and
+// upgrades it to the current aggregate. The current version is decoded
+// strictly, unknown fields are rejected, while version 1 events are
+// decoded as leniently as they always were.
+func decodeEvent(rawEvent json.RawMessage) (aggregates.Event, error) {
+	var envelope httpEventEnvelope
+	if err := json.Unmarshal(rawEvent, &envelope); err != nil {
+		return aggregates.Event{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	switch envelope.SchemaVersion {
+	case 0, 1:
+		var httpEventRequestV1 httpEventRequestV1
+		if err := json.Unmarshal(rawEvent, &httpEventRequestV1); err != nil {
+			return aggregates.Event{}, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		return httpEventRequestV1.ToAggregate(), nil
+
+	case aggregates.CurrentSchemaVersion:
+		decoder := json.NewDecoder(bytes.NewReader(rawEvent))
+		decoder.DisallowUnknownFields()
+
+		var httpEventRequest httpEventRequest
+		if err := decoder.Decode(&httpEventRequest); err != nil {
+			return aggregates.Event{}, fmt.Errorf("decoder.Decode: %w", err)
+		}
+
+		if decoder.More() {
+			return aggregates.Event{}, errors.New("unexpected data after the event")
+		}
+
+		return httpEventRequest.ToAggregate(), nil
+
+	default:
+		return aggregates.Event{}, fmt.Errorf("schema version %d is not supported: %w",
+			envelope.SchemaVersion, aggregates.ErrInvalidEvent)
+	}
+}
+
+// httpEventsResponse represents the outcome of the events of a request.
+type httpEventsResponse struct {
+	Accepted int              `json:"accepted"`
+	Rejected int              `json:"rejected"`
+	Errors   []httpEventError `json:"errors"`
+}
+
+// httpEventError represents why an event of a request was rejected, Index
+// is its position within the request.
+type httpEventError struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+func (her *httpEventsResponse) reject(index int, id string, err error) {
+	her.Rejected++
+	her.Errors = append(her.Errors, httpEventError{
+		Index: index,
+		ID:    id,
+		Error: err.Error(),
+	})
+}
+
+// httpStoredEvent represents a stored event in the HTTP response, a summary
+// of the call without its bodies and headers. Truncated is set when a body
+// was dropped for being too large.
+type httpStoredEvent struct {
+	ID          string               `json:"id"`
+	Agent       string               `json:"agent"`
+	EventType   string               `json:"event_type"`
+	Region      string               `json:"region,omitempty"`
+	BrowserID   string               `json:"browser_id"`
+	ClientID    string               `json:"client_id"`
+	EventTime   time.Time            `json:"event_time"`
+	Endpoint    string               `json:"endpoint,omitempty"`
+	Origin      string               `json:"origin,omitempty"`
+	RequestTime *time.Time           `json:"request_time,omitempty"`
+	Status      uint16               `json:"status,omitempty"`
+	Error       *httpCallError       `json:"error,omitempty"`
+	Truncated   bool                 `json:"truncated,omitempty"`
+	ProgramIDs  []string             `json:"program_ids"`
+	Transaction *httpSentTransaction `json:"transaction,omitempty"`
+}
+
+// httpSentTransaction represents the transaction a sendTransaction call
+// sent, the compute unit price is in micro-lamports.
+type httpSentTransaction struct {
+	Signature        string   `json:"signature"`
+	Version          string   `json:"version"`
+	FeePayer         string   `json:"fee_payer"`
+	RecentBlockhash  string   `json:"recent_blockhash"`
+	ComputeUnitLimit *uint32  `json:"compute_unit_limit,omitempty"`
+	ComputeUnitPrice *uint64  `json:"compute_unit_price,omitempty"`
+	Size             int      `json:"size"`
+	LookupTables     []string `json:"lookup_tables,omitempty"`
+}
+
+func httpStoredEventFromAggregate(event aggregates.Event) httpStoredEvent {
+	programIDs := event.ProgramIDs
+	if programIDs == nil {
+		programIDs = []string{}
+	}
+
+	httpEvent := httpStoredEvent{
+		ID:         event.ID,
+		Agent:      event.Agent,
+		EventType:  event.EventType,
+		Region:     event.Region,
+		BrowserID:  event.BrowserID,
+		ClientID:   event.ClientID,
+		EventTime:  event.EventTime,
+		Truncated:  event.Truncated,
+		ProgramIDs: programIDs,
+	}
+
+	if event.Request != nil {
+		httpEvent.Endpoint = event.Request.Endpoint()
+		httpEvent.Origin = event.Request.Origin()
+		httpEvent.RequestTime = &event.Request.RequestTime
+	}
+
+	if event.Response != nil {
+		httpEvent.Status = event.Response.Status
+	}
+
+	if event.Error != nil {
+		httpEvent.Error = &httpCallError{
+			Type:    event.Error.Type,
+			Message: event.Error.Message,
+		}
+	}
+
+	if event.Transaction != nil {
+		httpEvent.Transaction = &httpSentTransaction{
+			Signature:        event.Transaction.Signature,
+			Version:          event.Transaction.Version,
+			FeePayer:         event.Transaction.FeePayer,
+			RecentBlockhash:  event.Transaction.RecentBlockhash,
+			ComputeUnitLimit: event.Transaction.ComputeUnitLimit,
+			ComputeUnitPrice: event.Transaction.ComputeUnitPrice,
+			Size:             event.Transaction.Size,
+			LookupTables:     event.Transaction.LookupTables,
+		}
+	}
+
+	return httpEvent
+}
+
+// httpSubmissions represents the submissions of a transaction in the HTTP
+// response, the intervals between them are in milliseconds and
+// landing_submission is the index of the one it landed through.
+type httpSubmissions struct {
+	Signature         string            `json:"signature"`
+	Count             int               `json:"count"`
+	Endpoints         []string          `json:"endpoints"`
+	Intervals         []int64           `json:"intervals"`
+	LandedAt          *time.Time        `json:"landed_at,omitempty"`
+	LandingSubmission *int              `json:"landing_submission,omitempty"`
+	Submissions       []httpStoredEvent `json:"submissions"`
+}
+
+func httpSubmissionsFromAggregate(
+	submissions aggregates.Submissions) httpSubmissions {
+	httpSubmissions := httpSubmissions{
+		Signature:   submissions.Signature,
+		Count:       submissions.Count(),
+		Endpoints:   submissions.Endpoints(),
+		Intervals:   make([]int64, 0, submissions.Count()),
+		LandedAt:    submissions.LandedAt,
+		Submissions: make([]httpStoredEvent, submissions.Count()),
+	}
+
+	for _, interval := range submissions.Intervals() {
+		httpSubmissions.Intervals = append(
+			httpSubmissions.Intervals, interval.Milliseconds())
+	}
+
+	if submissions.LandedAt != nil {
+		landing := submissions.Landing(*submissions.LandedAt)
+		httpSubmissions.LandingSubmission = &landing
+	}
+
+	for i, event := range submissions.Events {
+		httpSubmissions.Submissions[i] = httpStoredEventFromAggregate(event)
+	}
+
+	return httpSubmissions
+}
+
+// httpStatusFromError maps the agent domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrEventNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidEventsFilter):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
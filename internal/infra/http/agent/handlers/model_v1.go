@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// legacyAgent is the agent of the events sent before the schema was
+// versioned, they don't identify their agent.
+const legacyAgent = "legacy"
+
+// httpEventRequestV1 represents the version 1 of the agent events, an
+// untyped mirror of the browser Request and Response objects sent before the
+// schema was versioned, without schema_version.
+type httpEventRequestV1 struct {
+	SchemaVersion int             `json:"schema_version"`
+	Agent         string          `json:"agent"`
+	AgentVersion  string          `json:"agent_version"`
+	Event         httpEventV1     `json:"event"`
+	Request       *httpRequestV1  `json:"request,omitempty"`
+	Response      *httpResponseV1 `json:"response,omitempty"`
+}
+
+// httpEventV1 represents the version 1 of the event data.
+type httpEventV1 struct {
+	ID                string      `json:"id"`
+	BrowserID         string      `json:"browserId"`
+	ClientID          string      `json:"clientId"`
+	Handled           interface{} `json:"handled"`
+	ReplacesClientID  *string     `json:"replacesClientId,omitempty"`
+	ResultingClientID string      `json:"resultingClientId"`
+	EventTime         int64       `json:"eventTime"`
+}
+
+// httpRequestV1 represents the version 1 of a browser/mobile request.
+type httpRequestV1 struct {
+	RequestTime    int64       `json:"requestTime"`
+	Body           *string     `json:"body,omitempty"`
+	BodyUsed       bool        `json:"bodyUsed"`
+	Cache          string      `json:"cache"`
+	Credentials    string      `json:"credentials"`
+	Destination    string      `json:"destination"`
+	Headers        interface{} `json:"headers"`
+	Integrity      string      `json:"integrity"`
+	Method         string      `json:"method"`
+	Mode           string      `json:"mode"`
+	Redirect       string      `json:"redirect"`
+	Referrer       string      `json:"referrer"`
+	ReferrerPolicy string      `json:"referrerPolicy"`
+	URL            string      `json:"url"`
+	Signal         interface{} `json:"signal"`
+}
+
+// httpResponseV1 represents the version 1 of a browser/mobile response.
+type httpResponseV1 struct {
+	ResponseTime int64       `json:"responseTime"`
+	Body         *string     `json:"body,omitempty"`
+	BodyUsed     bool        `json:"bodyUsed"`
+	Headers      interface{} `json:"headers"`
+	Ok           bool        `json:"ok"`
+	Redirected   bool        `json:"redirected"`
+	Status       uint16      `json:"status"`
+	StatusText   string      `json:"statusText"`
+	ResponseType string      `json:"responseType"`
+	URL          string      `json:"url"`
+}
+
+// ToAggregate upgrades the version 1 event to the current aggregate, the
+// handled promise and the abort signal are dropped and the headers are kept
+// when they were sent as an object or as a list of pairs.
+func (her *httpEventRequestV1) ToAggregate() aggregates.Event {
+	var request *aggregates.Request
+	if her.Request != nil {
+		request = her.Request.ToAggregate()
+	}
+
+	var response *aggregates.Response
+	if her.Response != nil {
+		response = her.Response.ToAggregate()
+	}
+
+	agent := her.Agent
+	if agent == "" {
+		agent = legacyAgent
+	}
+
+	handled, _ := her.Event.Handled.(bool)
+
+	return aggregates.Event{
+		ID:                her.Event.ID,
+		SchemaVersion:     1,
+		Agent:             agent,
+		AgentVersion:      her.AgentVersion,
+		EventType:         aggregates.EventTypeRPCCall,
+		BrowserID:         her.Event.BrowserID,
+		ClientID:          her.Event.ClientID,
+		Handled:           handled,
+		ReplacesClientID:  her.Event.ReplacesClientID,
+		ResultingClientID: her.Event.ResultingClientID,
+		EventTime:         time.UnixMilli(her.Event.EventTime),
+		Request:           request,
+		Response:          response,
+	}
+}
+
+func (hr *httpRequestV1) ToAggregate() *aggregates.Request {
+	return &aggregates.Request{
+		RequestTime:    time.UnixMilli(hr.RequestTime),
+		Body:           hr.Body,
+		BodyUsed:       hr.BodyUsed,
+		Cache:          hr.Cache,
+		Credentials:    hr.Credentials,
+		Destination:    hr.Destination,
+		Headers:        headersFromV1(hr.Headers),
+		Integrity:      hr.Integrity,
+		Method:         hr.Method,
+		Mode:           hr.Mode,
+		Redirect:       hr.Redirect,
+		Referrer:       hr.Referrer,
+		ReferrerPolicy: hr.ReferrerPolicy,
+		URL:            hr.URL,
+	}
+}
+
+func (hr *httpResponseV1) ToAggregate() *aggregates.Response {
+	return &aggregates.Response{
+		ResponseTime: time.UnixMilli(hr.ResponseTime),
+		Body:         hr.Body,
+		BodyUsed:     hr.BodyUsed,
+		Headers:      headersFromV1(hr.Headers),
+		Ok:           hr.Ok,
+		Redirected:   hr.Redirected,
+		Status:       hr.Status,
+		StatusText:   hr.StatusText,
+		ResponseType: hr.ResponseType,
+		URL:          hr.URL,
+	}
+}
+
+// headersFromV1 converts the untyped version 1 headers, either an object or
+// a list of [name, value] pairs, skipping the values that aren't scalars.
+func headersFromV1(headers interface{}) map[string]string {
+	converted := make(map[string]string)
+
+	switch headers := headers.(type) {
+	case map[string]interface{}:
+		for name, value := range headers {
+			if value, ok := scalarString(value); ok {
+				converted[name] = value
+			}
+		}
+
+	case []interface{}:
+		for _, pair := range headers {
+			pair, ok := pair.([]interface{})
+			if !ok || len(pair) != 2 {
+				continue
+			}
+
+			name, ok := pair[0].(string)
+			if !ok {
+				continue
+			}
+
+			if value, ok := scalarString(pair[1]); ok {
+				converted[name] = value
+			}
+		}
+	}
+
+	return converted
+}
+
+func scalarString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64, bool:
+		return fmt.Sprint(value), true
+	default:
+		return "", false
+	}
+}
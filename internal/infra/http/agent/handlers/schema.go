@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPI is the OpenAPI document of the agent events, for the agent
+// developers.
+//
+//go:embed schema/openapi.json
+var openAPI []byte
+
+// SchemaHandler serves the OpenAPI document of the agent events.
+type SchemaHandler struct{}
+
+// NewSchemaHandler initializes a new SchemaHandler.
+func NewSchemaHandler() *SchemaHandler {
+	return &SchemaHandler{}
+}
+
+// Handle is the handler function to get the OpenAPI document of the agent
+// events.
+func (sh *SchemaHandler) Handle(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPI)
+}
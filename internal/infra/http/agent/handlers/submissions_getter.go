@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// submissionsGetter defines the methods needed to get the submissions of a
+// transaction.
+type submissionsGetter interface {
+	GetSubmissions(context.Context, string) (aggregates.Submissions, error)
+}
+
+// SubmissionsGetterHandler defines the dependencies to get the submissions
+// of a transaction.
+type SubmissionsGetterHandler struct {
+	submissionsGetter submissionsGetter
+}
+
+// NewSubmissionsGetterHandler initializes a new SubmissionsGetterHandler.
+func NewSubmissionsGetterHandler(
+	submissionsGetter submissionsGetter) *SubmissionsGetterHandler {
+	return &SubmissionsGetterHandler{
+		submissionsGetter: submissionsGetter,
+	}
+}
+
+// Handle is the handler function to get every sendTransaction call that
+// sent the transaction of the "signature" path param.
+func (sgh *SubmissionsGetterHandler) Handle(c *gin.Context) {
+	submissions, err := sgh.submissionsGetter.GetSubmissions(
+		c.Request.Context(), c.Param("signature"))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpSubmissionsFromAggregate(submissions))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+// historyGetter defines the methods needed to get the alert history.
+type historyGetter interface {
+	GetHistory(context.Context, aggregates.EventsFilter) ([]aggregates.Event, error)
+}
+
+// HistoryGetterHandler defines the dependencies to get the alert history.
+type HistoryGetterHandler struct {
+	historyGetter historyGetter
+}
+
+// NewHistoryGetterHandler initializes a new HistoryGetterHandler.
+func NewHistoryGetterHandler(historyGetter historyGetter) *HistoryGetterHandler {
+	return &HistoryGetterHandler{
+		historyGetter: historyGetter,
+	}
+}
+
+// Handle is the handler function to get the alert history, the history can
+// be scoped to a rule either with the ":id" path param or the "rule_id"
+// query param, and filtered with the "since" (RFC 3339) and "limit" query
+// params.
+func (hgh *HistoryGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.EventsFilter{}
+
+	if c.Param("id") != "" {
+		id, err := ruleIDFromParam(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filter.RuleID = &id
+	} else if value := c.Query("rule_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rule_id must be an integer"})
+			return
+		}
+
+		filter.RuleID = &id
+	}
+
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Since = &since
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	events, err := hgh.historyGetter.GetHistory(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpHistoryGetResponseFromAggregates(events))
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+// httpRuleRequest represents the request to create or update an alert rule.
+type httpRuleRequest struct {
+	Name           string  `json:"name"`
+	Metric         string  `json:"metric"`
+	ProgramAddress string  `json:"program_address"`
+	Comparison     string  `json:"comparison"`
+	Threshold      float64 `json:"threshold"`
+	Hysteresis     float64 `json:"hysteresis"`
+	WindowSeconds  int64   `json:"window_seconds"`
+	ForSeconds     int64   `json:"for_seconds"`
+	Severity       string  `json:"severity"`
+	WebhookURL     string  `json:"webhook_url"`
+	ChannelIDs     []int64 `json:"channel_ids"`
+	Enabled        *bool   `json:"enabled"`
+}
+
+// ToAggregate converts the httpRuleRequest to an aggregates.Rule, rules are
+// enabled unless stated otherwise.
+func (hrr *httpRuleRequest) ToAggregate() aggregates.Rule {
+	enabled := true
+	if hrr.Enabled != nil {
+		enabled = *hrr.Enabled
+	}
+
+	return aggregates.Rule{
+		Name:           hrr.Name,
+		Metric:         aggregates.Metric(hrr.Metric),
+		ProgramAddress: hrr.ProgramAddress,
+		Comparison:     aggregates.Comparison(hrr.Comparison),
+		Threshold:      hrr.Threshold,
+		Hysteresis:     hrr.Hysteresis,
+		Window:         time.Duration(hrr.WindowSeconds) * time.Second,
+		For:            time.Duration(hrr.ForSeconds) * time.Second,
+		Severity:       aggregates.Severity(hrr.Severity),
+		WebhookURL:     hrr.WebhookURL,
+		ChannelIDs:     hrr.ChannelIDs,
+		Enabled:        enabled,
+	}
+}
+
+// httpRule represents an alert rule in the HTTP response.
+type httpRule struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	Metric         string    `json:"metric"`
+	Scope          string    `json:"scope"`
+	ProgramAddress string    `json:"program_address,omitempty"`
+	Comparison     string    `json:"comparison"`
+	Threshold      float64   `json:"threshold"`
+	Hysteresis     float64   `json:"hysteresis"`
+	WindowSeconds  int64     `json:"window_seconds"`
+	ForSeconds     int64     `json:"for_seconds"`
+	Severity       string    `json:"severity"`
+	WebhookURL     string    `json:"webhook_url,omitempty"`
+	ChannelIDs     []int64   `json:"channel_ids"`
+	Enabled        bool      `json:"enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func httpRuleFromAggregate(rule aggregates.Rule) httpRule {
+	scope := "program"
+	if rule.Global() {
+		scope = "global"
+	}
+
+	channelIDs := rule.ChannelIDs
+	if channelIDs == nil {
+		channelIDs = []int64{}
+	}
+
+	return httpRule{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Metric:         string(rule.Metric),
+		Scope:          scope,
+		ProgramAddress: rule.ProgramAddress,
+		Comparison:     string(rule.Comparison),
+		Threshold:      rule.Threshold,
+		Hysteresis:     rule.Hysteresis,
+		WindowSeconds:  int64(rule.Window.Seconds()),
+		ForSeconds:     int64(rule.For.Seconds()),
+		Severity:       string(rule.Severity),
+		WebhookURL:     rule.WebhookURL,
+		ChannelIDs:     channelIDs,
+		Enabled:        rule.Enabled,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+}
+
+// httpRulesGetResponse represents the response to get alert rules.
+type httpRulesGetResponse struct {
+	Rules []httpRule `json:"rules"`
+}
+
+func httpRulesGetResponseFromAggregates(
+	rules []aggregates.Rule) httpRulesGetResponse {
+	httpRules := make([]httpRule, len(rules))
+	for i, rule := range rules {
+		httpRules[i] = httpRuleFromAggregate(rule)
+	}
+
+	return httpRulesGetResponse{Rules: httpRules}
+}
+
+// httpEvent represents an alert state change in the HTTP response.
+type httpEvent struct {
+	ID             int64     `json:"id"`
+	RuleID         int64     `json:"rule_id"`
+	RuleName       string    `json:"rule_name"`
+	Metric         string    `json:"metric"`
+	ProgramAddress string    `json:"program_address,omitempty"`
+	Severity       string    `json:"severity"`
+	State          string    `json:"state"`
+	Value          float64   `json:"value"`
+	Threshold      float64   `json:"threshold"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// httpHistoryGetResponse represents the response to get the alert history.
+type httpHistoryGetResponse struct {
+	Events []httpEvent `json:"events"`
+}
+
+func httpHistoryGetResponseFromAggregates(
+	events []aggregates.Event) httpHistoryGetResponse {
+	httpEvents := make([]httpEvent, len(events))
+	for i, event := range events {
+		httpEvents[i] = httpEvent{
+			ID:             event.ID,
+			RuleID:         event.RuleID,
+			RuleName:       event.RuleName,
+			Metric:         string(event.Metric),
+			ProgramAddress: event.ProgramAddress,
+			Severity:       string(event.Severity),
+			State:          string(event.State),
+			Value:          event.Value,
+			Threshold:      event.Threshold,
+			CreatedAt:      event.CreatedAt,
+		}
+	}
+
+	return httpHistoryGetResponse{Events: httpEvents}
+}
+
+// ruleIDFromParam parses the rule ID from the ":id" path param.
+func ruleIDFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// httpStatusFromError maps the alert domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrRuleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidRule):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+// ruleCreator defines the methods needed to create alert rules.
+type ruleCreator interface {
+	Create(context.Context, aggregates.Rule) (aggregates.Rule, error)
+}
+
+// RuleCreatorHandler defines the dependencies to create alert rules.
+type RuleCreatorHandler struct {
+	ruleCreator ruleCreator
+}
+
+// NewRuleCreatorHandler initializes a new RuleCreatorHandler.
+func NewRuleCreatorHandler(ruleCreator ruleCreator) *RuleCreatorHandler {
+	return &RuleCreatorHandler{
+		ruleCreator: ruleCreator,
+	}
+}
+
+// Handle is the handler function to create alert rules.
+func (rch *RuleCreatorHandler) Handle(c *gin.Context) {
+	var httpRuleRequest httpRuleRequest
+	if err := c.ShouldBindJSON(&httpRuleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := rch.ruleCreator.Create(
+		c.Request.Context(), httpRuleRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpRuleFromAggregate(rule))
+}
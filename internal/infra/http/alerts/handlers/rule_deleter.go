@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ruleDeleter defines the methods needed to delete alert rules.
+type ruleDeleter interface {
+	Delete(context.Context, int64) error
+}
+
+// RuleDeleterHandler defines the dependencies to delete alert rules.
+type RuleDeleterHandler struct {
+	ruleDeleter ruleDeleter
+}
+
+// NewRuleDeleterHandler initializes a new RuleDeleterHandler.
+func NewRuleDeleterHandler(ruleDeleter ruleDeleter) *RuleDeleterHandler {
+	return &RuleDeleterHandler{
+		ruleDeleter: ruleDeleter,
+	}
+}
+
+// Handle is the handler function to delete alert rules.
+func (rdh *RuleDeleterHandler) Handle(c *gin.Context) {
+	id, err := ruleIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rdh.ruleDeleter.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
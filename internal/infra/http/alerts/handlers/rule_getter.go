@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+// ruleGetter defines the methods needed to get alert rules.
+type ruleGetter interface {
+	GetRules(context.Context) ([]aggregates.Rule, error)
+	GetRule(context.Context, int64) (aggregates.Rule, error)
+}
+
+// RulesGetterHandler defines the dependencies to list alert rules.
+type RulesGetterHandler struct {
+	ruleGetter ruleGetter
+}
+
+// NewRulesGetterHandler initializes a new RulesGetterHandler.
+func NewRulesGetterHandler(ruleGetter ruleGetter) *RulesGetterHandler {
+	return &RulesGetterHandler{
+		ruleGetter: ruleGetter,
+	}
+}
+
+// Handle is the handler function to list alert rules.
+func (rgh *RulesGetterHandler) Handle(c *gin.Context) {
+	rules, err := rgh.ruleGetter.GetRules(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpRulesGetResponseFromAggregates(rules))
+}
+
+// RuleGetterHandler defines the dependencies to get an alert rule.
+type RuleGetterHandler struct {
+	ruleGetter ruleGetter
+}
+
+// NewRuleGetterHandler initializes a new RuleGetterHandler.
+func NewRuleGetterHandler(ruleGetter ruleGetter) *RuleGetterHandler {
+	return &RuleGetterHandler{
+		ruleGetter: ruleGetter,
+	}
+}
+
+// Handle is the handler function to get an alert rule.
+func (rgh *RuleGetterHandler) Handle(c *gin.Context) {
+	id, err := ruleIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := rgh.ruleGetter.GetRule(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpRuleFromAggregate(rule))
+}
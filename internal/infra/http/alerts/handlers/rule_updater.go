@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+// ruleUpdater defines the methods needed to update alert rules.
+type ruleUpdater interface {
+	Update(context.Context, aggregates.Rule) (aggregates.Rule, error)
+}
+
+// RuleUpdaterHandler defines the dependencies to update alert rules.
+type RuleUpdaterHandler struct {
+	ruleUpdater ruleUpdater
+}
+
+// NewRuleUpdaterHandler initializes a new RuleUpdaterHandler.
+func NewRuleUpdaterHandler(ruleUpdater ruleUpdater) *RuleUpdaterHandler {
+	return &RuleUpdaterHandler{
+		ruleUpdater: ruleUpdater,
+	}
+}
+
+// Handle is the handler function to update alert rules.
+func (ruh *RuleUpdaterHandler) Handle(c *gin.Context) {
+	id, err := ruleIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var httpRuleRequest httpRuleRequest
+	if err := c.ShouldBindJSON(&httpRuleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := httpRuleRequest.ToAggregate()
+	rule.ID = id
+
+	rule, err = ruh.ruleUpdater.Update(c.Request.Context(), rule)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpRuleFromAggregate(rule))
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+)
+
+// anomaliesGetter defines the methods needed to get the anomalies.
+type anomaliesGetter interface {
+	GetAnomalies(context.Context,
+		aggregates.AnomaliesFilter) ([]aggregates.Anomaly, error)
+}
+
+// AnomaliesGetterHandler defines the dependencies to get the anomalies.
+type AnomaliesGetterHandler struct {
+	anomaliesGetter anomaliesGetter
+}
+
+// NewAnomaliesGetterHandler initializes a new AnomaliesGetterHandler.
+func NewAnomaliesGetterHandler(
+	anomaliesGetter anomaliesGetter) *AnomaliesGetterHandler {
+	return &AnomaliesGetterHandler{
+		anomaliesGetter: anomaliesGetter,
+	}
+}
+
+// Handle is the handler function to get the anomalies, they can be filtered
+// with the "program_id", "metric", "since", "until" (RFC 3339) and "limit"
+// query params.
+func (agh *AnomaliesGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.AnomaliesFilter{
+		ProgramAddress: c.Query("program_id"),
+		Metric:         aggregates.Metric(c.Query("metric")),
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest,
+				gin.H{"error": param.name + " must be a RFC 3339 date"})
+			return
+		}
+
+		*param.value = &date
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	anomalies, err := agh.anomaliesGetter.GetAnomalies(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpAnomaliesGetResponseFromAggregates(anomalies))
+}
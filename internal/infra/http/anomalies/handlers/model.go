@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+)
+
+// httpAnomaly represents an anomaly in the HTTP response, expected, lower and
+// upper are the baseline band the value fell out of.
+type httpAnomaly struct {
+	ID             int64     `json:"id"`
+	ProgramAddress string    `json:"program_address"`
+	Metric         string    `json:"metric"`
+	WindowStart    time.Time `json:"window_start"`
+	WindowEnd      time.Time `json:"window_end"`
+	Value          float64   `json:"value"`
+	Expected       float64   `json:"expected"`
+	Lower          float64   `json:"lower"`
+	Upper          float64   `json:"upper"`
+	ZScore         float64   `json:"z_score"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// httpAnomaliesGetResponse represents the response to get anomalies.
+type httpAnomaliesGetResponse struct {
+	Anomalies []httpAnomaly `json:"anomalies"`
+}
+
+func httpAnomaliesGetResponseFromAggregates(
+	anomalies []aggregates.Anomaly) httpAnomaliesGetResponse {
+	httpAnomalies := make([]httpAnomaly, len(anomalies))
+	for i, anomaly := range anomalies {
+		httpAnomalies[i] = httpAnomaly{
+			ID:             anomaly.ID,
+			ProgramAddress: anomaly.ProgramAddress,
+			Metric:         string(anomaly.Metric),
+			WindowStart:    anomaly.WindowStart,
+			WindowEnd:      anomaly.WindowEnd,
+			Value:          anomaly.Value,
+			Expected:       anomaly.Expected,
+			Lower:          anomaly.Lower,
+			Upper:          anomaly.Upper,
+			ZScore:         anomaly.ZScore,
+			CreatedAt:      anomaly.CreatedAt,
+		}
+	}
+
+	return httpAnomaliesGetResponse{Anomalies: httpAnomalies}
+}
+
+// httpStatusFromError maps the anomalies domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrInvalidFilter):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+// authenticator defines the methods needed to authenticate the principal of
+// a request from its bearer token.
+type authenticator interface {
+	Authenticate(context.Context, string) (aggregates.Principal, error)
+}
+
+// AuthMiddleware defines the dependencies to authenticate the requests.
+type AuthMiddleware struct {
+	authenticator authenticator
+	enabled       bool
+}
+
+// NewAuthMiddleware initializes a new AuthMiddleware, when the
+// authentication is not enabled every request is made by the anonymous
+// principal.
+func NewAuthMiddleware(authenticator authenticator, enabled bool) *AuthMiddleware {
+	return &AuthMiddleware{
+		authenticator: authenticator,
+		enabled:       enabled,
+	}
+}
+
+// Handle authenticates the principal of the request from its bearer token
+// and stores it in the request context.
+func (am *AuthMiddleware) Handle(c *gin.Context) {
+	principal := aggregates.AnonymousPrincipal()
+
+	if am.enabled {
+		var err error
+		principal, err = am.authenticator.Authenticate(
+			c.Request.Context(), bearerToken(c))
+		if err != nil {
+			if httpStatusFromError(err) == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", `Bearer realm="encinitas"`)
+			}
+
+			c.AbortWithStatusJSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.Request = c.Request.WithContext(
+		aggregates.NewContext(c.Request.Context(), principal))
+
+	c.Next()
+}
+
+// RoleMiddleware defines the role required to access a group of routes.
+type RoleMiddleware struct {
+	role        aggregates.Role
+	allProjects bool
+}
+
+// NewRoleMiddleware initializes a new RoleMiddleware, allProjects also
+// requires the principal not to be bound to a project, as for the routes
+// that manage every tenant.
+func NewRoleMiddleware(role aggregates.Role, allProjects bool) *RoleMiddleware {
+	return &RoleMiddleware{
+		role:        role,
+		allProjects: allProjects,
+	}
+}
+
+// Handle rejects the requests whose principal doesn't have the role.
+func (rm *RoleMiddleware) Handle(c *gin.Context) {
+	principal, ok := aggregates.FromContext(c.Request.Context())
+	if !ok {
+		c.AbortWithStatusJSON(httpStatusFromError(aggregates.ErrUnauthenticated),
+			gin.H{"error": aggregates.ErrUnauthenticated.Error()})
+		return
+	}
+
+	if !principal.Allows(rm.role) {
+		err := fmt.Errorf("%s role required: %w", rm.role, aggregates.ErrForbidden)
+		c.AbortWithStatusJSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if rm.allProjects && principal.ProjectID != 0 {
+		err := fmt.Errorf("principal is bound to a project: %w", aggregates.ErrForbidden)
+		c.AbortWithStatusJSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Next()
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+// bearerToken returns the token of the Authorization header, or of the
+// access_token query parameter for the clients that can't set headers, such
+// as the browsers EventSource and WebSocket.
+func bearerToken(c *gin.Context) string {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return c.Query("access_token")
+}
+
+// httpStatusFromError maps the auth domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrUnauthenticated),
+		errors.Is(err, aggregates.ErrInvalidToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, aggregates.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
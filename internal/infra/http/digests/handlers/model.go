@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// httpSubscriptionRequest represents the request to subscribe an email to
+// the weekly digest of a program.
+type httpSubscriptionRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// httpSubscription represents a digest subscription in the HTTP response,
+// the unsubscribe token is only sent by email.
+type httpSubscription struct {
+	ID             int64      `json:"id"`
+	ProgramAddress string     `json:"program_address"`
+	Email          string     `json:"email"`
+	LastSentAt     *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func httpSubscriptionFromAggregate(
+	subscription aggregates.Subscription) httpSubscription {
+	return httpSubscription{
+		ID:             subscription.ID,
+		ProgramAddress: subscription.ProgramAddress,
+		Email:          subscription.Email,
+		LastSentAt:     subscription.LastSentAt,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
+
+// httpSubscriptionsGetResponse represents the response to get the digest
+// subscriptions of a program.
+type httpSubscriptionsGetResponse struct {
+	Subscriptions []httpSubscription `json:"subscriptions"`
+}
+
+func httpSubscriptionsGetResponseFromAggregates(
+	subscriptions []aggregates.Subscription) httpSubscriptionsGetResponse {
+	httpSubscriptions := make([]httpSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		httpSubscriptions[i] = httpSubscriptionFromAggregate(subscription)
+	}
+
+	return httpSubscriptionsGetResponse{Subscriptions: httpSubscriptions}
+}
+
+// subscriptionIDFromParam parses the subscription ID from the ":id" path
+// param.
+func subscriptionIDFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// httpStatusFromError maps the digest domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrSubscriptionNotFound),
+		errors.Is(err, managerAggregates.ErrProgramNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, managerAggregates.ErrInvalidEmail):
+		return http.StatusBadRequest
+	case errors.Is(err, aggregates.ErrSubscriptionAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+)
+
+// subscriptionCreator defines the methods needed to subscribe emails to the
+// weekly digest of a program.
+type subscriptionCreator interface {
+	Create(context.Context, string, string) (aggregates.Subscription, error)
+}
+
+// SubscriptionCreatorHandler defines the dependencies to subscribe emails to
+// the weekly digest of a program.
+type SubscriptionCreatorHandler struct {
+	subscriptionCreator subscriptionCreator
+}
+
+// NewSubscriptionCreatorHandler initializes a new SubscriptionCreatorHandler.
+func NewSubscriptionCreatorHandler(
+	subscriptionCreator subscriptionCreator) *SubscriptionCreatorHandler {
+	return &SubscriptionCreatorHandler{
+		subscriptionCreator: subscriptionCreator,
+	}
+}
+
+// Handle is the handler function to subscribe an email to the weekly digest
+// of the ":address" program.
+func (sch *SubscriptionCreatorHandler) Handle(c *gin.Context) {
+	var httpSubscriptionRequest httpSubscriptionRequest
+	if err := c.ShouldBindJSON(&httpSubscriptionRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := sch.subscriptionCreator.Create(c.Request.Context(),
+		c.Param("address"), httpSubscriptionRequest.Email)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpSubscriptionFromAggregate(subscription))
+}
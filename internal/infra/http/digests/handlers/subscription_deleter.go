@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// subscriptionDeleter defines the methods needed to delete digest
+// subscriptions.
+type subscriptionDeleter interface {
+	Delete(context.Context, string, int64) error
+}
+
+// SubscriptionDeleterHandler defines the dependencies to delete digest
+// subscriptions.
+type SubscriptionDeleterHandler struct {
+	subscriptionDeleter subscriptionDeleter
+}
+
+// NewSubscriptionDeleterHandler initializes a new SubscriptionDeleterHandler.
+func NewSubscriptionDeleterHandler(
+	subscriptionDeleter subscriptionDeleter) *SubscriptionDeleterHandler {
+	return &SubscriptionDeleterHandler{
+		subscriptionDeleter: subscriptionDeleter,
+	}
+}
+
+// Handle is the handler function to delete a digest subscription of the
+// ":address" program.
+func (sdh *SubscriptionDeleterHandler) Handle(c *gin.Context) {
+	id, err := subscriptionIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sdh.subscriptionDeleter.Delete(
+		c.Request.Context(), c.Param("address"), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+)
+
+// subscriptionGetter defines the methods needed to get the digest
+// subscriptions.
+type subscriptionGetter interface {
+	GetSubscriptions(context.Context, string) ([]aggregates.Subscription, error)
+}
+
+// SubscriptionGetterHandler defines the dependencies to get the digest
+// subscriptions.
+type SubscriptionGetterHandler struct {
+	subscriptionGetter subscriptionGetter
+}
+
+// NewSubscriptionGetterHandler initializes a new SubscriptionGetterHandler.
+func NewSubscriptionGetterHandler(
+	subscriptionGetter subscriptionGetter) *SubscriptionGetterHandler {
+	return &SubscriptionGetterHandler{
+		subscriptionGetter: subscriptionGetter,
+	}
+}
+
+// Handle is the handler function to get the digest subscriptions of the
+// ":address" program.
+func (sgh *SubscriptionGetterHandler) Handle(c *gin.Context) {
+	subscriptions, err := sgh.subscriptionGetter.GetSubscriptions(
+		c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpSubscriptionsGetResponseFromAggregates(subscriptions))
+}
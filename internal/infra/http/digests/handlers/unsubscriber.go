@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unsubscriber defines the methods needed to unsubscribe from the digests.
+type unsubscriber interface {
+	Unsubscribe(context.Context, string) error
+}
+
+// UnsubscriberHandler defines the dependencies to unsubscribe from the
+// digests.
+type UnsubscriberHandler struct {
+	unsubscriber unsubscriber
+}
+
+// NewUnsubscriberHandler initializes a new UnsubscriberHandler.
+func NewUnsubscriberHandler(unsubscriber unsubscriber) *UnsubscriberHandler {
+	return &UnsubscriberHandler{
+		unsubscriber: unsubscriber,
+	}
+}
+
+// Handle is the handler function to delete a digest subscription with the
+// token query parameter, both from the unsubscribe links and the one click
+// List-Unsubscribe POST requests.
+func (uh *UnsubscriberHandler) Handle(c *gin.Context) {
+	if err := uh.unsubscriber.Unsubscribe(
+		c.Request.Context(), c.Query("token")); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "unsubscribed"})
+}
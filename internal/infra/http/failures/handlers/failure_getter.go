@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+)
+
+// failureGetter defines the methods needed to search the failed RPC calls.
+type failureGetter interface {
+	GetFailures(context.Context, aggregates.Filter) ([]aggregates.Failure, error)
+}
+
+// FailureGetterHandler defines the dependencies to search the failed RPC
+// calls.
+type FailureGetterHandler struct {
+	failureGetter failureGetter
+}
+
+// NewFailureGetterHandler initializes a new FailureGetterHandler.
+func NewFailureGetterHandler(failureGetter failureGetter) *FailureGetterHandler {
+	return &FailureGetterHandler{
+		failureGetter: failureGetter,
+	}
+}
+
+// Handle is the handler function to search the failed RPC calls, filtered
+// with the "endpoint", "method", "kind", "q" (matching the messages),
+// "since" and "until" (RFC 3339) and "limit" query params.
+func (fgh *FailureGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.Filter{
+		Endpoint: c.Query("endpoint"),
+		Method:   c.Query("method"),
+		Kind:     aggregates.Kind(c.Query("kind")),
+		Query:    c.Query("q"),
+	}
+
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Since = &since
+	}
+
+	if value := c.Query("until"); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Until = &until
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	failures, err := fgh.failureGetter.GetFailures(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpFailuresGetResponseFromAggregates(failures))
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+)
+
+// httpFailure represents a failed RPC call in the HTTP response.
+type httpFailure struct {
+	ID         int64             `json:"id"`
+	EventID    string            `json:"event_id"`
+	Endpoint   string            `json:"endpoint"`
+	Origin     string            `json:"origin"`
+	Method     string            `json:"method"`
+	Kind       string            `json:"kind"`
+	Batch      bool              `json:"batch"`
+	HTTPStatus int               `json:"http_status,omitempty"`
+	RPCCode    *int64            `json:"rpc_code,omitempty"`
+	Message    string            `json:"message"`
+	Logs       []string          `json:"logs,omitempty"`
+	RateLimit  map[string]string `json:"rate_limit,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// httpFailuresGetResponse represents the response to search the failed RPC
+// calls.
+type httpFailuresGetResponse struct {
+	Failures []httpFailure `json:"failures"`
+}
+
+func httpFailuresGetResponseFromAggregates(
+	failures []aggregates.Failure) httpFailuresGetResponse {
+	httpFailures := make([]httpFailure, len(failures))
+	for i, failure := range failures {
+		httpFailures[i] = httpFailure{
+			ID:         failure.ID,
+			EventID:    failure.EventID,
+			Endpoint:   failure.Endpoint,
+			Origin:     failure.Origin,
+			Method:     failure.Method,
+			Kind:       string(failure.Kind),
+			Batch:      failure.Batch,
+			HTTPStatus: failure.HTTPStatus,
+			RPCCode:    failure.RPCCode,
+			Message:    failure.Message,
+			Logs:       failure.Logs,
+			RateLimit:  failure.RateLimit,
+			OccurredAt: failure.OccurredAt,
+		}
+	}
+
+	return httpFailuresGetResponse{Failures: httpFailures}
+}
+
+// httpStatusFromError maps the failures domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrInvalidFilter):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// emailConfirmer defines the methods needed to confirm waitlist emails.
+type emailConfirmer interface {
+	Confirm(context.Context, string) (aggregates.Email, error)
+}
+
+// EmailConfirmerHandler defines the dependencies to confirm waitlist emails.
+type EmailConfirmerHandler struct {
+	emailConfirmer emailConfirmer
+}
+
+// NewEmailConfirmerHandler initializes a new EmailConfirmerHandler.
+func NewEmailConfirmerHandler(
+	emailConfirmer emailConfirmer) *EmailConfirmerHandler {
+	return &EmailConfirmerHandler{
+		emailConfirmer: emailConfirmer,
+	}
+}
+
+// Handle is the handler function to confirm a waitlist email with the token
+// query parameter of the confirmation link.
+func (ech *EmailConfirmerHandler) Handle(c *gin.Context) {
+	email, err := ech.emailConfirmer.Confirm(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email": email.Address, "status": email.Status})
+}
@@ -5,12 +5,11 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
 )
 
-// emailCreator defines the methods needed to publish emails.
+// emailCreator defines the methods needed to add emails to the waitlist.
 type emailCreator interface {
-	Create(context.Context, string) error
+	Create(context.Context, string, string) error
 }
 
 // EmailsCreatorHandler defines the dependencies to create emails.
@@ -28,9 +27,11 @@ func NewEmailsCreatorHandler(
 
 type httpEmailCreateRequest struct {
 	Email string `json:"email"`
+	Name  string `json:"name"`
 }
 
-// Handle is the handler function to create emails
+// Handle is the handler function to add an email to the waitlist, which is
+// sent a confirmation link.
 func (ech *EmailsCreatorHandler) Handle(c *gin.Context) {
 	var httpEmailCreateRequest httpEmailCreateRequest
 	if err := c.ShouldBindJSON(&httpEmailCreateRequest); err != nil {
@@ -38,16 +39,10 @@ func (ech *EmailsCreatorHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	if err := ech.emailCreator.Create(
-		c.Request.Context(), httpEmailCreateRequest.Email); err != nil {
-		switch err {
-		case aggregates.ErrEmailAlreadyExists:
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	if err := ech.emailCreator.Create(c.Request.Context(),
+		httpEmailCreateRequest.Email, httpEmailCreateRequest.Name); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{})
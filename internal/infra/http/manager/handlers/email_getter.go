@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// emailGetter defines the methods needed to get the waitlist emails.
+type emailGetter interface {
+	GetEmails(context.Context, aggregates.EmailStatus) ([]aggregates.Email, error)
+}
+
+// EmailsGetterHandler defines the dependencies to list the waitlist emails.
+type EmailsGetterHandler struct {
+	emailGetter emailGetter
+}
+
+// NewEmailsGetterHandler initializes a new EmailsGetterHandler.
+func NewEmailsGetterHandler(emailGetter emailGetter) *EmailsGetterHandler {
+	return &EmailsGetterHandler{
+		emailGetter: emailGetter,
+	}
+}
+
+// Handle is the handler function to list the waitlist emails, optionally
+// filtered by the status query parameter.
+func (egh *EmailsGetterHandler) Handle(c *gin.Context) {
+	emails, err := egh.emailGetter.GetEmails(c.Request.Context(),
+		aggregates.EmailStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpEmailsFromAggregates(emails))
+}
+
+// EmailsExporterHandler defines the dependencies to export the waitlist
+// emails.
+type EmailsExporterHandler struct {
+	emailGetter emailGetter
+}
+
+// NewEmailsExporterHandler initializes a new EmailsExporterHandler.
+func NewEmailsExporterHandler(emailGetter emailGetter) *EmailsExporterHandler {
+	return &EmailsExporterHandler{
+		emailGetter: emailGetter,
+	}
+}
+
+// Handle is the handler function to export the waitlist emails as CSV,
+// optionally filtered by the status query parameter.
+func (eeh *EmailsExporterHandler) Handle(c *gin.Context) {
+	emails, err := eeh.emailGetter.GetEmails(c.Request.Context(),
+		aggregates.EmailStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(
+		"attachment; filename=\"waitlist-%s.csv\"", time.Now().UTC().Format(time.DateOnly)))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{
+		"id", "email", "name", "status", "created_at", "confirmed_at", "invited_at",
+	})
+
+	for _, email := range emails {
+		_ = writer.Write([]string{
+			fmt.Sprint(email.ID),
+			csvSafe(email.Address),
+			csvSafe(email.Name),
+			string(email.Status),
+			email.CreatedAt.UTC().Format(time.RFC3339),
+			csvTime(email.ConfirmedAt),
+			csvTime(email.InvitedAt),
+		})
+	}
+
+	writer.Flush()
+}
+
+// csvSafe prevents the user provided values from being evaluated as
+// formulas by spreadsheets.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// emailInviter defines the methods needed to invite waitlist emails.
+type emailInviter interface {
+	Invite(context.Context, int64) (aggregates.Email, error)
+	InviteNext(context.Context, int) ([]aggregates.Email, error)
+}
+
+// EmailInviterHandler defines the dependencies to invite a waitlist email.
+type EmailInviterHandler struct {
+	emailInviter emailInviter
+}
+
+// NewEmailInviterHandler initializes a new EmailInviterHandler.
+func NewEmailInviterHandler(emailInviter emailInviter) *EmailInviterHandler {
+	return &EmailInviterHandler{
+		emailInviter: emailInviter,
+	}
+}
+
+// Handle is the handler function to invite a confirmed waitlist email.
+func (eih *EmailInviterHandler) Handle(c *gin.Context) {
+	id, err := emailIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	email, err := eih.emailInviter.Invite(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpEmailFromAggregate(email))
+}
+
+// httpEmailsInviteRequest represents the request to invite the next
+// confirmed waitlist emails.
+type httpEmailsInviteRequest struct {
+	Limit int `json:"limit"`
+}
+
+// EmailsInviterHandler defines the dependencies to invite the next waitlist
+// emails.
+type EmailsInviterHandler struct {
+	emailInviter emailInviter
+}
+
+// NewEmailsInviterHandler initializes a new EmailsInviterHandler.
+func NewEmailsInviterHandler(emailInviter emailInviter) *EmailsInviterHandler {
+	return &EmailsInviterHandler{
+		emailInviter: emailInviter,
+	}
+}
+
+// Handle is the handler function to invite the next confirmed waitlist
+// emails, it responds with the emails invited before any error.
+func (eih *EmailsInviterHandler) Handle(c *gin.Context) {
+	var httpEmailsInviteRequest httpEmailsInviteRequest
+	if err := c.ShouldBindJSON(&httpEmailsInviteRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	emails, err := eih.emailInviter.InviteNext(
+		c.Request.Context(), httpEmailsInviteRequest.Limit)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{
+			"error":   err.Error(),
+			"invited": httpEmailsFromAggregates(emails),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpEmailsFromAggregates(emails))
+}
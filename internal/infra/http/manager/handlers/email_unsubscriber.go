@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// emailUnsubscriber defines the methods needed to unsubscribe waitlist
+// emails.
+type emailUnsubscriber interface {
+	Unsubscribe(context.Context, string) error
+}
+
+// EmailUnsubscriberHandler defines the dependencies to unsubscribe waitlist
+// emails.
+type EmailUnsubscriberHandler struct {
+	emailUnsubscriber emailUnsubscriber
+}
+
+// NewEmailUnsubscriberHandler initializes a new EmailUnsubscriberHandler.
+func NewEmailUnsubscriberHandler(
+	emailUnsubscriber emailUnsubscriber) *EmailUnsubscriberHandler {
+	return &EmailUnsubscriberHandler{
+		emailUnsubscriber: emailUnsubscriber,
+	}
+}
+
+// Handle is the handler function to unsubscribe a waitlist email with the
+// token query parameter, both from the unsubscribe links and the one click
+// List-Unsubscribe POST requests.
+func (euh *EmailUnsubscriberHandler) Handle(c *gin.Context) {
+	if err := euh.emailUnsubscriber.Unsubscribe(
+		c.Request.Context(), c.Query("token")); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": aggregates.EmailStatusUnsubscribed})
+}
@@ -1,16 +1,53 @@
 package handlers
 
-import "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
 
 // httpProgramRequest represents the request to create a program.
 type httpProgramCreateRequest struct {
-	ProgramAddress string `json:"program_address"`
+	ProgramAddress string   `json:"program_address"`
+	ProgramName    string   `json:"program_name"`
+	Description    string   `json:"description"`
+	Priority       int      `json:"priority"`
+	Tags           []string `json:"tags"`
 }
 
 // ToAggregate converts the httpProgramCreateRequest to an aggregate.Program.
 func (hpcr *httpProgramCreateRequest) ToAggregate() aggregates.Program {
 	return aggregates.Program{
 		ProgramAddress: hpcr.ProgramAddress,
+		ProgramName:    hpcr.ProgramName,
+		Description:    hpcr.Description,
+		Priority:       hpcr.Priority,
+		Tags:           hpcr.Tags,
+	}
+}
+
+// httpProgramUpdateRequest represents the request to partially update a
+// program, the omitted fields are left untouched.
+type httpProgramUpdateRequest struct {
+	ProgramName *string   `json:"program_name"`
+	Description *string   `json:"description"`
+	Priority    *int      `json:"priority"`
+	Tags        *[]string `json:"tags"`
+}
+
+// ToAggregate converts the httpProgramUpdateRequest to an
+// aggregate.ProgramUpdate.
+func (hpur *httpProgramUpdateRequest) ToAggregate() aggregates.ProgramUpdate {
+	return aggregates.ProgramUpdate{
+		ProgramName: hpur.ProgramName,
+		Description: hpur.Description,
+		Priority:    hpur.Priority,
+		Tags:        hpur.Tags,
 	}
 }
 
@@ -21,15 +58,30 @@ type httpProgramGetResponse struct {
 
 // httpProgram represents a program in the HTTP response.
 type httpProgram struct {
-	ProgramAddress string `json:"program_address"`
-	ProgramName    string `json:"program_name"`
+	ProgramAddress string    `json:"program_address"`
+	ProgramName    string    `json:"program_name"`
+	Description    string    `json:"description"`
+	Priority       int       `json:"priority"`
+	Tags           []string  `json:"tags"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 func httpProgramFromAggregate(
 	program aggregates.Program) httpProgram {
+	tags := program.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	return httpProgram{
 		ProgramAddress: program.ProgramAddress,
 		ProgramName:    program.ProgramName,
+		Description:    program.Description,
+		Priority:       program.Priority,
+		Tags:           tags,
+		CreatedAt:      program.CreatedAt,
+		UpdatedAt:      program.UpdatedAt,
 	}
 }
 
@@ -44,3 +96,67 @@ func httpProgramsFromAggregates(
 
 	return httpPrograms
 }
+
+// httpEmail represents a waitlist email in the HTTP response.
+type httpEmail struct {
+	ID             int64      `json:"id"`
+	Email          string     `json:"email"`
+	Name           string     `json:"name,omitempty"`
+	Status         string     `json:"status"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`
+	InvitedAt      *time.Time `json:"invited_at,omitempty"`
+	UnsubscribedAt *time.Time `json:"unsubscribed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func httpEmailFromAggregate(email aggregates.Email) httpEmail {
+	return httpEmail{
+		ID:             email.ID,
+		Email:          email.Address,
+		Name:           email.Name,
+		Status:         string(email.Status),
+		ConfirmedAt:    email.ConfirmedAt,
+		InvitedAt:      email.InvitedAt,
+		UnsubscribedAt: email.UnsubscribedAt,
+		CreatedAt:      email.CreatedAt,
+		UpdatedAt:      email.UpdatedAt,
+	}
+}
+
+func httpEmailsFromAggregates(emails []aggregates.Email) []httpEmail {
+	httpEmails := make([]httpEmail, len(emails))
+	for i, email := range emails {
+		httpEmails[i] = httpEmailFromAggregate(email)
+	}
+
+	return httpEmails
+}
+
+func emailIDFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// httpStatusFromError maps the manager domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrProgramNotFound),
+		errors.Is(err, aggregates.ErrEmailNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidProgram),
+		errors.Is(err, aggregates.ErrInvalidEmail),
+		errors.Is(err, aggregates.ErrInvalidEmailToken):
+		return http.StatusBadRequest
+	case errors.Is(err, aggregates.ErrProgramAlreadyExists),
+		errors.Is(err, aggregates.ErrEmailAlreadyExists),
+		errors.Is(err, aggregates.ErrInvalidEmailStatus):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 // programCreator defines the methods needed to publish programs.
 type programCreator interface {
-	Create(context.Context, aggregates.Program) error
+	Create(context.Context, aggregates.Program) (aggregates.Program, error)
 }
 
 // ProgramsCreatorHandler defines the dependencies to create programs.
@@ -35,11 +35,12 @@ func (ech *ProgramsCreatorHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	if err := ech.programCreator.Create(
-		c.Request.Context(), httpProgramCreateRequest.ToAggregate()); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	program, err := ech.programCreator.Create(
+		c.Request.Context(), httpProgramCreateRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{})
+	c.JSON(http.StatusCreated, httpProgramFromAggregate(program))
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// programDeleter defines the methods needed to delete and restore programs.
+type programDeleter interface {
+	Delete(context.Context, string) error
+	Restore(context.Context, string) error
+}
+
+// ProgramDeleterHandler defines the dependencies to delete programs.
+type ProgramDeleterHandler struct {
+	programDeleter programDeleter
+}
+
+// NewProgramDeleterHandler initializes a new ProgramDeleterHandler.
+func NewProgramDeleterHandler(
+	programDeleter programDeleter) *ProgramDeleterHandler {
+	return &ProgramDeleterHandler{
+		programDeleter: programDeleter,
+	}
+}
+
+// Handle is the handler function to soft delete a program by its ":address".
+func (pdh *ProgramDeleterHandler) Handle(c *gin.Context) {
+	if err := pdh.programDeleter.Delete(
+		c.Request.Context(), c.Param("address")); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ProgramRestorerHandler defines the dependencies to restore programs.
+type ProgramRestorerHandler struct {
+	programDeleter programDeleter
+}
+
+// NewProgramRestorerHandler initializes a new ProgramRestorerHandler.
+func NewProgramRestorerHandler(
+	programDeleter programDeleter) *ProgramRestorerHandler {
+	return &ProgramRestorerHandler{
+		programDeleter: programDeleter,
+	}
+}
+
+// Handle is the handler function to restore a deleted program by its
+// ":address".
+func (prh *ProgramRestorerHandler) Handle(c *gin.Context) {
+	if err := prh.programDeleter.Restore(
+		c.Request.Context(), c.Param("address")); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
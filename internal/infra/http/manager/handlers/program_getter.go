@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -11,7 +12,8 @@ import (
 
 // programGetter defines the methods needed to publish programs.
 type programGetter interface {
-	GetPrograms(context.Context) ([]aggregates.Program, error)
+	GetPrograms(context.Context, aggregates.ProgramsFilter) ([]aggregates.Program, error)
+	GetProgram(context.Context, string) (aggregates.Program, error)
 }
 
 // ProgramGetterHandler defines the dependencies to create programs.
@@ -27,14 +29,56 @@ func NewProgramGetterHandler(
 	}
 }
 
-// Handle is the handler function to create programs
+// Handle is the handler function to list programs, the "search" query param
+// filters the programs whose name or address start with it (autocomplete)
+// and "limit" bounds the amount of matches.
 func (ech *ProgramGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.ProgramsFilter{
+		Search: c.Query("search"),
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
 	programs, err := ech.programGetter.GetPrograms(
-		c.Request.Context())
+		c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, httpProgramsFromAggregates(programs))
 }
+
+// ProgramByAddressGetterHandler defines the dependencies to get a program.
+type ProgramByAddressGetterHandler struct {
+	programGetter programGetter
+}
+
+// NewProgramByAddressGetterHandler initializes a new
+// ProgramByAddressGetterHandler.
+func NewProgramByAddressGetterHandler(
+	programGetter programGetter) *ProgramByAddressGetterHandler {
+	return &ProgramByAddressGetterHandler{
+		programGetter: programGetter,
+	}
+}
+
+// Handle is the handler function to get a program by its ":address".
+func (pgh *ProgramByAddressGetterHandler) Handle(c *gin.Context) {
+	program, err := pgh.programGetter.GetProgram(
+		c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpProgramFromAggregate(program))
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+)
+
+// programUpdater defines the methods needed to update programs.
+type programUpdater interface {
+	Update(context.Context, string, aggregates.ProgramUpdate) (aggregates.Program, error)
+}
+
+// ProgramUpdaterHandler defines the dependencies to update programs.
+type ProgramUpdaterHandler struct {
+	programUpdater programUpdater
+}
+
+// NewProgramUpdaterHandler initializes a new ProgramUpdaterHandler.
+func NewProgramUpdaterHandler(
+	programUpdater programUpdater) *ProgramUpdaterHandler {
+	return &ProgramUpdaterHandler{
+		programUpdater: programUpdater,
+	}
+}
+
+// Handle is the handler function to partially update a program by its
+// ":address".
+func (puh *ProgramUpdaterHandler) Handle(c *gin.Context) {
+	var httpProgramUpdateRequest httpProgramUpdateRequest
+	if err := c.ShouldBindJSON(&httpProgramUpdateRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	program, err := puh.programUpdater.Update(c.Request.Context(),
+		c.Param("address"), httpProgramUpdateRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpProgramFromAggregate(program))
+}
@@ -13,36 +13,42 @@ type MetricsCache struct {
 	Throughput  aggregates.ThroughputResults
 	Apdex       aggregates.ApdexResults
 	Errors      aggregates.ErrorResults
-	mu          sync.RWMutex // Ensure thread-safe access to the cache
 }
 
-// Global instance of the cache
-var metricsCache = MetricsCache{}
+var (
+	// Global instance of the cache, one entry per project.
+	metricsCaches = make(map[int64]MetricsCache)
+	mu            sync.RWMutex // Ensure thread-safe access to the cache
+)
 
-// UpdateCache updates the cache with new data
+// UpdateCache updates the cache of the project with new data
 func UpdateCache(
+	projectID int64,
 	performance aggregates.PerformanceResults,
 	throughput aggregates.ThroughputResults,
 	apdex aggregates.ApdexResults,
 	errors aggregates.ErrorResults) {
-	metricsCache.mu.Lock()
-	defer metricsCache.mu.Unlock()
-	metricsCache.Performance = performance
-	metricsCache.Throughput = throughput
-	metricsCache.Apdex = apdex
-	metricsCache.Errors = errors
-	metricsCache.LastUpdate = time.Now()
+	mu.Lock()
+	defer mu.Unlock()
+	metricsCaches[projectID] = MetricsCache{
+		Performance: performance,
+		Throughput:  throughput,
+		Apdex:       apdex,
+		Errors:      errors,
+		LastUpdate:  time.Now(),
+	}
 }
 
-// GetCache returns the current cache
-func GetCache() (
+// GetCache returns the current cache of the project
+func GetCache(projectID int64) (
 	aggregates.PerformanceResults,
 	aggregates.ThroughputResults,
 	aggregates.ApdexResults,
 	aggregates.ErrorResults,
 	bool) {
-	metricsCache.mu.RLock()
-	defer metricsCache.mu.RUnlock()
+	mu.RLock()
+	defer mu.RUnlock()
+	metricsCache := metricsCaches[projectID]
 	if time.Since(metricsCache.LastUpdate) > 10*time.Minute {
 		return aggregates.PerformanceResults{},
 			aggregates.ThroughputResults{},
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// defaultDropsQueryRange is the period the drop rates cover when the
+// request doesn't set a start.
+const defaultDropsQueryRange = 24 * time.Hour
+
+// dropRatesRetriever defines the methods needed to retrieve the drop rates
+// of the sent transactions.
+type dropRatesRetriever interface {
+	QueryDropRates(context.Context,
+		aggregates.DropRateFilter) ([]aggregates.DropRate, error)
+}
+
+// DropRatesRetrieverHandler defines the dependencies to retrieve the drop
+// rates of the sent transactions.
+type DropRatesRetrieverHandler struct {
+	dropRatesRetriever dropRatesRetriever
+}
+
+// NewDropRatesRetrieverHandler initializes a new DropRatesRetrieverHandler.
+func NewDropRatesRetrieverHandler(
+	dropRatesRetriever dropRatesRetriever,
+) *DropRatesRetrieverHandler {
+	return &DropRatesRetrieverHandler{
+		dropRatesRetriever: dropRatesRetriever,
+	}
+}
+
+// httpDropRate represents the outcomes of the transactions sent for a
+// program, through an RPC provider or from an origin.
+type httpDropRate struct {
+	ProgramAddress string  `json:"program_address,omitempty"`
+	Endpoint       string  `json:"endpoint,omitempty"`
+	Origin         string  `json:"origin,omitempty"`
+	Transactions   int64   `json:"transactions"`
+	Landed         int64   `json:"landed"`
+	Dropped        int64   `json:"dropped"`
+	Expired        int64   `json:"expired"`
+	DropRate       float64 `json:"drop_rate"`
+}
+
+// Handle is the handler function to retrieve the drop rates of the sent
+// transactions, broken down by the "breakdown" query param (program,
+// endpoint or origin, program by default), filtered by the "program" and
+// "endpoint" query params and between the "start" and "end" (RFC 3339)
+// query params, the last day by default.
+func (drrh *DropRatesRetrieverHandler) Handle(c *gin.Context) {
+	filter := aggregates.DropRateFilter{
+		End: time.Now().UTC(),
+		Breakdown: aggregates.OutcomeBreakdown(
+			c.DefaultQuery("breakdown", string(aggregates.OutcomeBreakdownProgram))),
+		ProgramAddress: c.Query("program"),
+		Endpoint:       c.Query("endpoint"),
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = end
+	}
+
+	filter.Start = filter.End.Add(-defaultDropsQueryRange)
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = start
+	}
+
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dropRates, err := drrh.dropRatesRetriever.QueryDropRates(
+		c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, aggregates.ErrInvalidRPCQuery) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpDropRates := make([]httpDropRate, len(dropRates))
+	for i, dropRate := range dropRates {
+		httpDropRates[i] = httpDropRate{
+			Transactions: dropRate.Total(),
+			Landed:       dropRate.Landed,
+			Dropped:      dropRate.Dropped,
+			Expired:      dropRate.Expired,
+			DropRate:     dropRate.DropRate(),
+		}
+
+		switch filter.Breakdown {
+		case aggregates.OutcomeBreakdownProgram:
+			httpDropRates[i].ProgramAddress = dropRate.Breakdown
+		case aggregates.OutcomeBreakdownEndpoint:
+			httpDropRates[i].Endpoint = dropRate.Breakdown
+		case aggregates.OutcomeBreakdownOrigin:
+			httpDropRates[i].Origin = dropRate.Breakdown
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"breakdown": filter.Breakdown,
+		"start":     filter.Start,
+		"end":       filter.End,
+		"drops":     httpDropRates,
+	})
+}
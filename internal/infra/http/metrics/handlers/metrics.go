@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 	"github.com/jcleira/encinitas-collector-go/internal/infra/http/metrics/handlers/cache"
 )
 
@@ -33,10 +34,15 @@ func NewMetricsRetriever(
 
 // Handle is the handler function to retrieve metrics
 func (ech *MetricsRetrieverHandler) Handle(c *gin.Context) {
-	performanceMetrics, throughputMetrics, apdexMetrics, errorMetrics, cached := cache.GetCache()
+	projectID, err := tenantsAggregates.ProjectIDFromContext(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	performanceMetrics, throughputMetrics, apdexMetrics, errorMetrics, cached := cache.GetCache(projectID)
 
 	if !cached {
-		var err error
 		performanceMetrics, err = ech.metricsRetriever.QueryPerformance(c.Request.Context())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -61,7 +67,7 @@ func (ech *MetricsRetrieverHandler) Handle(c *gin.Context) {
 			return
 		}
 
-		cache.UpdateCache(performanceMetrics, throughputMetrics, apdexMetrics, errorMetrics)
+		cache.UpdateCache(projectID, performanceMetrics, throughputMetrics, apdexMetrics, errorMetrics)
 	}
 
 	httpMetricsResponse := struct {
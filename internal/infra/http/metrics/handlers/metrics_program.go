@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	managerAggregates "github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
 	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 	"github.com/jcleira/encinitas-collector-go/internal/infra/http/metrics/handlers/cacheprogram"
 )
 
@@ -42,7 +45,22 @@ func (ech *MetricsProgramRetrieverHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	if cachedItem, found := metricsCache.Get(programID); found {
+	// The program address is the measurement the metrics are queried by.
+	if err := managerAggregates.ValidateProgramAddress(programID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	projectID, err := tenantsAggregates.ProjectIDFromContext(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Every project has its own metrics of the program.
+	cacheKey := fmt.Sprintf("%d/%s", projectID, programID)
+
+	if cachedItem, found := metricsCache.Get(cacheKey); found {
 		c.JSON(
 			http.StatusOK,
 			mapToHttpMetricsResponse(cachedItem.Performance, cachedItem.Throughput))
@@ -63,7 +81,7 @@ func (ech *MetricsProgramRetrieverHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	metricsCache.Set(programID, cacheprogram.CacheItem{
+	metricsCache.Set(cacheKey, cacheprogram.CacheItem{
 		Performance: performanceMetrics,
 		Throughput:  througputMetrics,
 		LastUpdated: time.Now(),
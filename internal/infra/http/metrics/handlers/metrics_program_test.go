@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// programRetriever records the programs it's queried for.
+type programRetriever struct {
+	programs []string
+}
+
+func (pr *programRetriever) QueryProgramPerformance(
+	_ context.Context, program string) (aggregates.PerformanceResults, error) {
+	pr.programs = append(pr.programs, program)
+	return aggregates.PerformanceResults{}, nil
+}
+
+func (pr *programRetriever) QueryProgramThroughput(
+	_ context.Context, program string) (aggregates.ThroughputResults, error) {
+	pr.programs = append(pr.programs, program)
+	return aggregates.ThroughputResults{}, nil
+}
+
+func TestMetricsProgramRetrieverHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		programID string
+		status    int
+	}{
+		{
+			name:      "program address",
+			programID: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA",
+			status:    http.StatusOK,
+		},
+		{
+			name:   "missing",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:      "flux injection",
+			programID: `x") or (r._measurement != "`,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "short address",
+			programID: "11111111",
+			status:    http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			retriever := &programRetriever{}
+
+			router := gin.New()
+			router.GET("/",
+				func(c *gin.Context) {
+					c.Request = c.Request.WithContext(tenantsAggregates.NewContext(
+						c.Request.Context(), tenantsAggregates.Tenant{ProjectID: 7}))
+				},
+				NewMetricsProgramRetrieverHandler(retriever).Handle,
+			)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+				"/?program_id="+url.QueryEscape(test.programID), nil))
+
+			if recorder.Code != test.status {
+				t.Fatalf("status = %d, want %d", recorder.Code, test.status)
+			}
+
+			if test.status != http.StatusOK && len(retriever.programs) != 0 {
+				t.Errorf("programs = %v, want no queries", retriever.programs)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// defaultProvidersQueryRange is the period the provider benchmarks cover
+// when the request doesn't set a start, long enough for the transactions to
+// land.
+const defaultProvidersQueryRange = 24 * time.Hour
+
+// providerBenchmarksRetriever defines the methods needed to retrieve the
+// RPC provider benchmarks.
+type providerBenchmarksRetriever interface {
+	QueryProviderBenchmarks(context.Context,
+		aggregates.ProviderBenchmarkFilter) ([]aggregates.ProviderBenchmark, error)
+}
+
+// ProviderBenchmarksRetrieverHandler defines the dependencies to retrieve
+// the RPC provider benchmarks.
+type ProviderBenchmarksRetrieverHandler struct {
+	providerBenchmarksRetriever providerBenchmarksRetriever
+}
+
+// NewProviderBenchmarksRetrieverHandler initializes a new
+// ProviderBenchmarksRetrieverHandler.
+func NewProviderBenchmarksRetrieverHandler(
+	providerBenchmarksRetriever providerBenchmarksRetriever,
+) *ProviderBenchmarksRetrieverHandler {
+	return &ProviderBenchmarksRetrieverHandler{
+		providerBenchmarksRetriever: providerBenchmarksRetriever,
+	}
+}
+
+// httpProviderBenchmark represents the benchmark of an RPC provider, the
+// latencies and times are in milliseconds.
+type httpProviderBenchmark struct {
+	Endpoint        string                  `json:"endpoint"`
+	Region          string                  `json:"region,omitempty"`
+	Origin          string                  `json:"origin,omitempty"`
+	Calls           int64                   `json:"calls"`
+	ErrorRate       float64                 `json:"error_rate"`
+	RateLimitRate   float64                 `json:"rate_limit_rate"`
+	Methods         []httpMethodLatency     `json:"methods"`
+	SendTransaction httpSendTransactionStat `json:"send_transaction"`
+}
+
+// httpMethodLatency represents the latency percentiles of a JSON-RPC method.
+type httpMethodLatency struct {
+	Method     string  `json:"method"`
+	Count      int64   `json:"count"`
+	LatencyP50 float64 `json:"latency_p50"`
+	LatencyP95 float64 `json:"latency_p95"`
+	LatencyP99 float64 `json:"latency_p99"`
+}
+
+// httpSendTransactionStat represents how the transactions sent through a
+// provider landed.
+type httpSendTransactionStat struct {
+	Sent                  int64   `json:"sent"`
+	Landed                int64   `json:"landed"`
+	LandingRate           float64 `json:"landing_rate"`
+	TimeToLandP50         float64 `json:"time_to_land_p50"`
+	TimeToLandP95         float64 `json:"time_to_land_p95"`
+	BlockhashStalenessP50 float64 `json:"blockhash_staleness_p50"`
+	BlockhashStalenessP95 float64 `json:"blockhash_staleness_p95"`
+	ResendsPerLanded      float64 `json:"resends_per_landed"`
+	LandingSubmission     float64 `json:"landing_submission"`
+}
+
+// Handle is the handler function to retrieve the RPC provider benchmarks,
+// one per RPC host, broken down by the "breakdown" query param (region or
+// origin, none by default), filtered by the "endpoint" query param and
+// between the "start" and "end" (RFC 3339) query params, the last day by
+// default.
+func (pbrh *ProviderBenchmarksRetrieverHandler) Handle(c *gin.Context) {
+	filter := aggregates.ProviderBenchmarkFilter{
+		End:       time.Now().UTC(),
+		Breakdown: aggregates.ProviderBreakdown(c.Query("breakdown")),
+		Endpoint:  c.Query("endpoint"),
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = end
+	}
+
+	filter.Start = filter.End.Add(-defaultProvidersQueryRange)
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = start
+	}
+
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	benchmarks, err := pbrh.providerBenchmarksRetriever.QueryProviderBenchmarks(
+		c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, aggregates.ErrInvalidRPCQuery) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpBenchmarks := make([]httpProviderBenchmark, len(benchmarks))
+	for i, benchmark := range benchmarks {
+		methods := make([]httpMethodLatency, len(benchmark.Methods))
+		for j, method := range benchmark.Methods {
+			methods[j] = httpMethodLatency{
+				Method:     method.Method,
+				Count:      method.Count,
+				LatencyP50: method.LatencyP50,
+				LatencyP95: method.LatencyP95,
+				LatencyP99: method.LatencyP99,
+			}
+		}
+
+		httpBenchmarks[i] = httpProviderBenchmark{
+			Endpoint:      benchmark.Endpoint,
+			Calls:         benchmark.Calls,
+			ErrorRate:     benchmark.ErrorRate(),
+			RateLimitRate: benchmark.RateLimitRate(),
+			Methods:       methods,
+			SendTransaction: httpSendTransactionStat{
+				Sent:                  benchmark.Sent,
+				Landed:                benchmark.Landed,
+				LandingRate:           benchmark.LandingRate(),
+				TimeToLandP50:         benchmark.TimeToLandP50,
+				TimeToLandP95:         benchmark.TimeToLandP95,
+				BlockhashStalenessP50: benchmark.BlockhashStalenessP50,
+				BlockhashStalenessP95: benchmark.BlockhashStalenessP95,
+				ResendsPerLanded:      benchmark.ResendsPerLanded,
+				LandingSubmission:     benchmark.LandingSubmission,
+			},
+		}
+
+		switch filter.Breakdown {
+		case aggregates.ProviderBreakdownRegion:
+			httpBenchmarks[i].Region = benchmark.Breakdown
+		case aggregates.ProviderBreakdownOrigin:
+			httpBenchmarks[i].Origin = benchmark.Breakdown
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"breakdown": filter.Breakdown,
+		"start":     filter.Start,
+		"end":       filter.End,
+		"providers": httpBenchmarks,
+	})
+}
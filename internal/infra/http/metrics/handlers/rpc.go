@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// defaultRPCQueryRange is the period the RPC stats cover when the request
+// doesn't set a start.
+const defaultRPCQueryRange = time.Hour
+
+// rpcStatsRetriever defines the methods needed to retrieve the RPC call
+// stats.
+type rpcStatsRetriever interface {
+	QueryRPCStats(context.Context,
+		aggregates.RPCStatsFilter) ([]aggregates.RPCStats, error)
+}
+
+// RPCStatsRetrieverHandler defines the dependencies to retrieve the RPC call
+// stats.
+type RPCStatsRetrieverHandler struct {
+	rpcStatsRetriever rpcStatsRetriever
+}
+
+// NewRPCStatsRetrieverHandler initializes a new RPCStatsRetrieverHandler.
+func NewRPCStatsRetrieverHandler(
+	rpcStatsRetriever rpcStatsRetriever) *RPCStatsRetrieverHandler {
+	return &RPCStatsRetrieverHandler{
+		rpcStatsRetriever: rpcStatsRetriever,
+	}
+}
+
+// httpRPCStats represents the stats of a group of RPC calls, the latencies
+// are in milliseconds, the rate in calls per minute and the response size
+// in bytes.
+type httpRPCStats struct {
+	Method       string  `json:"method,omitempty"`
+	Endpoint     string  `json:"endpoint,omitempty"`
+	Origin       string  `json:"origin,omitempty"`
+	Count        int64   `json:"count"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	Rate         float64 `json:"rate"`
+	LatencyP50   float64 `json:"latency_p50"`
+	LatencyP95   float64 `json:"latency_p95"`
+	LatencyP99   float64 `json:"latency_p99"`
+	ResponseSize float64 `json:"response_size"`
+}
+
+// Handle is the handler function to retrieve the RPC call stats, grouped by
+// the "group_by" query param (method, endpoint or origin, method by
+// default), filtered by the "method", "endpoint" and "origin" query params
+// and between the "start" and "end" (RFC 3339) query params, the last hour
+// by default.
+func (rsrh *RPCStatsRetrieverHandler) Handle(c *gin.Context) {
+	filter := aggregates.RPCStatsFilter{
+		End:      time.Now().UTC(),
+		GroupBy:  aggregates.RPCGroupBy(c.DefaultQuery("group_by", "method")),
+		Method:   c.Query("method"),
+		Endpoint: c.Query("endpoint"),
+		Origin:   c.Query("origin"),
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = end
+	}
+
+	filter.Start = filter.End.Add(-defaultRPCQueryRange)
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = start
+	}
+
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := rsrh.rpcStatsRetriever.QueryRPCStats(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, aggregates.ErrInvalidRPCQuery) {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpStats := make([]httpRPCStats, len(stats))
+	for i, stat := range stats {
+		httpStats[i] = httpRPCStats{
+			Count:        stat.Count,
+			Errors:       stat.Errors,
+			ErrorRate:    stat.ErrorRate(),
+			Rate:         stat.Rate(),
+			LatencyP50:   stat.LatencyP50,
+			LatencyP95:   stat.LatencyP95,
+			LatencyP99:   stat.LatencyP99,
+			ResponseSize: stat.ResponseSize,
+		}
+
+		switch filter.GroupBy {
+		case aggregates.RPCGroupByMethod:
+			httpStats[i].Method = stat.Group
+		case aggregates.RPCGroupByEndpoint:
+			httpStats[i].Endpoint = stat.Group
+		case aggregates.RPCGroupByOrigin:
+			httpStats[i].Origin = stat.Group
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_by": filter.GroupBy,
+		"start":    filter.Start,
+		"end":      filter.End,
+		"stats":    httpStats,
+	})
+}
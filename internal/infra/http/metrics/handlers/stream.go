@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/infra/http/websocket"
+)
+
+// streamSubscriber defines the methods needed to subscribe to the live stream.
+type streamSubscriber interface {
+	Subscribe(aggregates.StreamFilter) (<-chan aggregates.StreamEvent, func())
+}
+
+// StreamHandler defines the dependencies to stream the ingested metrics
+// using Server-Sent Events.
+type StreamHandler struct {
+	streamSubscriber  streamSubscriber
+	heartbeatInterval time.Duration
+}
+
+// NewStreamHandler initializes a new StreamHandler.
+func NewStreamHandler(
+	streamSubscriber streamSubscriber,
+	heartbeatInterval time.Duration) *StreamHandler {
+	return &StreamHandler{
+		streamSubscriber:  streamSubscriber,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// Handle is the handler function to stream metrics as Server-Sent Events.
+func (sh *StreamHandler) Handle(c *gin.Context) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := streamFilterFromQuery(c, projectID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, unsubscribe := sh.streamSubscriber.Subscribe(filter)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(sh.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// Flush the headers right away, otherwise clients wait for the first
+	// event to know that the stream is established.
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+
+		case event, ok := <-events:
+			if !ok {
+				c.SSEvent("close", gin.H{"reason": "slow consumer"})
+				return false
+			}
+
+			c.SSEvent(string(event.Type), httpStreamEventFromAggregate(event))
+			return true
+
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": time.Now().UTC()})
+			return true
+		}
+	})
+}
+
+// StreamWebSocketHandler defines the dependencies to stream the ingested
+// metrics using WebSockets to the web pages of the allowedOrigins.
+type StreamWebSocketHandler struct {
+	streamSubscriber  streamSubscriber
+	heartbeatInterval time.Duration
+	allowedOrigins    []string
+}
+
+// NewStreamWebSocketHandler initializes a new StreamWebSocketHandler.
+func NewStreamWebSocketHandler(
+	streamSubscriber streamSubscriber,
+	heartbeatInterval time.Duration,
+	allowedOrigins []string) *StreamWebSocketHandler {
+	return &StreamWebSocketHandler{
+		streamSubscriber:  streamSubscriber,
+		heartbeatInterval: heartbeatInterval,
+		allowedOrigins:    allowedOrigins,
+	}
+}
+
+// Handle is the handler function to stream metrics over a WebSocket.
+func (swh *StreamWebSocketHandler) Handle(c *gin.Context) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := streamFilterFromQuery(c, projectID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := websocket.Upgrade(c.Writer, c.Request, swh.allowedOrigins)
+	if err != nil {
+		if errors.Is(err, websocket.ErrNotWebSocket) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors.Is(err, websocket.ErrOriginNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := swh.streamSubscriber.Subscribe(filter)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(swh.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var message httpStreamMessage
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-conn.Done():
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				message = httpStreamMessage{
+					Event: "close",
+					Data:  gin.H{"reason": "slow consumer"},
+				}
+			} else {
+				message = httpStreamMessage{
+					Event: string(event.Type),
+					Data:  httpStreamEventFromAggregate(event),
+				}
+			}
+
+		case <-heartbeat.C:
+			message = httpStreamMessage{
+				Event: "heartbeat",
+				Data:  gin.H{"time": time.Now().UTC()},
+			}
+		}
+
+		data, err := json.Marshal(message)
+		if err != nil {
+			slog.Error("can't marshal stream message", slog.Any("error", err))
+			return
+		}
+
+		if err := conn.WriteText(data); err != nil {
+			return
+		}
+
+		if message.Event == "close" {
+			return
+		}
+	}
+}
+
+// streamFilterFromQuery builds the stream filter from the query params:
+//
+//   - program: program addresses, either repeated or comma separated.
+//   - error: "true" or "false" to only receive failed/successful events.
+//   - min_latency: minimum solana time in milliseconds.
+//
+// The events are always restricted to the given project.
+func streamFilterFromQuery(c *gin.Context,
+	projectID int64) (aggregates.StreamFilter, error) {
+	filter := aggregates.StreamFilter{
+		ProjectID: projectID,
+	}
+
+	for _, programs := range c.QueryArray("program") {
+		for _, program := range strings.Split(programs, ",") {
+			if program = strings.TrimSpace(program); program != "" {
+				filter.ProgramAddresses = append(filter.ProgramAddresses, program)
+			}
+		}
+	}
+
+	if value, ok := c.GetQuery("error"); ok {
+		isError, err := strconv.ParseBool(value)
+		if err != nil {
+			return aggregates.StreamFilter{}, errors.New("error must be a boolean")
+		}
+
+		filter.Error = &isError
+	}
+
+	if value, ok := c.GetQuery("min_latency"); ok {
+		minLatency, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || minLatency < 0 {
+			return aggregates.StreamFilter{},
+				errors.New("min_latency must be a positive number of milliseconds")
+		}
+
+		filter.MinSolanaTime = minLatency
+	}
+
+	return filter, nil
+}
+
+// httpStreamMessage represents a message sent through the WebSocket stream,
+// it mirrors the event/data pair of a Server-Sent Event.
+type httpStreamMessage struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// httpStreamTransaction represents an ingested transaction in the stream.
+type httpStreamTransaction struct {
+	Signature        string    `json:"signature"`
+	UpdatedOn        time.Time `json:"updated_on"`
+	SolanaTime       int64     `json:"solana_time"`
+	Error            bool      `json:"error"`
+	ProgramAddresses []string  `json:"program_addresses"`
+}
+
+// httpStreamProgram represents a per-program metric point in the stream.
+type httpStreamProgram struct {
+	ProgramAddress string    `json:"program_address"`
+	UpdatedOn      time.Time `json:"updated_on"`
+	SolanaTime     int64     `json:"solana_time"`
+	Error          bool      `json:"error"`
+}
+
+func httpStreamEventFromAggregate(event aggregates.StreamEvent) interface{} {
+	switch event.Type {
+	case aggregates.StreamEventTransaction:
+		return httpStreamTransaction{
+			Signature:        event.Transaction.Signature,
+			UpdatedOn:        event.Transaction.UpdatedOn,
+			SolanaTime:       event.Transaction.SolanaTime,
+			Error:            event.Transaction.Error,
+			ProgramAddresses: event.Transaction.ProgramAddresses,
+		}
+
+	case aggregates.StreamEventProgram:
+		return httpStreamProgram{
+			ProgramAddress: event.Program.ProgramAddress,
+			UpdatedOn:      event.Program.UpdatedOn,
+			SolanaTime:     event.Program.SolanaTime,
+			Error:          event.Program.Error,
+		}
+	}
+
+	return nil
+}
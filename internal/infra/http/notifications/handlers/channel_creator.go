@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// channelCreator defines the methods needed to create notification channels.
+type channelCreator interface {
+	Create(context.Context, aggregates.Channel) (aggregates.Channel, error)
+}
+
+// ChannelCreatorHandler defines the dependencies to create notification
+// channels.
+type ChannelCreatorHandler struct {
+	channelCreator channelCreator
+}
+
+// NewChannelCreatorHandler initializes a new ChannelCreatorHandler.
+func NewChannelCreatorHandler(channelCreator channelCreator) *ChannelCreatorHandler {
+	return &ChannelCreatorHandler{
+		channelCreator: channelCreator,
+	}
+}
+
+// Handle is the handler function to create notification channels.
+func (cch *ChannelCreatorHandler) Handle(c *gin.Context) {
+	var httpChannelRequest httpChannelRequest
+	if err := c.ShouldBindJSON(&httpChannelRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := cch.channelCreator.Create(
+		c.Request.Context(), httpChannelRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpChannelFromAggregate(channel))
+}
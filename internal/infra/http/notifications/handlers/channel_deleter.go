@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// channelDeleter defines the methods needed to delete notification channels.
+type channelDeleter interface {
+	Delete(context.Context, int64) error
+}
+
+// ChannelDeleterHandler defines the dependencies to delete notification
+// channels.
+type ChannelDeleterHandler struct {
+	channelDeleter channelDeleter
+}
+
+// NewChannelDeleterHandler initializes a new ChannelDeleterHandler.
+func NewChannelDeleterHandler(channelDeleter channelDeleter) *ChannelDeleterHandler {
+	return &ChannelDeleterHandler{
+		channelDeleter: channelDeleter,
+	}
+}
+
+// Handle is the handler function to delete notification channels.
+func (cdh *ChannelDeleterHandler) Handle(c *gin.Context) {
+	id, err := channelIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cdh.channelDeleter.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// channelGetter defines the methods needed to get notification channels.
+type channelGetter interface {
+	GetChannels(context.Context) ([]aggregates.Channel, error)
+	GetChannel(context.Context, int64) (aggregates.Channel, error)
+}
+
+// ChannelsGetterHandler defines the dependencies to list notification
+// channels.
+type ChannelsGetterHandler struct {
+	channelGetter channelGetter
+}
+
+// NewChannelsGetterHandler initializes a new ChannelsGetterHandler.
+func NewChannelsGetterHandler(channelGetter channelGetter) *ChannelsGetterHandler {
+	return &ChannelsGetterHandler{
+		channelGetter: channelGetter,
+	}
+}
+
+// Handle is the handler function to list notification channels.
+func (cgh *ChannelsGetterHandler) Handle(c *gin.Context) {
+	channels, err := cgh.channelGetter.GetChannels(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpChannelsGetResponseFromAggregates(channels))
+}
+
+// ChannelGetterHandler defines the dependencies to get a notification channel.
+type ChannelGetterHandler struct {
+	channelGetter channelGetter
+}
+
+// NewChannelGetterHandler initializes a new ChannelGetterHandler.
+func NewChannelGetterHandler(channelGetter channelGetter) *ChannelGetterHandler {
+	return &ChannelGetterHandler{
+		channelGetter: channelGetter,
+	}
+}
+
+// Handle is the handler function to get a notification channel.
+func (cgh *ChannelGetterHandler) Handle(c *gin.Context) {
+	id, err := channelIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := cgh.channelGetter.GetChannel(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpChannelFromAggregate(channel))
+}
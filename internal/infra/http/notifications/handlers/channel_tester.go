@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// channelTester defines the methods needed to send test notifications.
+type channelTester interface {
+	Test(context.Context, int64) error
+}
+
+// ChannelTesterHandler defines the dependencies to send test notifications.
+type ChannelTesterHandler struct {
+	channelTester channelTester
+}
+
+// NewChannelTesterHandler initializes a new ChannelTesterHandler.
+func NewChannelTesterHandler(channelTester channelTester) *ChannelTesterHandler {
+	return &ChannelTesterHandler{
+		channelTester: channelTester,
+	}
+}
+
+// Handle is the handler function to send a test notification, delivery
+// failures are reported as 502 Bad Gateway including the provider error.
+func (cth *ChannelTesterHandler) Handle(c *gin.Context) {
+	id, err := channelIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cth.channelTester.Test(c.Request.Context(), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "sent"})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// channelUpdater defines the methods needed to update notification channels.
+type channelUpdater interface {
+	Update(context.Context, aggregates.Channel) (aggregates.Channel, error)
+}
+
+// ChannelUpdaterHandler defines the dependencies to update notification
+// channels.
+type ChannelUpdaterHandler struct {
+	channelUpdater channelUpdater
+}
+
+// NewChannelUpdaterHandler initializes a new ChannelUpdaterHandler.
+func NewChannelUpdaterHandler(channelUpdater channelUpdater) *ChannelUpdaterHandler {
+	return &ChannelUpdaterHandler{
+		channelUpdater: channelUpdater,
+	}
+}
+
+// Handle is the handler function to update notification channels.
+func (cuh *ChannelUpdaterHandler) Handle(c *gin.Context) {
+	id, err := channelIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var httpChannelRequest httpChannelRequest
+	if err := c.ShouldBindJSON(&httpChannelRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel := httpChannelRequest.ToAggregate()
+	channel.ID = id
+
+	channel, err = cuh.channelUpdater.Update(c.Request.Context(), channel)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpChannelFromAggregate(channel))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// httpChannelRequest represents the request to create or update a
+// notification channel.
+type httpChannelRequest struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Config   map[string]string `json:"config"`
+	Template string            `json:"template"`
+	Enabled  *bool             `json:"enabled"`
+}
+
+// ToAggregate converts the httpChannelRequest to an aggregates.Channel,
+// channels are enabled unless stated otherwise.
+func (hcr *httpChannelRequest) ToAggregate() aggregates.Channel {
+	enabled := true
+	if hcr.Enabled != nil {
+		enabled = *hcr.Enabled
+	}
+
+	config := hcr.Config
+	if config == nil {
+		config = make(map[string]string)
+	}
+
+	return aggregates.Channel{
+		Name:     hcr.Name,
+		Type:     aggregates.ChannelType(hcr.Type),
+		Config:   config,
+		Template: hcr.Template,
+		Enabled:  enabled,
+	}
+}
+
+// httpChannel represents a notification channel in the HTTP response, its
+// credentials are always redacted.
+type httpChannel struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Config    map[string]string `json:"config"`
+	Template  string            `json:"template,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func httpChannelFromAggregate(channel aggregates.Channel) httpChannel {
+	channel = channel.Redacted()
+
+	return httpChannel{
+		ID:        channel.ID,
+		Name:      channel.Name,
+		Type:      string(channel.Type),
+		Config:    channel.Config,
+		Template:  channel.Template,
+		Enabled:   channel.Enabled,
+		CreatedAt: channel.CreatedAt,
+		UpdatedAt: channel.UpdatedAt,
+	}
+}
+
+// httpChannelsGetResponse represents the response to get notification
+// channels.
+type httpChannelsGetResponse struct {
+	Channels []httpChannel `json:"channels"`
+}
+
+func httpChannelsGetResponseFromAggregates(
+	channels []aggregates.Channel) httpChannelsGetResponse {
+	httpChannels := make([]httpChannel, len(channels))
+	for i, channel := range channels {
+		httpChannels[i] = httpChannelFromAggregate(channel)
+	}
+
+	return httpChannelsGetResponse{Channels: httpChannels}
+}
+
+// channelIDFromParam parses the channel ID from the ":id" path param.
+func channelIDFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// httpStatusFromError maps the notification domain errors to HTTP status
+// codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrChannelNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidChannel):
+		return http.StatusBadRequest
+	case errors.Is(err, aggregates.ErrDeliveryFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+)
+
+// httpStats represents how the transactions of a session or wallet went,
+// the median wait is in milliseconds.
+type httpStats struct {
+	Attempted  int64   `json:"attempted"`
+	Landed     int64   `json:"landed"`
+	Failed     int64   `json:"failed"`
+	Dropped    int64   `json:"dropped"`
+	MedianWait float64 `json:"median_wait"`
+	LastError  string  `json:"last_error,omitempty"`
+}
+
+// httpSession represents a session in the HTTP response.
+type httpSession struct {
+	BrowserID string    `json:"browser_id"`
+	ClientIDs []string  `json:"client_ids"`
+	Wallets   []string  `json:"wallets"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	httpStats
+}
+
+// httpWallet represents a fee payer wallet in the HTTP response.
+type httpWallet struct {
+	Address  string `json:"address"`
+	Sessions int64  `json:"sessions"`
+	httpStats
+}
+
+// httpReportGetResponse represents the response to get the worst affected
+// sessions and wallets.
+type httpReportGetResponse struct {
+	Sessions []httpSession `json:"sessions"`
+	Wallets  []httpWallet  `json:"wallets"`
+}
+
+func httpStatsFromAggregate(stats aggregates.Stats) httpStats {
+	return httpStats{
+		Attempted:  stats.Attempted,
+		Landed:     stats.Landed,
+		Failed:     stats.Failed,
+		Dropped:    stats.Dropped,
+		MedianWait: stats.MedianWait,
+		LastError:  stats.LastError,
+	}
+}
+
+func httpReportGetResponseFromAggregate(
+	report aggregates.Report) httpReportGetResponse {
+	response := httpReportGetResponse{
+		Sessions: make([]httpSession, len(report.Sessions)),
+		Wallets:  make([]httpWallet, len(report.Wallets)),
+	}
+
+	for i, session := range report.Sessions {
+		response.Sessions[i] = httpSession{
+			BrowserID: session.BrowserID,
+			ClientIDs: session.ClientIDs,
+			Wallets:   session.Wallets,
+			StartedAt: session.StartedAt,
+			EndedAt:   session.EndedAt,
+			httpStats: httpStatsFromAggregate(session.Stats),
+		}
+	}
+
+	for i, wallet := range report.Wallets {
+		response.Wallets[i] = httpWallet{
+			Address:   wallet.Address,
+			Sessions:  wallet.Sessions,
+			httpStats: httpStatsFromAggregate(wallet.Stats),
+		}
+	}
+
+	return response
+}
+
+// httpStatusFromError maps the sessions domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrInvalidFilter):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
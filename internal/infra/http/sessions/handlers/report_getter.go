@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+)
+
+// defaultSessionsQueryRange is the period the report covers when the
+// request doesn't set a start.
+const defaultSessionsQueryRange = 24 * time.Hour
+
+// reportGetter defines the methods needed to get the worst affected
+// sessions and wallets.
+type reportGetter interface {
+	GetReport(context.Context, aggregates.Filter) (aggregates.Report, error)
+}
+
+// ReportGetterHandler defines the dependencies to get the worst affected
+// sessions and wallets.
+type ReportGetterHandler struct {
+	reportGetter reportGetter
+}
+
+// NewReportGetterHandler initializes a new ReportGetterHandler.
+func NewReportGetterHandler(reportGetter reportGetter) *ReportGetterHandler {
+	return &ReportGetterHandler{
+		reportGetter: reportGetter,
+	}
+}
+
+// Handle is the handler function to get the worst affected sessions and
+// wallets of the program of the "program" query param, narrowed down by the
+// "wallet" and "browser_id" query params, between the "start" and "end"
+// (RFC 3339) query params, the last day by default, and up to the "limit"
+// query param.
+func (rgh *ReportGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.Filter{
+		ProgramAddress: c.Query("program"),
+		Wallet:         c.Query("wallet"),
+		BrowserID:      c.Query("browser_id"),
+		End:            time.Now().UTC(),
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = end
+	}
+
+	filter.Start = filter.End.Add(-defaultSessionsQueryRange)
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = start
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	report, err := rgh.reportGetter.GetReport(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpReportGetResponseFromAggregate(report))
+}
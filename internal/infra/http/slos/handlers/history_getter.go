@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// historyGetter defines the methods needed to get the SLO status history.
+type historyGetter interface {
+	GetHistory(context.Context,
+		aggregates.StatusesFilter) (aggregates.SLO, []aggregates.Status, error)
+}
+
+// HistoryGetterHandler defines the dependencies to get the SLO status
+// history.
+type HistoryGetterHandler struct {
+	historyGetter historyGetter
+}
+
+// NewHistoryGetterHandler initializes a new HistoryGetterHandler.
+func NewHistoryGetterHandler(historyGetter historyGetter) *HistoryGetterHandler {
+	return &HistoryGetterHandler{
+		historyGetter: historyGetter,
+	}
+}
+
+// Handle is the handler function to get the status history of an SLO, it
+// can be filtered with the "since", "until" (RFC 3339) and "limit" query
+// params.
+func (hgh *HistoryGetterHandler) Handle(c *gin.Context) {
+	id, err := sloIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := aggregates.StatusesFilter{SLOID: id}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest,
+				gin.H{"error": param.name + " must be a RFC 3339 date"})
+			return
+		}
+
+		*param.value = &date
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+
+		filter.Limit = limit
+	}
+
+	slo, statuses, err := hgh.historyGetter.GetHistory(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	slos := make([]aggregates.SLO, len(statuses))
+	for i := range statuses {
+		slos[i] = slo
+	}
+
+	c.JSON(http.StatusOK, httpStatusesGetResponseFromAggregates(slos, statuses))
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// defaultWindow is the SLO window when the request doesn't set one.
+const defaultWindow = 30 * 24 * time.Hour
+
+// httpSLORequest represents the request to create or update an SLO, the
+// objective is a percentage, e.g. 99.5.
+type httpSLORequest struct {
+	Name               string  `json:"name"`
+	ProgramAddress     string  `json:"program_address"`
+	Indicator          string  `json:"indicator"`
+	Objective          float64 `json:"objective"`
+	LatencyThresholdMS int64   `json:"latency_threshold_ms"`
+	WindowSeconds      int64   `json:"window_seconds"`
+	Enabled            *bool   `json:"enabled"`
+}
+
+// ToAggregate converts the httpSLORequest to an aggregates.SLO, SLOs are
+// enabled and cover 30 days unless stated otherwise.
+func (hsr *httpSLORequest) ToAggregate() aggregates.SLO {
+	enabled := true
+	if hsr.Enabled != nil {
+		enabled = *hsr.Enabled
+	}
+
+	window := defaultWindow
+	if hsr.WindowSeconds != 0 {
+		window = time.Duration(hsr.WindowSeconds) * time.Second
+	}
+
+	return aggregates.SLO{
+		Name:             hsr.Name,
+		ProgramAddress:   hsr.ProgramAddress,
+		Indicator:        aggregates.Indicator(hsr.Indicator),
+		Objective:        hsr.Objective,
+		LatencyThreshold: time.Duration(hsr.LatencyThresholdMS) * time.Millisecond,
+		Window:           window,
+		Enabled:          enabled,
+	}
+}
+
+// httpSLO represents an SLO in the HTTP response.
+type httpSLO struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Scope              string    `json:"scope"`
+	ProgramAddress     string    `json:"program_address,omitempty"`
+	Indicator          string    `json:"indicator"`
+	Objective          float64   `json:"objective"`
+	LatencyThresholdMS int64     `json:"latency_threshold_ms,omitempty"`
+	WindowSeconds      int64     `json:"window_seconds"`
+	Enabled            bool      `json:"enabled"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func httpSLOFromAggregate(slo aggregates.SLO) httpSLO {
+	scope := "program"
+	if slo.ProgramAddress == "" {
+		scope = "global"
+	}
+
+	return httpSLO{
+		ID:                 slo.ID,
+		Name:               slo.Name,
+		Scope:              scope,
+		ProgramAddress:     slo.ProgramAddress,
+		Indicator:          string(slo.Indicator),
+		Objective:          slo.Objective,
+		LatencyThresholdMS: slo.LatencyThreshold.Milliseconds(),
+		WindowSeconds:      int64(slo.Window.Seconds()),
+		Enabled:            slo.Enabled,
+		CreatedAt:          slo.CreatedAt,
+		UpdatedAt:          slo.UpdatedAt,
+	}
+}
+
+// httpSLOsGetResponse represents the response to get SLOs.
+type httpSLOsGetResponse struct {
+	SLOs []httpSLO `json:"slos"`
+}
+
+func httpSLOsGetResponseFromAggregates(
+	slos []aggregates.SLO) httpSLOsGetResponse {
+	httpSLOs := make([]httpSLO, len(slos))
+	for i, slo := range slos {
+		httpSLOs[i] = httpSLOFromAggregate(slo)
+	}
+
+	return httpSLOsGetResponse{SLOs: httpSLOs}
+}
+
+// httpStatus represents the status of an SLO in the HTTP response, the
+// compliance and the remaining error budget are percentages, the remaining
+// budget goes negative once it's exhausted.
+type httpStatus struct {
+	SLOID                int64          `json:"slo_id"`
+	Name                 string         `json:"name,omitempty"`
+	Objective            float64        `json:"objective"`
+	Total                int64          `json:"total"`
+	Good                 int64          `json:"good"`
+	Bad                  int64          `json:"bad"`
+	Compliance           float64        `json:"compliance"`
+	ErrorBudgetRemaining float64        `json:"error_budget_remaining"`
+	Met                  bool           `json:"met"`
+	BurnRates            []httpBurnRate `json:"burn_rates"`
+	ComputedAt           time.Time      `json:"computed_at"`
+}
+
+// httpBurnRate represents the error budget burn rate within a window.
+type httpBurnRate struct {
+	Window        string  `json:"window"`
+	WindowSeconds int64   `json:"window_seconds"`
+	Rate          float64 `json:"rate"`
+}
+
+func httpStatusFromAggregates(
+	slo aggregates.SLO, status aggregates.Status) httpStatus {
+	burnRates := make([]httpBurnRate, len(status.BurnRates))
+	for i, burnRate := range status.BurnRates {
+		burnRates[i] = httpBurnRate{
+			Window:        windowName(burnRate.Window),
+			WindowSeconds: int64(burnRate.Window.Seconds()),
+			Rate:          burnRate.Rate,
+		}
+	}
+
+	return httpStatus{
+		SLOID:                status.SLOID,
+		Name:                 slo.Name,
+		Objective:            slo.Objective,
+		Total:                status.Total,
+		Good:                 status.Good,
+		Bad:                  status.Total - status.Good,
+		Compliance:           status.Compliance * 100,
+		ErrorBudgetRemaining: status.BudgetRemaining * 100,
+		Met:                  status.Met(slo),
+		BurnRates:            burnRates,
+		ComputedAt:           status.ComputedAt,
+	}
+}
+
+// httpStatusesGetResponse represents the response to get SLO statuses.
+type httpStatusesGetResponse struct {
+	Statuses []httpStatus `json:"statuses"`
+}
+
+func httpStatusesGetResponseFromAggregates(slos []aggregates.SLO,
+	statuses []aggregates.Status) httpStatusesGetResponse {
+	httpStatuses := make([]httpStatus, len(statuses))
+	for i, status := range statuses {
+		httpStatuses[i] = httpStatusFromAggregates(slos[i], status)
+	}
+
+	return httpStatusesGetResponse{Statuses: httpStatuses}
+}
+
+// windowName returns the short name of a burn rate window, e.g. "6h" or "3d".
+func windowName(window time.Duration) string {
+	if window >= 24*time.Hour && window%(24*time.Hour) == 0 {
+		return strconv.FormatInt(int64(window/(24*time.Hour)), 10) + "d"
+	}
+
+	return strconv.FormatInt(int64(window/time.Hour), 10) + "h"
+}
+
+// sloIDFromParam parses the SLO ID from the ":id" path param.
+func sloIDFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// httpStatusFromError maps the SLO domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrSLONotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidSLO):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// sloCreator defines the methods needed to create SLOs.
+type sloCreator interface {
+	Create(context.Context, aggregates.SLO) (aggregates.SLO, error)
+}
+
+// SLOCreatorHandler defines the dependencies to create SLOs.
+type SLOCreatorHandler struct {
+	sloCreator sloCreator
+}
+
+// NewSLOCreatorHandler initializes a new SLOCreatorHandler.
+func NewSLOCreatorHandler(sloCreator sloCreator) *SLOCreatorHandler {
+	return &SLOCreatorHandler{
+		sloCreator: sloCreator,
+	}
+}
+
+// Handle is the handler function to create SLOs.
+func (sch *SLOCreatorHandler) Handle(c *gin.Context) {
+	var httpSLORequest httpSLORequest
+	if err := c.ShouldBindJSON(&httpSLORequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo, err := sch.sloCreator.Create(
+		c.Request.Context(), httpSLORequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpSLOFromAggregate(slo))
+}
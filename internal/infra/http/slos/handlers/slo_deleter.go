@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sloDeleter defines the methods needed to delete SLOs.
+type sloDeleter interface {
+	Delete(context.Context, int64) error
+}
+
+// SLODeleterHandler defines the dependencies to delete SLOs.
+type SLODeleterHandler struct {
+	sloDeleter sloDeleter
+}
+
+// NewSLODeleterHandler initializes a new SLODeleterHandler.
+func NewSLODeleterHandler(sloDeleter sloDeleter) *SLODeleterHandler {
+	return &SLODeleterHandler{
+		sloDeleter: sloDeleter,
+	}
+}
+
+// Handle is the handler function to delete SLOs.
+func (sdh *SLODeleterHandler) Handle(c *gin.Context) {
+	id, err := sloIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sdh.sloDeleter.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// sloGetter defines the methods needed to get SLOs.
+type sloGetter interface {
+	GetSLOs(context.Context) ([]aggregates.SLO, error)
+	GetSLO(context.Context, int64) (aggregates.SLO, error)
+}
+
+// SLOsGetterHandler defines the dependencies to list SLOs.
+type SLOsGetterHandler struct {
+	sloGetter sloGetter
+}
+
+// NewSLOsGetterHandler initializes a new SLOsGetterHandler.
+func NewSLOsGetterHandler(sloGetter sloGetter) *SLOsGetterHandler {
+	return &SLOsGetterHandler{
+		sloGetter: sloGetter,
+	}
+}
+
+// Handle is the handler function to list SLOs.
+func (sgh *SLOsGetterHandler) Handle(c *gin.Context) {
+	slos, err := sgh.sloGetter.GetSLOs(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpSLOsGetResponseFromAggregates(slos))
+}
+
+// SLOGetterHandler defines the dependencies to get an SLO.
+type SLOGetterHandler struct {
+	sloGetter sloGetter
+}
+
+// NewSLOGetterHandler initializes a new SLOGetterHandler.
+func NewSLOGetterHandler(sloGetter sloGetter) *SLOGetterHandler {
+	return &SLOGetterHandler{
+		sloGetter: sloGetter,
+	}
+}
+
+// Handle is the handler function to get an SLO.
+func (sgh *SLOGetterHandler) Handle(c *gin.Context) {
+	id, err := sloIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo, err := sgh.sloGetter.GetSLO(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpSLOFromAggregate(slo))
+}
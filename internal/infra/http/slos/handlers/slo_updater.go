@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// sloUpdater defines the methods needed to update SLOs.
+type sloUpdater interface {
+	Update(context.Context, aggregates.SLO) (aggregates.SLO, error)
+}
+
+// SLOUpdaterHandler defines the dependencies to update SLOs.
+type SLOUpdaterHandler struct {
+	sloUpdater sloUpdater
+}
+
+// NewSLOUpdaterHandler initializes a new SLOUpdaterHandler.
+func NewSLOUpdaterHandler(sloUpdater sloUpdater) *SLOUpdaterHandler {
+	return &SLOUpdaterHandler{
+		sloUpdater: sloUpdater,
+	}
+}
+
+// Handle is the handler function to update SLOs.
+func (suh *SLOUpdaterHandler) Handle(c *gin.Context) {
+	id, err := sloIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var httpSLORequest httpSLORequest
+	if err := c.ShouldBindJSON(&httpSLORequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo := httpSLORequest.ToAggregate()
+	slo.ID = id
+
+	slo, err = suh.sloUpdater.Update(c.Request.Context(), slo)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpSLOFromAggregate(slo))
+}
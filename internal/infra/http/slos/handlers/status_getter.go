@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+// statusGetter defines the methods needed to get the current SLO status.
+type statusGetter interface {
+	GetStatuses(context.Context) ([]aggregates.SLO, []aggregates.Status, error)
+	GetStatus(context.Context, int64) (aggregates.SLO, aggregates.Status, error)
+}
+
+// StatusesGetterHandler defines the dependencies to get the current status
+// of every SLO.
+type StatusesGetterHandler struct {
+	statusGetter statusGetter
+}
+
+// NewStatusesGetterHandler initializes a new StatusesGetterHandler.
+func NewStatusesGetterHandler(statusGetter statusGetter) *StatusesGetterHandler {
+	return &StatusesGetterHandler{
+		statusGetter: statusGetter,
+	}
+}
+
+// Handle is the handler function to get the current status of every SLO.
+func (sgh *StatusesGetterHandler) Handle(c *gin.Context) {
+	slos, statuses, err := sgh.statusGetter.GetStatuses(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpStatusesGetResponseFromAggregates(slos, statuses))
+}
+
+// StatusGetterHandler defines the dependencies to get the current status of
+// an SLO.
+type StatusGetterHandler struct {
+	statusGetter statusGetter
+}
+
+// NewStatusGetterHandler initializes a new StatusGetterHandler.
+func NewStatusGetterHandler(statusGetter statusGetter) *StatusGetterHandler {
+	return &StatusGetterHandler{
+		statusGetter: statusGetter,
+	}
+}
+
+// Handle is the handler function to get the current status of an SLO.
+func (sgh *StatusGetterHandler) Handle(c *gin.Context) {
+	id, err := sloIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slo, status, err := sgh.statusGetter.GetStatus(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpStatusFromAggregates(slo, status))
+}
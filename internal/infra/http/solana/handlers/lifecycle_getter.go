@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+// lifecycleGetter defines the methods needed to get the lifecycle of a
+// transaction.
+type lifecycleGetter interface {
+	GetLifecycle(context.Context, string) (aggregates.Lifecycle, error)
+}
+
+// LifecycleGetterHandler defines the dependencies to get the lifecycle of a
+// transaction.
+type LifecycleGetterHandler struct {
+	lifecycleGetter lifecycleGetter
+}
+
+// NewLifecycleGetterHandler initializes a new LifecycleGetterHandler.
+func NewLifecycleGetterHandler(
+	lifecycleGetter lifecycleGetter) *LifecycleGetterHandler {
+	return &LifecycleGetterHandler{
+		lifecycleGetter: lifecycleGetter,
+	}
+}
+
+// Handle is the handler function to get the lifecycle of the transaction of
+// the "signature" path param.
+func (lgh *LifecycleGetterHandler) Handle(c *gin.Context) {
+	lifecycle, err := lgh.lifecycleGetter.GetLifecycle(
+		c.Request.Context(), c.Param("signature"))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpLifecycleFromAggregate(lifecycle))
+}
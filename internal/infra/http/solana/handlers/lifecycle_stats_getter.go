@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+// defaultLifecyclesQueryRange is the period the lifecycle stats cover when
+// the request doesn't set a start.
+const defaultLifecyclesQueryRange = 24 * time.Hour
+
+// lifecycleStatsGetter defines the methods needed to get the transaction
+// lifecycle stats.
+type lifecycleStatsGetter interface {
+	GetLifecycleStats(context.Context,
+		aggregates.LifecycleStatsFilter) ([]aggregates.LifecycleStats, error)
+}
+
+// LifecycleStatsGetterHandler defines the dependencies to get the
+// transaction lifecycle stats.
+type LifecycleStatsGetterHandler struct {
+	lifecycleStatsGetter lifecycleStatsGetter
+}
+
+// NewLifecycleStatsGetterHandler initializes a new
+// LifecycleStatsGetterHandler.
+func NewLifecycleStatsGetterHandler(
+	lifecycleStatsGetter lifecycleStatsGetter) *LifecycleStatsGetterHandler {
+	return &LifecycleStatsGetterHandler{
+		lifecycleStatsGetter: lifecycleStatsGetter,
+	}
+}
+
+// Handle is the handler function to get the percentiles of the times to
+// confirmed and finalized per program, filtered by the "program" query
+// param, measured from the "from" query param (submitted or processed, the
+// default) and between the "start" and "end" (RFC 3339) query params, the
+// last day by default.
+func (lsgh *LifecycleStatsGetterHandler) Handle(c *gin.Context) {
+	filter := aggregates.LifecycleStatsFilter{
+		End:            time.Now().UTC(),
+		ProgramAddress: c.Query("program"),
+		From: aggregates.LifecycleFrom(
+			c.DefaultQuery("from", string(aggregates.LifecycleFromProcessed))),
+	}
+
+	if value := c.Query("end"); value != "" {
+		end, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end must be a RFC 3339 date"})
+			return
+		}
+
+		filter.End = end
+	}
+
+	filter.Start = filter.End.Add(-defaultLifecyclesQueryRange)
+	if value := c.Query("start"); value != "" {
+		start, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be a RFC 3339 date"})
+			return
+		}
+
+		filter.Start = start
+	}
+
+	stats, err := lsgh.lifecycleStatsGetter.GetLifecycleStats(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":     filter.From,
+		"start":    filter.Start,
+		"end":      filter.End,
+		"programs": httpLifecycleStatsFromAggregates(stats),
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+// httpLifecycle represents the lifecycle of a transaction in the HTTP
+// response, the times of the commitment levels not reached yet are omitted.
+type httpLifecycle struct {
+	Signature        string     `json:"signature"`
+	Slot             int64      `json:"slot"`
+	ProgramAddresses []string   `json:"program_addresses"`
+	Commitment       string     `json:"commitment"`
+	SubmittedAt      *time.Time `json:"submitted_at,omitempty"`
+	ProcessedAt      *time.Time `json:"processed_at,omitempty"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	FinalizedAt      *time.Time `json:"finalized_at,omitempty"`
+}
+
+func httpLifecycleFromAggregate(lifecycle aggregates.Lifecycle) httpLifecycle {
+	return httpLifecycle{
+		Signature:        lifecycle.Signature,
+		Slot:             lifecycle.Slot,
+		ProgramAddresses: lifecycle.ProgramAddresses,
+		Commitment:       string(lifecycle.Commitment()),
+		SubmittedAt:      lifecycle.SubmittedAt,
+		ProcessedAt:      lifecycle.ProcessedAt,
+		ConfirmedAt:      lifecycle.ConfirmedAt,
+		FinalizedAt:      lifecycle.FinalizedAt,
+	}
+}
+
+// httpLifecycleStats represents the percentiles of the times to confirmed
+// and finalized of a program, in milliseconds.
+type httpLifecycleStats struct {
+	ProgramAddress string  `json:"program_address"`
+	Count          int64   `json:"count"`
+	ConfirmedP50   float64 `json:"confirmed_p50"`
+	ConfirmedP95   float64 `json:"confirmed_p95"`
+	ConfirmedP99   float64 `json:"confirmed_p99"`
+	FinalizedP50   float64 `json:"finalized_p50"`
+	FinalizedP95   float64 `json:"finalized_p95"`
+	FinalizedP99   float64 `json:"finalized_p99"`
+}
+
+func httpLifecycleStatsFromAggregates(
+	stats []aggregates.LifecycleStats) []httpLifecycleStats {
+	httpStats := make([]httpLifecycleStats, len(stats))
+	for i, programStats := range stats {
+		httpStats[i] = httpLifecycleStats{
+			ProgramAddress: programStats.ProgramAddress,
+			Count:          programStats.Count,
+			ConfirmedP50:   programStats.ConfirmedP50,
+			ConfirmedP95:   programStats.ConfirmedP95,
+			ConfirmedP99:   programStats.ConfirmedP99,
+			FinalizedP50:   programStats.FinalizedP50,
+			FinalizedP95:   programStats.FinalizedP95,
+			FinalizedP99:   programStats.FinalizedP99,
+		}
+	}
+
+	return httpStats
+}
+
+// httpStatusFromError maps the solana domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrLifecycleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidLifecycleQuery):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
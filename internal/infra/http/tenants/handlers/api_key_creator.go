@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// apiKeyCreator defines the methods needed to create API keys.
+type apiKeyCreator interface {
+	Create(context.Context, aggregates.APIKey) (aggregates.APIKey, string, error)
+}
+
+// APIKeyCreatorHandler defines the dependencies to create API keys.
+type APIKeyCreatorHandler struct {
+	apiKeyCreator apiKeyCreator
+}
+
+// NewAPIKeyCreatorHandler initializes a new APIKeyCreatorHandler.
+func NewAPIKeyCreatorHandler(apiKeyCreator apiKeyCreator) *APIKeyCreatorHandler {
+	return &APIKeyCreatorHandler{
+		apiKeyCreator: apiKeyCreator,
+	}
+}
+
+// Handle is the handler function to create an API key, the response is the
+// only time the key is returned.
+func (kch *APIKeyCreatorHandler) Handle(c *gin.Context) {
+	var httpAPIKeyRequest httpAPIKeyRequest
+	if err := c.ShouldBindJSON(&httpAPIKeyRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apiKey, key, err := kch.apiKeyCreator.Create(
+		c.Request.Context(), httpAPIKeyRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	httpAPIKey := httpAPIKeyFromAggregate(apiKey)
+	httpAPIKey.Key = key
+
+	c.JSON(http.StatusCreated, httpAPIKey)
+}
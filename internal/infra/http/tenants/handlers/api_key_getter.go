@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// apiKeyGetter defines the methods needed to get API keys.
+type apiKeyGetter interface {
+	GetAPIKeys(context.Context) ([]aggregates.APIKey, error)
+	GetAPIKey(context.Context, int64) (aggregates.APIKey, error)
+}
+
+// APIKeysGetterHandler defines the dependencies to list API keys.
+type APIKeysGetterHandler struct {
+	apiKeyGetter apiKeyGetter
+}
+
+// NewAPIKeysGetterHandler initializes a new APIKeysGetterHandler.
+func NewAPIKeysGetterHandler(apiKeyGetter apiKeyGetter) *APIKeysGetterHandler {
+	return &APIKeysGetterHandler{
+		apiKeyGetter: apiKeyGetter,
+	}
+}
+
+// Handle is the handler function to list the API keys of the project.
+func (kgh *APIKeysGetterHandler) Handle(c *gin.Context) {
+	apiKeys, err := kgh.apiKeyGetter.GetAPIKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpAPIKeysFromAggregates(apiKeys))
+}
+
+// APIKeyGetterHandler defines the dependencies to get an API key.
+type APIKeyGetterHandler struct {
+	apiKeyGetter apiKeyGetter
+}
+
+// NewAPIKeyGetterHandler initializes a new APIKeyGetterHandler.
+func NewAPIKeyGetterHandler(apiKeyGetter apiKeyGetter) *APIKeyGetterHandler {
+	return &APIKeyGetterHandler{
+		apiKeyGetter: apiKeyGetter,
+	}
+}
+
+// Handle is the handler function to get an API key.
+func (kgh *APIKeyGetterHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apiKey, err := kgh.apiKeyGetter.GetAPIKey(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpAPIKeyFromAggregate(apiKey))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyRevoker defines the methods needed to revoke API keys.
+type apiKeyRevoker interface {
+	Revoke(context.Context, int64) error
+}
+
+// APIKeyRevokerHandler defines the dependencies to revoke API keys.
+type APIKeyRevokerHandler struct {
+	apiKeyRevoker apiKeyRevoker
+}
+
+// NewAPIKeyRevokerHandler initializes a new APIKeyRevokerHandler.
+func NewAPIKeyRevokerHandler(apiKeyRevoker apiKeyRevoker) *APIKeyRevokerHandler {
+	return &APIKeyRevokerHandler{
+		apiKeyRevoker: apiKeyRevoker,
+	}
+}
+
+// Handle is the handler function to revoke an API key.
+func (krh *APIKeyRevokerHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := krh.apiKeyRevoker.Revoke(c.Request.Context(), id); err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// apiKeyRotator defines the methods needed to rotate API keys.
+type apiKeyRotator interface {
+	Rotate(context.Context, int64, time.Duration) (aggregates.APIKey, string, error)
+}
+
+// APIKeyRotatorHandler defines the dependencies to rotate API keys.
+type APIKeyRotatorHandler struct {
+	apiKeyRotator apiKeyRotator
+}
+
+// NewAPIKeyRotatorHandler initializes a new APIKeyRotatorHandler.
+func NewAPIKeyRotatorHandler(apiKeyRotator apiKeyRotator) *APIKeyRotatorHandler {
+	return &APIKeyRotatorHandler{
+		apiKeyRotator: apiKeyRotator,
+	}
+}
+
+// Handle is the handler function to rotate an API key, it responds with the
+// new API key and its key, the body is optional.
+func (krh *APIKeyRotatorHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var httpAPIKeyRotationRequest httpAPIKeyRotationRequest
+	if err := c.ShouldBindJSON(&httpAPIKeyRotationRequest); err != nil &&
+		!errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apiKey, key, err := krh.apiKeyRotator.Rotate(c.Request.Context(),
+		id, httpAPIKeyRotationRequest.GracePeriod())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	httpAPIKey := httpAPIKeyFromAggregate(apiKey)
+	httpAPIKey.Key = key
+
+	c.JSON(http.StatusCreated, httpAPIKey)
+}
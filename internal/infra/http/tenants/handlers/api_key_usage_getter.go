@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// defaultUsageDays is the number of days of usage returned by default.
+const defaultUsageDays = 7
+
+// apiKeyUsageGetter defines the methods needed to get the usage of API keys.
+type apiKeyUsageGetter interface {
+	GetUsage(context.Context, int64, int) ([]aggregates.APIKeyUsage, error)
+}
+
+// APIKeyUsageGetterHandler defines the dependencies to get the usage of API
+// keys.
+type APIKeyUsageGetterHandler struct {
+	apiKeyUsageGetter apiKeyUsageGetter
+}
+
+// NewAPIKeyUsageGetterHandler initializes a new APIKeyUsageGetterHandler.
+func NewAPIKeyUsageGetterHandler(
+	apiKeyUsageGetter apiKeyUsageGetter) *APIKeyUsageGetterHandler {
+	return &APIKeyUsageGetterHandler{
+		apiKeyUsageGetter: apiKeyUsageGetter,
+	}
+}
+
+// Handle is the handler function to get the daily usage of an API key, the
+// number of days is set with the days query parameter.
+func (kugh *APIKeyUsageGetterHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days := defaultUsageDays
+	if value := c.Query("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be an integer"})
+			return
+		}
+	}
+
+	usage, err := kugh.apiKeyUsageGetter.GetUsage(c.Request.Context(), id, days)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpAPIKeyUsagesFromAggregates(usage))
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	authAggregates "github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// tenantResolver defines the methods needed to resolve the tenant of a
+// request from its credentials.
+type tenantResolver interface {
+	Resolve(context.Context, string) (aggregates.Tenant, error)
+	ResolveProject(context.Context, int64) (aggregates.Tenant, error)
+}
+
+// projectHeader is the header the principals that can act on any project
+// choose the project of the request with.
+const projectHeader = "X-Project-ID"
+
+// errProjectNotAllowed is returned when a principal that isn't bound to a
+// project can't choose one either, only the admins can act on any project.
+var errProjectNotAllowed = errors.New("principal can't choose the project")
+
+// TenantMiddleware defines the dependencies to scope the requests to their
+// tenant.
+type TenantMiddleware struct {
+	tenantResolver tenantResolver
+	enabled        bool
+}
+
+// NewTenantMiddleware initializes a new TenantMiddleware, when tenancy is not
+// enabled every request is scoped to the default tenant.
+func NewTenantMiddleware(
+	tenantResolver tenantResolver, enabled bool) *TenantMiddleware {
+	return &TenantMiddleware{
+		tenantResolver: tenantResolver,
+		enabled:        enabled,
+	}
+}
+
+// Handle resolves the tenant of the request and stores it in the request
+// context. The tenant is the project of the authenticated principal, the one
+// chosen with the X-Project-ID header by the principals that aren't bound to
+// a project or, when the authentication is disabled, the project of the
+// project token sent as a Bearer token. Only the admins can choose the
+// project, the other principals without one are forbidden.
+func (tm *TenantMiddleware) Handle(c *gin.Context) {
+	tenant, err := tm.tenant(c)
+	if err != nil {
+		status := httpStatusFromError(err)
+		if errors.Is(err, errProjectNotAllowed) {
+			status = http.StatusForbidden
+		}
+
+		c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request = c.Request.WithContext(
+		aggregates.NewContext(c.Request.Context(), tenant))
+
+	c.Next()
+}
+
+func (tm *TenantMiddleware) tenant(c *gin.Context) (aggregates.Tenant, error) {
+	if !tm.enabled {
+		return aggregates.DefaultTenant(), nil
+	}
+
+	ctx := c.Request.Context()
+
+	principal, ok := authAggregates.FromContext(ctx)
+	if !ok || principal.Anonymous {
+		return tm.tenantResolver.Resolve(ctx, bearerToken(c))
+	}
+
+	if principal.ProjectID != 0 {
+		if principal.OrganizationID != 0 {
+			return aggregates.Tenant{
+				OrganizationID: principal.OrganizationID,
+				ProjectID:      principal.ProjectID,
+			}, nil
+		}
+
+		return tm.tenantResolver.ResolveProject(ctx, principal.ProjectID)
+	}
+
+	if principal.Role != authAggregates.RoleAdmin {
+		return aggregates.Tenant{}, fmt.Errorf("%w: %w",
+			errProjectNotAllowed, aggregates.ErrInvalidProject)
+	}
+
+	value := c.GetHeader(projectHeader)
+	if value == "" {
+		return aggregates.DefaultTenant(), nil
+	}
+
+	projectID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || projectID <= 0 {
+		return aggregates.Tenant{}, fmt.Errorf("%s must be a positive integer: %w",
+			projectHeader, aggregates.ErrInvalidProject)
+	}
+
+	return tm.tenantResolver.ResolveProject(ctx, projectID)
+}
+
+// ingestionAuthorizer defines the methods needed to authorize the agents
+// events sent with an API key.
+type ingestionAuthorizer interface {
+	Authorize(context.Context, string, string) (aggregates.APIKey, error)
+}
+
+// IngestionMiddleware defines the dependencies to authorize the agents
+// events with their API key.
+type IngestionMiddleware struct {
+	ingestionAuthorizer ingestionAuthorizer
+	required            bool
+}
+
+// NewIngestionMiddleware initializes a new IngestionMiddleware, when API keys
+// are not required the events sent without one belong to the default
+// tenant.
+func NewIngestionMiddleware(
+	ingestionAuthorizer ingestionAuthorizer, required bool) *IngestionMiddleware {
+	return &IngestionMiddleware{
+		ingestionAuthorizer: ingestionAuthorizer,
+		required:            required,
+	}
+}
+
+// Handle authorizes the request with the API key sent in the X-API-Key
+// header, or the key query parameter for beacons, and stores the key and
+// its tenant in the request context, the events are rate limited with the
+// key once they are counted.
+func (im *IngestionMiddleware) Handle(c *gin.Context) {
+	key := c.GetHeader(apiKeyHeader)
+	if key == "" {
+		key = c.Query("key")
+	}
+
+	ctx := aggregates.NewContext(c.Request.Context(), aggregates.DefaultTenant())
+
+	if key != "" || im.required {
+		apiKey, err := im.ingestionAuthorizer.Authorize(
+			c.Request.Context(), key, requestOrigin(c))
+		if err != nil {
+			c.AbortWithStatusJSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx = aggregates.NewAPIKeyContext(
+			aggregates.NewContext(c.Request.Context(), apiKey.Tenant()), apiKey)
+	}
+
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
+
+// apiKeyHeader is the header the agents send their API key in.
+const apiKeyHeader = "X-API-Key"
+
+// requestOrigin returns the origin of the request, from its Origin header or
+// from its Referer when the browser didn't send it.
+func requestOrigin(c *gin.Context) string {
+	if origin := c.GetHeader("Origin"); origin != "" && origin != "null" {
+		return origin
+	}
+
+	referer, err := url.Parse(c.GetHeader("Referer"))
+	if err != nil || referer.Scheme == "" || referer.Host == "" {
+		return ""
+	}
+
+	return referer.Scheme + "://" + referer.Host
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	authAggregates "github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// projectResolver resolves every project to a tenant of organization 1.
+type projectResolver struct{}
+
+func (projectResolver) Resolve(
+	context.Context, string) (aggregates.Tenant, error) {
+	return aggregates.Tenant{}, aggregates.ErrUnauthorized
+}
+
+func (projectResolver) ResolveProject(
+	_ context.Context, projectID int64) (aggregates.Tenant, error) {
+	return aggregates.Tenant{OrganizationID: 1, ProjectID: projectID}, nil
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		principal authAggregates.Principal
+		project   string
+		status    int
+		projectID int64
+	}{
+		{
+			name: "principal bound to a project",
+			principal: authAggregates.Principal{
+				Role: authAggregates.RoleViewer, OrganizationID: 1, ProjectID: 7,
+			},
+			project:   "8",
+			status:    http.StatusOK,
+			projectID: 7,
+		},
+		{
+			name:      "admin choosing the project",
+			principal: authAggregates.Principal{Role: authAggregates.RoleAdmin},
+			project:   "8",
+			status:    http.StatusOK,
+			projectID: 8,
+		},
+		{
+			name:      "admin without a project",
+			principal: authAggregates.Principal{Role: authAggregates.RoleAdmin},
+			status:    http.StatusOK,
+			projectID: aggregates.DefaultProjectID,
+		},
+		{
+			name:      "admin choosing an invalid project",
+			principal: authAggregates.Principal{Role: authAggregates.RoleAdmin},
+			project:   "-1",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "editor choosing the project",
+			principal: authAggregates.Principal{Role: authAggregates.RoleEditor},
+			project:   "8",
+			status:    http.StatusForbidden,
+		},
+		{
+			name:      "viewer without a project",
+			principal: authAggregates.Principal{Role: authAggregates.RoleViewer},
+			status:    http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var projectID int64
+
+			router := gin.New()
+			router.GET("/",
+				func(c *gin.Context) {
+					c.Request = c.Request.WithContext(authAggregates.NewContext(
+						c.Request.Context(), test.principal))
+				},
+				NewTenantMiddleware(projectResolver{}, true).Handle,
+				func(c *gin.Context) {
+					projectID, _ = aggregates.ProjectIDFromContext(c.Request.Context())
+					c.Status(http.StatusOK)
+				},
+			)
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.project != "" {
+				request.Header.Set(projectHeader, test.project)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.status {
+				t.Fatalf("status = %d, want %d", recorder.Code, test.status)
+			}
+
+			if projectID != test.projectID {
+				t.Errorf("projectID = %d, want %d", projectID, test.projectID)
+			}
+		})
+	}
+}
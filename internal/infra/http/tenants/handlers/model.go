@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// httpOrganizationRequest represents the request to create an organization.
+type httpOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// ToAggregate converts the httpOrganizationRequest to an
+// aggregates.Organization.
+func (hor *httpOrganizationRequest) ToAggregate() aggregates.Organization {
+	return aggregates.Organization{
+		Name: strings.TrimSpace(hor.Name),
+		Slug: strings.TrimSpace(hor.Slug),
+	}
+}
+
+// httpOrganization represents an organization in the HTTP response.
+type httpOrganization struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func httpOrganizationFromAggregate(
+	organization aggregates.Organization) httpOrganization {
+	return httpOrganization{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		CreatedAt: organization.CreatedAt,
+		UpdatedAt: organization.UpdatedAt,
+	}
+}
+
+func httpOrganizationsFromAggregates(
+	organizations []aggregates.Organization) []httpOrganization {
+	httpOrganizations := make([]httpOrganization, len(organizations))
+	for i, organization := range organizations {
+		httpOrganizations[i] = httpOrganizationFromAggregate(organization)
+	}
+
+	return httpOrganizations
+}
+
+// httpProjectRequest represents the request to create a project.
+type httpProjectRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// ToAggregate converts the httpProjectRequest to an aggregates.Project of
+// the given organization.
+func (hpr *httpProjectRequest) ToAggregate(
+	organizationID int64) aggregates.Project {
+	return aggregates.Project{
+		OrganizationID: organizationID,
+		Name:           strings.TrimSpace(hpr.Name),
+		Slug:           strings.TrimSpace(hpr.Slug),
+	}
+}
+
+// httpProject represents a project in the HTTP response, the token is only
+// returned when the project is created.
+type httpProject struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	Name           string    `json:"name"`
+	Slug           string    `json:"slug"`
+	Token          string    `json:"token,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func httpProjectFromAggregate(project aggregates.Project) httpProject {
+	return httpProject{
+		ID:             project.ID,
+		OrganizationID: project.OrganizationID,
+		Name:           project.Name,
+		Slug:           project.Slug,
+		CreatedAt:      project.CreatedAt,
+		UpdatedAt:      project.UpdatedAt,
+	}
+}
+
+func httpProjectsFromAggregates(projects []aggregates.Project) []httpProject {
+	httpProjects := make([]httpProject, len(projects))
+	for i, project := range projects {
+		httpProjects[i] = httpProjectFromAggregate(project)
+	}
+
+	return httpProjects
+}
+
+// httpAPIKeyRequest represents the request to create an API key, the rate
+// limit and burst default to the aggregates ones when omitted.
+type httpAPIKeyRequest struct {
+	Name           string   `json:"name"`
+	AllowedOrigins []string `json:"allowed_origins"`
+	RateLimit      float64  `json:"rate_limit"`
+	Burst          int64    `json:"burst"`
+}
+
+// ToAggregate converts the httpAPIKeyRequest to an aggregates.APIKey.
+func (hkr *httpAPIKeyRequest) ToAggregate() aggregates.APIKey {
+	allowedOrigins := make([]string, 0, len(hkr.AllowedOrigins))
+	for _, origin := range hkr.AllowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowedOrigins = append(allowedOrigins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+
+	return aggregates.APIKey{
+		Name:           strings.TrimSpace(hkr.Name),
+		AllowedOrigins: allowedOrigins,
+		RateLimit:      hkr.RateLimit,
+		Burst:          hkr.Burst,
+	}
+}
+
+// httpAPIKeyRotationRequest represents the request to rotate an API key,
+// the old key keeps working for GracePeriodSeconds, a day when omitted.
+type httpAPIKeyRotationRequest struct {
+	GracePeriodSeconds *int64 `json:"grace_period_seconds"`
+}
+
+// GracePeriod returns the grace period of the rotation.
+func (hkrr *httpAPIKeyRotationRequest) GracePeriod() time.Duration {
+	if hkrr.GracePeriodSeconds == nil {
+		return defaultGracePeriod
+	}
+
+	return time.Duration(*hkrr.GracePeriodSeconds) * time.Second
+}
+
+// defaultGracePeriod is how long a rotated API key keeps working by default.
+const defaultGracePeriod = 24 * time.Hour
+
+// httpAPIKey represents an API key in the HTTP response, the key is only
+// returned when the API key is created or rotated.
+type httpAPIKey struct {
+	ID             int64      `json:"id"`
+	ProjectID      int64      `json:"project_id"`
+	Name           string     `json:"name"`
+	Hint           string     `json:"hint"`
+	Key            string     `json:"key,omitempty"`
+	AllowedOrigins []string   `json:"allowed_origins"`
+	RateLimit      float64    `json:"rate_limit"`
+	Burst          int64      `json:"burst"`
+	Active         bool       `json:"active"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func httpAPIKeyFromAggregate(apiKey aggregates.APIKey) httpAPIKey {
+	return httpAPIKey{
+		ID:             apiKey.ID,
+		ProjectID:      apiKey.ProjectID,
+		Name:           apiKey.Name,
+		Hint:           apiKey.Hint,
+		AllowedOrigins: apiKey.AllowedOrigins,
+		RateLimit:      apiKey.RateLimit,
+		Burst:          apiKey.Burst,
+		Active:         apiKey.Active(time.Now()),
+		ExpiresAt:      apiKey.ExpiresAt,
+		RevokedAt:      apiKey.RevokedAt,
+		CreatedAt:      apiKey.CreatedAt,
+		UpdatedAt:      apiKey.UpdatedAt,
+	}
+}
+
+func httpAPIKeysFromAggregates(apiKeys []aggregates.APIKey) []httpAPIKey {
+	httpAPIKeys := make([]httpAPIKey, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		httpAPIKeys[i] = httpAPIKeyFromAggregate(apiKey)
+	}
+
+	return httpAPIKeys
+}
+
+// httpAPIKeyUsage represents the usage of an API key in a day.
+type httpAPIKeyUsage struct {
+	Day              string `json:"day"`
+	Accepted         int64  `json:"accepted"`
+	RateLimited      int64  `json:"rate_limited"`
+	OriginNotAllowed int64  `json:"origin_not_allowed"`
+}
+
+func httpAPIKeyUsagesFromAggregates(
+	usages []aggregates.APIKeyUsage) []httpAPIKeyUsage {
+	httpUsages := make([]httpAPIKeyUsage, len(usages))
+	for i, usage := range usages {
+		httpUsages[i] = httpAPIKeyUsage{
+			Day:              usage.Day.Format(time.DateOnly),
+			Accepted:         usage.Accepted,
+			RateLimited:      usage.RateLimited,
+			OriginNotAllowed: usage.OriginNotAllowed,
+		}
+	}
+
+	return httpUsages
+}
+
+func idFromParam(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// bearerToken returns the token of the Authorization header, if any.
+func bearerToken(c *gin.Context) string {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// httpStatusFromError maps the tenants domain errors to HTTP status codes.
+func httpStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, aggregates.ErrOrganizationNotFound),
+		errors.Is(err, aggregates.ErrProjectNotFound),
+		errors.Is(err, aggregates.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, aggregates.ErrInvalidOrganization),
+		errors.Is(err, aggregates.ErrInvalidProject),
+		errors.Is(err, aggregates.ErrInvalidAPIKey):
+		return http.StatusBadRequest
+	case errors.Is(err, aggregates.ErrOrganizationAlreadyExists),
+		errors.Is(err, aggregates.ErrProjectAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, aggregates.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, aggregates.ErrOriginNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, aggregates.ErrRateLimited):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
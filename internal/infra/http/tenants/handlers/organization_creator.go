@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// organizationCreator defines the methods needed to create organizations.
+type organizationCreator interface {
+	Create(context.Context, aggregates.Organization) (aggregates.Organization, error)
+}
+
+// OrganizationCreatorHandler defines the dependencies to create
+// organizations.
+type OrganizationCreatorHandler struct {
+	organizationCreator organizationCreator
+}
+
+// NewOrganizationCreatorHandler initializes a new OrganizationCreatorHandler.
+func NewOrganizationCreatorHandler(
+	organizationCreator organizationCreator) *OrganizationCreatorHandler {
+	return &OrganizationCreatorHandler{
+		organizationCreator: organizationCreator,
+	}
+}
+
+// Handle is the handler function to create organizations.
+func (och *OrganizationCreatorHandler) Handle(c *gin.Context) {
+	var httpOrganizationRequest httpOrganizationRequest
+	if err := c.ShouldBindJSON(&httpOrganizationRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := och.organizationCreator.Create(
+		c.Request.Context(), httpOrganizationRequest.ToAggregate())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, httpOrganizationFromAggregate(organization))
+}
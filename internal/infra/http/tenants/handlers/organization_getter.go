@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// organizationGetter defines the methods needed to get organizations.
+type organizationGetter interface {
+	GetOrganizations(context.Context) ([]aggregates.Organization, error)
+	GetOrganization(context.Context, int64) (aggregates.Organization, error)
+}
+
+// OrganizationsGetterHandler defines the dependencies to list organizations.
+type OrganizationsGetterHandler struct {
+	organizationGetter organizationGetter
+}
+
+// NewOrganizationsGetterHandler initializes a new OrganizationsGetterHandler.
+func NewOrganizationsGetterHandler(
+	organizationGetter organizationGetter) *OrganizationsGetterHandler {
+	return &OrganizationsGetterHandler{
+		organizationGetter: organizationGetter,
+	}
+}
+
+// Handle is the handler function to list organizations.
+func (ogh *OrganizationsGetterHandler) Handle(c *gin.Context) {
+	organizations, err := ogh.organizationGetter.GetOrganizations(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpOrganizationsFromAggregates(organizations))
+}
+
+// OrganizationGetterHandler defines the dependencies to get an organization.
+type OrganizationGetterHandler struct {
+	organizationGetter organizationGetter
+}
+
+// NewOrganizationGetterHandler initializes a new OrganizationGetterHandler.
+func NewOrganizationGetterHandler(
+	organizationGetter organizationGetter) *OrganizationGetterHandler {
+	return &OrganizationGetterHandler{
+		organizationGetter: organizationGetter,
+	}
+}
+
+// Handle is the handler function to get an organization.
+func (ogh *OrganizationGetterHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := ogh.organizationGetter.GetOrganization(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpOrganizationFromAggregate(organization))
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// projectCreator defines the methods needed to create projects.
+type projectCreator interface {
+	Create(context.Context, aggregates.Project) (aggregates.Project, string, error)
+}
+
+// ProjectCreatorHandler defines the dependencies to create projects.
+type ProjectCreatorHandler struct {
+	projectCreator projectCreator
+}
+
+// NewProjectCreatorHandler initializes a new ProjectCreatorHandler.
+func NewProjectCreatorHandler(projectCreator projectCreator) *ProjectCreatorHandler {
+	return &ProjectCreatorHandler{
+		projectCreator: projectCreator,
+	}
+}
+
+// Handle is the handler function to create a project in an organization, the
+// response is the only time the project token is returned.
+func (pch *ProjectCreatorHandler) Handle(c *gin.Context) {
+	organizationID, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var httpProjectRequest httpProjectRequest
+	if err := c.ShouldBindJSON(&httpProjectRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, token, err := pch.projectCreator.Create(
+		c.Request.Context(), httpProjectRequest.ToAggregate(organizationID))
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	httpProject := httpProjectFromAggregate(project)
+	httpProject.Token = token
+
+	c.JSON(http.StatusCreated, httpProject)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// projectGetter defines the methods needed to get projects.
+type projectGetter interface {
+	GetProjects(context.Context, int64) ([]aggregates.Project, error)
+	GetProject(context.Context, int64) (aggregates.Project, error)
+}
+
+// ProjectsGetterHandler defines the dependencies to list the projects of an
+// organization.
+type ProjectsGetterHandler struct {
+	projectGetter projectGetter
+}
+
+// NewProjectsGetterHandler initializes a new ProjectsGetterHandler.
+func NewProjectsGetterHandler(projectGetter projectGetter) *ProjectsGetterHandler {
+	return &ProjectsGetterHandler{
+		projectGetter: projectGetter,
+	}
+}
+
+// Handle is the handler function to list the projects of an organization.
+func (pgh *ProjectsGetterHandler) Handle(c *gin.Context) {
+	organizationID, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	projects, err := pgh.projectGetter.GetProjects(c.Request.Context(), organizationID)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpProjectsFromAggregates(projects))
+}
+
+// ProjectGetterHandler defines the dependencies to get a project.
+type ProjectGetterHandler struct {
+	projectGetter projectGetter
+}
+
+// NewProjectGetterHandler initializes a new ProjectGetterHandler.
+func NewProjectGetterHandler(projectGetter projectGetter) *ProjectGetterHandler {
+	return &ProjectGetterHandler{
+		projectGetter: projectGetter,
+	}
+}
+
+// Handle is the handler function to get a project.
+func (pgh *ProjectGetterHandler) Handle(c *gin.Context) {
+	id, err := idFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := pgh.projectGetter.GetProject(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(httpStatusFromError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, httpProjectFromAggregate(project))
+}
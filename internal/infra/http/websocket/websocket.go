@@ -0,0 +1,289 @@
+// Package websocket implements the minimal subset of RFC 6455 needed to push
+// server-side text messages to a browser: the opening handshake, unmasked
+// text frames from the server and the close/ping handling of client frames.
+// The handshake checks the Origin of the browsers, which send the cookies
+// and credentials of the page's user along with cross-site WebSockets.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by RFC 6455.
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	handshakeGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opcodeText  = 0x1
+	opcodeClose = 0x8
+	opcodePing  = 0x9
+	opcodePong  = 0xA
+
+	// maxControlPayload is the maximum payload size RFC 6455 allows for
+	// control frames, we don't accept bigger data frames from clients either
+	// as clients are not expected to send anything but control frames.
+	maxControlPayload = 125
+
+	writeTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNotWebSocket is returned when the request is not a WebSocket
+	// upgrade request.
+	ErrNotWebSocket = errors.New("not a websocket upgrade request")
+	// ErrOriginNotAllowed is returned when the request comes from a web page
+	// whose origin is not allowed.
+	ErrOriginNotAllowed = errors.New("websocket origin not allowed")
+	// ErrClosed is returned when writing to a closed connection.
+	ErrClosed = errors.New("websocket connection closed")
+)
+
+// Conn is a server side WebSocket connection.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// Upgrade performs the WebSocket opening handshake and hijacks the underlying
+// connection. Requests sent by browsers must come from one of the
+// allowedOrigins, which can use a wildcard for the subdomains such as
+// "https://*.example.com", or from the same host when there are none.
+// Requests without Origin are not sent by browsers and are allowed.
+func Upgrade(w http.ResponseWriter, r *http.Request,
+	allowedOrigins []string) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, ErrNotWebSocket
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("unsupported websocket version: %w", ErrNotWebSocket)
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key: %w", ErrNotWebSocket)
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" &&
+		!originAllowed(origin, r.Host, allowedOrigins) {
+		return nil, fmt.Errorf("origin %q: %w", origin, ErrOriginNotAllowed)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("response writer doesn't support hijacking")
+	}
+
+	netConn, readWriter, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijacker.Hijack: %w", err)
+	}
+
+	hash := sha1.Sum([]byte(key + handshakeGUID)) //nolint:gosec
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n"
+
+	if _, err := readWriter.WriteString(response); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("readWriter.WriteString: %w", err)
+	}
+
+	if err := readWriter.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("readWriter.Flush: %w", err)
+	}
+
+	conn := &Conn{
+		conn:   netConn,
+		reader: readWriter.Reader,
+		done:   make(chan struct{}),
+	}
+
+	go conn.readLoop()
+
+	return conn, nil
+}
+
+// Done returns a channel that is closed once the connection is closed, either
+// by the client or by the server.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// WriteText sends a text message to the client.
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(opcodeText, data)
+}
+
+// Close sends a close frame to the client and closes the connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opcodeClose, []byte{0x03, 0xE8}) // 1000: normal closure.
+
+	return c.close()
+}
+
+func (c *Conn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	close(c.done)
+
+	return c.conn.Close()
+}
+
+// writeFrame writes a single unmasked, unfragmented frame.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+
+	switch length := len(payload); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return fmt.Errorf("c.conn.SetWriteDeadline: %w", err)
+	}
+
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("c.conn.Write: %w", err)
+	}
+
+	return nil
+}
+
+// readLoop reads the client frames, answering pings and closing the
+// connection whenever the client closes it or sends something unexpected.
+func (c *Conn) readLoop() {
+	defer c.close()
+
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opcodePing:
+			if err := c.writeFrame(opcodePong, payload); err != nil {
+				return
+			}
+		case opcodeClose:
+			_ = c.writeFrame(opcodeClose, payload)
+			return
+		}
+	}
+}
+
+func (c *Conn) readFrame() (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return 0, nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	final := header[0]&0x80 != 0
+	reserved := header[0] & 0x70
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int(header[1] & 0x7F)
+
+	// The client frames must be masked and no extension was negotiated to
+	// use the reserved bits. Control frames, from opcodeClose on, can't be
+	// fragmented but may be sent between the fragments of the data frames,
+	// which are ignored.
+	if !masked || reserved != 0 || length > maxControlPayload ||
+		(opcode >= opcodeClose && !final) {
+		return 0, nil, errors.New("unexpected client frame")
+	}
+
+	mask := make([]byte, 4)
+	if _, err := io.ReadFull(c.reader, mask); err != nil {
+		return 0, nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
+
+// originAllowed returns whether the origin is one of the allowed origins,
+// or the host itself when there are none.
+func originAllowed(origin, host string, allowedOrigins []string) bool {
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+
+	if len(allowedOrigins) == 0 {
+		parsed, err := url.Parse(origin)
+		return err == nil && parsed.Host == strings.ToLower(host)
+	}
+
+	for _, allowed := range allowedOrigins {
+		allowed = strings.ToLower(strings.TrimSuffix(allowed, "/"))
+
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		scheme, domain, found := strings.Cut(allowed, "://*.")
+		if found && strings.HasPrefix(origin, scheme+"://") &&
+			strings.HasSuffix(origin, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func headerContains(header http.Header, name, value string) bool {
+	for _, headerValue := range header.Values(name) {
+		for _, token := range strings.Split(headerValue, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,471 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKey and testAccept are the handshake example of RFC 6455 section 1.3.
+const (
+	testKey    = "dGhlIHNhbXBsZSBub25jZQ=="
+	testAccept = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+)
+
+var testMask = []byte{0x37, 0xfa, 0x21, 0x3d}
+
+// newTestServer starts a server upgrading every request, the upgraded
+// connections are sent to the returned channel.
+func newTestServer(t *testing.T, allowedOrigins []string) (*httptest.Server, <-chan *Conn) {
+	t.Helper()
+
+	conns := make(chan *Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, allowedOrigins)
+		switch {
+		case errors.Is(err, ErrOriginNotAllowed):
+			w.WriteHeader(http.StatusForbidden)
+		case err != nil:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			conns <- conn
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, conns
+}
+
+// dial sends a handshake request with the headers, which replace the
+// default ones when set and are removed when empty.
+func dial(t *testing.T, server *httptest.Server,
+	headers map[string]string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+
+	netConn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial: %v", err)
+	}
+	t.Cleanup(func() { netConn.Close() })
+
+	if err := netConn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("netConn.SetDeadline: %v", err)
+	}
+
+	request := map[string]string{
+		"Upgrade":               "websocket",
+		"Connection":            "Upgrade",
+		"Sec-WebSocket-Key":     testKey,
+		"Sec-WebSocket-Version": "13",
+	}
+	for name, value := range headers {
+		request[name] = value
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "GET / HTTP/1.1\r\nHost: %s\r\n", server.Listener.Addr())
+	for name, value := range request {
+		if value != "" {
+			fmt.Fprintf(&builder, "%s: %s\r\n", name, value)
+		}
+	}
+	builder.WriteString("\r\n")
+
+	if _, err := netConn.Write([]byte(builder.String())); err != nil {
+		t.Fatalf("netConn.Write: %v", err)
+	}
+
+	reader := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("http.ReadResponse: %v", err)
+	}
+
+	return netConn, reader, resp
+}
+
+// connect dials a server upgrading every request, returning both ends of
+// the connection.
+func connect(t *testing.T) (net.Conn, *bufio.Reader, *Conn) {
+	t.Helper()
+
+	server, conns := newTestServer(t, nil)
+
+	netConn, reader, resp := dial(t, server, nil)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	select {
+	case conn := <-conns:
+		t.Cleanup(func() { conn.close() })
+		return netConn, reader, conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection wasn't upgraded")
+		return nil, nil, nil
+	}
+}
+
+// clientFrame encodes a client frame, masked with testMask when masked.
+func clientFrame(first byte, payload []byte, masked bool) []byte {
+	frame := []byte{first, byte(len(payload))}
+	if !masked {
+		return append(frame, payload...)
+	}
+
+	frame[1] |= 0x80
+	frame = append(frame, testMask...)
+	for i, b := range payload {
+		frame = append(frame, b^testMask[i%4])
+	}
+
+	return frame
+}
+
+// readServerFrame reads a server frame, failing when it's masked.
+func readServerFrame(t *testing.T, reader *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("io.ReadFull(header): %v", err)
+	}
+
+	if header[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			t.Fatalf("io.ReadFull(length): %v", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			t.Fatalf("io.ReadFull(length): %v", err)
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("io.ReadFull(payload): %v", err)
+	}
+
+	return header[0], payload
+}
+
+// expectClosed checks the server closed the connection without sending
+// anything else.
+func expectClosed(t *testing.T, reader *bufio.Reader, conn *Conn) {
+	t.Helper()
+
+	if b, err := reader.ReadByte(); err == nil {
+		t.Fatalf("read %#x, want the connection closed", b)
+	}
+
+	select {
+	case <-conn.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done wasn't closed")
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		headers        map[string]string
+		wantStatus     int
+	}{
+		{
+			name:       "without origin",
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:           "without origin and allowed origins",
+			allowedOrigins: []string{"https://app.example.com"},
+			wantStatus:     http.StatusSwitchingProtocols,
+		},
+		{
+			name:           "allowed origin",
+			allowedOrigins: []string{"https://app.example.com"},
+			headers:        map[string]string{"Origin": "https://app.example.com"},
+			wantStatus:     http.StatusSwitchingProtocols,
+		},
+		{
+			name:           "not allowed origin",
+			allowedOrigins: []string{"https://app.example.com"},
+			headers:        map[string]string{"Origin": "https://evil.example.org"},
+			wantStatus:     http.StatusForbidden,
+		},
+		{
+			name:       "cross-site origin without allowed origins",
+			headers:    map[string]string{"Origin": "https://evil.example.org"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing key",
+			headers:    map[string]string{"Sec-WebSocket-Key": ""},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported version",
+			headers:    map[string]string{"Sec-WebSocket-Version": "8"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not an upgrade",
+			headers:    map[string]string{"Upgrade": "", "Connection": "keep-alive"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, tt.allowedOrigins)
+
+			_, _, resp := dial(t, server, tt.headers)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantStatus != http.StatusSwitchingProtocols {
+				return
+			}
+
+			if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != testAccept {
+				t.Errorf("Sec-WebSocket-Accept = %q, want %q", accept, testAccept)
+			}
+		})
+	}
+}
+
+func TestUpgradeSameOrigin(t *testing.T) {
+	server, _ := newTestServer(t, nil)
+
+	_, _, resp := dial(t, server,
+		map[string]string{"Origin": "http://" + server.Listener.Addr().String()})
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name           string
+		origin         string
+		host           string
+		allowedOrigins []string
+		want           bool
+	}{
+		{"same host", "https://collector.example.com", "collector.example.com", nil, true},
+		{"same host and port", "http://localhost:3001", "localhost:3001", nil, true},
+		{"other host", "https://evil.example.org", "collector.example.com", nil, false},
+		{"other port", "http://localhost:3000", "localhost:3001", nil, false},
+		{"exact", "https://app.example.com", "", []string{"https://app.example.com"}, true},
+		{"case and trailing slash", "HTTPS://App.Example.com/", "", []string{"https://app.example.com"}, true},
+		{"other scheme", "http://app.example.com", "", []string{"https://app.example.com"}, false},
+		{"any", "https://evil.example.org", "", []string{"*"}, true},
+		{"subdomain wildcard", "https://app.example.com", "", []string{"https://*.example.com"}, true},
+		{"wildcard apex", "https://example.com", "", []string{"https://*.example.com"}, false},
+		{"wildcard suffix", "https://app.example.com.evil.org", "", []string{"https://*.example.com"}, false},
+		{"wildcard lookalike", "https://evilexample.com", "", []string{"https://*.example.com"}, false},
+		{"null origin", "null", "collector.example.com", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originAllowed(tt.origin, tt.host, tt.allowedOrigins); got != tt.want {
+				t.Errorf("originAllowed(%q, %q, %v) = %t, want %t",
+					tt.origin, tt.host, tt.allowedOrigins, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     int
+		wantLength byte
+	}{
+		{"empty", 0, 0},
+		{"7-bit length", 125, 125},
+		{"16-bit length", 126, 126},
+		{"16-bit length max", 0xFFFF, 126},
+		{"64-bit length", 0x10000, 127},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reader, conn := connect(t)
+
+			payload := bytes.Repeat([]byte("a"), tt.length)
+			go func() {
+				if err := conn.WriteText(payload); err != nil {
+					t.Errorf("conn.WriteText: %v", err)
+				}
+			}()
+
+			header, err := reader.Peek(2)
+			if err != nil {
+				t.Fatalf("reader.Peek: %v", err)
+			}
+
+			if header[1] != tt.wantLength {
+				t.Errorf("length byte = %d, want %d", header[1], tt.wantLength)
+			}
+
+			first, got := readServerFrame(t, reader)
+			if first != 0x80|opcodeText {
+				t.Errorf("first byte = %#x, want a final text frame", first)
+			}
+
+			if !bytes.Equal(got, payload) {
+				t.Errorf("payload length = %d, want %d", len(got), len(payload))
+			}
+		})
+	}
+}
+
+func TestReadFrames(t *testing.T) {
+	tests := []struct {
+		name       string
+		frames     [][]byte
+		wantPongs  [][]byte
+		wantClosed bool
+	}{
+		{
+			name:      "masked ping",
+			frames:    [][]byte{clientFrame(0x80|opcodePing, []byte("hello"), true)},
+			wantPongs: [][]byte{[]byte("hello")},
+		},
+		{
+			name:       "unmasked ping",
+			frames:     [][]byte{clientFrame(0x80|opcodePing, []byte("hello"), false)},
+			wantClosed: true,
+		},
+		{
+			name:       "fragmented ping",
+			frames:     [][]byte{clientFrame(opcodePing, []byte("hello"), true)},
+			wantClosed: true,
+		},
+		{
+			name:       "reserved bits",
+			frames:     [][]byte{clientFrame(0xC0|opcodePing, []byte("hello"), true)},
+			wantClosed: true,
+		},
+		{
+			name: "too long frame",
+			frames: [][]byte{append([]byte{0x80 | opcodeText, 0x80 | 126, 0x00, 0xFF},
+				testMask...)},
+			wantClosed: true,
+		},
+		{
+			name: "ping between fragments",
+			frames: [][]byte{
+				clientFrame(opcodeText, []byte("frag"), true),
+				clientFrame(0x80|opcodePing, []byte("one"), true),
+				clientFrame(0x80, []byte("ment"), true),
+				clientFrame(0x80|opcodePing, []byte("two"), true),
+			},
+			wantPongs: [][]byte{[]byte("one"), []byte("two")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			netConn, reader, conn := connect(t)
+
+			for _, frame := range tt.frames {
+				if _, err := netConn.Write(frame); err != nil {
+					t.Fatalf("netConn.Write: %v", err)
+				}
+			}
+
+			for _, want := range tt.wantPongs {
+				first, payload := readServerFrame(t, reader)
+				if first != 0x80|opcodePong {
+					t.Fatalf("first byte = %#x, want a final pong frame", first)
+				}
+
+				if !bytes.Equal(payload, want) {
+					t.Errorf("pong payload = %q, want %q", payload, want)
+				}
+			}
+
+			if tt.wantClosed {
+				expectClosed(t, reader, conn)
+				return
+			}
+
+			select {
+			case <-conn.Done():
+				t.Fatal("the connection was closed")
+			default:
+			}
+		})
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	netConn, reader, conn := connect(t)
+
+	normalClosure := []byte{0x03, 0xE8}
+	if _, err := netConn.Write(
+		clientFrame(0x80|opcodeClose, normalClosure, true)); err != nil {
+		t.Fatalf("netConn.Write: %v", err)
+	}
+
+	first, payload := readServerFrame(t, reader)
+	if first != 0x80|opcodeClose {
+		t.Fatalf("first byte = %#x, want a final close frame", first)
+	}
+
+	if !bytes.Equal(payload, normalClosure) {
+		t.Errorf("close payload = %v, want %v", payload, normalClosure)
+	}
+
+	expectClosed(t, reader, conn)
+
+	if err := conn.WriteText([]byte("late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("conn.WriteText = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	_, reader, conn := connect(t)
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("conn.Close: %v", err)
+	}
+
+	first, payload := readServerFrame(t, reader)
+	if first != 0x80|opcodeClose {
+		t.Fatalf("first byte = %#x, want a final close frame", first)
+	}
+
+	if want := []byte{0x03, 0xE8}; !bytes.Equal(payload, want) {
+		t.Errorf("close payload = %v, want %v", payload, want)
+	}
+
+	expectClosed(t, reader, conn)
+
+	if err := conn.Close(); err != nil {
+		t.Errorf("second conn.Close = %v, want nil", err)
+	}
+}
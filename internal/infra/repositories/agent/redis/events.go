@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/redis/go-redis/v9"
+
 	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
 )
 
@@ -44,49 +46,30 @@ func (r *Repository) SubscribeToEvents(
 	return eventChannel, errorChannel
 }
 
-// PublishEvent publishes an event to the 'agent_events' channel.
-func (r *Repository) PublishEvent(
-	ctx context.Context, event aggregates.Event) error {
-	message, err := json.Marshal(redisEventFromAggregate(event))
-	if err != nil {
-		return fmt.Errorf("json.Marshal: %w", err)
-	}
+// PublishEvents publishes a batch of events to the 'agent_events' channel in
+// a single pipelined round trip.
+func (r *Repository) PublishEvents(
+	ctx context.Context, events []aggregates.Event) error {
+	messages := make([]string, len(events))
+	for i, event := range events {
+		message, err := json.Marshal(redisEventFromAggregate(event))
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
 
-	err = r.client.Publish(ctx, channel, string(message)).Err()
-	if err != nil {
-		return fmt.Errorf("client.Publish: %w", err)
+		messages[i] = string(message)
 	}
 
-	return nil
-}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, message := range messages {
+			pipe.Publish(ctx, channel, message)
+		}
 
-// SetEvent sets an event in the redis repository.
-func (r *Repository) SetEvent(
-	ctx context.Context, key string, event aggregates.Event) error {
-	message, err := json.Marshal(redisEventFromAggregate(event))
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("json.Marshal: %w", err)
-	}
-
-	if err = r.client.Set(ctx, key, string(message), 0).Err(); err != nil {
-		return fmt.Errorf("client.Set: %w", err)
+		return fmt.Errorf("client.Pipelined: %w", err)
 	}
 
 	return nil
 }
-
-// GetEvent gets an event from the redis repository.
-func (r *Repository) GetEvent(
-	ctx context.Context, key string) (aggregates.Event, error) {
-	message, err := r.client.Get(ctx, key).Result()
-	if err != nil {
-		return aggregates.Event{}, fmt.Errorf("client.Get: %w", err)
-	}
-
-	var redisEvent redisEvent
-	if err = json.Unmarshal([]byte(message), &redisEvent); err != nil {
-		return aggregates.Event{}, fmt.Errorf("json.Unmarshal: %w", err)
-	}
-
-	return redisEvent.toAggregate(), nil
-}
@@ -10,6 +10,12 @@ import (
 // browser/mobile, including both request and response data.
 type redisEvent struct {
 	ID                string         `json:"id"`
+	ProjectID         int64          `json:"project_id"`
+	SchemaVersion     int            `json:"schema_version"`
+	Agent             string         `json:"agent"`
+	AgentVersion      string         `json:"agent_version"`
+	EventType         string         `json:"event_type"`
+	Region            string         `json:"region,omitempty"`
 	ClientID          string         `json:"client_id"`
 	BrowserID         string         `json:"browser_id"`
 	Handled           interface{}    `json:"handled"`
@@ -18,63 +24,129 @@ type redisEvent struct {
 	EventTime         time.Time      `json:"event_time"`
 	Request           *redisRequest  `json:"request,omitempty"`
 	Response          *redisResponse `json:"response,omitempty"`
+	Error             *redisError    `json:"error,omitempty"`
+	Truncated         bool           `json:"truncated,omitempty"`
 
 	// Processed Information
-	ProgramIDs []string `json:"program_ids"`
+	ProgramIDs  []string              `json:"program_ids"`
+	Transaction *redisSentTransaction `json:"transaction,omitempty"`
+}
+
+// redisSentTransaction represents the redis version of the transaction a
+// sendTransaction call sent.
+type redisSentTransaction struct {
+	Signature        string   `json:"signature"`
+	Version          string   `json:"version"`
+	FeePayer         string   `json:"fee_payer"`
+	RecentBlockhash  string   `json:"recent_blockhash"`
+	ComputeUnitLimit *uint32  `json:"compute_unit_limit,omitempty"`
+	ComputeUnitPrice *uint64  `json:"compute_unit_price,omitempty"`
+	Size             int      `json:"size"`
+	LookupTables     []string `json:"lookup_tables,omitempty"`
+}
+
+// redisError represents the redis version of the error the agent reported.
+type redisError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
 
 // redisRequest struct represents the redis version of a browser/mobile request.
 type redisRequest struct {
-	RequestTime    time.Time   `json:"request_time"`
-	Body           *string     `json:"body,omitempty"`
-	BodyUsed       bool        `json:"body_used"`
-	Cache          string      `json:"cache"`
-	Credentials    string      `json:"credentials"`
-	Destination    string      `json:"destination"`
-	Headers        interface{} `json:"headers"`
-	Integrity      string      `json:"integrity"`
-	Method         string      `json:"method"`
-	Mode           string      `json:"mode"`
-	Redirect       string      `json:"redirect"`
-	Referrer       string      `json:"referrer"`
-	ReferrerPolicy string      `json:"referrer_policy"`
-	URL            string      `json:"url"`
-	Signal         interface{} `json:"signal"`
+	RequestTime    time.Time         `json:"request_time"`
+	Body           *string           `json:"body,omitempty"`
+	BodyUsed       bool              `json:"body_used"`
+	Cache          string            `json:"cache"`
+	Credentials    string            `json:"credentials"`
+	Destination    string            `json:"destination"`
+	Headers        map[string]string `json:"headers"`
+	Integrity      string            `json:"integrity"`
+	Method         string            `json:"method"`
+	Mode           string            `json:"mode"`
+	Redirect       string            `json:"redirect"`
+	Referrer       string            `json:"referrer"`
+	ReferrerPolicy string            `json:"referrer_policy"`
+	URL            string            `json:"url"`
 }
 
 // redisResponse struct represents the redis version of a browser/mobile
 // response.
 type redisResponse struct {
-	ResponseTime time.Time   `json:"response_time"`
-	Body         *string     `json:"body,omitempty"`
-	BodyUsed     bool        `json:"body_used"`
-	Headers      interface{} `json:"headers"`
-	Ok           bool        `json:"ok"`
-	Redirected   bool        `json:"redirected"`
-	Status       uint16      `json:"status"`
-	StatusText   string      `json:"status_text"`
-	ResponseType string      `json:"response_type"`
-	URL          string      `json:"url"`
+	ResponseTime time.Time         `json:"response_time"`
+	Body         *string           `json:"body,omitempty"`
+	BodyUsed     bool              `json:"body_used"`
+	Headers      map[string]string `json:"headers"`
+	Ok           bool              `json:"ok"`
+	Redirected   bool              `json:"redirected"`
+	Status       uint16            `json:"status"`
+	StatusText   string            `json:"status_text"`
+	ResponseType string            `json:"response_type"`
+	URL          string            `json:"url"`
 }
 
 func (r *redisEvent) toAggregate() aggregates.Event {
+	// Events published before the schema was versioned carry the browser
+	// handled value as is, only booleans are kept.
+	handled, _ := r.Handled.(bool)
+
 	return aggregates.Event{
 		ID:                r.ID,
+		ProjectID:         r.ProjectID,
+		SchemaVersion:     r.SchemaVersion,
+		Agent:             r.Agent,
+		AgentVersion:      r.AgentVersion,
+		EventType:         r.EventType,
+		Region:            r.Region,
 		ClientID:          r.ClientID,
 		BrowserID:         r.BrowserID,
-		Handled:           r.Handled,
+		Handled:           handled,
 		ReplacesClientID:  r.ReplacesClientID,
 		ResultingClientID: r.ResultingClientID,
 		EventTime:         r.EventTime,
 		Request:           r.Request.toAggregate(),
 		Response:          r.Response.toAggregate(),
+		Error:             r.Error.toAggregate(),
+		Truncated:         r.Truncated,
 
 		// Processed Information
-		ProgramIDs: r.ProgramIDs,
+		ProgramIDs:  r.ProgramIDs,
+		Transaction: r.Transaction.toAggregate(),
+	}
+}
+
+func (r *redisSentTransaction) toAggregate() *aggregates.SentTransaction {
+	if r == nil {
+		return nil
+	}
+
+	return &aggregates.SentTransaction{
+		Signature:        r.Signature,
+		Version:          r.Version,
+		FeePayer:         r.FeePayer,
+		RecentBlockhash:  r.RecentBlockhash,
+		ComputeUnitLimit: r.ComputeUnitLimit,
+		ComputeUnitPrice: r.ComputeUnitPrice,
+		Size:             r.Size,
+		LookupTables:     r.LookupTables,
+	}
+}
+
+func (r *redisError) toAggregate() *aggregates.EventError {
+	if r == nil {
+		return nil
+	}
+
+	return &aggregates.EventError{
+		Type:    r.Type,
+		Message: r.Message,
 	}
 }
 
 func (r *redisRequest) toAggregate() *aggregates.Request {
+	if r == nil {
+		return nil
+	}
+
 	return &aggregates.Request{
 		RequestTime:    r.RequestTime,
 		Body:           r.Body,
@@ -90,11 +162,14 @@ func (r *redisRequest) toAggregate() *aggregates.Request {
 		Referrer:       r.Referrer,
 		ReferrerPolicy: r.ReferrerPolicy,
 		URL:            r.URL,
-		Signal:         r.Signal,
 	}
 }
 
 func (r *redisResponse) toAggregate() *aggregates.Response {
+	if r == nil {
+		return nil
+	}
+
 	return &aggregates.Response{
 		ResponseTime: r.ResponseTime,
 		Body:         r.Body,
@@ -120,8 +195,24 @@ func redisEventFromAggregate(event aggregates.Event) redisEvent {
 		redisResponse = redisResponseFromAggregate(*event.Response)
 	}
 
+	var redisError *redisError
+	if event.Error != nil {
+		redisError = redisErrorFromAggregate(*event.Error)
+	}
+
+	var redisSentTransaction *redisSentTransaction
+	if event.Transaction != nil {
+		redisSentTransaction = redisSentTransactionFromAggregate(*event.Transaction)
+	}
+
 	return redisEvent{
 		ID:                event.ID,
+		ProjectID:         event.ProjectID,
+		SchemaVersion:     event.SchemaVersion,
+		Agent:             event.Agent,
+		AgentVersion:      event.AgentVersion,
+		EventType:         event.EventType,
+		Region:            event.Region,
 		BrowserID:         event.BrowserID,
 		ClientID:          event.ClientID,
 		Handled:           event.Handled,
@@ -130,9 +221,33 @@ func redisEventFromAggregate(event aggregates.Event) redisEvent {
 		EventTime:         event.EventTime,
 		Request:           redisRequest,
 		Response:          redisResponse,
+		Error:             redisError,
+		Truncated:         event.Truncated,
 
 		// Processed Information
-		ProgramIDs: event.ProgramIDs,
+		ProgramIDs:  event.ProgramIDs,
+		Transaction: redisSentTransaction,
+	}
+}
+
+func redisSentTransactionFromAggregate(
+	transaction aggregates.SentTransaction) *redisSentTransaction {
+	return &redisSentTransaction{
+		Signature:        transaction.Signature,
+		Version:          transaction.Version,
+		FeePayer:         transaction.FeePayer,
+		RecentBlockhash:  transaction.RecentBlockhash,
+		ComputeUnitLimit: transaction.ComputeUnitLimit,
+		ComputeUnitPrice: transaction.ComputeUnitPrice,
+		Size:             transaction.Size,
+		LookupTables:     transaction.LookupTables,
+	}
+}
+
+func redisErrorFromAggregate(eventError aggregates.EventError) *redisError {
+	return &redisError{
+		Type:    eventError.Type,
+		Message: eventError.Message,
 	}
 }
 
@@ -152,7 +267,6 @@ func redisRequestFromAggregate(request aggregates.Request) *redisRequest {
 		Referrer:       request.Referrer,
 		ReferrerPolicy: request.ReferrerPolicy,
 		URL:            request.URL,
-		Signal:         request.Signal,
 	}
 }
 
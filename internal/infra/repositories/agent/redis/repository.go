@@ -2,16 +2,27 @@ package redis
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
 )
 
+// Options are the options of the stored events, every key is namespaced
+// with KeyPrefix and the events expire after the TTL of their class.
+type Options struct {
+	KeyPrefix string
+	TTLs      map[aggregates.EventClass]time.Duration
+}
+
 type Repository struct {
-	client *redis.Client
+	client  *redis.Client
+	options Options
 }
 
-func New(client *redis.Client) *Repository {
-	return &Repository{client: client}
+func New(client *redis.Client, options Options) *Repository {
+	return &Repository{client: client, options: options}
 }
 
 func newClient(url, port string, dataBase int) *redis.Client {
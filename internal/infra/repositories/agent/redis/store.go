@@ -0,0 +1,557 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// memorySamples is the amount of events of every class the memory of
+	// the class is estimated from.
+	memorySamples = 20
+
+	// scanCount is the amount of keys every SCAN iteration asks for.
+	scanCount = 500
+
+	// maxSubmissions is the amount of submissions of a transaction kept, a
+	// client stuck resending it can't grow its list forever.
+	maxSubmissions = 100
+
+	// legacyEventsPattern matches the events stored before the keys were
+	// namespaced, which were stored without expiration.
+	legacyEventsPattern = "[0-9]*.sendTransaction.*"
+
+	// baselineEventsPattern matches the events stored before the projects,
+	// whose keys have no project, also stored without expiration.
+	baselineEventsPattern = "sendTransaction.*"
+)
+
+// SetEvent sets an event in the redis repository, expiring after the TTL of
+// its class, and indexes it by time, client and browser. The pending events
+// are queued to be reconciled with the transactions that landed.
+//
+// Every event set under the same key is kept as a submission of the same
+// transaction, the key itself holds the latest one. The submissions of a
+// transaction that already landed stay joined.
+//
+// The indexes are sorted sets of the event keys scored by the event time,
+// and the class indexes by the expiration time so the expired events can
+// be pruned from them.
+func (r *Repository) SetEvent(ctx context.Context, key string,
+	class aggregates.EventClass, event aggregates.Event) error {
+	ttl, ok := r.options.TTLs[class]
+	if !ok || ttl <= 0 {
+		return fmt.Errorf("no ttl for the %q events", class)
+	}
+
+	message, err := json.Marshal(redisEventFromAggregate(event))
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	landed, err := r.client.Exists(ctx, r.landedKey(key)).Result()
+	if err != nil {
+		return fmt.Errorf("client.Exists: %w", err)
+	}
+
+	if landed > 0 {
+		class = aggregates.EventClassJoined
+		ttl = r.options.TTLs[class]
+	}
+
+	eventTime := redis.Z{Score: float64(event.EventTime.UnixMilli()), Member: key}
+	indexes := []string{r.timeIndex(event.ProjectID)}
+	if event.ClientID != "" {
+		indexes = append(indexes, r.clientIndex(event.ProjectID, event.ClientID))
+	}
+	if event.BrowserID != "" {
+		indexes = append(indexes, r.browserIndex(event.ProjectID, event.BrowserID))
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, r.key(key), string(message), ttl)
+		pipe.RPush(ctx, r.submissionsKey(key), string(message))
+		pipe.LTrim(ctx, r.submissionsKey(key), -maxSubmissions, -1)
+		pipe.Expire(ctx, r.submissionsKey(key), ttl)
+
+		for _, other := range aggregates.EventClasses {
+			if other != class {
+				pipe.ZRem(ctx, r.classIndex(other), key)
+			}
+		}
+		pipe.ZAdd(ctx, r.classIndex(class), redis.Z{
+			Score:  float64(time.Now().Add(ttl).UnixMilli()),
+			Member: key,
+		})
+
+		if class == aggregates.EventClassPending {
+			pipe.ZAdd(ctx, r.reconcileIndex(), eventTime)
+		}
+
+		// The indexes outlive their events at most by the longest TTL, the
+		// events they point to are pruned as they expire.
+		for _, index := range indexes {
+			pipe.ZAdd(ctx, index, eventTime)
+			pipe.Expire(ctx, index, r.maxTTL())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("client.TxPipelined: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvent gets an event from the redis repository, falling back to the
+// events stored before the keys were namespaced.
+func (r *Repository) GetEvent(
+	ctx context.Context, key string) (aggregates.Event, error) {
+	message, err := r.client.Get(ctx, r.key(key)).Result()
+	for _, legacyKey := range r.legacyKeys(key) {
+		if !errors.Is(err, redis.Nil) {
+			break
+		}
+
+		message, err = r.client.Get(ctx, legacyKey).Result()
+	}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return aggregates.Event{}, aggregates.ErrEventNotFound
+		}
+
+		return aggregates.Event{}, fmt.Errorf("client.Get: %w", err)
+	}
+
+	var redisEvent redisEvent
+	if err = json.Unmarshal([]byte(message), &redisEvent); err != nil {
+		return aggregates.Event{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return redisEvent.toAggregate(), nil
+}
+
+// GetSubmissions gets every submission of the transaction of an event,
+// sorted by request time, and when it landed. The events stored before the
+// submissions were kept only have their latest one.
+func (r *Repository) GetSubmissions(
+	ctx context.Context, key string) (aggregates.Submissions, error) {
+	var (
+		messages *redis.StringSliceCmd
+		landedAt *redis.StringCmd
+	)
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		messages = pipe.LRange(ctx, r.submissionsKey(key), 0, -1)
+		landedAt = pipe.Get(ctx, r.landedKey(key))
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return aggregates.Submissions{}, fmt.Errorf("client.Pipelined: %w", err)
+	}
+
+	var submissions aggregates.Submissions
+	for _, message := range messages.Val() {
+		var redisEvent redisEvent
+		if err := json.Unmarshal([]byte(message), &redisEvent); err != nil {
+			return aggregates.Submissions{}, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		if event := redisEvent.toAggregate(); event.Request != nil {
+			submissions.Events = append(submissions.Events, event)
+		}
+	}
+
+	if len(submissions.Events) == 0 {
+		event, err := r.GetEvent(ctx, key)
+		if err != nil {
+			return aggregates.Submissions{}, fmt.Errorf("r.GetEvent: %w", err)
+		}
+
+		if event.Request == nil {
+			return aggregates.Submissions{}, aggregates.ErrEventNotFound
+		}
+
+		submissions.Events = []aggregates.Event{event}
+	}
+
+	if value := landedAt.Val(); value != "" {
+		landed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return aggregates.Submissions{}, fmt.Errorf("time.Parse: %w", err)
+		}
+
+		submissions.LandedAt = &landed
+	}
+
+	events := submissions.Events
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Request.RequestTime.Before(events[j].Request.RequestTime)
+	})
+
+	if transaction := events[len(events)-1].Transaction; transaction != nil {
+		submissions.Signature = transaction.Signature
+	}
+
+	return submissions, nil
+}
+
+// JoinEvent moves a pending event to the joined class once it's joined
+// with its transaction, landed at the given time, so it expires after the
+// joined TTL along with its submissions.
+func (r *Repository) JoinEvent(ctx context.Context,
+	key string, landedAt time.Time) error {
+	ttl := r.options.TTLs[aggregates.EventClassJoined]
+
+	joined, err := r.client.Expire(ctx, r.key(key), ttl).Result()
+	if err != nil {
+		return fmt.Errorf("client.Expire, err: %w", err)
+	}
+
+	if !joined {
+		// The events stored before the keys were namespaced aren't
+		// indexed, they only need to expire.
+		for _, legacyKey := range r.legacyKeys(key) {
+			joined, err = r.client.Expire(ctx, legacyKey, ttl).Result()
+			if err != nil {
+				return fmt.Errorf("client.Expire(legacy), err: %w", err)
+			}
+
+			if joined {
+				return nil
+			}
+		}
+
+		return aggregates.ErrEventNotFound
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, r.classIndex(aggregates.EventClassPending), key)
+		pipe.ZAdd(ctx, r.classIndex(aggregates.EventClassJoined), redis.Z{
+			Score:  float64(time.Now().Add(ttl).UnixMilli()),
+			Member: key,
+		})
+		pipe.Expire(ctx, r.submissionsKey(key), ttl)
+		pipe.Set(ctx, r.landedKey(key), landedAt.UTC().Format(time.RFC3339Nano), ttl)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("client.TxPipelined: %w", err)
+	}
+
+	return nil
+}
+
+// SelectEvents looks up the stored events of the project matching the
+// filter, newest first. The events expired since they were indexed are
+// left out, so fewer than the limit may be returned.
+func (r *Repository) SelectEvents(ctx context.Context,
+	filter aggregates.EventsFilter) ([]aggregates.Event, error) {
+	projectID := tenantsAggregates.ProjectIDOrDefault(ctx)
+
+	index := r.timeIndex(projectID)
+	switch {
+	case filter.ClientID != "":
+		index = r.clientIndex(projectID, filter.ClientID)
+	case filter.BrowserID != "":
+		index = r.browserIndex(projectID, filter.BrowserID)
+	}
+
+	scores := &redis.ZRangeBy{Min: "-inf", Max: "+inf", Count: int64(filter.Limit)}
+	if filter.Start != nil {
+		scores.Min = strconv.FormatInt(filter.Start.UnixMilli(), 10)
+	}
+	if filter.End != nil {
+		scores.Max = "(" + strconv.FormatInt(filter.End.UnixMilli(), 10)
+	}
+
+	keys, err := r.client.ZRevRangeByScore(ctx, index, scores).Result()
+	if err != nil {
+		return nil, fmt.Errorf("client.ZRevRangeByScore: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return []aggregates.Event{}, nil
+	}
+
+	namespacedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		namespacedKeys[i] = r.key(key)
+	}
+
+	messages, err := r.client.MGet(ctx, namespacedKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("client.MGet: %w", err)
+	}
+
+	events := make([]aggregates.Event, 0, len(messages))
+	expired := make([]interface{}, 0)
+	for i, message := range messages {
+		value, ok := message.(string)
+		if !ok {
+			expired = append(expired, keys[i])
+			continue
+		}
+
+		var redisEvent redisEvent
+		if err := json.Unmarshal([]byte(value), &redisEvent); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		events = append(events, redisEvent.toAggregate())
+	}
+
+	if len(expired) > 0 {
+		if err := r.client.ZRem(ctx, index, expired...).Err(); err != nil {
+			return nil, fmt.Errorf("client.ZRem: %w", err)
+		}
+	}
+
+	return events, nil
+}
+
+// SelectUnreconciledEvents selects the keys of the events sent before the
+// given time not reconciled yet, oldest first.
+func (r *Repository) SelectUnreconciledEvents(ctx context.Context,
+	before time.Time, limit int64) ([]string, error) {
+	keys, err := r.client.ZRangeByScore(ctx, r.reconcileIndex(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("client.ZRangeByScore: %w", err)
+	}
+
+	return keys, nil
+}
+
+// MarkEventReconciled removes an event from the events to reconcile.
+func (r *Repository) MarkEventReconciled(ctx context.Context, key string) error {
+	if err := r.client.ZRem(ctx, r.reconcileIndex(), key).Err(); err != nil {
+		return fmt.Errorf("client.ZRem: %w", err)
+	}
+
+	return nil
+}
+
+// PruneIndexes removes the expired events from the indexes.
+func (r *Repository) PruneIndexes(ctx context.Context) error {
+	now := time.Now()
+
+	for _, class := range aggregates.EventClasses {
+		if err := r.client.ZRemRangeByScore(ctx, r.classIndex(class), "-inf",
+			strconv.FormatInt(now.UnixMilli(), 10)).Err(); err != nil {
+			return fmt.Errorf("client.ZRemRangeByScore(%s): %w", class, err)
+		}
+	}
+
+	// No event outlives the longest TTL, so the older entries of the time,
+	// client and browser indexes point to expired events.
+	oldest := strconv.FormatInt(now.Add(-r.maxTTL()).UnixMilli(), 10)
+
+	if err := r.client.ZRemRangeByScore(ctx, r.reconcileIndex(), "-inf",
+		"("+oldest).Err(); err != nil {
+		return fmt.Errorf("client.ZRemRangeByScore(reconcile): %w", err)
+	}
+
+	for _, pattern := range []string{
+		r.key("index:time:*"), r.key("index:client:*"), r.key("index:browser:*"),
+	} {
+		iter := r.client.Scan(ctx, 0, pattern, scanCount).Iterator()
+		for iter.Next(ctx) {
+			if err := r.client.ZRemRangeByScore(ctx, iter.Val(), "-inf",
+				"("+oldest).Err(); err != nil {
+				return fmt.Errorf("client.ZRemRangeByScore: %w", err)
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("iter.Err: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ExpireLegacyEvents sets the pending TTL to the events stored before the
+// keys were namespaced, which were stored without expiration, and returns
+// how many of them were found.
+func (r *Repository) ExpireLegacyEvents(ctx context.Context) (int64, error) {
+	ttl := r.options.TTLs[aggregates.EventClassPending]
+
+	var expired int64
+	for _, pattern := range []string{legacyEventsPattern, baselineEventsPattern} {
+		iter := r.client.Scan(ctx, 0, pattern, scanCount).Iterator()
+		for iter.Next(ctx) {
+			// Only the keys without expiration are legacy ones, whatever
+			// the key prefix is.
+			keyTTL, err := r.client.TTL(ctx, iter.Val()).Result()
+			if err != nil {
+				return expired, fmt.Errorf("client.TTL: %w", err)
+			}
+
+			if keyTTL != -1 {
+				continue
+			}
+
+			if err := r.client.Expire(ctx, iter.Val(), ttl).Err(); err != nil {
+				return expired, fmt.Errorf("client.Expire, err: %w", err)
+			}
+
+			expired++
+		}
+
+		if err := iter.Err(); err != nil {
+			return expired, fmt.Errorf("iter.Err: %w", err)
+		}
+	}
+
+	return expired, nil
+}
+
+// Report reports the events stored per class and the memory of the store,
+// the memory of every class is estimated from a sample of its events.
+func (r *Repository) Report(ctx context.Context) (aggregates.StoreReport, error) {
+	report := aggregates.StoreReport{
+		Classes: make([]aggregates.ClassReport, 0, len(aggregates.EventClasses)),
+	}
+
+	for _, class := range aggregates.EventClasses {
+		count, err := r.client.ZCard(ctx, r.classIndex(class)).Result()
+		if err != nil {
+			return aggregates.StoreReport{}, fmt.Errorf("client.ZCard(%s): %w", class, err)
+		}
+
+		keys, err := r.client.ZRandMember(ctx, r.classIndex(class), memorySamples).Result()
+		if err != nil {
+			return aggregates.StoreReport{}, fmt.Errorf("client.ZRandMember(%s): %w", class, err)
+		}
+
+		var sampled, memory int64
+		for _, key := range keys {
+			usage, err := r.client.MemoryUsage(ctx, r.key(key)).Result()
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					continue
+				}
+
+				return aggregates.StoreReport{}, fmt.Errorf("client.MemoryUsage: %w", err)
+			}
+
+			sampled++
+			memory += usage
+		}
+
+		classReport := aggregates.ClassReport{Class: class, Count: count}
+		if sampled > 0 {
+			classReport.Memory = memory / sampled * count
+		}
+
+		report.Classes = append(report.Classes, classReport)
+	}
+
+	info, err := r.client.Info(ctx, "memory").Result()
+	if err != nil {
+		return aggregates.StoreReport{}, fmt.Errorf("client.Info: %w", err)
+	}
+
+	for _, line := range strings.Split(info, "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "used_memory":
+			report.UsedMemory, _ = strconv.ParseInt(value, 10, 64)
+		case "maxmemory":
+			report.MaxMemory, _ = strconv.ParseInt(value, 10, 64)
+		case "maxmemory_policy":
+			report.EvictionPolicy = value
+		}
+	}
+
+	return report, nil
+}
+
+// key returns the namespaced key.
+func (r *Repository) key(key string) string {
+	if r.options.KeyPrefix == "" {
+		return key
+	}
+
+	return r.options.KeyPrefix + ":" + key
+}
+
+// legacyKeys returns the keys an event may have been stored under before
+// the keys were namespaced: without the key prefix and, for the default
+// project, without the project as the baseline stored them.
+func (r *Repository) legacyKeys(key string) []string {
+	var keys []string
+	if r.options.KeyPrefix != "" {
+		keys = append(keys, key)
+	}
+
+	if baselineKey, ok := strings.CutPrefix(key,
+		fmt.Sprintf("%d.", tenantsAggregates.DefaultProjectID)); ok {
+		keys = append(keys, baselineKey)
+	}
+
+	return keys
+}
+
+func (r *Repository) classIndex(class aggregates.EventClass) string {
+	return r.key("index:class:" + string(class))
+}
+
+// submissionsKey is the list of the submissions of the transaction of an
+// event.
+func (r *Repository) submissionsKey(key string) string {
+	return r.key("submissions:" + key)
+}
+
+// landedKey is when the transaction of an event landed.
+func (r *Repository) landedKey(key string) string {
+	return r.key("landed:" + key)
+}
+
+// reconcileIndex is the index of the events to reconcile, of every project.
+func (r *Repository) reconcileIndex() string {
+	return r.key("index:reconcile")
+}
+
+func (r *Repository) timeIndex(projectID int64) string {
+	return r.key(fmt.Sprintf("index:time:%d", projectID))
+}
+
+func (r *Repository) clientIndex(projectID int64, clientID string) string {
+	return r.key(fmt.Sprintf("index:client:%d:%s", projectID, clientID))
+}
+
+func (r *Repository) browserIndex(projectID int64, browserID string) string {
+	return r.key(fmt.Sprintf("index:browser:%d:%s", projectID, browserID))
+}
+
+// maxTTL returns the longest TTL of the event classes.
+func (r *Repository) maxTTL() time.Duration {
+	var maxTTL time.Duration
+	for _, ttl := range r.options.TTLs {
+		maxTTL = max(maxTTL, ttl)
+	}
+
+	return maxTTL
+}
@@ -0,0 +1,298 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
+)
+
+// fakeServer is a redis server keeping plain keys in memory, it serves
+// the commands the legacy events are read and expired with.
+type fakeServer struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+// newFakeServer serves the given keys, stored without expiration, and
+// returns the repository with the given key prefix reading them.
+func newFakeServer(t *testing.T, keyPrefix string,
+	values map[string]string) (*fakeServer, *Repository) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeServer{values: values, ttls: map[string]time.Duration{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String()})
+	t.Cleanup(func() { client.Close() })
+
+	return server, New(client, Options{
+		KeyPrefix: keyPrefix,
+		TTLs: map[aggregates.EventClass]time.Duration{
+			aggregates.EventClassPending: time.Hour,
+			aggregates.EventClassJoined:  2 * time.Hour,
+		},
+	})
+}
+
+// ttl returns the expiration set to a key, zero when it has none.
+func (s *fakeServer) ttl(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ttls[key]
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		if _, err := io.WriteString(conn, s.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case args[0] == "get" && len(args) == 2:
+		value, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+
+		return bulkString(value)
+
+	case args[0] == "expire" && len(args) == 3:
+		if _, ok := s.values[args[1]]; !ok {
+			return ":0\r\n"
+		}
+
+		seconds, _ := strconv.Atoi(args[2])
+		s.ttls[args[1]] = time.Duration(seconds) * time.Second
+
+		return ":1\r\n"
+
+	case args[0] == "ttl" && len(args) == 2:
+		if _, ok := s.values[args[1]]; !ok {
+			return ":-2\r\n"
+		}
+
+		ttl, ok := s.ttls[args[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+
+		return fmt.Sprintf(":%d\r\n", int(ttl.Seconds()))
+
+	case args[0] == "scan" && len(args) >= 4 && args[2] == "match":
+		var keys []string
+		for key := range s.values {
+			if ok, _ := path.Match(args[3], key); ok {
+				keys = append(keys, bulkString(key))
+			}
+		}
+
+		reply := fmt.Sprintf("*2\r\n%s*%d\r\n", bulkString("0"), len(keys))
+		for _, key := range keys {
+			reply += key
+		}
+
+		return reply
+	}
+
+	return "-ERR unknown command\r\n"
+}
+
+// readCommand reads a command as the array of bulk strings the clients
+// send, the command name lower cased.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	var count int
+	if _, err := fmt.Fscanf(reader, "*%d\r\n", &count); err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		var length int
+		if _, err := fmt.Fscanf(reader, "$%d\r\n", &length); err != nil {
+			return nil, err
+		}
+
+		arg := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+
+		args[i] = string(arg[:length])
+	}
+
+	if count == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	args[0] = strings.ToLower(args[0])
+
+	return args, nil
+}
+
+func bulkString(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func TestGetEventLegacyKeys(t *testing.T) {
+	const event = `{"id":"event","event_type":"rpc"}`
+
+	tests := []struct {
+		name      string
+		keyPrefix string
+		storedKey string
+		key       string
+		found     bool
+	}{
+		{
+			name:      "namespaced key",
+			keyPrefix: "collector",
+			storedKey: "collector:1.sendTransaction.signature",
+			key:       "1.sendTransaction.signature",
+			found:     true,
+		},
+		{
+			name:      "key stored before the key prefix",
+			keyPrefix: "collector",
+			storedKey: "1.sendTransaction.signature",
+			key:       "1.sendTransaction.signature",
+			found:     true,
+		},
+		{
+			name:      "baseline key",
+			keyPrefix: "collector",
+			storedKey: "sendTransaction.signature",
+			key:       "1.sendTransaction.signature",
+			found:     true,
+		},
+		{
+			name:      "baseline key without key prefix",
+			storedKey: "sendTransaction.signature",
+			key:       "1.sendTransaction.signature",
+			found:     true,
+		},
+		{
+			name:      "baseline key read by another project",
+			keyPrefix: "collector",
+			storedKey: "sendTransaction.signature",
+			key:       "2.sendTransaction.signature",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, repository := newFakeServer(t, test.keyPrefix,
+				map[string]string{test.storedKey: event})
+
+			got, err := repository.GetEvent(context.Background(), test.key)
+			if !test.found {
+				if !errors.Is(err, aggregates.ErrEventNotFound) {
+					t.Fatalf("err = %v, want %v", err, aggregates.ErrEventNotFound)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if got.ID != "event" {
+				t.Errorf("ID = %q, want %q", got.ID, "event")
+			}
+		})
+	}
+}
+
+func TestJoinEventBaselineKey(t *testing.T) {
+	server, repository := newFakeServer(t, "collector",
+		map[string]string{"sendTransaction.signature": `{"id":"event"}`})
+
+	if err := repository.JoinEvent(context.Background(),
+		"1.sendTransaction.signature", time.Now()); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	if ttl := server.ttl("sendTransaction.signature"); ttl != 2*time.Hour {
+		t.Errorf("ttl = %s, want %s", ttl, 2*time.Hour)
+	}
+
+	if err := repository.JoinEvent(context.Background(),
+		"2.sendTransaction.signature", time.Now()); !errors.Is(
+		err, aggregates.ErrEventNotFound) {
+		t.Fatalf("err = %v, want %v", err, aggregates.ErrEventNotFound)
+	}
+}
+
+func TestExpireLegacyEvents(t *testing.T) {
+	server, repository := newFakeServer(t, "collector", map[string]string{
+		"sendTransaction.baseline":              `{}`,
+		"1.sendTransaction.legacy":              `{}`,
+		"collector:1.sendTransaction.namespace": `{}`,
+		"other":                                 `{}`,
+	})
+	server.ttls["collector:1.sendTransaction.namespace"] = time.Minute
+
+	expired, err := repository.ExpireLegacyEvents(context.Background())
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	if expired != 2 {
+		t.Errorf("expired = %d, want 2", expired)
+	}
+
+	for key, want := range map[string]time.Duration{
+		"sendTransaction.baseline":              time.Hour,
+		"1.sendTransaction.legacy":              time.Hour,
+		"collector:1.sendTransaction.namespace": time.Minute,
+		"other":                                 0,
+	} {
+		if ttl := server.ttl(key); ttl != want {
+			t.Errorf("ttl(%s) = %s, want %s", key, ttl, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	defaultEventsLimit = 100
+
+	selectEvents = `
+SELECT id, project_id, rule_id, rule_name, metric, program_address, severity, state,
+  value, threshold, created_at
+FROM alert_events
+`
+
+	insertEvent = `
+INSERT INTO alert_events
+(project_id, rule_id, rule_name, metric, program_address, severity, state,
+  value, threshold, created_at)
+VALUES
+(:project_id, :rule_id, :rule_name, :metric, :program_address, :severity, :state,
+  :value, :threshold, :created_at)
+RETURNING id;
+`
+)
+
+// SelectEvents returns the alert history matching the filter, newest first.
+func (r *Repository) SelectEvents(ctx context.Context,
+	filter aggregates.EventsFilter) ([]aggregates.Event, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	if projectID != 0 {
+		args = append(args, projectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	if filter.RuleID != nil {
+		args = append(args, *filter.RuleID)
+		conditions = append(conditions, fmt.Sprintf("rule_id = $%d", len(args)))
+	}
+
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultEventsLimit
+	}
+
+	query := selectEvents
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf("ORDER BY created_at DESC, id DESC\nLIMIT $%d;", len(args))
+
+	var dbEvents dbEvents
+	if err := r.db.SelectContext(ctx, &dbEvents, query, args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	events := make([]aggregates.Event, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = dbEvent.toAggregate()
+	}
+
+	return events, nil
+}
+
+// InsertEvent inserts a new alert state change, returning it with its ID.
+func (r *Repository) InsertEvent(ctx context.Context,
+	event aggregates.Event) (aggregates.Event, error) {
+	rows, err := r.db.NamedQueryContext(ctx, insertEvent, dbEventFromAggregate(event))
+	if err != nil {
+		return aggregates.Event{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Event{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&event.ID); err != nil {
+		return aggregates.Event{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return event, nil
+}
+
+type dbEvent struct {
+	ID             int64          `db:"id"`
+	ProjectID      int64          `db:"project_id"`
+	RuleID         int64          `db:"rule_id"`
+	RuleName       string         `db:"rule_name"`
+	Metric         string         `db:"metric"`
+	ProgramAddress sql.NullString `db:"program_address"`
+	Severity       string         `db:"severity"`
+	State          string         `db:"state"`
+	Value          float64        `db:"value"`
+	Threshold      float64        `db:"threshold"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+type dbEvents []dbEvent
+
+func (dbe dbEvent) toAggregate() aggregates.Event {
+	return aggregates.Event{
+		ID:             dbe.ID,
+		ProjectID:      dbe.ProjectID,
+		RuleID:         dbe.RuleID,
+		RuleName:       dbe.RuleName,
+		Metric:         aggregates.Metric(dbe.Metric),
+		ProgramAddress: dbe.ProgramAddress.String,
+		Severity:       aggregates.Severity(dbe.Severity),
+		State:          aggregates.State(dbe.State),
+		Value:          dbe.Value,
+		Threshold:      dbe.Threshold,
+		CreatedAt:      dbe.CreatedAt,
+	}
+}
+
+func dbEventFromAggregate(event aggregates.Event) dbEvent {
+	return dbEvent{
+		ID:        event.ID,
+		ProjectID: event.ProjectID,
+		RuleID:    event.RuleID,
+		RuleName:  event.RuleName,
+		Metric:    string(event.Metric),
+		ProgramAddress: sql.NullString{
+			String: event.ProgramAddress,
+			Valid:  event.ProgramAddress != "",
+		},
+		Severity:  string(event.Severity),
+		State:     string(event.State),
+		Value:     event.Value,
+		Threshold: event.Threshold,
+		CreatedAt: event.CreatedAt,
+	}
+}
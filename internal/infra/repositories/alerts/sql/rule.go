@@ -0,0 +1,282 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectAllRules = `
+SELECT id, project_id, name, metric, program_address, comparison, threshold, hysteresis,
+  window_seconds, for_seconds, severity, webhook_url, channel_ids, enabled,
+  created_at, updated_at
+FROM alert_rules
+WHERE deleted_at IS NULL AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
+ORDER BY id;
+`
+
+	selectEnabledRules = `
+SELECT id, project_id, name, metric, program_address, comparison, threshold, hysteresis,
+  window_seconds, for_seconds, severity, webhook_url, channel_ids, enabled,
+  created_at, updated_at
+FROM alert_rules
+WHERE deleted_at IS NULL AND enabled
+  AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
+ORDER BY id;
+`
+
+	selectRuleByID = `
+SELECT id, project_id, name, metric, program_address, comparison, threshold, hysteresis,
+  window_seconds, for_seconds, severity, webhook_url, channel_ids, enabled,
+  created_at, updated_at
+FROM alert_rules
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::BIGINT = 0 OR project_id = $2::BIGINT);
+`
+
+	insertRule = `
+INSERT INTO alert_rules
+(project_id, name, metric, program_address, comparison, threshold, hysteresis,
+  window_seconds, for_seconds, severity, webhook_url, channel_ids, enabled,
+  created_at, updated_at)
+VALUES
+(:project_id, :name, :metric, :program_address, :comparison, :threshold, :hysteresis,
+  :window_seconds, :for_seconds, :severity, :webhook_url, :channel_ids, :enabled,
+  :created_at, :updated_at)
+RETURNING id;
+`
+
+	updateRule = `
+UPDATE alert_rules
+SET name = :name, metric = :metric, program_address = :program_address,
+  comparison = :comparison, threshold = :threshold, hysteresis = :hysteresis,
+  window_seconds = :window_seconds, for_seconds = :for_seconds,
+  severity = :severity, webhook_url = :webhook_url,
+  channel_ids = :channel_ids, enabled = :enabled, updated_at = :updated_at
+WHERE id = :id AND project_id = :project_id AND deleted_at IS NULL
+RETURNING created_at;
+`
+
+	deleteRule = `
+UPDATE alert_rules
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+)
+
+// SelectAllRules returns every alert rule of the tenant in ctx that hasn't
+// been deleted.
+func (r *Repository) SelectAllRules(
+	ctx context.Context) ([]aggregates.Rule, error) {
+	return r.selectRules(ctx, selectAllRules)
+}
+
+// SelectEnabledRules returns the alert rules that have to be evaluated, the
+// ones of every tenant when ctx acts on every project.
+func (r *Repository) SelectEnabledRules(
+	ctx context.Context) ([]aggregates.Rule, error) {
+	return r.selectRules(ctx, selectEnabledRules)
+}
+
+func (r *Repository) selectRules(
+	ctx context.Context, query string) ([]aggregates.Rule, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbRules dbRules
+	if err := r.db.SelectContext(ctx, &dbRules, query, projectID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	rules := make([]aggregates.Rule, len(dbRules))
+	for i, dbRule := range dbRules {
+		rules[i] = dbRule.toAggregate()
+	}
+
+	return rules, nil
+}
+
+// SelectRuleByID returns the alert rule with the given ID.
+func (r *Repository) SelectRuleByID(
+	ctx context.Context, id int64) (aggregates.Rule, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf(
+			"tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbRule dbRule
+	if err := r.db.GetContext(ctx, &dbRule, selectRuleByID,
+		id, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Rule{}, aggregates.ErrRuleNotFound
+		}
+
+		return aggregates.Rule{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbRule.toAggregate(), nil
+}
+
+// InsertRule inserts a new alert rule, returning it with its ID.
+func (r *Repository) InsertRule(
+	ctx context.Context, rule aggregates.Rule) (aggregates.Rule, error) {
+	now := time.Now().UTC()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if rule.ProjectID == 0 {
+		rule.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, insertRule, dbRuleFromAggregate(rule))
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Rule{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&rule.ID); err != nil {
+		return aggregates.Rule{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return rule, nil
+}
+
+// UpdateRule updates an existing alert rule.
+func (r *Repository) UpdateRule(
+	ctx context.Context, rule aggregates.Rule) (aggregates.Rule, error) {
+	rule.UpdatedAt = time.Now().UTC()
+
+	if rule.ProjectID == 0 {
+		rule.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, updateRule, dbRuleFromAggregate(rule))
+	if err != nil {
+		return aggregates.Rule{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return aggregates.Rule{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+		}
+
+		return aggregates.Rule{}, aggregates.ErrRuleNotFound
+	}
+
+	if err := rows.Scan(&rule.CreatedAt); err != nil {
+		return aggregates.Rule{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule soft deletes an alert rule.
+func (r *Repository) DeleteRule(ctx context.Context, id int64) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, deleteRule, id,
+		time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrRuleNotFound
+	}
+
+	return nil
+}
+
+type dbRule struct {
+	ID             int64          `db:"id"`
+	ProjectID      int64          `db:"project_id"`
+	Name           string         `db:"name"`
+	Metric         string         `db:"metric"`
+	ProgramAddress sql.NullString `db:"program_address"`
+	Comparison     string         `db:"comparison"`
+	Threshold      float64        `db:"threshold"`
+	Hysteresis     float64        `db:"hysteresis"`
+	WindowSeconds  int64          `db:"window_seconds"`
+	ForSeconds     int64          `db:"for_seconds"`
+	Severity       string         `db:"severity"`
+	WebhookURL     sql.NullString `db:"webhook_url"`
+	ChannelIDs     pq.Int64Array  `db:"channel_ids"`
+	Enabled        bool           `db:"enabled"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+type dbRules []dbRule
+
+func (dbr dbRule) toAggregate() aggregates.Rule {
+	return aggregates.Rule{
+		ID:             dbr.ID,
+		ProjectID:      dbr.ProjectID,
+		Name:           dbr.Name,
+		Metric:         aggregates.Metric(dbr.Metric),
+		ProgramAddress: dbr.ProgramAddress.String,
+		Comparison:     aggregates.Comparison(dbr.Comparison),
+		Threshold:      dbr.Threshold,
+		Hysteresis:     dbr.Hysteresis,
+		Window:         time.Duration(dbr.WindowSeconds) * time.Second,
+		For:            time.Duration(dbr.ForSeconds) * time.Second,
+		Severity:       aggregates.Severity(dbr.Severity),
+		WebhookURL:     dbr.WebhookURL.String,
+		ChannelIDs:     dbr.ChannelIDs,
+		Enabled:        dbr.Enabled,
+		CreatedAt:      dbr.CreatedAt,
+		UpdatedAt:      dbr.UpdatedAt,
+	}
+}
+
+func dbRuleFromAggregate(rule aggregates.Rule) dbRule {
+	return dbRule{
+		ID:        rule.ID,
+		ProjectID: rule.ProjectID,
+		Name:      rule.Name,
+		Metric:    string(rule.Metric),
+		ProgramAddress: sql.NullString{
+			String: rule.ProgramAddress,
+			Valid:  rule.ProgramAddress != "",
+		},
+		Comparison:    string(rule.Comparison),
+		Threshold:     rule.Threshold,
+		Hysteresis:    rule.Hysteresis,
+		WindowSeconds: int64(rule.Window.Seconds()),
+		ForSeconds:    int64(rule.For.Seconds()),
+		Severity:      string(rule.Severity),
+		WebhookURL: sql.NullString{
+			String: rule.WebhookURL,
+			Valid:  rule.WebhookURL != "",
+		},
+		ChannelIDs: pq.Int64Array(rule.ChannelIDs),
+		Enabled:    rule.Enabled,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
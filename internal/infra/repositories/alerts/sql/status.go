@@ -0,0 +1,106 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+const (
+	selectStatuses = `
+SELECT rule_id, state, pending_since, firing_since, last_value, last_evaluated_at
+FROM alert_states;
+`
+
+	upsertStatus = `
+INSERT INTO alert_states
+(rule_id, state, pending_since, firing_since, last_value, last_evaluated_at)
+VALUES
+(:rule_id, :state, :pending_since, :firing_since, :last_value, :last_evaluated_at)
+ON CONFLICT (rule_id) DO UPDATE
+SET state = EXCLUDED.state,
+  pending_since = EXCLUDED.pending_since,
+  firing_since = EXCLUDED.firing_since,
+  last_value = EXCLUDED.last_value,
+  last_evaluated_at = EXCLUDED.last_evaluated_at;
+`
+)
+
+// SelectStatuses returns the current status of every evaluated alert rule.
+func (r *Repository) SelectStatuses(
+	ctx context.Context) ([]aggregates.Status, error) {
+	var dbStatuses dbStatuses
+	if err := r.db.SelectContext(ctx, &dbStatuses, selectStatuses); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	statuses := make([]aggregates.Status, len(dbStatuses))
+	for i, dbStatus := range dbStatuses {
+		statuses[i] = dbStatus.toAggregate()
+	}
+
+	return statuses, nil
+}
+
+// UpsertStatus stores the current status of an alert rule.
+func (r *Repository) UpsertStatus(
+	ctx context.Context, status aggregates.Status) error {
+	if _, err := r.db.NamedExecContext(ctx,
+		upsertStatus, dbStatusFromAggregate(status)); err != nil {
+		return fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+type dbStatus struct {
+	RuleID          int64        `db:"rule_id"`
+	State           string       `db:"state"`
+	PendingSince    sql.NullTime `db:"pending_since"`
+	FiringSince     sql.NullTime `db:"firing_since"`
+	LastValue       float64      `db:"last_value"`
+	LastEvaluatedAt time.Time    `db:"last_evaluated_at"`
+}
+
+type dbStatuses []dbStatus
+
+func (dbs dbStatus) toAggregate() aggregates.Status {
+	status := aggregates.Status{
+		RuleID:          dbs.RuleID,
+		State:           aggregates.State(dbs.State),
+		LastValue:       dbs.LastValue,
+		LastEvaluatedAt: dbs.LastEvaluatedAt,
+	}
+
+	if dbs.PendingSince.Valid {
+		status.PendingSince = &dbs.PendingSince.Time
+	}
+
+	if dbs.FiringSince.Valid {
+		status.FiringSince = &dbs.FiringSince.Time
+	}
+
+	return status
+}
+
+func dbStatusFromAggregate(status aggregates.Status) dbStatus {
+	dbStatus := dbStatus{
+		RuleID:          status.RuleID,
+		State:           string(status.State),
+		LastValue:       status.LastValue,
+		LastEvaluatedAt: status.LastEvaluatedAt,
+	}
+
+	if status.PendingSince != nil {
+		dbStatus.PendingSince = sql.NullTime{Time: *status.PendingSince, Valid: true}
+	}
+
+	if status.FiringSince != nil {
+		dbStatus.FiringSince = sql.NullTime{Time: *status.FiringSince, Valid: true}
+	}
+
+	return dbStatus
+}
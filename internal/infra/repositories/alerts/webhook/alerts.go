@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/alerts/aggregates"
+)
+
+const (
+	signatureHeader = "X-Encinitas-Signature"
+	timestampHeader = "X-Encinitas-Timestamp"
+)
+
+// Notify sends the alert event as a signed JSON webhook, the signature is the
+// hex encoded HMAC-SHA256 of "<timestamp>.<body>" using the webhook secret.
+//
+// Rules without webhook URL are skipped when there is no default URL either.
+func (r *Repository) Notify(ctx context.Context,
+	rule aggregates.Rule, event aggregates.Event) error {
+	url := rule.WebhookURL
+	if url == "" {
+		url = r.defaultURL
+	}
+
+	if url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookAlertFromAggregates(rule, event))
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx,
+		http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, "sha256="+r.sign(timestamp, body))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("r.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send webhook, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (r *Repository) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(r.secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookAlert represents the webhook payload of an alert state change.
+type webhookAlert struct {
+	ID        int64            `json:"id"`
+	State     string           `json:"state"`
+	Value     float64          `json:"value"`
+	CreatedAt time.Time        `json:"created_at"`
+	Rule      webhookAlertRule `json:"rule"`
+}
+
+// webhookAlertRule represents the rule that changed its state.
+type webhookAlertRule struct {
+	ID             int64   `json:"id"`
+	Name           string  `json:"name"`
+	Metric         string  `json:"metric"`
+	ProgramAddress string  `json:"program_address,omitempty"`
+	Comparison     string  `json:"comparison"`
+	Threshold      float64 `json:"threshold"`
+	Severity       string  `json:"severity"`
+}
+
+func webhookAlertFromAggregates(
+	rule aggregates.Rule, event aggregates.Event) webhookAlert {
+	return webhookAlert{
+		ID:        event.ID,
+		State:     string(event.State),
+		Value:     event.Value,
+		CreatedAt: event.CreatedAt,
+		Rule: webhookAlertRule{
+			ID:             rule.ID,
+			Name:           rule.Name,
+			Metric:         string(rule.Metric),
+			ProgramAddress: rule.ProgramAddress,
+			Comparison:     string(rule.Comparison),
+			Threshold:      rule.Threshold,
+			Severity:       string(rule.Severity),
+		},
+	}
+}
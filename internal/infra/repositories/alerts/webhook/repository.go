@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Repository defines the dependencies needed to send signed webhooks.
+type Repository struct {
+	client     *http.Client
+	defaultURL string
+	secret     string
+}
+
+// New creates a new webhook repository, defaultURL is used for the rules that
+// don't define their own webhook URL and secret is used to sign the payloads.
+func New(defaultURL, secret string) *Repository {
+	return &Repository{
+		client:     &http.Client{Timeout: requestTimeout},
+		defaultURL: defaultURL,
+		secret:     secret,
+	}
+}
@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	defaultAnomaliesLimit = 500
+
+	selectAnomalies = `
+SELECT id, project_id, program_address, metric, window_start, window_end, value,
+  expected, lower_bound, upper_bound, z_score, created_at
+FROM anomalies
+`
+
+	insertAnomaly = `
+INSERT INTO anomalies
+(project_id, program_address, metric, window_start, window_end, value,
+  expected, lower_bound, upper_bound, z_score, created_at)
+VALUES
+(:project_id, :program_address, :metric, :window_start, :window_end, :value,
+  :expected, :lower_bound, :upper_bound, :z_score, :created_at)
+RETURNING id;
+`
+)
+
+// SelectAnomalies returns the anomalies matching the filter, newest first.
+func (r *Repository) SelectAnomalies(ctx context.Context,
+	filter aggregates.AnomaliesFilter) ([]aggregates.Anomaly, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	if projectID != 0 {
+		args = append(args, projectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	if filter.ProgramAddress != "" {
+		args = append(args, filter.ProgramAddress)
+		conditions = append(conditions, fmt.Sprintf("program_address = $%d", len(args)))
+	}
+
+	if filter.Metric != "" {
+		args = append(args, string(filter.Metric))
+		conditions = append(conditions, fmt.Sprintf("metric = $%d", len(args)))
+	}
+
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("window_end >= $%d", len(args)))
+	}
+
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("window_start < $%d", len(args)))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAnomaliesLimit
+	}
+
+	query := selectAnomalies
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf("ORDER BY window_start DESC, id DESC\nLIMIT $%d;", len(args))
+
+	var dbAnomalies dbAnomalies
+	if err := r.db.SelectContext(ctx, &dbAnomalies, query, args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	anomalies := make([]aggregates.Anomaly, len(dbAnomalies))
+	for i, dbAnomaly := range dbAnomalies {
+		anomalies[i] = dbAnomaly.toAggregate()
+	}
+
+	return anomalies, nil
+}
+
+// InsertAnomaly inserts a new anomaly, returning it with its ID.
+func (r *Repository) InsertAnomaly(ctx context.Context,
+	anomaly aggregates.Anomaly) (aggregates.Anomaly, error) {
+	if anomaly.ProjectID == 0 {
+		anomaly.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx,
+		insertAnomaly, dbAnomalyFromAggregate(anomaly))
+	if err != nil {
+		return aggregates.Anomaly{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Anomaly{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&anomaly.ID); err != nil {
+		return aggregates.Anomaly{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return anomaly, nil
+}
+
+type dbAnomaly struct {
+	ID             int64     `db:"id"`
+	ProjectID      int64     `db:"project_id"`
+	ProgramAddress string    `db:"program_address"`
+	Metric         string    `db:"metric"`
+	WindowStart    time.Time `db:"window_start"`
+	WindowEnd      time.Time `db:"window_end"`
+	Value          float64   `db:"value"`
+	Expected       float64   `db:"expected"`
+	LowerBound     float64   `db:"lower_bound"`
+	UpperBound     float64   `db:"upper_bound"`
+	ZScore         float64   `db:"z_score"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type dbAnomalies []dbAnomaly
+
+func (dba dbAnomaly) toAggregate() aggregates.Anomaly {
+	return aggregates.Anomaly{
+		ID:             dba.ID,
+		ProjectID:      dba.ProjectID,
+		ProgramAddress: dba.ProgramAddress,
+		Metric:         aggregates.Metric(dba.Metric),
+		WindowStart:    dba.WindowStart,
+		WindowEnd:      dba.WindowEnd,
+		Value:          dba.Value,
+		Expected:       dba.Expected,
+		Lower:          dba.LowerBound,
+		Upper:          dba.UpperBound,
+		ZScore:         dba.ZScore,
+		CreatedAt:      dba.CreatedAt,
+	}
+}
+
+func dbAnomalyFromAggregate(anomaly aggregates.Anomaly) dbAnomaly {
+	return dbAnomaly{
+		ID:             anomaly.ID,
+		ProjectID:      anomaly.ProjectID,
+		ProgramAddress: anomaly.ProgramAddress,
+		Metric:         string(anomaly.Metric),
+		WindowStart:    anomaly.WindowStart,
+		WindowEnd:      anomaly.WindowEnd,
+		Value:          anomaly.Value,
+		Expected:       anomaly.Expected,
+		LowerBound:     anomaly.Lower,
+		UpperBound:     anomaly.Upper,
+		ZScore:         anomaly.ZScore,
+		CreatedAt:      anomaly.CreatedAt,
+	}
+}
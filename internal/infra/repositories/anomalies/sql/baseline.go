@@ -0,0 +1,109 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/anomalies/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectBaseline = `
+SELECT project_id, program_address, metric, hour_of_week, samples, mean,
+  variance, last_window_end, updated_at
+FROM anomaly_baselines
+WHERE project_id = $1 AND program_address = $2 AND metric = $3
+  AND hour_of_week = $4;
+`
+
+	upsertBaseline = `
+INSERT INTO anomaly_baselines
+(project_id, program_address, metric, hour_of_week, samples, mean, variance,
+  last_window_end, updated_at)
+VALUES
+(:project_id, :program_address, :metric, :hour_of_week, :samples, :mean,
+  :variance, :last_window_end, :updated_at)
+ON CONFLICT (project_id, program_address, metric, hour_of_week) DO UPDATE
+SET samples = EXCLUDED.samples, mean = EXCLUDED.mean,
+  variance = EXCLUDED.variance, last_window_end = EXCLUDED.last_window_end,
+  updated_at = EXCLUDED.updated_at;
+`
+)
+
+// SelectBaseline returns the baseline of the program metric at the hour of
+// week for the tenant in ctx, an empty baseline is returned when there is none
+// yet.
+func (r *Repository) SelectBaseline(ctx context.Context, programAddress string,
+	metric aggregates.Metric, hourOfWeek int) (aggregates.Baseline, error) {
+	var dbBaseline dbBaseline
+	if err := r.db.GetContext(ctx, &dbBaseline, selectBaseline,
+		tenantsAggregates.ProjectIDOrDefault(ctx), programAddress,
+		string(metric), hourOfWeek); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Baseline{}, nil
+		}
+
+		return aggregates.Baseline{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbBaseline.toAggregate(), nil
+}
+
+// UpsertBaseline stores the baseline of a program metric at an hour of week.
+func (r *Repository) UpsertBaseline(
+	ctx context.Context, baseline aggregates.Baseline) error {
+	if baseline.ProjectID == 0 {
+		baseline.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	if _, err := r.db.NamedExecContext(ctx,
+		upsertBaseline, dbBaselineFromAggregate(baseline)); err != nil {
+		return fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+type dbBaseline struct {
+	ProjectID      int64     `db:"project_id"`
+	ProgramAddress string    `db:"program_address"`
+	Metric         string    `db:"metric"`
+	HourOfWeek     int       `db:"hour_of_week"`
+	Samples        int64     `db:"samples"`
+	Mean           float64   `db:"mean"`
+	Variance       float64   `db:"variance"`
+	LastWindowEnd  time.Time `db:"last_window_end"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (dbb dbBaseline) toAggregate() aggregates.Baseline {
+	return aggregates.Baseline{
+		ProjectID:      dbb.ProjectID,
+		ProgramAddress: dbb.ProgramAddress,
+		Metric:         aggregates.Metric(dbb.Metric),
+		HourOfWeek:     dbb.HourOfWeek,
+		Samples:        dbb.Samples,
+		Mean:           dbb.Mean,
+		Variance:       dbb.Variance,
+		LastWindowEnd:  dbb.LastWindowEnd,
+		UpdatedAt:      dbb.UpdatedAt,
+	}
+}
+
+func dbBaselineFromAggregate(baseline aggregates.Baseline) dbBaseline {
+	return dbBaseline{
+		ProjectID:      baseline.ProjectID,
+		ProgramAddress: baseline.ProgramAddress,
+		Metric:         string(baseline.Metric),
+		HourOfWeek:     baseline.HourOfWeek,
+		Samples:        baseline.Samples,
+		Mean:           baseline.Mean,
+		Variance:       baseline.Variance,
+		LastWindowEnd:  baseline.LastWindowEnd,
+		UpdatedAt:      baseline.UpdatedAt,
+	}
+}
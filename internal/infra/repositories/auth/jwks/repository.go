@@ -0,0 +1,215 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+const (
+	// minReloadInterval limits how often an unknown key ID triggers a reload
+	// of the keys, so forged tokens can't flood the JWKS endpoint.
+	minReloadInterval = time.Minute
+
+	// maxJWKSSize is the maximum size of a JWKS document.
+	maxJWKSSize = 1 << 20
+)
+
+// Repository provides the public keys of a JSON Web Key Set, loaded from a
+// local file or a URL. The keys are reloaded every refresh interval and when
+// a token is signed with an unknown key, which allows rotating them.
+//
+// The keys are loaded without holding the lock, so a slow JWKS endpoint only
+// delays the requests that can't be verified with the cached keys.
+type Repository struct {
+	file            string
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	loadedAt  time.Time
+	reloading chan struct{}
+}
+
+// New returns a new JWKS repository, the URL is only used when no file is
+// set.
+func New(file, url string, refreshInterval time.Duration) *Repository {
+	return &Repository{
+		file:            file,
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Key returns the public key with the given ID, a token without key ID can
+// only be verified when the set has a single key.
+func (r *Repository) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	keys, loadedAt := r.keys, r.loadedAt
+	r.mu.Unlock()
+
+	if keys == nil {
+		keys, loadedAt = r.reload(ctx)
+	}
+
+	key, ok := lookupKey(keys, kid)
+	switch {
+	case !ok && time.Since(loadedAt) > minReloadInterval:
+		// The key may have just been rotated in.
+		keys, _ = r.reload(ctx)
+		key, ok = lookupKey(keys, kid)
+
+	case time.Since(loadedAt) > r.refreshInterval:
+		// The cached keys are served while they are refreshed.
+		go r.reload(context.WithoutCancel(ctx))
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("key %q not found: %w", kid, aggregates.ErrInvalidToken)
+	}
+
+	return key, nil
+}
+
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+	return key, ok
+}
+
+// reload loads the keys and returns them along with when they were loaded,
+// or waits for the reload in progress, if any. The current keys are kept
+// when they can't be loaded so a JWKS outage doesn't lock everybody out.
+func (r *Repository) reload(
+	ctx context.Context) (map[string]crypto.PublicKey, time.Time) {
+	r.mu.Lock()
+	if reloading := r.reloading; reloading != nil {
+		r.mu.Unlock()
+
+		select {
+		case <-reloading:
+		case <-ctx.Done():
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		return r.keys, r.loadedAt
+	}
+
+	reloading := make(chan struct{})
+	r.reloading = reloading
+	loadedAt := time.Now()
+	r.loadedAt = loadedAt
+	r.mu.Unlock()
+
+	keys, err := r.load(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reloading = nil
+	close(reloading)
+
+	if err != nil {
+		slog.Error("error while loading the jwks", slog.Any("error", err))
+
+		if r.keys == nil {
+			r.keys = map[string]crypto.PublicKey{}
+		}
+
+		return r.keys, loadedAt
+	}
+
+	r.keys = keys
+
+	return keys, loadedAt
+}
+
+func (r *Repository) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var (
+		document []byte
+		err      error
+	)
+
+	switch {
+	case r.file != "":
+		document, err = os.ReadFile(r.file)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile, err: %w", err)
+		}
+
+	case r.url != "":
+		document, err = r.fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("r.fetch, err: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("neither a jwks file nor url is configured")
+	}
+
+	var set jsonWebKeySet
+	if err := json.Unmarshal(document, &set); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal, err: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			slog.Warn("skipping jwks key",
+				slog.String("kid", jwk.Kid), slog.Any("error", err))
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (r *Repository) fetch(ctx context.Context) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext, err: %w", err)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("r.client.Do, err: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks status code: %d", response.StatusCode)
+	}
+
+	document, err := io.ReadAll(io.LimitReader(response.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll, err: %w", err)
+	}
+
+	return document, nil
+}
@@ -0,0 +1,339 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+)
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func rsaJWK(t *testing.T, kid string) (jsonWebKey, *rsa.PublicKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		N:   encodeBigInt(key.N),
+		E:   encodeBigInt(big.NewInt(int64(key.E))),
+	}, &key.PublicKey
+}
+
+// jwksServer serves the keys, the requests are blocked while block is
+// non-nil and open.
+func jwksServer(t *testing.T, keys *atomic.Value,
+	block <-chan struct{}) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if block != nil {
+			<-block
+		}
+
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: keys.Load().([]jsonWebKey)})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestKey(t *testing.T) {
+	rsaKey, rsaPublicKey := rsaJWK(t, "rsa")
+
+	ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := []jsonWebKey{
+		rsaKey,
+		{
+			Kty: "EC",
+			Kid: "ec",
+			Crv: "P-256",
+			X:   encodeBigInt(ecPrivateKey.X),
+			Y:   encodeBigInt(ecPrivateKey.Y),
+		},
+		{
+			Kty: "OKP",
+			Kid: "ed",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edPublicKey),
+		},
+		{Kty: "RSA", Kid: "encryption", Use: "enc", N: rsaKey.N, E: rsaKey.E},
+		{Kty: "EC", Kid: "off-curve", Crv: "P-256", X: "AQ", Y: "AQ"},
+		{Kty: "oct", Kid: "symmetric", X: "c2VjcmV0"},
+	}
+
+	var keys atomic.Value
+	keys.Store(set)
+
+	server, _ := jwksServer(t, &keys, nil)
+	repository := New("", server.URL, time.Hour)
+
+	tests := []struct {
+		kid   string
+		found bool
+	}{
+		{kid: "rsa", found: true},
+		{kid: "ec", found: true},
+		{kid: "ed", found: true},
+		{kid: "unknown"},
+		{kid: ""},
+		{kid: "encryption"},
+		{kid: "off-curve"},
+		{kid: "symmetric"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kid, func(t *testing.T) {
+			key, err := repository.Key(context.Background(), test.kid)
+			if !test.found {
+				if !errors.Is(err, aggregates.ErrInvalidToken) {
+					t.Fatalf("err = %v, want %v", err, aggregates.ErrInvalidToken)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if test.kid == "rsa" && !rsaPublicKey.Equal(key) {
+				t.Errorf("key = %v, want %v", key, rsaPublicKey)
+			}
+		})
+	}
+}
+
+func TestKeyWithoutKid(t *testing.T) {
+	rsaKey, _ := rsaJWK(t, "rsa")
+
+	var keys atomic.Value
+	keys.Store([]jsonWebKey{rsaKey})
+
+	server, _ := jwksServer(t, &keys, nil)
+	repository := New("", server.URL, time.Hour)
+
+	if _, err := repository.Key(context.Background(), ""); err != nil {
+		t.Fatalf("err = %v, the single key should be used", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, _ := rsaJWK(t, "old")
+	newKey, _ := rsaJWK(t, "new")
+
+	var keys atomic.Value
+	keys.Store([]jsonWebKey{oldKey})
+
+	server, requests := jwksServer(t, &keys, nil)
+	repository := New("", server.URL, time.Hour)
+
+	if _, err := repository.Key(context.Background(), "old"); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	keys.Store([]jsonWebKey{newKey})
+
+	// Unknown keys don't reload the set more than once per minReloadInterval.
+	if _, err := repository.Key(context.Background(), "new"); !errors.Is(
+		err, aggregates.ErrInvalidToken) {
+		t.Fatalf("err = %v, want %v", err, aggregates.ErrInvalidToken)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+
+	repository.mu.Lock()
+	repository.loadedAt = time.Now().Add(-minReloadInterval - time.Second)
+	repository.mu.Unlock()
+
+	if _, err := repository.Key(context.Background(), "new"); err != nil {
+		t.Fatalf("err = %v, the rotated key should be loaded", err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("requests = %d, want 2", got)
+	}
+}
+
+func TestKeyFetches(t *testing.T) {
+	rsaKey, _ := rsaJWK(t, "rsa")
+
+	tests := []struct {
+		name     string
+		loadedAt time.Duration
+		kids     []string
+		requests int64
+	}{
+		{
+			name:     "unknown key on the first load",
+			kids:     []string{"unknown"},
+			requests: 1,
+		},
+		{
+			name:     "known key",
+			kids:     []string{"rsa", "rsa"},
+			requests: 1,
+		},
+		{
+			name:     "unknown keys within the reload interval",
+			kids:     []string{"rsa", "unknown", "unknown"},
+			requests: 1,
+		},
+		{
+			name:     "unknown key of stale keys",
+			loadedAt: -2 * time.Hour,
+			kids:     []string{"rsa", "unknown"},
+			requests: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var keys atomic.Value
+			keys.Store([]jsonWebKey{rsaKey})
+
+			server, requests := jwksServer(t, &keys, nil)
+			repository := New("", server.URL, time.Hour)
+
+			for i, kid := range test.kids {
+				repository.Key(context.Background(), kid)
+
+				if i == 0 && test.loadedAt != 0 {
+					repository.mu.Lock()
+					repository.loadedAt = time.Now().Add(test.loadedAt)
+					repository.mu.Unlock()
+				}
+			}
+
+			// Any refresh in the background would have hit the server by now.
+			time.Sleep(50 * time.Millisecond)
+
+			if got := requests.Load(); got != test.requests {
+				t.Errorf("requests = %d, want %d", got, test.requests)
+			}
+		})
+	}
+}
+
+func TestKeyServesCachedKeysWhileRefreshing(t *testing.T) {
+	rsaKey, _ := rsaJWK(t, "rsa")
+
+	var keys atomic.Value
+	keys.Store([]jsonWebKey{rsaKey})
+
+	server, requests := jwksServer(t, &keys, nil)
+	repository := New("", server.URL, time.Hour)
+
+	if _, err := repository.Key(context.Background(), "rsa"); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	// The refresh hangs on the JWKS endpoint from now on.
+	hang := make(chan struct{})
+	defer close(hang)
+
+	slowServer, slowRequests := jwksServer(t, &keys, hang)
+	repository.url = slowServer.URL
+
+	repository.mu.Lock()
+	repository.loadedAt = time.Now().Add(-2 * time.Hour)
+	repository.mu.Unlock()
+
+	done := make(chan error)
+	go func() {
+		_, err := repository.Key(context.Background(), "rsa")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("err = %v", err)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("Key blocked on the refresh of the keys")
+	}
+
+	// The refresh is in progress, so the cached keys keep being served.
+	if _, err := repository.Key(context.Background(), "rsa"); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for slowRequests.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := slowRequests.Load(); got != 1 {
+		t.Errorf("refresh requests = %d, want 1", got)
+	}
+}
+
+func TestKeyKeepsKeysOnOutage(t *testing.T) {
+	rsaKey, _ := rsaJWK(t, "rsa")
+
+	var keys atomic.Value
+	keys.Store([]jsonWebKey{rsaKey})
+
+	server, _ := jwksServer(t, &keys, nil)
+	repository := New("", server.URL, time.Hour)
+
+	if _, err := repository.Key(context.Background(), "rsa"); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+
+	server.Close()
+
+	repository.mu.Lock()
+	repository.loadedAt = time.Now().Add(-2 * time.Hour)
+	repository.mu.Unlock()
+
+	// Both the background refresh and the reload of the unknown key fail.
+	if _, err := repository.Key(context.Background(), "unknown"); !errors.Is(
+		err, aggregates.ErrInvalidToken) {
+		t.Fatalf("err = %v, want %v", err, aggregates.ErrInvalidToken)
+	}
+
+	if _, err := repository.Key(context.Background(), "rsa"); err != nil {
+		t.Fatalf("err = %v, the cached keys should be kept", err)
+	}
+}
@@ -0,0 +1,258 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// uniqueViolation is the Postgres error code of unique constraints.
+	uniqueViolation = "23505"
+
+	selectSubscriptions = `
+SELECT id, project_id, program_address, email, unsubscribe_token,
+  last_sent_at, created_at
+FROM digest_subscriptions
+WHERE program_address = $1 AND ($2::BIGINT = 0 OR project_id = $2::BIGINT)
+ORDER BY id;
+`
+
+	selectDueSubscriptions = `
+SELECT id, project_id, program_address, email, unsubscribe_token,
+  last_sent_at, created_at
+FROM digest_subscriptions
+WHERE (last_sent_at IS NULL OR last_sent_at < $1) AND created_at < $1
+  AND ($2::BIGINT = 0 OR project_id = $2::BIGINT)
+ORDER BY project_id, program_address, id;
+`
+
+	insertSubscription = `
+INSERT INTO digest_subscriptions
+(project_id, program_address, email, unsubscribe_token, created_at)
+VALUES
+(:project_id, :program_address, :email, :unsubscribe_token, :created_at)
+RETURNING id;
+`
+
+	claimSubscription = `
+UPDATE digest_subscriptions
+SET last_sent_at = $2
+WHERE id = $1 AND (last_sent_at IS NULL OR last_sent_at < $2);
+`
+
+	releaseSubscription = `
+UPDATE digest_subscriptions
+SET last_sent_at = $2
+WHERE id = $1;
+`
+
+	deleteSubscription = `
+DELETE FROM digest_subscriptions
+WHERE id = $1 AND program_address = $2
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+
+	deleteSubscriptionByToken = `
+DELETE FROM digest_subscriptions
+WHERE unsubscribe_token = $1;
+`
+)
+
+// SelectSubscriptions returns the digest subscriptions of a program of the
+// tenant in ctx.
+func (r *Repository) SelectSubscriptions(ctx context.Context,
+	programAddress string) ([]aggregates.Subscription, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	return r.selectSubscriptions(ctx, selectSubscriptions, programAddress, projectID)
+}
+
+// SelectDueSubscriptions returns the subscriptions that haven't received the
+// digest of the period ending at periodEnd, the ones of every tenant when
+// ctx acts on every project. Subscriptions created after the period get
+// their first digest on the next one.
+func (r *Repository) SelectDueSubscriptions(ctx context.Context,
+	periodEnd time.Time) ([]aggregates.Subscription, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	return r.selectSubscriptions(ctx, selectDueSubscriptions, periodEnd, projectID)
+}
+
+func (r *Repository) selectSubscriptions(ctx context.Context,
+	query string, args ...any) ([]aggregates.Subscription, error) {
+	var dbSubscriptions dbSubscriptions
+	if err := r.db.SelectContext(ctx, &dbSubscriptions, query, args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	subscriptions := make([]aggregates.Subscription, len(dbSubscriptions))
+	for i, dbSubscription := range dbSubscriptions {
+		subscriptions[i] = dbSubscription.toAggregate()
+	}
+
+	return subscriptions, nil
+}
+
+// InsertSubscription inserts a new digest subscription, returning it with
+// its ID.
+func (r *Repository) InsertSubscription(ctx context.Context,
+	subscription aggregates.Subscription) (aggregates.Subscription, error) {
+	subscription.CreatedAt = time.Now().UTC()
+
+	if subscription.ProjectID == 0 {
+		subscription.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, insertSubscription,
+		dbSubscriptionFromAggregate(subscription))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return aggregates.Subscription{}, aggregates.ErrSubscriptionAlreadyExists
+		}
+
+		return aggregates.Subscription{}, fmt.Errorf(
+			"r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Subscription{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&subscription.ID); err != nil {
+		return aggregates.Subscription{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// ClaimSubscription marks the digest of the period ending at periodEnd as
+// sent to a subscription, returning false when it was already claimed.
+func (r *Repository) ClaimSubscription(ctx context.Context,
+	id int64, periodEnd time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, claimSubscription, id, periodEnd)
+	if err != nil {
+		return false, fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// ReleaseSubscription restores the last period sent to a subscription,
+// after its digest couldn't be sent.
+func (r *Repository) ReleaseSubscription(ctx context.Context,
+	id int64, lastSentAt *time.Time) error {
+	if _, err := r.db.ExecContext(ctx, releaseSubscription, id,
+		nullTime(lastSentAt)); err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a digest subscription of a program.
+func (r *Repository) DeleteSubscription(ctx context.Context,
+	programAddress string, id int64) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	return r.deleteSubscription(ctx, deleteSubscription, id, programAddress, projectID)
+}
+
+// DeleteSubscriptionByToken deletes the digest subscription with the given
+// unsubscribe token.
+func (r *Repository) DeleteSubscriptionByToken(
+	ctx context.Context, token string) error {
+	return r.deleteSubscription(ctx, deleteSubscriptionByToken, token)
+}
+
+func (r *Repository) deleteSubscription(ctx context.Context,
+	query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+type dbSubscription struct {
+	ID               int64        `db:"id"`
+	ProjectID        int64        `db:"project_id"`
+	ProgramAddress   string       `db:"program_address"`
+	Email            string       `db:"email"`
+	UnsubscribeToken string       `db:"unsubscribe_token"`
+	LastSentAt       sql.NullTime `db:"last_sent_at"`
+	CreatedAt        time.Time    `db:"created_at"`
+}
+
+type dbSubscriptions []dbSubscription
+
+func (dbs dbSubscription) toAggregate() aggregates.Subscription {
+	subscription := aggregates.Subscription{
+		ID:               dbs.ID,
+		ProjectID:        dbs.ProjectID,
+		ProgramAddress:   dbs.ProgramAddress,
+		Email:            dbs.Email,
+		UnsubscribeToken: dbs.UnsubscribeToken,
+		CreatedAt:        dbs.CreatedAt,
+	}
+
+	if dbs.LastSentAt.Valid {
+		subscription.LastSentAt = &dbs.LastSentAt.Time
+	}
+
+	return subscription
+}
+
+func dbSubscriptionFromAggregate(
+	subscription aggregates.Subscription) dbSubscription {
+	return dbSubscription{
+		ID:               subscription.ID,
+		ProjectID:        subscription.ProjectID,
+		ProgramAddress:   subscription.ProgramAddress,
+		Email:            subscription.Email,
+		UnsubscribeToken: subscription.UnsubscribeToken,
+		LastSentAt:       nullTime(subscription.LastSentAt),
+		CreatedAt:        subscription.CreatedAt,
+	}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,209 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/failures/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectFailures = `
+SELECT id, project_id, event_id, endpoint, origin, method, kind, batch,
+  http_status, rpc_code, message, logs, rate_limit, occurred_at, created_at
+FROM rpc_failures
+`
+
+	insertFailure = `
+INSERT INTO rpc_failures
+(project_id, event_id, endpoint, origin, method, kind, batch, http_status,
+  rpc_code, message, logs, rate_limit, occurred_at, created_at)
+VALUES
+(:project_id, :event_id, :endpoint, :origin, :method, :kind, :batch, :http_status,
+  :rpc_code, :message, :logs, :rate_limit, :occurred_at, :created_at);
+`
+)
+
+// SelectFailures returns the failed RPC calls matching the filter, newest
+// first.
+func (r *Repository) SelectFailures(ctx context.Context,
+	filter aggregates.Filter) ([]aggregates.Failure, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	if projectID != 0 {
+		args = append(args, projectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	for column, value := range map[string]string{
+		"endpoint": filter.Endpoint,
+		"method":   filter.Method,
+		"kind":     string(filter.Kind),
+	} {
+		if value != "" {
+			args = append(args, value)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		conditions = append(conditions, fmt.Sprintf("message ILIKE $%d", len(args)))
+	}
+
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+
+	query := selectFailures
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf("ORDER BY occurred_at DESC, id DESC\nLIMIT $%d;", len(args))
+
+	var dbFailures dbFailures
+	if err := r.db.SelectContext(ctx, &dbFailures, query, args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	failures := make([]aggregates.Failure, len(dbFailures))
+	for i, dbFailure := range dbFailures {
+		failures[i] = dbFailure.toAggregate()
+	}
+
+	return failures, nil
+}
+
+// InsertFailure inserts a new failed RPC call.
+func (r *Repository) InsertFailure(ctx context.Context,
+	failure aggregates.Failure) error {
+	failure.CreatedAt = time.Now().UTC()
+
+	dbFailure, err := dbFailureFromAggregate(failure)
+	if err != nil {
+		return fmt.Errorf("dbFailureFromAggregate, err: %w", err)
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, insertFailure, dbFailure); err != nil {
+		return fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// escapeLike escapes the LIKE wildcards of a search term.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+type dbFailure struct {
+	ID         int64          `db:"id"`
+	ProjectID  int64          `db:"project_id"`
+	EventID    string         `db:"event_id"`
+	Endpoint   string         `db:"endpoint"`
+	Origin     string         `db:"origin"`
+	Method     string         `db:"method"`
+	Kind       string         `db:"kind"`
+	Batch      bool           `db:"batch"`
+	HTTPStatus int            `db:"http_status"`
+	RPCCode    sql.NullInt64  `db:"rpc_code"`
+	Message    string         `db:"message"`
+	Logs       pq.StringArray `db:"logs"`
+	RateLimit  []byte         `db:"rate_limit"`
+	OccurredAt time.Time      `db:"occurred_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+type dbFailures []dbFailure
+
+func (dbf dbFailure) toAggregate() aggregates.Failure {
+	failure := aggregates.Failure{
+		ID:         dbf.ID,
+		ProjectID:  dbf.ProjectID,
+		EventID:    dbf.EventID,
+		Endpoint:   dbf.Endpoint,
+		Origin:     dbf.Origin,
+		Method:     dbf.Method,
+		Kind:       aggregates.Kind(dbf.Kind),
+		Batch:      dbf.Batch,
+		HTTPStatus: dbf.HTTPStatus,
+		Message:    dbf.Message,
+		Logs:       []string(dbf.Logs),
+		RateLimit:  map[string]string{},
+		OccurredAt: dbf.OccurredAt,
+		CreatedAt:  dbf.CreatedAt,
+	}
+
+	if dbf.RPCCode.Valid {
+		failure.RPCCode = &dbf.RPCCode.Int64
+	}
+
+	// The rate limit headers are informative, a malformed value is left
+	// empty rather than failing the whole search.
+	_ = json.Unmarshal(dbf.RateLimit, &failure.RateLimit)
+
+	return failure
+}
+
+func dbFailureFromAggregate(failure aggregates.Failure) (dbFailure, error) {
+	rateLimit := failure.RateLimit
+	if rateLimit == nil {
+		rateLimit = map[string]string{}
+	}
+
+	rawRateLimit, err := json.Marshal(rateLimit)
+	if err != nil {
+		return dbFailure{}, fmt.Errorf("json.Marshal, err: %w", err)
+	}
+
+	logs := failure.Logs
+	if logs == nil {
+		logs = []string{}
+	}
+
+	var rpcCode sql.NullInt64
+	if failure.RPCCode != nil {
+		rpcCode = sql.NullInt64{Int64: *failure.RPCCode, Valid: true}
+	}
+
+	return dbFailure{
+		ID:         failure.ID,
+		ProjectID:  failure.ProjectID,
+		EventID:    failure.EventID,
+		Endpoint:   failure.Endpoint,
+		Origin:     failure.Origin,
+		Method:     failure.Method,
+		Kind:       string(failure.Kind),
+		Batch:      failure.Batch,
+		HTTPStatus: failure.HTTPStatus,
+		RPCCode:    rpcCode,
+		Message:    failure.Message,
+		Logs:       pq.StringArray(logs),
+		RateLimit:  rawRateLimit,
+		OccurredAt: failure.OccurredAt,
+		CreatedAt:  failure.CreatedAt,
+	}, nil
+}
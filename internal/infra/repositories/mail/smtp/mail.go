@@ -0,0 +1,222 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/mail/aggregates"
+)
+
+// implicitTLSPort is the port where the SMTP servers expect TLS from the
+// start instead of upgrading the connection with STARTTLS.
+const implicitTLSPort = "465"
+
+// Send sends the email, the configured From is used when the mail doesn't set
+// its own sender.
+func (r *Repository) Send(ctx context.Context, mail aggregates.Mail) error {
+	if mail.From == "" {
+		mail.From = r.config.From
+	}
+
+	if mail.From == "" || len(mail.To) == 0 {
+		return errors.New("mail sender and recipients are required")
+	}
+
+	message, err := buildMessage(mail)
+	if err != nil {
+		return fmt.Errorf("buildMessage: %w", err)
+	}
+
+	client, err := r.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("r.dial: %w", err)
+	}
+	defer client.Close()
+
+	if r.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("",
+				r.config.Username, r.config.Password, r.config.Host)); err != nil {
+				return fmt.Errorf("client.Auth: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(mail.From); err != nil {
+		return fmt.Errorf("client.Mail: %w", err)
+	}
+
+	for _, to := range mail.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("client.Rcpt: %w", err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("client.Data: %w", err)
+	}
+
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("writer.Write: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("writer.Close: %w", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("client.Quit: %w", err)
+	}
+
+	return nil
+}
+
+// dial connects to the SMTP server, using implicit TLS on port 465 and
+// STARTTLS whenever the server supports it on any other port.
+func (r *Repository) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(r.config.Host, r.config.Port)
+	tlsConfig := &tls.Config{ServerName: r.config.Host, MinVersion: tls.VersionTLS12}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if r.config.Port == implicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).
+			DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dialer.DialContext: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	client, err := smtp.NewClient(conn, r.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp.NewClient: %w", err)
+	}
+
+	if r.config.Port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("client.StartTLS: %w", err)
+			}
+		}
+	}
+
+	return client, nil
+}
+
+// buildMessage builds the RFC 5322 message, as multipart/alternative when
+// the mail has both a text and an HTML version.
+func buildMessage(mail aggregates.Mail) ([]byte, error) {
+	var message bytes.Buffer
+
+	fmt.Fprintf(&message, "From: %s\r\n", mail.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(mail.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+
+	names := make([]string, 0, len(mail.Headers))
+	for name := range mail.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.NewReplacer("\r", "", "\n", "").Replace(mail.Headers[name])
+		fmt.Fprintf(&message, "%s: %s\r\n", textproto.CanonicalMIMEHeaderKey(name), value)
+	}
+
+	switch {
+	case mail.Text != "" && mail.HTML != "":
+		boundary, err := randomBoundary()
+		if err != nil {
+			return nil, fmt.Errorf("randomBoundary: %w", err)
+		}
+
+		fmt.Fprintf(&message,
+			"Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+		for _, part := range []struct {
+			contentType string
+			body        string
+		}{
+			{"text/plain", mail.Text},
+			{"text/html", mail.HTML},
+		} {
+			fmt.Fprintf(&message, "--%s\r\n", boundary)
+			if err := writeBody(&message, part.contentType, part.body); err != nil {
+				return nil, err
+			}
+			message.WriteString("\r\n")
+		}
+
+		fmt.Fprintf(&message, "--%s--\r\n", boundary)
+
+	case mail.HTML != "":
+		if err := writeBody(&message, "text/html", mail.HTML); err != nil {
+			return nil, err
+		}
+
+	default:
+		if err := writeBody(&message, "text/plain", mail.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.Bytes(), nil
+}
+
+func writeBody(message *bytes.Buffer, contentType, body string) error {
+	fmt.Fprintf(message, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(message)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return fmt.Errorf("writer.Write: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("writer.Close: %w", err)
+	}
+
+	message.WriteString("\r\n")
+
+	return nil
+}
+
+func randomBoundary() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,27 @@
+package smtp
+
+import "time"
+
+const dialTimeout = 10 * time.Second
+
+// Config holds the SMTP server settings, Username and Password are optional
+// for servers that don't require authentication (e.g. a local stand-in).
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Repository define the dependencies needed to send emails through SMTP.
+type Repository struct {
+	config Config
+}
+
+// New creates a new instance of the SMTP repository.
+func New(config Config) *Repository {
+	return &Repository{
+		config: config,
+	}
+}
@@ -3,67 +3,258 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
 )
 
 const (
-	selectEmailsByEmail = `
-SELECT *
+	selectEmails = `
+SELECT id, email, email_name, status, confirmation_token_hash,
+  confirmation_sent_at, unsubscribe_token, confirmed_at, invited_at,
+  unsubscribed_at, created_at, updated_at
 FROM emails
-WHERE email = :email
-deleted_at IS NULL
+WHERE deleted_at IS NULL AND ($1::TEXT = '' OR status = $1::TEXT)
+ORDER BY created_at, id;
+`
+
+	selectEmailByID = `
+SELECT id, email, email_name, status, confirmation_token_hash,
+  confirmation_sent_at, unsubscribe_token, confirmed_at, invited_at,
+  unsubscribed_at, created_at, updated_at
+FROM emails
+WHERE id = $1 AND deleted_at IS NULL;
+`
+
+	selectEmailByEmail = `
+SELECT id, email, email_name, status, confirmation_token_hash,
+  confirmation_sent_at, unsubscribe_token, confirmed_at, invited_at,
+  unsubscribed_at, created_at, updated_at
+FROM emails
+WHERE email = $1 AND deleted_at IS NULL;
+`
+
+	selectEmailByConfirmationTokenHash = `
+SELECT id, email, email_name, status, confirmation_token_hash,
+  confirmation_sent_at, unsubscribe_token, confirmed_at, invited_at,
+  unsubscribed_at, created_at, updated_at
+FROM emails
+WHERE confirmation_token_hash = $1 AND deleted_at IS NULL;
+`
+
+	selectEmailByUnsubscribeToken = `
+SELECT id, email, email_name, status, confirmation_token_hash,
+  confirmation_sent_at, unsubscribe_token, confirmed_at, invited_at,
+  unsubscribed_at, created_at, updated_at
+FROM emails
+WHERE unsubscribe_token = $1 AND deleted_at IS NULL;
 `
 
 	insertEmail = `
 INSERT INTO emails
-(email, email_name, created_at)
-VALUES (:email, :email_name, :created_at)
+(email, email_name, status, confirmation_token_hash, confirmation_sent_at,
+  unsubscribe_token, created_at, updated_at)
+VALUES
+(:email, :email_name, :status, :confirmation_token_hash, :confirmation_sent_at,
+  :unsubscribe_token, :created_at, :updated_at)
+RETURNING id;
+`
+
+	updateEmail = `
+UPDATE emails
+SET email_name = :email_name, status = :status,
+  confirmation_token_hash = :confirmation_token_hash,
+  confirmation_sent_at = :confirmation_sent_at,
+  unsubscribe_token = :unsubscribe_token,
+  confirmed_at = :confirmed_at, invited_at = :invited_at,
+  unsubscribed_at = :unsubscribed_at, updated_at = :updated_at
+WHERE id = :id AND deleted_at IS NULL;
 `
 )
 
-func (r *Repository) CheckEmailExists(ctx context.Context, email string) error {
-	dbEmails := []dbEmails{}
+// SelectEmails returns the waitlist emails with the given status, every
+// email when the status is empty.
+func (r *Repository) SelectEmails(ctx context.Context,
+	status aggregates.EmailStatus) ([]aggregates.Email, error) {
+	var dbEmails dbEmails
 	if err := r.db.SelectContext(ctx,
-		&dbEmails, selectEmailsByEmail, email); err != nil {
-		return fmt.Errorf("r.db.SelectContext, err: %w", err)
+		&dbEmails, selectEmails, string(status)); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
 	}
 
-	if len(dbEmails) > 0 {
-		return aggregates.ErrEmailAlreadyExists
+	emails := make([]aggregates.Email, len(dbEmails))
+	for i, dbEmail := range dbEmails {
+		emails[i] = dbEmail.toAggregate()
+	}
+
+	return emails, nil
+}
+
+// SelectEmailByID returns the waitlist email with the given ID.
+func (r *Repository) SelectEmailByID(
+	ctx context.Context, id int64) (aggregates.Email, error) {
+	return r.selectEmail(ctx, selectEmailByID, id)
+}
+
+// SelectEmailByAddress returns the waitlist email with the given normalized
+// address.
+func (r *Repository) SelectEmailByAddress(
+	ctx context.Context, address string) (aggregates.Email, error) {
+	return r.selectEmail(ctx, selectEmailByEmail, address)
+}
+
+// SelectEmailByConfirmationTokenHash returns the waitlist email whose
+// confirmation token has the given hash.
+func (r *Repository) SelectEmailByConfirmationTokenHash(
+	ctx context.Context, tokenHash string) (aggregates.Email, error) {
+	return r.selectEmail(ctx, selectEmailByConfirmationTokenHash, tokenHash)
+}
+
+// SelectEmailByUnsubscribeToken returns the waitlist email with the given
+// unsubscribe token.
+func (r *Repository) SelectEmailByUnsubscribeToken(
+	ctx context.Context, token string) (aggregates.Email, error) {
+	return r.selectEmail(ctx, selectEmailByUnsubscribeToken, token)
+}
+
+func (r *Repository) selectEmail(ctx context.Context,
+	query string, arg interface{}) (aggregates.Email, error) {
+	var dbEmail dbEmail
+	if err := r.db.GetContext(ctx, &dbEmail, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Email{}, aggregates.ErrEmailNotFound
+		}
+
+		return aggregates.Email{}, fmt.Errorf("r.db.GetContext, err: %w", err)
 	}
 
-	return nil
+	return dbEmail.toAggregate(), nil
 }
 
+// InsertEmail inserts a new waitlist email, returning it with its ID.
 func (r *Repository) InsertEmail(
-	ctx context.Context, email string) error {
-	dbEmail := dbEmailFromAggregate(email)
-	if _, err := sqlx.NamedExec(r.db, insertEmail, dbEmail); err != nil {
-		return fmt.Errorf("sqlx.NamedExec, err: %w", err)
+	ctx context.Context, email aggregates.Email) (aggregates.Email, error) {
+	now := time.Now().UTC()
+	email.CreatedAt = now
+	email.UpdatedAt = now
+
+	rows, err := r.db.NamedQueryContext(ctx, insertEmail, dbEmailFromAggregate(email))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return aggregates.Email{}, aggregates.ErrEmailAlreadyExists
+		}
+
+		return aggregates.Email{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Email{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&email.ID); err != nil {
+		return aggregates.Email{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return email, nil
+}
+
+// UpdateEmail updates the name, status, tokens and lifecycle dates of a
+// waitlist email.
+func (r *Repository) UpdateEmail(
+	ctx context.Context, email aggregates.Email) (aggregates.Email, error) {
+	email.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.NamedExecContext(ctx, updateEmail, dbEmailFromAggregate(email))
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return aggregates.Email{}, fmt.Errorf("result.RowsAffected, err: %w", err)
 	}
 
-	return nil
+	if affected == 0 {
+		return aggregates.Email{}, aggregates.ErrEmailNotFound
+	}
+
+	return email, nil
 }
 
 type dbEmail struct {
-	Email     string       `db:"email"`
-	CreatedAt time.Time    `db:"created_at"`
-	DeleteAt  sql.NullTime `db:"deleted_at"`
+	ID                    int64          `db:"id"`
+	Email                 string         `db:"email"`
+	EmailName             sql.NullString `db:"email_name"`
+	Status                string         `db:"status"`
+	ConfirmationTokenHash sql.NullString `db:"confirmation_token_hash"`
+	ConfirmationSentAt    sql.NullTime   `db:"confirmation_sent_at"`
+	UnsubscribeToken      sql.NullString `db:"unsubscribe_token"`
+	ConfirmedAt           sql.NullTime   `db:"confirmed_at"`
+	InvitedAt             sql.NullTime   `db:"invited_at"`
+	UnsubscribedAt        sql.NullTime   `db:"unsubscribed_at"`
+	CreatedAt             time.Time      `db:"created_at"`
+	UpdatedAt             time.Time      `db:"updated_at"`
 }
 
 type dbEmails []dbEmail
 
-func (dbe dbEmail) toAggregate() string {
-	return dbe.Email
+func (dbe dbEmail) toAggregate() aggregates.Email {
+	return aggregates.Email{
+		ID:                    dbe.ID,
+		Address:               dbe.Email,
+		Name:                  dbe.EmailName.String,
+		Status:                aggregates.EmailStatus(dbe.Status),
+		ConfirmationTokenHash: dbe.ConfirmationTokenHash.String,
+		ConfirmationSentAt:    timePtr(dbe.ConfirmationSentAt),
+		UnsubscribeToken:      dbe.UnsubscribeToken.String,
+		ConfirmedAt:           timePtr(dbe.ConfirmedAt),
+		InvitedAt:             timePtr(dbe.InvitedAt),
+		UnsubscribedAt:        timePtr(dbe.UnsubscribedAt),
+		CreatedAt:             dbe.CreatedAt,
+		UpdatedAt:             dbe.UpdatedAt,
+	}
 }
 
-func dbEmailFromAggregate(email string) dbEmail {
+func dbEmailFromAggregate(email aggregates.Email) dbEmail {
 	return dbEmail{
-		Email: email,
+		ID:                    email.ID,
+		Email:                 email.Address,
+		EmailName:             nullString(email.Name),
+		Status:                string(email.Status),
+		ConfirmationTokenHash: nullString(email.ConfirmationTokenHash),
+		ConfirmationSentAt:    nullTime(email.ConfirmationSentAt),
+		UnsubscribeToken:      nullString(email.UnsubscribeToken),
+		ConfirmedAt:           nullTime(email.ConfirmedAt),
+		InvitedAt:             nullTime(email.InvitedAt),
+		UnsubscribedAt:        nullTime(email.UnsubscribedAt),
+		CreatedAt:             email.CreatedAt,
+		UpdatedAt:             email.UpdatedAt,
 	}
 }
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+func timePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+
+	t := value.Time
+	return &t
+}
@@ -3,76 +3,299 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/manager/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 )
 
 const (
+	defaultProgramsSearchLimit = 20
+
 	selectAllPrograms = `
-SELECT *
+SELECT project_id, program_address, program_name, description, priority,
+  tags, created_at, updated_at, deleted_at
 FROM programs
-WHERE deleted_at IS NULL
+WHERE deleted_at IS NULL AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
 ORDER BY priority asc, created_at desc;
+`
+
+	searchPrograms = `
+SELECT project_id, program_address, program_name, description, priority,
+  tags, created_at, updated_at, deleted_at
+FROM programs
+WHERE deleted_at IS NULL AND ($3::BIGINT = 0 OR project_id = $3::BIGINT)
+  AND (lower(program_name) LIKE lower($1) OR program_address LIKE $1)
+ORDER BY priority asc, created_at desc
+LIMIT $2;
+`
+
+	selectProgramByAddress = `
+SELECT project_id, program_address, program_name, description, priority,
+  tags, created_at, updated_at, deleted_at
+FROM programs
+WHERE program_address = $1 AND ($2::BIGINT = 0 OR project_id = $2::BIGINT)
+ORDER BY project_id
+LIMIT 1;
 `
 
 	insertProgram = `
 INSERT INTO programs
-(program_address, program_name, created_at)
-VALUES (:program_address, :program_name, :created_at)
+(project_id, program_address, program_name, description, priority, tags,
+  created_at, updated_at)
+VALUES
+(:project_id, :program_address, :program_name, :description, :priority, :tags,
+  :created_at, :updated_at)
 `
+
+	updateProgram = `
+UPDATE programs
+SET program_name = :program_name, description = :description,
+  priority = :priority, tags = :tags, updated_at = :updated_at
+WHERE project_id = :project_id AND program_address = :program_address
+  AND deleted_at IS NULL
+`
+
+	deleteProgram = `
+UPDATE programs
+SET deleted_at = $2
+WHERE program_address = $1 AND deleted_at IS NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+
+	restoreProgram = `
+UPDATE programs
+SET deleted_at = NULL, updated_at = $2
+WHERE program_address = $1 AND deleted_at IS NOT NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+
+	// uniqueViolation is the Postgres error code of unique constraints.
+	uniqueViolation = "23505"
 )
 
+// SelectAllPrograms returns the programs of the tenant in ctx, the programs
+// of every tenant when ctx acts on every project.
 func (r *Repository) SelectAllPrograms(
 	ctx context.Context) ([]aggregates.Program, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
 	var dbPrograms dbPrograms
-	if err := r.db.SelectContext(ctx,
-		&dbPrograms, selectAllPrograms); err != nil {
+	if err := r.db.SelectContext(ctx, &dbPrograms, selectAllPrograms,
+		projectID); err != nil {
 		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
 	}
 
-	programs := make([]aggregates.Program, len(dbPrograms))
-	for i, dbProgram := range dbPrograms {
-		programs[i] = dbProgram.toAggregate()
+	return dbPrograms.toAggregates(), nil
+}
+
+// SelectPrograms returns the programs matching the filter, every program
+// when the filter has no search.
+func (r *Repository) SelectPrograms(ctx context.Context,
+	filter aggregates.ProgramsFilter) ([]aggregates.Program, error) {
+	if filter.Search == "" {
+		return r.SelectAllPrograms(ctx)
 	}
 
-	return programs, nil
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultProgramsSearchLimit
+	}
+
+	var dbPrograms dbPrograms
+	if err := r.db.SelectContext(ctx, &dbPrograms, searchPrograms,
+		escapeLike(filter.Search)+"%", limit,
+		projectID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	return dbPrograms.toAggregates(), nil
 }
 
-func (r *Repository) InsertProgram(
-	ctx context.Context, program aggregates.Program) error {
-	dbProgram := dbProgramFromAggregate(program)
-	if _, err := sqlx.NamedExec(r.db, insertProgram, dbProgram); err != nil {
-		return fmt.Errorf("sqlx.NamedExec, err: %w", err)
+// SelectProgramByAddress returns the program with the given address, even if
+// it has been deleted.
+func (r *Repository) SelectProgramByAddress(
+	ctx context.Context, address string) (aggregates.Program, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbProgram dbProgram
+	if err := r.db.GetContext(ctx, &dbProgram, selectProgramByAddress,
+		address, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Program{}, aggregates.ErrProgramNotFound
+		}
+
+		return aggregates.Program{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbProgram.toAggregate(), nil
+}
+
+func (r *Repository) InsertProgram(ctx context.Context,
+	program aggregates.Program) (aggregates.Program, error) {
+	now := time.Now().UTC()
+	program.CreatedAt = now
+	program.UpdatedAt = now
+
+	if program.ProjectID == 0 {
+		program.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	if _, err := r.db.NamedExecContext(ctx,
+		insertProgram, dbProgramFromAggregate(program)); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return aggregates.Program{}, aggregates.ErrProgramAlreadyExists
+		}
+
+		return aggregates.Program{}, fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return program, nil
+}
+
+// UpdateProgram updates the editable fields of a program.
+func (r *Repository) UpdateProgram(ctx context.Context,
+	program aggregates.Program) (aggregates.Program, error) {
+	program.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.NamedExecContext(ctx,
+		updateProgram, dbProgramFromAggregate(program))
+	if err != nil {
+		return aggregates.Program{}, fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	if err := expectAffected(result); err != nil {
+		return aggregates.Program{}, err
+	}
+
+	return program, nil
+}
+
+// DeleteProgram soft deletes a program.
+func (r *Repository) DeleteProgram(ctx context.Context, address string) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, deleteProgram, address,
+		time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return expectAffected(result)
+}
+
+// RestoreProgram restores a soft deleted program.
+func (r *Repository) RestoreProgram(ctx context.Context, address string) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, restoreProgram, address,
+		time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return expectAffected(result)
+}
+
+// expectAffected returns aggregates.ErrProgramNotFound when the statement
+// didn't affect any program.
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrProgramNotFound
 	}
 
 	return nil
 }
 
+// escapeLike escapes the LIKE wildcards of a user provided search.
+func escapeLike(search string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(search)
+}
+
 type dbProgram struct {
-	ProgramAddress string       `db:"program_address"`
-	ProgramName    string       `db:"program_name"`
-	CreatedAt      time.Time    `db:"created_at"`
-	UpdateAt       time.Time    `db:"updated_at"`
-	DeleteAt       sql.NullTime `db:"deleted_at"`
-	Priority       int          `db:"priority"`
+	ProjectID      int64          `db:"project_id"`
+	ProgramAddress string         `db:"program_address"`
+	ProgramName    string         `db:"program_name"`
+	Description    string         `db:"description"`
+	Priority       int            `db:"priority"`
+	Tags           pq.StringArray `db:"tags"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdateAt       time.Time      `db:"updated_at"`
+	DeleteAt       sql.NullTime   `db:"deleted_at"`
 }
 
 type dbPrograms []dbProgram
 
+func (dbps dbPrograms) toAggregates() []aggregates.Program {
+	programs := make([]aggregates.Program, len(dbps))
+	for i, dbProgram := range dbps {
+		programs[i] = dbProgram.toAggregate()
+	}
+
+	return programs
+}
+
 func (dbe dbProgram) toAggregate() aggregates.Program {
-	return aggregates.Program{
+	program := aggregates.Program{
+		ProjectID:      dbe.ProjectID,
 		ProgramAddress: dbe.ProgramAddress,
 		ProgramName:    dbe.ProgramName,
+		Description:    dbe.Description,
+		Priority:       dbe.Priority,
+		Tags:           dbe.Tags,
+		CreatedAt:      dbe.CreatedAt,
+		UpdatedAt:      dbe.UpdateAt,
 	}
+
+	if dbe.DeleteAt.Valid {
+		program.DeletedAt = &dbe.DeleteAt.Time
+	}
+
+	return program
 }
 
 func dbProgramFromAggregate(e aggregates.Program) dbProgram {
+	tags := pq.StringArray(e.Tags)
+	if tags == nil {
+		tags = pq.StringArray{}
+	}
+
 	return dbProgram{
+		ProjectID:      e.ProjectID,
 		ProgramAddress: e.ProgramAddress,
 		ProgramName:    e.ProgramName,
+		Description:    e.Description,
+		Priority:       e.Priority,
+		Tags:           tags,
+		CreatedAt:      e.CreatedAt,
+		UpdateAt:       e.UpdatedAt,
 	}
 }
@@ -0,0 +1,78 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+// QueryWindowCountsBetween queries the InfluxDB server for the transaction
+// counts between start and end, for every transaction if programAddress is
+// empty or for the given program otherwise.
+//
+// Transactions are stored aggregated, so the slow transactions are the ones
+// aggregated in points whose mean solana time is above latencyThreshold, they
+// are only counted when latencyThreshold is positive.
+func (r *Repository) QueryWindowCountsBetween(ctx context.Context,
+	programAddress string, start, end time.Time,
+	latencyThreshold time.Duration) (aggregates.WindowCounts, error) {
+	start, end = start.UTC(), end.UTC()
+
+	bucket, measurement, filter, err := r.source(ctx, programAddress)
+	if err != nil {
+		return aggregates.WindowCounts{}, fmt.Errorf("r.source: %w", err)
+	}
+
+	base := fmt.Sprintf(`from(bucket:"%s")
+    |> range(start: %s, stop: %s)
+    |> filter(fn: (r) => r._measurement == %s)%s`,
+		bucket, start.Format(time.RFC3339), end.Format(time.RFC3339),
+		fluxString(measurement), filter)
+
+	counts := aggregates.WindowCounts{
+		Start: start,
+		End:   end,
+	}
+
+	total, err := r.queryScalar(ctx, base+`
+    |> filter(fn: (r) => r._field == "solana_time_count")
+    |> group()
+    |> sum()`)
+	if err != nil {
+		return aggregates.WindowCounts{}, fmt.Errorf("r.queryScalar(total): %w", err)
+	}
+	counts.Total = int64(total)
+
+	if counts.Total == 0 {
+		return counts, nil
+	}
+
+	errors, err := r.queryScalar(ctx, base+`
+    |> filter(fn: (r) => r._field == "solana_time_count" and r.error == "true")
+    |> group()
+    |> sum()`)
+	if err != nil {
+		return aggregates.WindowCounts{}, fmt.Errorf("r.queryScalar(errors): %w", err)
+	}
+	counts.Errors = int64(errors)
+
+	if latencyThreshold <= 0 {
+		return counts, nil
+	}
+
+	slow, err := r.queryScalar(ctx, base+fmt.Sprintf(`
+    |> filter(fn: (r) => r._field == "solana_time_count" or r._field == "solana_time_mean")
+    |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+    |> filter(fn: (r) => r.solana_time_mean > %d.0)
+    |> map(fn: (r) => ({ _value: float(v: r.solana_time_count) }))
+    |> group()
+    |> sum()`, latencyThreshold.Milliseconds()))
+	if err != nil {
+		return aggregates.WindowCounts{}, fmt.Errorf("r.queryScalar(slow): %w", err)
+	}
+	counts.Slow = int64(slow)
+
+	return counts, nil
+}
@@ -23,8 +23,9 @@ func (r *Repository) WriteTransaction(ctx context.Context,
 	// TODO I'm writing the metric with time.Now().UnixNano() as the timestamp
 	// but I should be using the timestamp from the Solana block.
 	data := fmt.Sprintf(
-		"transactions,event_id=%s,signature=%s,error=%t solana_time=%d %d",
+		"transactions,event_id=%s,signature=%s,error=%t%s solana_time=%d %d",
 		metric.EventID, metric.Signature, metric.Error,
+		r.projectTags(TransactionsBucket, metric.ProjectID),
 		metric.SolanaTime, time.Now().UTC().UnixNano())
 
 	req, err := http.NewRequestWithContext(ctx,
@@ -52,8 +53,9 @@ func (r *Repository) WriteProgram(ctx context.Context,
 	// TODO I'm writing the metric with time.Now().UnixNano() as the timestamp
 	// but I should be using the timestamp from the Solana block.
 	data := fmt.Sprintf(
-		"%s,program_address=%s solana_time=%d %d",
-		metric.ProgramAddress, metric.ProgramAddress,
+		"%s,program_address=%s,error=%t%s solana_time=%d %d",
+		metric.ProgramAddress, metric.ProgramAddress, metric.Error,
+		r.projectTags(ProgramsBucket, metric.ProjectID),
 		metric.SolanaTime, time.Now().UTC().UnixNano())
 
 	req, err := http.NewRequestWithContext(ctx,
@@ -80,14 +82,19 @@ func (r *Repository) WriteProgram(ctx context.Context,
 // Currently is agreggating the metrics by the mean of a fixed value which is
 // not ideal; but it's a good starting point.
 func (r *Repository) QueryPerformance(ctx context.Context) (aggregates.PerformanceResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	result, err := r.client.QueryAPI(organization).Query(ctx,
 		fmt.Sprintf(
 			`from(bucket:"%s")
     |> range(start: -8h)
-    |> filter(fn: (r) => r._measurement == "transactions")
+    |> filter(fn: (r) => r._measurement == "transactions")%s
     |> filter(fn: (r) => r._field == "solana_time_mean")
 		|> group(columns: ["_field"])
-    |> aggregateWindow(every: 30m, fn: mean)`, r.bucket),
+    |> aggregateWindow(every: 30m, fn: mean)`, bucket, filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -129,14 +136,19 @@ func (r *Repository) QueryPerformance(ctx context.Context) (aggregates.Performan
 // not ideal; but it's a good starting point.
 func (r *Repository) QueryProgramPerformance(
 	ctx context.Context, program string) (aggregates.PerformanceResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	result, err := r.client.QueryAPI(organization).Query(ctx,
 		fmt.Sprintf(`
 			from(bucket:"%s")
     |> range(start: -8h)
-    |> filter(fn: (r) => r._measurement == "%s")
+    |> filter(fn: (r) => r._measurement == %s)%s
     |> filter(fn: (r) => r._field == "solana_time_mean")
 		|> group(columns: ["_field"])
-    |> aggregateWindow(every: 30m, fn: mean)`, r.bucket, program),
+    |> aggregateWindow(every: 30m, fn: mean)`, bucket, fluxString(program), filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -180,14 +192,19 @@ func (r *Repository) QueryProgramPerformance(
 // Currently is agreggating the metrics by the mean of a fixed value which is
 // not ideal; but it's a good starting point.
 func (r *Repository) QueryThroughput(ctx context.Context) (aggregates.ThroughputResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	result, err := r.client.QueryAPI(organization).Query(ctx,
 		fmt.Sprintf(
 			`from(bucket:"%s")
     |> range(start: -8h)
-    |> filter(fn: (r) => r._measurement == "transactions")
+    |> filter(fn: (r) => r._measurement == "transactions")%s
     |> filter(fn: (r) => r._field == "solana_time_count")
 		|> group()
-    |> aggregateWindow(every: 30m, fn: count)`, r.bucket),
+    |> aggregateWindow(every: 30m, fn: count)`, bucket, filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -227,14 +244,19 @@ func (r *Repository) QueryThroughput(ctx context.Context) (aggregates.Throughput
 // not ideal; but it's a good starting point.
 func (r *Repository) QueryProgramThroughput(
 	ctx context.Context, programAddress string) (aggregates.ThroughputResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	result, err := r.client.QueryAPI(organization).Query(ctx,
 		fmt.Sprintf(
 			`from(bucket:"%s")
     |> range(start: -8h)
-    |> filter(fn: (r) => r._measurement == "%s")
+    |> filter(fn: (r) => r._measurement == %s)%s
     |> filter(fn: (r) => r._field == "solana_time_count")
 		|> group()
-    |> aggregateWindow(every: 30m, fn: sum)`, r.bucket, programAddress),
+    |> aggregateWindow(every: 30m, fn: sum)`, bucket, fluxString(programAddress), filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -274,17 +296,22 @@ func (r *Repository) QueryProgramThroughput(
 // Currently is agreggating the metrics by the mean of a fixed value which is
 // not ideal; but it's a good starting point.
 func (r *Repository) QueryApdex(ctx context.Context) (aggregates.ApdexResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	satisfactoryMetrics, err := r.client.QueryAPI(organization).Query(ctx,
 		// TODO: This query is using only the solana time for the apdex calculation,
 		// but it should be using both the solana time.
 		fmt.Sprintf(`
 			from(bucket:"%s")
 		|> range(start: -8h)
-		|> filter(fn: (r) => r._measurement == "transactions")
+		|> filter(fn: (r) => r._measurement == "transactions")%s
 		|> filter(fn: (r) => r._field == "solana_time_mean")
 		|> filter(fn: (r) => r._value < %d)
 		|> group()
-		|> aggregateWindow(every: 30m, fn: mean, createEmpty: false)`, r.bucket, apdexSatisfactory),
+		|> aggregateWindow(every: 30m, fn: mean, createEmpty: false)`, bucket, filter, apdexSatisfactory),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -296,13 +323,13 @@ func (r *Repository) QueryApdex(ctx context.Context) (aggregates.ApdexResults, e
 		fmt.Sprintf(`
 			from(bucket:"%s")
 		|> range(start: -8h)
-		|> filter(fn: (r) => r._measurement == "transactions")
+		|> filter(fn: (r) => r._measurement == "transactions")%s
 		|> filter(fn: (r) => r._field == "solana_time_mean")
 		|> filter(fn: (r) => r._value > %d)
 		|> filter(fn: (r) => r._value < %d)
 		|> group()
 		|> aggregateWindow(every: 30m, fn: mean, createEmpty: false)`,
-			r.bucket, apdexSatisfactory, apdexTolerable),
+			bucket, filter, apdexSatisfactory, apdexTolerable),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -314,12 +341,12 @@ func (r *Repository) QueryApdex(ctx context.Context) (aggregates.ApdexResults, e
 		fmt.Sprintf(`
 			from(bucket:"%s")
 		|> range(start: -8h)
-		|> filter(fn: (r) => r._measurement == "transactions")
+		|> filter(fn: (r) => r._measurement == "transactions")%s
 		|> filter(fn: (r) => r._field == "solana_time_mean")
 		|> filter(fn: (r) => r._value > %d)
 		|> group()
 		|> aggregateWindow(every: 30m, fn: mean, createEmpty: false)`,
-			r.bucket, apdexTolerable),
+			bucket, filter, apdexTolerable),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -401,7 +428,7 @@ func (r *Repository) QueryApdex(ctx context.Context) (aggregates.ApdexResults, e
 		if err != nil {
 			// This should never happen, but if it does, we should log it and
 			// continue to the next iteration.
-			slog.Error("time.Parse", err)
+			slog.Error("time.Parse", slog.Any("error", err))
 			continue
 		}
 
@@ -429,16 +456,21 @@ func (r *Repository) QueryApdex(ctx context.Context) (aggregates.ApdexResults, e
 
 func (r *Repository) QueryErrors(
 	ctx context.Context) (aggregates.ErrorResults, error) {
+	bucket, filter, err := r.scope(ctx, r.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+
 	influxErrors, err := r.client.QueryAPI(organization).Query(ctx,
 		fmt.Sprintf(`
 			from(bucket: "%s")
 			|> range(start: -8h)
-			|> filter(fn: (r) => r._measurement == "transactions")
+			|> filter(fn: (r) => r._measurement == "transactions")%s
 			|> filter(fn: (r) => r.error == "true")
 			|> group(columns: ["_time"])
 			|> group()
 			|> aggregateWindow(every: 30m, fn: count, createEmpty: false)
-			|> yield(name: "errors")`, r.bucket),
+			|> yield(name: "errors")`, bucket, filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
@@ -448,12 +480,12 @@ func (r *Repository) QueryErrors(
 		fmt.Sprintf(`
 			from(bucket: "%s")
 			|> range(start: -8h)
-			|> filter(fn: (r) => r._measurement == "transactions")
+			|> filter(fn: (r) => r._measurement == "transactions")%s
 			|> filter(fn: (r) => r.error == "false" or r.error == "true")
 			|> group(columns: ["_time"])
 			|> group()
 			|> aggregateWindow(every: 30m, fn: count, createEmpty: false)
-			|> yield(name: "total")`, r.bucket),
+			|> yield(name: "total")`, bucket, filter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
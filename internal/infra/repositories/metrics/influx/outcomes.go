@@ -0,0 +1,121 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+const rpcOutcomesMeasurement = "rpc_outcomes"
+
+// WriteOutcome writes the outcome of a sent transaction to Telegraf using
+// HTTP, tagged by the program and the endpoint, origin and region of its
+// sendTransaction call.
+func (r *Repository) WriteOutcome(ctx context.Context,
+	metric aggregates.OutcomeMetric) error {
+	data := fmt.Sprintf(
+		"%s,program=%s,endpoint=%s,origin=%s,region=%s,outcome=%s%s transactions=1 %d",
+		rpcOutcomesMeasurement, tagValue(metric.ProgramAddress),
+		tagValue(metric.Endpoint), tagValue(metric.Origin), tagValue(metric.Region),
+		tagValue(string(metric.Outcome)), r.projectTags(TransactionsBucket, metric.ProjectID),
+		metric.Time.UTC().UnixNano())
+
+	req, err := http.NewRequestWithContext(ctx,
+		"POST", r.telegrafURL, bytes.NewBufferString(data))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http.DefaultClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to write metric, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// QueryDropRates queries the InfluxDB server for the outcomes of the sent
+// transactions matching the filter, one drop rate per program, endpoint or
+// origin, sorted by the amount of transactions.
+func (r *Repository) QueryDropRates(ctx context.Context,
+	filter aggregates.DropRateFilter) ([]aggregates.DropRate, error) {
+	bucket, tenantFilter, err := r.scope(ctx, TransactionsBucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+	start, end := filter.Start.UTC(), filter.End.UTC()
+
+	query := fmt.Sprintf(`from(bucket:"%s")
+    |> range(start: %s, stop: %s)
+    |> filter(fn: (r) => r._measurement == "%s" and r._field == "transactions_count")%s`,
+		bucket, start.Format(time.RFC3339), end.Format(time.RFC3339),
+		rpcOutcomesMeasurement, tenantFilter)
+
+	if filter.ProgramAddress != "" {
+		query += fmt.Sprintf(`
+    |> filter(fn: (r) => r.program == %s)`, fluxString(filter.ProgramAddress))
+	}
+
+	if filter.Endpoint != "" {
+		query += fmt.Sprintf(`
+    |> filter(fn: (r) => r.endpoint == %s)`, fluxString(filter.Endpoint))
+	}
+
+	columns := []string{string(filter.Breakdown), "outcome"}
+
+	counts, err := r.queryGrouped(ctx, columns, query+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped: %w", err)
+	}
+
+	dropRates := make(map[string]*aggregates.DropRate)
+	for key, count := range counts {
+		breakdown, outcome, _ := strings.Cut(key, groupKeySeparator)
+
+		dropRate, ok := dropRates[breakdown]
+		if !ok {
+			dropRate = &aggregates.DropRate{
+				Breakdown: breakdown,
+				Start:     start,
+				End:       end,
+			}
+			dropRates[breakdown] = dropRate
+		}
+
+		switch aggregates.Outcome(outcome) {
+		case aggregates.OutcomeLanded:
+			dropRate.Landed += int64(count)
+		case aggregates.OutcomeDropped:
+			dropRate.Dropped += int64(count)
+		case aggregates.OutcomeExpired:
+			dropRate.Expired += int64(count)
+		}
+	}
+
+	result := make([]aggregates.DropRate, 0, len(dropRates))
+	for _, dropRate := range dropRates {
+		result = append(result, *dropRate)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total() != result[j].Total() {
+			return result[i].Total() > result[j].Total()
+		}
+
+		return result[i].Breakdown < result[j].Breakdown
+	})
+
+	return result, nil
+}
@@ -0,0 +1,255 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+const rpcLandingsMeasurement = "rpc_landings"
+
+// WriteLanding writes a landed transaction metric to Telegraf using HTTP,
+// tagged by the endpoint, origin and region of the sendTransaction call it
+// landed through.
+func (r *Repository) WriteLanding(ctx context.Context,
+	metric aggregates.LandingMetric) error {
+	data := fmt.Sprintf(
+		"%s,endpoint=%s,origin=%s,region=%s%s time_to_land=%d,blockhash_staleness=%d,submissions=%d,landing_submission=%d %d",
+		rpcLandingsMeasurement, tagValue(metric.Endpoint), tagValue(metric.Origin),
+		tagValue(metric.Region), r.projectTags(TransactionsBucket, metric.ProjectID),
+		metric.TimeToLand.Milliseconds(), metric.BlockhashStaleness.Milliseconds(),
+		metric.Submissions, metric.LandingSubmission, metric.Time.UTC().UnixNano())
+
+	req, err := http.NewRequestWithContext(ctx,
+		"POST", r.telegrafURL, bytes.NewBufferString(data))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http.DefaultClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to write metric, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// QueryProviderBenchmarks queries the InfluxDB server for the benchmarks of
+// the RPC providers matching the filter, one per provider and client region
+// or origin, sorted by the amount of calls.
+func (r *Repository) QueryProviderBenchmarks(ctx context.Context,
+	filter aggregates.ProviderBenchmarkFilter) ([]aggregates.ProviderBenchmark, error) {
+	bucket, tenantFilter, err := r.scope(ctx, TransactionsBucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+	start, end := filter.Start.UTC(), filter.End.UTC()
+
+	base := func(measurement string) string {
+		query := fmt.Sprintf(`from(bucket:"%s")
+    |> range(start: %s, stop: %s)
+    |> filter(fn: (r) => r._measurement == "%s")%s`,
+			bucket, start.Format(time.RFC3339), end.Format(time.RFC3339),
+			measurement, tenantFilter)
+
+		if filter.Endpoint != "" {
+			query += fmt.Sprintf(`
+    |> filter(fn: (r) => r.endpoint == %s)`, fluxString(filter.Endpoint))
+		}
+
+		return query
+	}
+
+	columns := []string{"endpoint"}
+	if filter.Breakdown != aggregates.ProviderBreakdownNone {
+		columns = append(columns, string(filter.Breakdown))
+	}
+	methodColumns := append(append([]string{}, columns...), "method")
+
+	calls, landings := base(rpcCallsMeasurement), base(rpcLandingsMeasurement)
+
+	counts, err := r.queryGrouped(ctx, columns, calls+`
+    |> filter(fn: (r) => r._field == "latency_count")`+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(count): %w", err)
+	}
+
+	if len(counts) == 0 {
+		return []aggregates.ProviderBenchmark{}, nil
+	}
+
+	errors, err := r.queryGrouped(ctx, columns, calls+`
+    |> filter(fn: (r) => r._field == "latency_count" and r.error == "true")`+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(errors): %w", err)
+	}
+
+	rateLimited, err := r.queryGrouped(ctx, columns, calls+`
+    |> filter(fn: (r) => r._field == "latency_count" and r.failure == "rate_limited")`+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(rate_limited): %w", err)
+	}
+
+	sent, err := r.queryGrouped(ctx, columns, calls+`
+    |> filter(fn: (r) => r._field == "latency_count" and r.method == "sendTransaction" and r.error == "false")`+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(sent): %w", err)
+	}
+
+	landed, err := r.queryGrouped(ctx, columns, landings+`
+    |> filter(fn: (r) => r._field == "time_to_land_count")`+fluxGroup(columns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(landed): %w", err)
+	}
+
+	methodCounts, err := r.queryGrouped(ctx, methodColumns, calls+`
+    |> filter(fn: (r) => r._field == "latency_count")`+fluxGroup(methodColumns)+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(method_count): %w", err)
+	}
+
+	latencies, err := r.queryQuantiles(ctx, methodColumns, calls+`
+    |> filter(fn: (r) => r._field == "latency_mean")`+fluxGroup(methodColumns),
+		0.50, 0.95, 0.99)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryQuantiles(latency): %w", err)
+	}
+
+	timesToLand, err := r.queryQuantiles(ctx, columns, landings+`
+    |> filter(fn: (r) => r._field == "time_to_land_mean")`+fluxGroup(columns),
+		0.50, 0.95)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryQuantiles(time_to_land): %w", err)
+	}
+
+	stalenesses, err := r.queryQuantiles(ctx, columns, landings+`
+    |> filter(fn: (r) => r._field == "blockhash_staleness_mean")`+fluxGroup(columns),
+		0.50, 0.95)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryQuantiles(blockhash_staleness): %w", err)
+	}
+
+	submissions, err := r.queryGrouped(ctx, columns, landings+`
+    |> filter(fn: (r) => r._field == "submissions_mean")`+fluxGroup(columns)+`
+    |> mean()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(submissions): %w", err)
+	}
+
+	landingSubmissions, err := r.queryGrouped(ctx, columns, landings+`
+    |> filter(fn: (r) => r._field == "landing_submission_mean")`+fluxGroup(columns)+`
+    |> mean()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(landing_submission): %w", err)
+	}
+
+	benchmarks := make(map[string]*aggregates.ProviderBenchmark, len(counts))
+	for key, count := range counts {
+		endpoint, breakdown, _ := strings.Cut(key, groupKeySeparator)
+		if filter.Breakdown != aggregates.ProviderBreakdownNone && breakdown == "" {
+			breakdown = unknownTag
+		}
+
+		benchmarks[key] = &aggregates.ProviderBenchmark{
+			Endpoint:              endpoint,
+			Breakdown:             breakdown,
+			Start:                 start,
+			End:                   end,
+			Calls:                 int64(count),
+			Errors:                int64(errors[key]),
+			RateLimited:           int64(rateLimited[key]),
+			Methods:               []aggregates.MethodLatency{},
+			Sent:                  int64(sent[key]),
+			Landed:                int64(landed[key]),
+			TimeToLandP50:         timesToLand[0][key],
+			TimeToLandP95:         timesToLand[1][key],
+			BlockhashStalenessP50: stalenesses[0][key],
+			BlockhashStalenessP95: stalenesses[1][key],
+			ResendsPerLanded:      max(submissions[key]-1, 0),
+			LandingSubmission:     landingSubmissions[key],
+		}
+	}
+
+	for key, count := range methodCounts {
+		separator := strings.LastIndex(key, groupKeySeparator)
+		benchmark, ok := benchmarks[key[:separator]]
+		if !ok {
+			continue
+		}
+
+		benchmark.Methods = append(benchmark.Methods, aggregates.MethodLatency{
+			Method:     key[separator+len(groupKeySeparator):],
+			Count:      int64(count),
+			LatencyP50: latencies[0][key],
+			LatencyP95: latencies[1][key],
+			LatencyP99: latencies[2][key],
+		})
+	}
+
+	result := make([]aggregates.ProviderBenchmark, 0, len(benchmarks))
+	for _, benchmark := range benchmarks {
+		sort.Slice(benchmark.Methods, func(i, j int) bool {
+			if benchmark.Methods[i].Count != benchmark.Methods[j].Count {
+				return benchmark.Methods[i].Count > benchmark.Methods[j].Count
+			}
+
+			return benchmark.Methods[i].Method < benchmark.Methods[j].Method
+		})
+
+		result = append(result, *benchmark)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Calls != result[j].Calls {
+			return result[i].Calls > result[j].Calls
+		}
+
+		if result[i].Endpoint != result[j].Endpoint {
+			return result[i].Endpoint < result[j].Endpoint
+		}
+
+		return result[i].Breakdown < result[j].Breakdown
+	})
+
+	return result, nil
+}
+
+// queryQuantiles runs a query once per quantile, returning the quantiles of
+// every group in the same order.
+func (r *Repository) queryQuantiles(ctx context.Context, columns []string,
+	query string, quantiles ...float64) ([]map[string]float64, error) {
+	values := make([]map[string]float64, len(quantiles))
+	for i, quantile := range quantiles {
+		var err error
+		values[i], err = r.queryGrouped(ctx, columns, query+fmt.Sprintf(`
+    |> quantile(q: %.2f, method: "estimate_tdigest")`, quantile))
+		if err != nil {
+			return nil, fmt.Errorf("r.queryGrouped(p%.0f): %w", quantile*100, err)
+		}
+	}
+
+	return values, nil
+}
+
+// fluxGroup returns the Flux group step of the columns.
+func fluxGroup(columns []string) string {
+	return fmt.Sprintf(`
+    |> group(columns: ["%s"])`, strings.Join(columns, `", "`))
+}
@@ -11,20 +11,27 @@ const (
 )
 
 // Repository define the dependencies needed to store events in InfluxDB.
+//
+// Every point is tagged with the project it belongs to, when
+// bucketPerProject is set the points of every project are also written to,
+// and queried from, their own buckets for a strict isolation.
 type Repository struct {
-	client       influxdb.Client
-	telegrafURL  string
-	organization string
-	bucket       string
+	client           influxdb.Client
+	telegrafURL      string
+	organization     string
+	bucket           string
+	bucketPerProject bool
 }
 
 // New creates a new instance of the InfluxDB repository.
-func New(client influxdb.Client, telegrafURL, bucket string) *Repository {
+func New(client influxdb.Client,
+	telegrafURL, bucket string, bucketPerProject bool) *Repository {
 	return &Repository{
-		client:       client,
-		organization: organization,
-		bucket:       bucket,
-		telegrafURL:  telegrafURL,
+		client:           client,
+		organization:     organization,
+		bucket:           bucket,
+		telegrafURL:      telegrafURL,
+		bucketPerProject: bucketPerProject,
 	}
 }
 
@@ -0,0 +1,207 @@
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+const (
+	rpcCallsMeasurement = "rpc_calls"
+
+	// unknownTag is the value of the RPC call tags the agent couldn't tell,
+	// the line protocol doesn't allow empty tag values.
+	unknownTag = "unknown"
+)
+
+// tagEscaper escapes the line protocol tag values.
+var tagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// WriteRPCCall writes an RPC call metric to Telegraf using HTTP, tagged by
+// method, endpoint, origin and region. The remaining rate limit is only
+// written when the RPC node sent it.
+func (r *Repository) WriteRPCCall(ctx context.Context,
+	metric aggregates.RPCCallMetric) error {
+	var rateLimitRemaining string
+	if metric.RateLimitRemaining != nil {
+		rateLimitRemaining = fmt.Sprintf(",ratelimit_remaining=%d", *metric.RateLimitRemaining)
+	}
+
+	data := fmt.Sprintf(
+		"%s,method=%s,endpoint=%s,origin=%s,region=%s,batch=%t,error=%t,failure=%s%s latency=%d,response_size=%d%s %d",
+		rpcCallsMeasurement, tagValue(metric.Method), tagValue(metric.Endpoint),
+		tagValue(metric.Origin), tagValue(metric.Region), metric.Batch, metric.Error, failureTag(metric.FailureKind),
+		r.projectTags(TransactionsBucket, metric.ProjectID),
+		metric.Latency.Milliseconds(), metric.ResponseSize, rateLimitRemaining,
+		metric.Time.UTC().UnixNano())
+
+	req, err := http.NewRequestWithContext(ctx,
+		"POST", r.telegrafURL, bytes.NewBufferString(data))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http.DefaultClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to write metric, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// QueryRPCStats queries the InfluxDB server for the RPC call stats matching
+// the filter, grouped by the filter tag and sorted by the amount of calls.
+func (r *Repository) QueryRPCStats(ctx context.Context,
+	filter aggregates.RPCStatsFilter) ([]aggregates.RPCStats, error) {
+	bucket, tenantFilter, err := r.scope(ctx, TransactionsBucket)
+	if err != nil {
+		return nil, fmt.Errorf("r.scope: %w", err)
+	}
+	start, end := filter.Start.UTC(), filter.End.UTC()
+
+	base := fmt.Sprintf(`from(bucket:"%s")
+    |> range(start: %s, stop: %s)
+    |> filter(fn: (r) => r._measurement == "%s")%s`,
+		bucket, start.Format(time.RFC3339), end.Format(time.RFC3339),
+		rpcCallsMeasurement, tenantFilter)
+
+	for tag, value := range map[string]string{
+		"method":   filter.Method,
+		"endpoint": filter.Endpoint,
+		"origin":   filter.Origin,
+	} {
+		if value != "" {
+			base += fmt.Sprintf(`
+    |> filter(fn: (r) => r.%s == %s)`, tag, fluxString(value))
+		}
+	}
+
+	groupBy := []string{string(filter.GroupBy)}
+	group := fmt.Sprintf(`
+    |> group(columns: ["%s"])`, filter.GroupBy)
+
+	counts, err := r.queryGrouped(ctx, groupBy, base+`
+    |> filter(fn: (r) => r._field == "latency_count")`+group+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(count): %w", err)
+	}
+
+	if len(counts) == 0 {
+		return []aggregates.RPCStats{}, nil
+	}
+
+	errors, err := r.queryGrouped(ctx, groupBy, base+`
+    |> filter(fn: (r) => r._field == "latency_count" and r.error == "true")`+group+`
+    |> sum()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(errors): %w", err)
+	}
+
+	sizes, err := r.queryGrouped(ctx, groupBy, base+`
+    |> filter(fn: (r) => r._field == "response_size_mean")`+group+`
+    |> mean()`)
+	if err != nil {
+		return nil, fmt.Errorf("r.queryGrouped(response_size): %w", err)
+	}
+
+	percentiles := make([]map[string]float64, 3)
+	for i, quantile := range []float64{0.50, 0.95, 0.99} {
+		percentiles[i], err = r.queryGrouped(ctx, groupBy, base+`
+    |> filter(fn: (r) => r._field == "latency_mean")`+group+fmt.Sprintf(`
+    |> quantile(q: %.2f, method: "estimate_tdigest")`, quantile))
+		if err != nil {
+			return nil, fmt.Errorf("r.queryGrouped(p%.0f): %w", quantile*100, err)
+		}
+	}
+
+	stats := make([]aggregates.RPCStats, 0, len(counts))
+	for key, count := range counts {
+		stats = append(stats, aggregates.RPCStats{
+			Group:        key,
+			Start:        start,
+			End:          end,
+			Count:        int64(count),
+			Errors:       int64(errors[key]),
+			LatencyP50:   percentiles[0][key],
+			LatencyP95:   percentiles[1][key],
+			LatencyP99:   percentiles[2][key],
+			ResponseSize: sizes[key],
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+
+		return stats[i].Group < stats[j].Group
+	})
+
+	return stats, nil
+}
+
+// groupKeySeparator separates the values of the columns of the group keys
+// of the queries grouped by more than one column.
+const groupKeySeparator = "\x00"
+
+// queryGrouped runs a query that is expected to return a single value per
+// group, keyed by the values of the columns the query is grouped by.
+func (r *Repository) queryGrouped(ctx context.Context,
+	columns []string, query string) (map[string]float64, error) {
+	result, err := r.client.QueryAPI(organization).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
+	}
+	defer result.Close()
+
+	values := make(map[string]float64)
+	for result.Next() {
+		keys := make([]string, len(columns))
+		for i, column := range columns {
+			keys[i], _ = result.Record().ValueByKey(column).(string)
+		}
+
+		values[strings.Join(keys, groupKeySeparator)] = toFloat64(result.Record().Value())
+	}
+
+	if result.Err() != nil {
+		return nil, fmt.Errorf("result.Err: %w", result.Err())
+	}
+
+	return values, nil
+}
+
+// failureTag returns the failure tag of an RPC call, none for the calls
+// that succeeded.
+func failureTag(kind string) string {
+	if kind == "" {
+		return "none"
+	}
+
+	return tagValue(kind)
+}
+
+func tagValue(value string) string {
+	if value == "" {
+		return unknownTag
+	}
+
+	return tagEscaper.Replace(value)
+}
+
+// fluxString quotes a value as a Flux string literal.
+func fluxString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`).Replace(value) + `"`
+}
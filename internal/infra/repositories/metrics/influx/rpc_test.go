@@ -0,0 +1,23 @@
+package influx
+
+import "testing"
+
+func TestFluxString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", want: `"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"`},
+		{value: `x") or (r._measurement != "`, want: `"x\") or (r._measurement != \""`},
+		{value: `a\`, want: `"a\\"`},
+		{value: "${r.project_id}", want: `"\${r.project_id}"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := fluxString(test.value); got != test.want {
+				t.Errorf("fluxString() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// bucketTag is the tag Telegraf routes the points by in the bucket per
+// project mode, its influxdb_v2 output has to be configured with
+// bucket_tag = "bucket" and exclude_bucket_tag = true.
+const bucketTag = "bucket"
+
+// CreateProjectBuckets creates the buckets of a project in the bucket per
+// project mode, it's a no-op otherwise.
+func (r *Repository) CreateProjectBuckets(
+	ctx context.Context, projectID int64) error {
+	if !r.bucketPerProject {
+		return nil
+	}
+
+	org, err := r.client.OrganizationsAPI().FindOrganizationByName(
+		ctx, r.organization)
+	if err != nil {
+		return fmt.Errorf("r.client.OrganizationsAPI().FindOrganizationByName: %w", err)
+	}
+
+	for _, bucket := range []string{TransactionsBucket, ProgramsBucket} {
+		name := r.projectBucket(bucket, projectID)
+
+		if _, err := r.client.BucketsAPI().FindBucketByName(ctx, name); err == nil {
+			continue
+		}
+
+		if _, err := r.client.BucketsAPI().CreateBucketWithName(
+			ctx, org, name); err != nil {
+			return fmt.Errorf("r.client.BucketsAPI().CreateBucketWithName(%s): %w",
+				name, err)
+		}
+	}
+
+	return nil
+}
+
+// projectBucket returns the bucket where the project points of the given
+// bucket are stored, the default project keeps using the shared buckets as
+// they hold the points that predate the multi-tenancy.
+func (r *Repository) projectBucket(bucket string, projectID int64) string {
+	if !r.bucketPerProject || projectID == 0 ||
+		projectID == tenantsAggregates.DefaultProjectID {
+		return bucket
+	}
+
+	return fmt.Sprintf("%s_%d", bucket, projectID)
+}
+
+// projectTags returns the line protocol tags of a project point, the bucket
+// tag is only set in the bucket per project mode.
+func (r *Repository) projectTags(bucket string, projectID int64) string {
+	if projectID == 0 {
+		projectID = tenantsAggregates.DefaultProjectID
+	}
+
+	tags := fmt.Sprintf(",project_id=%d", projectID)
+	if r.bucketPerProject {
+		tags += fmt.Sprintf(",%s=%s", bucketTag, r.projectBucket(bucket, projectID))
+	}
+
+	return tags
+}
+
+// scope returns the bucket to query and the filter restricting the points
+// to the project of the tenant in ctx, the filter is empty when ctx acts on
+// every project.
+func (r *Repository) scope(
+	ctx context.Context, bucket string) (string, string, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("tenantsAggregates.ProjectIDFromContext: %w", err)
+	}
+
+	switch projectID {
+	case 0:
+		return bucket, "", nil
+
+	case tenantsAggregates.DefaultProjectID:
+		// The points that predate the multi-tenancy don't have the tag.
+		return r.projectBucket(bucket, projectID), fmt.Sprintf(`
+    |> filter(fn: (r) => not exists r.project_id or r.project_id == "%d")`,
+			projectID), nil
+
+	default:
+		return r.projectBucket(bucket, projectID), fmt.Sprintf(`
+    |> filter(fn: (r) => r.project_id == "%d")`, projectID), nil
+	}
+}
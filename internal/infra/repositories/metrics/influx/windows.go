@@ -0,0 +1,151 @@
+package influx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/metrics/aggregates"
+)
+
+const transactionsMeasurement = "transactions"
+
+// QueryWindowStats queries the InfluxDB server for the aggregated metrics of
+// the last window, for every transaction if programAddress is empty or for
+// the given program otherwise.
+func (r *Repository) QueryWindowStats(ctx context.Context,
+	programAddress string, window time.Duration) (aggregates.WindowStats, error) {
+	end := time.Now().UTC()
+
+	return r.queryWindowStats(ctx, programAddress, end.Add(-window), end)
+}
+
+// QueryWindowStatsBetween queries the InfluxDB server for the aggregated
+// metrics between start and end, for every transaction if programAddress is
+// empty or for the given program otherwise.
+func (r *Repository) QueryWindowStatsBetween(ctx context.Context,
+	programAddress string, start, end time.Time) (aggregates.WindowStats, error) {
+	return r.queryWindowStats(ctx, programAddress, start.UTC(), end.UTC())
+}
+
+func (r *Repository) queryWindowStats(ctx context.Context,
+	programAddress string, start, end time.Time) (aggregates.WindowStats, error) {
+	bucket, measurement, filter, err := r.source(ctx, programAddress)
+	if err != nil {
+		return aggregates.WindowStats{}, fmt.Errorf("r.source: %w", err)
+	}
+
+	base := fmt.Sprintf(`from(bucket:"%s")
+    |> range(start: %s, stop: %s)
+    |> filter(fn: (r) => r._measurement == %s)%s`,
+		bucket, start.Format(time.RFC3339), end.Format(time.RFC3339),
+		fluxString(measurement), filter)
+
+	stats := aggregates.WindowStats{
+		Start: start,
+		End:   end,
+	}
+
+	count, err := r.queryScalar(ctx, base+`
+    |> filter(fn: (r) => r._field == "solana_time_count")
+    |> group()
+    |> sum()`)
+	if err != nil {
+		return aggregates.WindowStats{}, fmt.Errorf("r.queryScalar(count): %w", err)
+	}
+	stats.Count = int64(count)
+
+	if stats.Count == 0 {
+		return stats, nil
+	}
+
+	errors, err := r.queryScalar(ctx, base+`
+    |> filter(fn: (r) => r._field == "solana_time_count" and r.error == "true")
+    |> group()
+    |> sum()`)
+	if err != nil {
+		return aggregates.WindowStats{}, fmt.Errorf("r.queryScalar(errors): %w", err)
+	}
+	stats.Errors = int64(errors)
+
+	for _, percentile := range []struct {
+		quantile float64
+		value    *float64
+	}{
+		{0.50, &stats.LatencyP50},
+		{0.95, &stats.LatencyP95},
+		{0.99, &stats.LatencyP99},
+	} {
+		*percentile.value, err = r.queryScalar(ctx, base+fmt.Sprintf(`
+    |> filter(fn: (r) => r._field == "solana_time_mean")
+    |> group()
+    |> quantile(q: %.2f, method: "estimate_tdigest")`, percentile.quantile))
+		if err != nil {
+			return aggregates.WindowStats{}, fmt.Errorf(
+				"r.queryScalar(p%.0f): %w", percentile.quantile*100, err)
+		}
+	}
+
+	stats.Apdex, err = r.queryScalar(ctx, base+fmt.Sprintf(`
+    |> filter(fn: (r) => r._field == "solana_time_mean")
+    |> map(fn: (r) => ({ r with _value:
+        if r._value < %d.0 then 1.0
+        else if r._value < %d.0 then 0.5
+        else 0.0 }))
+    |> group()
+    |> mean()`, apdexSatisfactory, apdexTolerable))
+	if err != nil {
+		return aggregates.WindowStats{}, fmt.Errorf("r.queryScalar(apdex): %w", err)
+	}
+
+	return stats, nil
+}
+
+// queryScalar runs a query that is expected to return a single value, it
+// returns 0 when the query doesn't return any record.
+func (r *Repository) queryScalar(ctx context.Context, query string) (float64, error) {
+	result, err := r.client.QueryAPI(organization).Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("r.client.QueryAPI(organization).Query: %w", err)
+	}
+	defer result.Close()
+
+	var value float64
+	if result.Next() {
+		value = toFloat64(result.Record().Value())
+	}
+
+	if result.Err() != nil {
+		return 0, fmt.Errorf("result.Err: %w", result.Err())
+	}
+
+	return value, nil
+}
+
+// source returns the bucket and the measurement where the metrics are
+// stored, transactions metrics are stored in the transactions bucket while
+// program metrics use the program address as measurement in the programs
+// bucket. The filter restricts the points to the tenant in ctx.
+func (r *Repository) source(ctx context.Context,
+	programAddress string) (string, string, string, error) {
+	if programAddress == "" {
+		bucket, filter, err := r.scope(ctx, TransactionsBucket)
+		return bucket, transactionsMeasurement, filter, err
+	}
+
+	bucket, filter, err := r.scope(ctx, ProgramsBucket)
+	return bucket, programAddress, filter, err
+}
+
+func toFloat64(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,53 @@
+package senders
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// sendDiscord posts the message to a Discord webhook, the fields are sent as
+// an embed.
+func (r *Repository) sendDiscord(ctx context.Context, channel aggregates.Channel,
+	message aggregates.Message, text string) error {
+	fields := make([]discordField, len(message.Fields))
+	for i, field := range message.Fields {
+		fields[i] = discordField{Name: field.Name, Value: field.Value, Inline: true}
+	}
+
+	// Discord embed colors are decimal RGB values.
+	embedColor, _ := strconv.ParseInt(strings.TrimPrefix(color(message), "#"), 16, 64)
+
+	return r.postJSON(ctx, channel.Config["webhook_url"], discordMessage{
+		Content: text,
+		Embeds: []discordEmbed{
+			{
+				Title:     message.Title,
+				Color:     embedColor,
+				Fields:    fields,
+				Timestamp: message.CreatedAt.UTC().Format(time.RFC3339),
+			},
+		},
+	}, nil)
+}
+
+type discordMessage struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	Color     int64          `json:"color"`
+	Fields    []discordField `json:"fields,omitempty"`
+	Timestamp string         `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
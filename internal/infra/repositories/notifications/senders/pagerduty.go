@@ -0,0 +1,75 @@
+package senders
+
+import (
+	"context"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+const pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// sendPagerDuty sends the message as a PagerDuty Events API v2 event, the
+// message dedup key makes a resolved message resolve its triggered incident.
+func (r *Repository) sendPagerDuty(ctx context.Context, channel aggregates.Channel,
+	message aggregates.Message, text string) error {
+	event := pagerDutyEvent{
+		RoutingKey:  channel.Config["routing_key"],
+		EventAction: "trigger",
+		DedupKey:    message.DedupKey,
+	}
+
+	if message.Resolved() {
+		// Resolve events only need the routing and dedup keys.
+		event.EventAction = "resolve"
+		return r.postJSON(ctx, pagerDutyURL, event, nil)
+	}
+
+	details := make(map[string]string, len(message.Fields))
+	for _, field := range message.Fields {
+		details[field.Name] = field.Value
+	}
+
+	source := message.Source
+	if source == "" {
+		source = "encinitas"
+	}
+
+	event.Payload = &pagerDutyPayload{
+		Summary:       text,
+		Source:        source,
+		Severity:      pagerDutySeverity(message.Severity),
+		Timestamp:     message.CreatedAt.UTC().Format(time.RFC3339),
+		CustomDetails: details,
+	}
+
+	return r.postJSON(ctx, pagerDutyURL, event, nil)
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// pagerDutySeverity maps the message severity to the PagerDuty ones, which
+// are critical, error, warning and info.
+func pagerDutySeverity(severity aggregates.Severity) string {
+	switch severity {
+	case aggregates.SeverityCritical:
+		return "critical"
+	case aggregates.SeverityWarning:
+		return "warning"
+	}
+
+	return "info"
+}
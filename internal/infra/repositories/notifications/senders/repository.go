@@ -0,0 +1,91 @@
+package senders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Repository defines the dependencies needed to deliver notifications to
+// every supported channel type.
+type Repository struct {
+	client *http.Client
+}
+
+// New creates a new instance of the senders repository.
+func New() *Repository {
+	return &Repository{
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Send delivers the message through the channel, rendering the payload
+// expected by the channel type.
+func (r *Repository) Send(ctx context.Context,
+	channel aggregates.Channel, message aggregates.Message) error {
+	text, err := render(channel, message)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	switch channel.Type {
+	case aggregates.ChannelTypeSlack:
+		return r.sendSlack(ctx, channel, message, text)
+	case aggregates.ChannelTypeDiscord:
+		return r.sendDiscord(ctx, channel, message, text)
+	case aggregates.ChannelTypeTelegram:
+		return r.sendTelegram(ctx, channel, text)
+	case aggregates.ChannelTypePagerDuty:
+		return r.sendPagerDuty(ctx, channel, message, text)
+	case aggregates.ChannelTypeSMTP:
+		return r.sendSMTP(ctx, channel, message, text)
+	case aggregates.ChannelTypeWebhook:
+		return r.sendWebhook(ctx, channel, message, text)
+	}
+
+	return fmt.Errorf("unknown channel type %q: %w",
+		channel.Type, aggregates.ErrInvalidChannel)
+}
+
+// postJSON posts the payload as JSON to the url, any non 2xx response is
+// returned as an error including the beginning of the response body.
+func (r *Repository) postJSON(ctx context.Context,
+	url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx,
+		http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("r.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code: %d, body: %s",
+			resp.StatusCode, respBody)
+	}
+
+	return nil
+}
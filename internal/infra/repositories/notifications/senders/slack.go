@@ -0,0 +1,64 @@
+package senders
+
+import (
+	"context"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// sendSlack posts the message to a Slack incoming webhook, the fields are
+// sent as a colored attachment.
+func (r *Repository) sendSlack(ctx context.Context, channel aggregates.Channel,
+	message aggregates.Message, text string) error {
+	fields := make([]slackField, len(message.Fields))
+	for i, field := range message.Fields {
+		fields[i] = slackField{Title: field.Name, Value: field.Value, Short: true}
+	}
+
+	return r.postJSON(ctx, channel.Config["webhook_url"], slackMessage{
+		Text: text,
+		Attachments: []slackAttachment{
+			{
+				Color:  color(message),
+				Fields: fields,
+				Footer: message.Source,
+				TS:     message.CreatedAt.Unix(),
+			},
+		},
+	}, nil)
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Fields []slackField `json:"fields,omitempty"`
+	Footer string       `json:"footer,omitempty"`
+	TS     int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// color returns the hex color of the message, green for resolved messages
+// and depending on the severity otherwise.
+func color(message aggregates.Message) string {
+	if message.Resolved() {
+		return "#2eb886"
+	}
+
+	switch message.Severity {
+	case aggregates.SeverityCritical:
+		return "#e01e5a"
+	case aggregates.SeverityWarning:
+		return "#ecb22e"
+	}
+
+	return "#439fe0"
+}
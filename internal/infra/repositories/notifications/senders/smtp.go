@@ -0,0 +1,40 @@
+package senders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	mailAggregates "github.com/jcleira/encinitas-collector-go/internal/app/mail/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+	"github.com/jcleira/encinitas-collector-go/internal/infra/repositories/mail/smtp"
+)
+
+// sendSMTP emails the message text to the comma separated "to" recipients.
+func (r *Repository) sendSMTP(ctx context.Context, channel aggregates.Channel,
+	message aggregates.Message, text string) error {
+	var to []string
+	for _, recipient := range strings.Split(channel.Config["to"], ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			to = append(to, recipient)
+		}
+	}
+
+	mailer := smtp.New(smtp.Config{
+		Host:     channel.Config["host"],
+		Port:     channel.Config["port"],
+		Username: channel.Config["username"],
+		Password: channel.Config["password"],
+		From:     channel.Config["from"],
+	})
+
+	if err := mailer.Send(ctx, mailAggregates.Mail{
+		To:      to,
+		Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(string(message.State)), message.Title),
+		Text:    text,
+	}); err != nil {
+		return fmt.Errorf("mailer.Send: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package senders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+const telegramURL = "https://api.telegram.org/bot%s/sendMessage"
+
+// sendTelegram sends the message text to a Telegram chat through the Bot API.
+func (r *Repository) sendTelegram(ctx context.Context,
+	channel aggregates.Channel, text string) error {
+	return r.postJSON(ctx,
+		fmt.Sprintf(telegramURL, channel.Config["bot_token"]), telegramMessage{
+			ChatID: channel.Config["chat_id"],
+			Text:   text,
+		}, nil)
+}
+
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
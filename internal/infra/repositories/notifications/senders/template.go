@@ -0,0 +1,46 @@
+package senders
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+// render returns the text of the message for the channel, using the channel
+// template when it defines one. Templates are executed with the message as
+// data, e.g. "{{ .Title }} is {{ .State }}".
+func render(channel aggregates.Channel, message aggregates.Message) (string, error) {
+	if channel.Template == "" {
+		return defaultText(message), nil
+	}
+
+	tmpl, err := template.New(channel.Name).Parse(channel.Template)
+	if err != nil {
+		return "", fmt.Errorf("template.Parse: %w", err)
+	}
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, message); err != nil {
+		return "", fmt.Errorf("tmpl.Execute: %w", err)
+	}
+
+	return text.String(), nil
+}
+
+func defaultText(message aggregates.Message) string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "[%s] %s", strings.ToUpper(string(message.State)), message.Title)
+
+	if message.Text != "" {
+		fmt.Fprintf(&text, "\n%s", message.Text)
+	}
+
+	for _, field := range message.Fields {
+		fmt.Fprintf(&text, "\n%s: %s", field.Name, field.Value)
+	}
+
+	return text.String()
+}
@@ -0,0 +1,75 @@
+package senders
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+)
+
+const (
+	signatureHeader = "X-Encinitas-Signature"
+	timestampHeader = "X-Encinitas-Timestamp"
+)
+
+// sendWebhook posts the message as JSON to a generic webhook, when the
+// channel defines a secret the payload is signed the same way as the alert
+// webhooks, an HMAC-SHA256 of "<timestamp>.<body>".
+func (r *Repository) sendWebhook(ctx context.Context, channel aggregates.Channel,
+	message aggregates.Message, text string) error {
+	fields := make(map[string]string, len(message.Fields))
+	for _, field := range message.Fields {
+		fields[field.Name] = field.Value
+	}
+
+	payload := webhookMessage{
+		Title:     message.Title,
+		Text:      text,
+		Severity:  string(message.Severity),
+		State:     string(message.State),
+		DedupKey:  message.DedupKey,
+		Source:    message.Source,
+		Fields:    fields,
+		CreatedAt: message.CreatedAt,
+	}
+
+	secret := channel.Config["secret"]
+	if secret == "" {
+		return r.postJSON(ctx, channel.Config["url"], payload, nil)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return r.postJSON(ctx, channel.Config["url"], json.RawMessage(body),
+		map[string]string{
+			timestampHeader: timestamp,
+			signatureHeader: "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		})
+}
+
+type webhookMessage struct {
+	Title     string            `json:"title"`
+	Text      string            `json:"text"`
+	Severity  string            `json:"severity"`
+	State     string            `json:"state"`
+	DedupKey  string            `json:"dedup_key,omitempty"`
+	Source    string            `json:"source,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
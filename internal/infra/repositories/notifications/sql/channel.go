@@ -0,0 +1,255 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectAllChannels = `
+SELECT id, project_id, name, type, config, template, enabled, created_at, updated_at
+FROM notification_channels
+WHERE deleted_at IS NULL AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
+ORDER BY id;
+`
+
+	selectChannelByID = `
+SELECT id, project_id, name, type, config, template, enabled, created_at, updated_at
+FROM notification_channels
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::BIGINT = 0 OR project_id = $2::BIGINT);
+`
+
+	insertChannel = `
+INSERT INTO notification_channels
+(project_id, name, type, config, template, enabled, created_at, updated_at)
+VALUES
+(:project_id, :name, :type, :config, :template, :enabled, :created_at, :updated_at)
+RETURNING id;
+`
+
+	updateChannel = `
+UPDATE notification_channels
+SET name = :name, type = :type, config = :config, template = :template,
+  enabled = :enabled, updated_at = :updated_at
+WHERE id = :id AND project_id = :project_id AND deleted_at IS NULL
+RETURNING created_at;
+`
+
+	deleteChannel = `
+UPDATE notification_channels
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+)
+
+// SelectAllChannels returns every notification channel of the tenant in ctx.
+func (r *Repository) SelectAllChannels(
+	ctx context.Context) ([]aggregates.Channel, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbChannels dbChannels
+	if err := r.db.SelectContext(ctx, &dbChannels, selectAllChannels,
+		projectID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	channels := make([]aggregates.Channel, len(dbChannels))
+	for i, dbChannel := range dbChannels {
+		channel, err := dbChannel.toAggregate()
+		if err != nil {
+			return nil, fmt.Errorf("dbChannel.toAggregate, err: %w", err)
+		}
+
+		channels[i] = channel
+	}
+
+	return channels, nil
+}
+
+// SelectChannelByID returns the notification channel with the given ID.
+func (r *Repository) SelectChannelByID(
+	ctx context.Context, id int64) (aggregates.Channel, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbChannel dbChannel
+	if err := r.db.GetContext(ctx, &dbChannel, selectChannelByID,
+		id, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Channel{}, aggregates.ErrChannelNotFound
+		}
+
+		return aggregates.Channel{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	channel, err := dbChannel.toAggregate()
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("dbChannel.toAggregate, err: %w", err)
+	}
+
+	return channel, nil
+}
+
+// InsertChannel inserts a new notification channel, returning it with its ID.
+func (r *Repository) InsertChannel(ctx context.Context,
+	channel aggregates.Channel) (aggregates.Channel, error) {
+	now := time.Now().UTC()
+	channel.CreatedAt = now
+	channel.UpdatedAt = now
+
+	if channel.ProjectID == 0 {
+		channel.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	dbChannel, err := dbChannelFromAggregate(channel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("dbChannelFromAggregate, err: %w", err)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, insertChannel, dbChannel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Channel{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&channel.ID); err != nil {
+		return aggregates.Channel{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return channel, nil
+}
+
+// UpdateChannel updates an existing notification channel.
+func (r *Repository) UpdateChannel(ctx context.Context,
+	channel aggregates.Channel) (aggregates.Channel, error) {
+	channel.UpdatedAt = time.Now().UTC()
+
+	if channel.ProjectID == 0 {
+		channel.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	dbChannel, err := dbChannelFromAggregate(channel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("dbChannelFromAggregate, err: %w", err)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, updateChannel, dbChannel)
+	if err != nil {
+		return aggregates.Channel{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return aggregates.Channel{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+		}
+
+		return aggregates.Channel{}, aggregates.ErrChannelNotFound
+	}
+
+	if err := rows.Scan(&channel.CreatedAt); err != nil {
+		return aggregates.Channel{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel soft deletes a notification channel.
+func (r *Repository) DeleteChannel(ctx context.Context, id int64) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, deleteChannel, id,
+		time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrChannelNotFound
+	}
+
+	return nil
+}
+
+type dbChannel struct {
+	ID        int64          `db:"id"`
+	ProjectID int64          `db:"project_id"`
+	Name      string         `db:"name"`
+	Type      string         `db:"type"`
+	Config    []byte         `db:"config"`
+	Template  sql.NullString `db:"template"`
+	Enabled   bool           `db:"enabled"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+type dbChannels []dbChannel
+
+func (dbc dbChannel) toAggregate() (aggregates.Channel, error) {
+	config := make(map[string]string)
+	if len(dbc.Config) > 0 {
+		if err := json.Unmarshal(dbc.Config, &config); err != nil {
+			return aggregates.Channel{}, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	}
+
+	return aggregates.Channel{
+		ID:        dbc.ID,
+		ProjectID: dbc.ProjectID,
+		Name:      dbc.Name,
+		Type:      aggregates.ChannelType(dbc.Type),
+		Config:    config,
+		Template:  dbc.Template.String,
+		Enabled:   dbc.Enabled,
+		CreatedAt: dbc.CreatedAt,
+		UpdatedAt: dbc.UpdatedAt,
+	}, nil
+}
+
+func dbChannelFromAggregate(channel aggregates.Channel) (dbChannel, error) {
+	config, err := json.Marshal(channel.Config)
+	if err != nil {
+		return dbChannel{}, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return dbChannel{
+		ID:        channel.ID,
+		ProjectID: channel.ProjectID,
+		Name:      channel.Name,
+		Type:      string(channel.Type),
+		Config:    config,
+		Template: sql.NullString{
+			String: channel.Template,
+			Valid:  channel.Template != "",
+		},
+		Enabled:   channel.Enabled,
+		CreatedAt: channel.CreatedAt,
+		UpdatedAt: channel.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/notifications/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	insertDeliveries = `
+INSERT INTO notification_outbox
+(channel_id, message, status, attempts, next_attempt_at, created_at)
+SELECT id, $2, 'pending', 0, $3, $3
+FROM notification_channels
+WHERE id = ANY($1::BIGINT[]) AND ($4::BIGINT = 0 OR project_id = $4::BIGINT);
+`
+
+	// claimDueDeliveries hides the due deliveries from other dispatchers
+	// until the lease ends by moving their next attempt, so a delivery
+	// claimed by a crashed dispatcher is retried once the lease expires.
+	claimDueDeliveries = `
+UPDATE notification_outbox
+SET next_attempt_at = $2
+WHERE id IN (
+  SELECT id
+  FROM notification_outbox
+  WHERE status = 'pending' AND next_attempt_at <= $1
+  ORDER BY next_attempt_at
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, channel_id, message, status, attempts, next_attempt_at,
+  last_error, created_at, sent_at;
+`
+
+	markDeliverySent = `
+UPDATE notification_outbox
+SET status = 'sent', sent_at = $2, last_error = NULL
+WHERE id = $1;
+`
+
+	markDeliveryFailed = `
+UPDATE notification_outbox
+SET status = :status, attempts = :attempts,
+  next_attempt_at = :next_attempt_at, last_error = :last_error
+WHERE id = :id;
+`
+)
+
+// InsertDeliveries queues the message in the outbox for every channel, the
+// channels that don't belong to the tenant in ctx are skipped.
+func (r *Repository) InsertDeliveries(ctx context.Context,
+	channelIDs []int64, message aggregates.Message) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	jsonMessage, err := json.Marshal(jsonMessageFromAggregate(message))
+	if err != nil {
+		return fmt.Errorf("json.Marshal, err: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, insertDeliveries,
+		pq.Int64Array(channelIDs), jsonMessage, time.Now().UTC(),
+		projectID); err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries claims up to limit pending deliveries whose next attempt
+// is due, hiding them from other dispatchers for the lease duration.
+func (r *Repository) ClaimDueDeliveries(ctx context.Context,
+	now time.Time, lease time.Duration, limit int) ([]aggregates.Delivery, error) {
+	var dbDeliveries dbDeliveries
+	if err := r.db.SelectContext(ctx, &dbDeliveries,
+		claimDueDeliveries, now, now.Add(lease), limit); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	deliveries := make([]aggregates.Delivery, len(dbDeliveries))
+	for i, dbDelivery := range dbDeliveries {
+		delivery, err := dbDelivery.toAggregate()
+		if err != nil {
+			return nil, fmt.Errorf("dbDelivery.toAggregate, err: %w", err)
+		}
+
+		deliveries[i] = delivery
+	}
+
+	return deliveries, nil
+}
+
+// MarkDeliverySent marks the delivery as sent.
+func (r *Repository) MarkDeliverySent(
+	ctx context.Context, id int64, sentAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, markDeliverySent, id, sentAt); err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDeliveryFailed stores the failed attempt of the delivery and when it's
+// going to be retried, if it's going to be retried at all.
+func (r *Repository) MarkDeliveryFailed(
+	ctx context.Context, delivery aggregates.Delivery) error {
+	if _, err := r.db.NamedExecContext(ctx, markDeliveryFailed,
+		map[string]interface{}{
+			"id":              delivery.ID,
+			"status":          string(delivery.Status),
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+		}); err != nil {
+		return fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+type dbDelivery struct {
+	ID            int64          `db:"id"`
+	ChannelID     int64          `db:"channel_id"`
+	Message       []byte         `db:"message"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	CreatedAt     time.Time      `db:"created_at"`
+	SentAt        sql.NullTime   `db:"sent_at"`
+}
+
+type dbDeliveries []dbDelivery
+
+func (dbd dbDelivery) toAggregate() (aggregates.Delivery, error) {
+	var jsonMessage jsonMessage
+	if err := json.Unmarshal(dbd.Message, &jsonMessage); err != nil {
+		return aggregates.Delivery{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	delivery := aggregates.Delivery{
+		ID:            dbd.ID,
+		ChannelID:     dbd.ChannelID,
+		Message:       jsonMessage.toAggregate(),
+		Status:        aggregates.DeliveryStatus(dbd.Status),
+		Attempts:      dbd.Attempts,
+		NextAttemptAt: dbd.NextAttemptAt,
+		LastError:     dbd.LastError.String,
+		CreatedAt:     dbd.CreatedAt,
+	}
+
+	if dbd.SentAt.Valid {
+		delivery.SentAt = &dbd.SentAt.Time
+	}
+
+	return delivery, nil
+}
+
+// jsonMessage represents the JSON version of a notification message, as it's
+// stored in the outbox.
+type jsonMessage struct {
+	Title     string      `json:"title"`
+	Text      string      `json:"text"`
+	Severity  string      `json:"severity"`
+	State     string      `json:"state"`
+	DedupKey  string      `json:"dedup_key"`
+	Source    string      `json:"source"`
+	Fields    []jsonField `json:"fields"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type jsonField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (jm jsonMessage) toAggregate() aggregates.Message {
+	fields := make([]aggregates.Field, len(jm.Fields))
+	for i, field := range jm.Fields {
+		fields[i] = aggregates.Field{Name: field.Name, Value: field.Value}
+	}
+
+	return aggregates.Message{
+		Title:     jm.Title,
+		Text:      jm.Text,
+		Severity:  aggregates.Severity(jm.Severity),
+		State:     aggregates.State(jm.State),
+		DedupKey:  jm.DedupKey,
+		Source:    jm.Source,
+		Fields:    fields,
+		CreatedAt: jm.CreatedAt,
+	}
+}
+
+func jsonMessageFromAggregate(message aggregates.Message) jsonMessage {
+	fields := make([]jsonField, len(message.Fields))
+	for i, field := range message.Fields {
+		fields[i] = jsonField{Name: field.Name, Value: field.Value}
+	}
+
+	return jsonMessage{
+		Title:     message.Title,
+		Text:      message.Text,
+		Severity:  string(message.Severity),
+		State:     string(message.State),
+		DedupKey:  message.DedupKey,
+		Source:    message.Source,
+		Fields:    fields,
+		CreatedAt: message.CreatedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// upsertAttemptQuery records a submission of a transaction. A failed
+	// submission only fails the transaction when nothing else is known
+	// about it, and a successful one undoes the failure of the previous
+	// ones, e.g. a resend rejected as already processed.
+	upsertAttemptQuery = `
+INSERT INTO transaction_attempts
+(project_id, signature, browser_id, client_id, fee_payer, program_addresses,
+ submitted_at, outcome, error)
+VALUES
+(:project_id, :signature, :browser_id, :client_id, :fee_payer, :program_addresses,
+ :submitted_at, :outcome, :error)
+ON CONFLICT (project_id, signature) DO UPDATE SET
+  submissions = transaction_attempts.submissions + 1,
+  submitted_at = LEAST(transaction_attempts.submitted_at, EXCLUDED.submitted_at),
+  browser_id = COALESCE(NULLIF(transaction_attempts.browser_id, ''), EXCLUDED.browser_id),
+  client_id = COALESCE(NULLIF(transaction_attempts.client_id, ''), EXCLUDED.client_id),
+  outcome = CASE
+    WHEN EXCLUDED.outcome = 'failed' THEN COALESCE(transaction_attempts.outcome, 'failed')
+    ELSE NULLIF(transaction_attempts.outcome, 'failed')
+  END,
+  error = CASE
+    WHEN EXCLUDED.error <> '' THEN EXCLUDED.error
+    ELSE transaction_attempts.error
+  END;
+`
+
+	// updateAttemptOutcomeQuery sets the outcome of a transaction, a landed
+	// transaction stays landed.
+	updateAttemptOutcomeQuery = `
+UPDATE transaction_attempts
+SET outcome = $3
+WHERE project_id = $1 AND signature = $2 AND outcome IS DISTINCT FROM 'landed';
+`
+)
+
+// UpsertAttempt records a submission of a transaction.
+func (r *Repository) UpsertAttempt(ctx context.Context,
+	attempt aggregates.Attempt) error {
+	if attempt.ProjectID == 0 {
+		attempt.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	if _, err := sqlx.NamedExecContext(ctx, r.db, upsertAttemptQuery,
+		dbAttemptFromAggregate(attempt)); err != nil {
+		return fmt.Errorf("sqlx.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAttemptOutcome sets the outcome of a transaction of a project.
+func (r *Repository) UpdateAttemptOutcome(ctx context.Context, projectID int64,
+	signature string, outcome aggregates.Outcome) error {
+	// The attempts without a project are stored in the default one, see
+	// UpsertAttempt.
+	if projectID == 0 {
+		projectID = tenantsAggregates.DefaultProjectID
+	}
+
+	if _, err := r.db.ExecContext(ctx, updateAttemptOutcomeQuery,
+		projectID, signature, string(outcome)); err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+type dbAttempt struct {
+	ProjectID        int64          `db:"project_id"`
+	Signature        string         `db:"signature"`
+	BrowserID        string         `db:"browser_id"`
+	ClientID         string         `db:"client_id"`
+	FeePayer         string         `db:"fee_payer"`
+	ProgramAddresses pq.StringArray `db:"program_addresses"`
+	SubmittedAt      time.Time      `db:"submitted_at"`
+	Outcome          sql.NullString `db:"outcome"`
+	Error            string         `db:"error"`
+}
+
+func dbAttemptFromAggregate(a aggregates.Attempt) dbAttempt {
+	programAddresses := pq.StringArray(a.ProgramAddresses)
+	if programAddresses == nil {
+		programAddresses = pq.StringArray{}
+	}
+
+	return dbAttempt{
+		ProjectID:        a.ProjectID,
+		Signature:        a.Signature,
+		BrowserID:        a.BrowserID,
+		ClientID:         a.ClientID,
+		FeePayer:         a.FeePayer,
+		ProgramAddresses: programAddresses,
+		SubmittedAt:      a.SubmittedAt,
+		Outcome: sql.NullString{
+			String: string(aggregates.OutcomeFailed),
+			Valid:  a.Failed,
+		},
+		Error: a.Error,
+	}
+}
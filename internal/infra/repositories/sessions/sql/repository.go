@@ -0,0 +1,17 @@
+package sql
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository is a SQL repository for the sessions.
+type Repository struct {
+	db *sqlx.DB
+}
+
+// New returns a new SQL repository for the sessions.
+func New(db *sqlx.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
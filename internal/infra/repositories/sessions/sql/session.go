@@ -0,0 +1,212 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/sessions/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// sessionsQuery numbers the sessions of every browser, or client when
+	// the browser isn't known, starting a new one after a pause longer than
+	// the session gap. A transaction with a lifecycle landed, whatever the
+	// reconciler knows about it yet, and waited until it was processed.
+	sessionsQuery = `
+WITH attempts AS (
+  SELECT
+    COALESCE(NULLIF(t.browser_id, ''), t.client_id) AS session_key,
+    t.client_id, t.fee_payer, t.submitted_at, t.error,
+    CASE WHEN l.signature IS NOT NULL THEN 'landed' ELSE t.outcome END AS outcome,
+    CASE WHEN l.signature IS NOT NULL THEN
+      GREATEST(EXTRACT(EPOCH FROM l.seen_at - t.submitted_at)::FLOAT * 1000, 0)
+    END AS wait
+  FROM transaction_attempts t
+  LEFT JOIN transaction_lifecycles l
+    ON l.project_id = t.project_id AND l.signature = t.signature
+  WHERE ($1::BIGINT = 0 OR t.project_id = $1::BIGINT)
+    AND $2 = ANY(t.program_addresses)
+    AND t.submitted_at >= $3 AND t.submitted_at < $4
+    AND ($5 = '' OR t.fee_payer = $5)
+    AND ($6 = '' OR t.browser_id = $6)
+),
+marked AS (
+  SELECT *,
+    CASE WHEN submitted_at - LAG(submitted_at) OVER (
+      PARTITION BY session_key ORDER BY submitted_at) > $7::INTERVAL
+    THEN 1 ELSE 0 END AS new_session
+  FROM attempts
+),
+sessions AS (
+  SELECT *,
+    SUM(new_session) OVER (
+      PARTITION BY session_key ORDER BY submitted_at) AS session_number
+  FROM marked
+)
+`
+
+	statsColumns = `
+  COUNT(*) AS attempted,
+  COUNT(*) FILTER (WHERE outcome = 'landed') AS landed,
+  COUNT(*) FILTER (WHERE outcome = 'failed') AS failed,
+  COUNT(*) FILTER (WHERE outcome IN ('dropped', 'expired')) AS dropped,
+  COALESCE(percentile_cont(0.50) WITHIN GROUP (ORDER BY wait), 0) AS median_wait,
+  COALESCE((array_agg(error ORDER BY submitted_at DESC)
+    FILTER (WHERE error <> ''))[1], '') AS last_error`
+
+	// The worst affected come first, those with the most transactions that
+	// didn't land and then the longest waits.
+	worstAffectedOrder = `
+ORDER BY
+  COUNT(*) FILTER (WHERE outcome IN ('failed', 'dropped', 'expired')) DESC,
+  median_wait DESC`
+
+	selectSessionsQuery = sessionsQuery + `
+SELECT
+  session_key AS browser_id,
+  array_agg(DISTINCT client_id) FILTER (WHERE client_id <> '') AS client_ids,
+  array_agg(DISTINCT fee_payer) AS wallets,
+  MIN(submitted_at) AS started_at,
+  MAX(submitted_at) AS ended_at,` + statsColumns + `
+FROM sessions
+GROUP BY session_key, session_number` + worstAffectedOrder + `, started_at DESC
+LIMIT $8;
+`
+
+	selectWalletsQuery = sessionsQuery + `
+SELECT
+  fee_payer AS address,
+  COUNT(DISTINCT session_key || ':' || session_number) AS sessions,` + statsColumns + `
+FROM sessions
+GROUP BY fee_payer` + worstAffectedOrder + `, address
+LIMIT $8;
+`
+)
+
+// SelectSessions selects the sessions matching the filter, the worst
+// affected first.
+func (r *Repository) SelectSessions(ctx context.Context,
+	filter aggregates.Filter) ([]aggregates.Session, error) {
+	args, err := sessionsQueryArgs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("sessionsQueryArgs, err: %w", err)
+	}
+
+	var dbSessions []dbSession
+	if err := r.db.SelectContext(ctx, &dbSessions, selectSessionsQuery,
+		args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	sessions := make([]aggregates.Session, len(dbSessions))
+	for i, dbSession := range dbSessions {
+		sessions[i] = dbSession.toAggregate()
+	}
+
+	return sessions, nil
+}
+
+// SelectWallets selects the fee payer wallets of the transactions matching
+// the filter, the worst affected first.
+func (r *Repository) SelectWallets(ctx context.Context,
+	filter aggregates.Filter) ([]aggregates.Wallet, error) {
+	args, err := sessionsQueryArgs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("sessionsQueryArgs, err: %w", err)
+	}
+
+	var dbWallets []dbWallet
+	if err := r.db.SelectContext(ctx, &dbWallets, selectWalletsQuery,
+		args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	wallets := make([]aggregates.Wallet, len(dbWallets))
+	for i, dbWallet := range dbWallets {
+		wallets[i] = dbWallet.toAggregate()
+	}
+
+	return wallets, nil
+}
+
+func sessionsQueryArgs(ctx context.Context,
+	filter aggregates.Filter) ([]interface{}, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	return []interface{}{
+		projectID,
+		filter.ProgramAddress,
+		filter.Start.UTC(),
+		filter.End.UTC(),
+		filter.Wallet,
+		filter.BrowserID,
+		fmt.Sprintf("%d seconds", int64(aggregates.SessionGap.Seconds())),
+		filter.Limit,
+	}, nil
+}
+
+type dbStats struct {
+	Attempted  int64   `db:"attempted"`
+	Landed     int64   `db:"landed"`
+	Failed     int64   `db:"failed"`
+	Dropped    int64   `db:"dropped"`
+	MedianWait float64 `db:"median_wait"`
+	LastError  string  `db:"last_error"`
+}
+
+func (dbs dbStats) toAggregate() aggregates.Stats {
+	return aggregates.Stats{
+		Attempted:  dbs.Attempted,
+		Landed:     dbs.Landed,
+		Failed:     dbs.Failed,
+		Dropped:    dbs.Dropped,
+		MedianWait: dbs.MedianWait,
+		LastError:  dbs.LastError,
+	}
+}
+
+type dbSession struct {
+	BrowserID string         `db:"browser_id"`
+	ClientIDs pq.StringArray `db:"client_ids"`
+	Wallets   pq.StringArray `db:"wallets"`
+	StartedAt time.Time      `db:"started_at"`
+	EndedAt   time.Time      `db:"ended_at"`
+	dbStats
+}
+
+func (dbs dbSession) toAggregate() aggregates.Session {
+	clientIDs := []string(dbs.ClientIDs)
+	if clientIDs == nil {
+		clientIDs = []string{}
+	}
+
+	return aggregates.Session{
+		BrowserID: dbs.BrowserID,
+		ClientIDs: clientIDs,
+		Wallets:   dbs.Wallets,
+		StartedAt: dbs.StartedAt,
+		EndedAt:   dbs.EndedAt,
+		Stats:     dbs.dbStats.toAggregate(),
+	}
+}
+
+type dbWallet struct {
+	Address  string `db:"address"`
+	Sessions int64  `db:"sessions"`
+	dbStats
+}
+
+func (dbw dbWallet) toAggregate() aggregates.Wallet {
+	return aggregates.Wallet{
+		Address:  dbw.Address,
+		Sessions: dbw.Sessions,
+		Stats:    dbw.dbStats.toAggregate(),
+	}
+}
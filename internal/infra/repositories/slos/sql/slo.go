@@ -0,0 +1,257 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectAllSLOs = `
+SELECT id, project_id, name, program_address, indicator, objective,
+  latency_threshold_ms, window_seconds, enabled, created_at, updated_at
+FROM slos
+WHERE deleted_at IS NULL AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
+ORDER BY id;
+`
+
+	selectEnabledSLOs = `
+SELECT id, project_id, name, program_address, indicator, objective,
+  latency_threshold_ms, window_seconds, enabled, created_at, updated_at
+FROM slos
+WHERE deleted_at IS NULL AND enabled
+  AND ($1::BIGINT = 0 OR project_id = $1::BIGINT)
+ORDER BY id;
+`
+
+	selectSLOByID = `
+SELECT id, project_id, name, program_address, indicator, objective,
+  latency_threshold_ms, window_seconds, enabled, created_at, updated_at
+FROM slos
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::BIGINT = 0 OR project_id = $2::BIGINT);
+`
+
+	insertSLO = `
+INSERT INTO slos
+(project_id, name, program_address, indicator, objective,
+  latency_threshold_ms, window_seconds, enabled, created_at, updated_at)
+VALUES
+(:project_id, :name, :program_address, :indicator, :objective,
+  :latency_threshold_ms, :window_seconds, :enabled, :created_at, :updated_at)
+RETURNING id;
+`
+
+	updateSLO = `
+UPDATE slos
+SET name = :name, program_address = :program_address,
+  indicator = :indicator, objective = :objective,
+  latency_threshold_ms = :latency_threshold_ms,
+  window_seconds = :window_seconds, enabled = :enabled,
+  updated_at = :updated_at
+WHERE id = :id AND project_id = :project_id AND deleted_at IS NULL
+RETURNING created_at;
+`
+
+	deleteSLO = `
+UPDATE slos
+SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+)
+
+// SelectAllSLOs returns every SLO of the tenant in ctx that hasn't been
+// deleted.
+func (r *Repository) SelectAllSLOs(
+	ctx context.Context) ([]aggregates.SLO, error) {
+	return r.selectSLOs(ctx, selectAllSLOs)
+}
+
+// SelectEnabledSLOs returns the SLOs that have to be tracked, the ones of
+// every tenant when ctx acts on every project.
+func (r *Repository) SelectEnabledSLOs(
+	ctx context.Context) ([]aggregates.SLO, error) {
+	return r.selectSLOs(ctx, selectEnabledSLOs)
+}
+
+func (r *Repository) selectSLOs(
+	ctx context.Context, query string) ([]aggregates.SLO, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbSLOs dbSLOs
+	if err := r.db.SelectContext(ctx, &dbSLOs, query,
+		projectID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	slos := make([]aggregates.SLO, len(dbSLOs))
+	for i, dbSLO := range dbSLOs {
+		slos[i] = dbSLO.toAggregate()
+	}
+
+	return slos, nil
+}
+
+// SelectSLOByID returns the SLO with the given ID.
+func (r *Repository) SelectSLOByID(
+	ctx context.Context, id int64) (aggregates.SLO, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbSLO dbSLO
+	if err := r.db.GetContext(ctx, &dbSLO, selectSLOByID,
+		id, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.SLO{}, aggregates.ErrSLONotFound
+		}
+
+		return aggregates.SLO{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbSLO.toAggregate(), nil
+}
+
+// InsertSLO inserts a new SLO, returning it with its ID.
+func (r *Repository) InsertSLO(
+	ctx context.Context, slo aggregates.SLO) (aggregates.SLO, error) {
+	now := time.Now().UTC()
+	slo.CreatedAt = now
+	slo.UpdatedAt = now
+
+	if slo.ProjectID == 0 {
+		slo.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, insertSLO, dbSLOFromAggregate(slo))
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.SLO{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&slo.ID); err != nil {
+		return aggregates.SLO{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return slo, nil
+}
+
+// UpdateSLO updates an existing SLO.
+func (r *Repository) UpdateSLO(
+	ctx context.Context, slo aggregates.SLO) (aggregates.SLO, error) {
+	slo.UpdatedAt = time.Now().UTC()
+
+	if slo.ProjectID == 0 {
+		slo.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, updateSLO, dbSLOFromAggregate(slo))
+	if err != nil {
+		return aggregates.SLO{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return aggregates.SLO{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+		}
+
+		return aggregates.SLO{}, aggregates.ErrSLONotFound
+	}
+
+	if err := rows.Scan(&slo.CreatedAt); err != nil {
+		return aggregates.SLO{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return slo, nil
+}
+
+// DeleteSLO soft deletes an SLO.
+func (r *Repository) DeleteSLO(ctx context.Context, id int64) error {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, deleteSLO, id,
+		time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrSLONotFound
+	}
+
+	return nil
+}
+
+type dbSLO struct {
+	ID                 int64          `db:"id"`
+	ProjectID          int64          `db:"project_id"`
+	Name               string         `db:"name"`
+	ProgramAddress     sql.NullString `db:"program_address"`
+	Indicator          string         `db:"indicator"`
+	Objective          float64        `db:"objective"`
+	LatencyThresholdMS int64          `db:"latency_threshold_ms"`
+	WindowSeconds      int64          `db:"window_seconds"`
+	Enabled            bool           `db:"enabled"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+}
+
+type dbSLOs []dbSLO
+
+func (dbs dbSLO) toAggregate() aggregates.SLO {
+	return aggregates.SLO{
+		ID:               dbs.ID,
+		ProjectID:        dbs.ProjectID,
+		Name:             dbs.Name,
+		ProgramAddress:   dbs.ProgramAddress.String,
+		Indicator:        aggregates.Indicator(dbs.Indicator),
+		Objective:        dbs.Objective,
+		LatencyThreshold: time.Duration(dbs.LatencyThresholdMS) * time.Millisecond,
+		Window:           time.Duration(dbs.WindowSeconds) * time.Second,
+		Enabled:          dbs.Enabled,
+		CreatedAt:        dbs.CreatedAt,
+		UpdatedAt:        dbs.UpdatedAt,
+	}
+}
+
+func dbSLOFromAggregate(slo aggregates.SLO) dbSLO {
+	return dbSLO{
+		ID:        slo.ID,
+		ProjectID: slo.ProjectID,
+		Name:      slo.Name,
+		ProgramAddress: sql.NullString{
+			String: slo.ProgramAddress,
+			Valid:  slo.ProgramAddress != "",
+		},
+		Indicator:          string(slo.Indicator),
+		Objective:          slo.Objective,
+		LatencyThresholdMS: slo.LatencyThreshold.Milliseconds(),
+		WindowSeconds:      int64(slo.Window.Seconds()),
+		Enabled:            slo.Enabled,
+		CreatedAt:          slo.CreatedAt,
+		UpdatedAt:          slo.UpdatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/slos/aggregates"
+)
+
+const (
+	defaultStatusesLimit = 1000
+
+	selectStatuses = `
+SELECT slo_id, total, good, compliance, budget_remaining, burn_rates,
+  computed_at
+FROM slo_statuses
+`
+
+	insertStatus = `
+INSERT INTO slo_statuses
+(slo_id, total, good, compliance, budget_remaining, burn_rates, computed_at)
+VALUES
+(:slo_id, :total, :good, :compliance, :budget_remaining, :burn_rates,
+  :computed_at);
+`
+)
+
+// SelectStatuses returns the recorded statuses of an SLO matching the
+// filter, newest first.
+func (r *Repository) SelectStatuses(ctx context.Context,
+	filter aggregates.StatusesFilter) ([]aggregates.Status, error) {
+	args := []interface{}{filter.SLOID}
+	conditions := []string{"slo_id = $1"}
+
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("computed_at >= $%d", len(args)))
+	}
+
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("computed_at < $%d", len(args)))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultStatusesLimit
+	}
+
+	args = append(args, limit)
+	query := selectStatuses +
+		"WHERE " + strings.Join(conditions, " AND ") + "\n" +
+		fmt.Sprintf("ORDER BY computed_at DESC\nLIMIT $%d;", len(args))
+
+	var dbStatuses dbStatuses
+	if err := r.db.SelectContext(ctx, &dbStatuses, query, args...); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	statuses := make([]aggregates.Status, len(dbStatuses))
+	for i, dbStatus := range dbStatuses {
+		statuses[i] = dbStatus.toAggregate()
+	}
+
+	return statuses, nil
+}
+
+// InsertStatus records the status of an SLO.
+func (r *Repository) InsertStatus(
+	ctx context.Context, status aggregates.Status) error {
+	if _, err := r.db.NamedExecContext(ctx,
+		insertStatus, dbStatusFromAggregate(status)); err != nil {
+		return fmt.Errorf("r.db.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// dbStatus represents a recorded SLO status, the burn rates are stored in
+// the aggregates.BurnRateWindows order.
+type dbStatus struct {
+	SLOID           int64           `db:"slo_id"`
+	Total           int64           `db:"total"`
+	Good            int64           `db:"good"`
+	Compliance      float64         `db:"compliance"`
+	BudgetRemaining float64         `db:"budget_remaining"`
+	BurnRates       pq.Float64Array `db:"burn_rates"`
+	ComputedAt      time.Time       `db:"computed_at"`
+}
+
+type dbStatuses []dbStatus
+
+func (dbs dbStatus) toAggregate() aggregates.Status {
+	burnRates := make([]aggregates.BurnRate, 0, len(aggregates.BurnRateWindows))
+	for i, window := range aggregates.BurnRateWindows {
+		if i >= len(dbs.BurnRates) {
+			break
+		}
+
+		burnRates = append(burnRates, aggregates.BurnRate{
+			Window: window,
+			Rate:   dbs.BurnRates[i],
+		})
+	}
+
+	return aggregates.Status{
+		SLOID:           dbs.SLOID,
+		Total:           dbs.Total,
+		Good:            dbs.Good,
+		Compliance:      dbs.Compliance,
+		BudgetRemaining: dbs.BudgetRemaining,
+		BurnRates:       burnRates,
+		ComputedAt:      dbs.ComputedAt,
+	}
+}
+
+func dbStatusFromAggregate(status aggregates.Status) dbStatus {
+	burnRates := make(pq.Float64Array, len(status.BurnRates))
+	for i, burnRate := range status.BurnRates {
+		burnRates[i] = burnRate.Rate
+	}
+
+	return dbStatus{
+		SLOID:           status.SLOID,
+		Total:           status.Total,
+		Good:            status.Good,
+		Compliance:      status.Compliance,
+		BudgetRemaining: status.BudgetRemaining,
+		BurnRates:       burnRates,
+		ComputedAt:      status.ComputedAt,
+	}
+}
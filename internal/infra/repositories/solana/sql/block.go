@@ -0,0 +1,96 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+)
+
+const (
+	selectBlockHeightByBlockHash = `
+SELECT block_height FROM block WHERE blockhash = $1 AND block_height IS NOT NULL;
+`
+
+	// selectBlockHeightAt walks the blocks back from the latest slot, the
+	// block heights grow with the slots.
+	selectBlockHeightAt = `
+SELECT block_height FROM block
+WHERE updated_on <= $1 AND block_height IS NOT NULL
+ORDER BY slot DESC
+LIMIT 1;
+`
+
+	selectLandedSignatures = `
+SELECT signature FROM encinitas_transactions WHERE signature = ANY($1);
+`
+)
+
+// GetBlockHeightByBlockHash gets the height of the block of a blockhash.
+func (r *Repository) GetBlockHeightByBlockHash(
+	ctx context.Context, blockHash string) (int64, error) {
+	var height int64
+	if err := r.db.GetContext(ctx, &height,
+		selectBlockHeightByBlockHash, blockHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, aggregates.ErrBlockNotFound
+		}
+
+		return 0, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return height, nil
+}
+
+// GetBlockHeight gets the height of the latest block stored at the given
+// time.
+func (r *Repository) GetBlockHeight(
+	ctx context.Context, at time.Time) (int64, error) {
+	var height int64
+	if err := r.db.GetContext(ctx, &height,
+		selectBlockHeightAt, at.UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, aggregates.ErrBlockNotFound
+		}
+
+		return 0, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return height, nil
+}
+
+// SelectLandedSignatures selects which of the signatures, base58 encoded,
+// belong to transactions stored by the geyser plugin. The signatures are
+// stored hex encoded, the way the ingester decodes them.
+func (r *Repository) SelectLandedSignatures(
+	ctx context.Context, signatures []string) (map[string]bool, error) {
+	stored := make(pq.StringArray, len(signatures))
+	for i, signature := range signatures {
+		stored[i] = `\x` + hex.EncodeToString(base58.Decode(signature))
+	}
+
+	var landedSignatures []string
+	if err := r.db.SelectContext(ctx, &landedSignatures,
+		selectLandedSignatures, stored); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	landed := make(map[string]bool, len(landedSignatures))
+	for _, signature := range landedSignatures {
+		bytes, err := hex.DecodeString(signature[min(len(signature), 2):])
+		if err != nil {
+			continue
+		}
+
+		landed[base58.Encode(bytes)] = true
+	}
+
+	return landed, nil
+}
@@ -0,0 +1,267 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	// insertSlotStatusesQuery records the statuses of the slots updated since
+	// the last recorded one, with a minute of overlap as the plugin doesn't
+	// write them in order. A slot seen confirmed or rooted for the first time
+	// also reached the lower commitment levels, which are recorded along.
+	insertSlotStatusesQuery = `
+INSERT INTO slot_statuses (slot, status, seen_at)
+SELECT s.slot, c.status, s.updated_on
+FROM slot s
+CROSS JOIN LATERAL unnest(
+  CASE s.status
+    WHEN 'rooted' THEN ARRAY['processed', 'confirmed', 'finalized']
+    WHEN 'confirmed' THEN ARRAY['processed', 'confirmed']
+    ELSE ARRAY['processed']
+  END
+) AS c(status)
+WHERE s.status IN ('processed', 'confirmed', 'rooted')
+  AND s.updated_on > (
+    SELECT COALESCE(MAX(seen_at), NOW() - INTERVAL '1 hour')
+    FROM slot_statuses
+  ) - INTERVAL '1 minute'
+ON CONFLICT (slot, status) DO NOTHING;
+`
+
+	upsertLifecycleQuery = `
+INSERT INTO transaction_lifecycles
+(project_id, signature, slot, program_addresses, submitted_at, seen_at)
+VALUES
+(:project_id, :signature, :slot, :program_addresses, :submitted_at, :seen_at)
+ON CONFLICT (project_id, signature) DO UPDATE SET
+  submitted_at = COALESCE(transaction_lifecycles.submitted_at, EXCLUDED.submitted_at),
+  seen_at = LEAST(transaction_lifecycles.seen_at, EXCLUDED.seen_at);
+`
+
+	// lifecyclesQuery joins the lifecycles with the statuses of their slots,
+	// a transaction is processed once the geyser plugin notifies it even if
+	// its slot status wasn't recorded yet.
+	lifecyclesQuery = `
+SELECT
+  l.project_id, l.signature, l.slot, l.program_addresses, l.submitted_at,
+  LEAST(l.seen_at, p.seen_at) AS processed_at,
+  c.seen_at AS confirmed_at,
+  f.seen_at AS finalized_at
+FROM transaction_lifecycles l
+LEFT JOIN slot_statuses p ON p.slot = l.slot AND p.status = 'processed'
+LEFT JOIN slot_statuses c ON c.slot = l.slot AND c.status = 'confirmed'
+LEFT JOIN slot_statuses f ON f.slot = l.slot AND f.status = 'finalized'
+`
+
+	selectLifecycleQuery = lifecyclesQuery + `
+WHERE ($1::BIGINT = 0 OR l.project_id = $1::BIGINT) AND l.signature = $2
+ORDER BY l.project_id
+LIMIT 1;
+`
+
+	// selectLifecycleStatsQuery measures the times to confirmed and finalized
+	// from the submission or processing of the transactions, in milliseconds,
+	// the clocks of the agents may be slightly ahead of the geyser plugin so
+	// the negative times are counted as zero. The percentiles skip the
+	// transactions which didn't reach the commitment level yet.
+	selectLifecycleStatsQuery = `
+WITH lifecycles AS (` + lifecyclesQuery + `
+  WHERE ($1::BIGINT = 0 OR l.project_id = $1::BIGINT)
+    AND l.seen_at >= $2 AND l.seen_at < $3
+),
+timed AS (
+  SELECT
+    program_address,
+    CASE WHEN $5 = 'submitted' THEN submitted_at ELSE processed_at END AS from_at,
+    confirmed_at,
+    finalized_at
+  FROM lifecycles
+  CROSS JOIN LATERAL unnest(program_addresses) AS program_address
+  WHERE $4 = '' OR program_address = $4
+),
+measured AS (
+  SELECT
+    program_address,
+    CASE WHEN confirmed_at IS NOT NULL THEN
+      GREATEST(EXTRACT(EPOCH FROM confirmed_at - from_at)::FLOAT * 1000, 0)
+    END AS to_confirmed,
+    CASE WHEN finalized_at IS NOT NULL THEN
+      GREATEST(EXTRACT(EPOCH FROM finalized_at - from_at)::FLOAT * 1000, 0)
+    END AS to_finalized
+  FROM timed
+  WHERE from_at IS NOT NULL
+)
+SELECT
+  program_address,
+  COUNT(*) AS count,
+  COALESCE(percentile_cont(0.50) WITHIN GROUP (ORDER BY to_confirmed), 0) AS confirmed_p50,
+  COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY to_confirmed), 0) AS confirmed_p95,
+  COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY to_confirmed), 0) AS confirmed_p99,
+  COALESCE(percentile_cont(0.50) WITHIN GROUP (ORDER BY to_finalized), 0) AS finalized_p50,
+  COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY to_finalized), 0) AS finalized_p95,
+  COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY to_finalized), 0) AS finalized_p99
+FROM measured
+GROUP BY program_address
+ORDER BY count DESC, program_address;
+`
+)
+
+// InsertSlotStatuses records the commitment levels the slots reached since
+// the last call, returning the amount of statuses recorded.
+func (r *Repository) InsertSlotStatuses(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, insertSlotStatusesQuery)
+	if err != nil {
+		return 0, fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// UpsertLifecycle records the lifecycle of a transaction, keeping the
+// earliest time it was seen and its submission time once known.
+func (r *Repository) UpsertLifecycle(ctx context.Context,
+	lifecycle aggregates.Lifecycle) error {
+	if lifecycle.ProjectID == 0 {
+		lifecycle.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
+	if _, err := sqlx.NamedExecContext(ctx, r.db, upsertLifecycleQuery,
+		dbLifecycleFromAggregate(lifecycle)); err != nil {
+		return fmt.Errorf("sqlx.NamedExecContext, err: %w", err)
+	}
+
+	return nil
+}
+
+// SelectLifecycle selects the lifecycle of a transaction by its signature.
+func (r *Repository) SelectLifecycle(ctx context.Context,
+	signature string) (aggregates.Lifecycle, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.Lifecycle{}, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbLifecycle dbLifecycle
+	if err := r.db.GetContext(ctx, &dbLifecycle, selectLifecycleQuery,
+		projectID, signature); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Lifecycle{}, aggregates.ErrLifecycleNotFound
+		}
+
+		return aggregates.Lifecycle{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbLifecycle.toAggregate(), nil
+}
+
+// SelectLifecycleStats selects the percentiles of the times to confirmed
+// and finalized of the transactions seen within the filter range, per
+// program.
+func (r *Repository) SelectLifecycleStats(ctx context.Context,
+	filter aggregates.LifecycleStatsFilter) ([]aggregates.LifecycleStats, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbLifecycleStats []dbLifecycleStats
+	if err := r.db.SelectContext(ctx, &dbLifecycleStats, selectLifecycleStatsQuery,
+		projectID, filter.Start.UTC(),
+		filter.End.UTC(), filter.ProgramAddress, string(filter.From)); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	stats := make([]aggregates.LifecycleStats, len(dbLifecycleStats))
+	for i, dbStats := range dbLifecycleStats {
+		stats[i] = dbStats.toAggregate()
+	}
+
+	return stats, nil
+}
+
+type dbLifecycle struct {
+	ProjectID        int64          `db:"project_id"`
+	Signature        string         `db:"signature"`
+	Slot             int64          `db:"slot"`
+	ProgramAddresses pq.StringArray `db:"program_addresses"`
+	SubmittedAt      *time.Time     `db:"submitted_at"`
+	SeenAt           time.Time      `db:"seen_at"`
+	ProcessedAt      *time.Time     `db:"processed_at"`
+	ConfirmedAt      *time.Time     `db:"confirmed_at"`
+	FinalizedAt      *time.Time     `db:"finalized_at"`
+}
+
+func (dbl dbLifecycle) toAggregate() aggregates.Lifecycle {
+	return aggregates.Lifecycle{
+		ProjectID:        dbl.ProjectID,
+		Signature:        dbl.Signature,
+		Slot:             dbl.Slot,
+		ProgramAddresses: dbl.ProgramAddresses,
+		SubmittedAt:      dbl.SubmittedAt,
+		ProcessedAt:      dbl.ProcessedAt,
+		ConfirmedAt:      dbl.ConfirmedAt,
+		FinalizedAt:      dbl.FinalizedAt,
+	}
+}
+
+// dbLifecycleFromAggregate returns the lifecycle to upsert, the time it was
+// processed is the time the geyser plugin notified it.
+func dbLifecycleFromAggregate(l aggregates.Lifecycle) dbLifecycle {
+	programAddresses := pq.StringArray(l.ProgramAddresses)
+	if programAddresses == nil {
+		programAddresses = pq.StringArray{}
+	}
+
+	var seenAt time.Time
+	if l.ProcessedAt != nil {
+		seenAt = *l.ProcessedAt
+	}
+
+	return dbLifecycle{
+		ProjectID:        l.ProjectID,
+		Signature:        l.Signature,
+		Slot:             l.Slot,
+		ProgramAddresses: programAddresses,
+		SubmittedAt:      l.SubmittedAt,
+		SeenAt:           seenAt,
+	}
+}
+
+type dbLifecycleStats struct {
+	ProgramAddress string  `db:"program_address"`
+	Count          int64   `db:"count"`
+	ConfirmedP50   float64 `db:"confirmed_p50"`
+	ConfirmedP95   float64 `db:"confirmed_p95"`
+	ConfirmedP99   float64 `db:"confirmed_p99"`
+	FinalizedP50   float64 `db:"finalized_p50"`
+	FinalizedP95   float64 `db:"finalized_p95"`
+	FinalizedP99   float64 `db:"finalized_p99"`
+}
+
+func (dbs dbLifecycleStats) toAggregate() aggregates.LifecycleStats {
+	return aggregates.LifecycleStats{
+		ProgramAddress: dbs.ProgramAddress,
+		Count:          dbs.Count,
+		ConfirmedP50:   dbs.ConfirmedP50,
+		ConfirmedP95:   dbs.ConfirmedP95,
+		ConfirmedP99:   dbs.ConfirmedP99,
+		FinalizedP50:   dbs.FinalizedP50,
+		FinalizedP95:   dbs.FinalizedP95,
+		FinalizedP99:   dbs.FinalizedP99,
+	}
+}
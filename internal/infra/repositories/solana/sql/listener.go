@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// transactionsChannel is the channel the encinitas_transactions inserts
+	// are notified on, see the notify_encinitas_transactions trigger.
+	transactionsChannel = "encinitas_transactions"
+
+	// listenerPingInterval is how often an idle listener connection is
+	// checked, a connection silently dropped would otherwise go unnoticed
+	// until the next notification never arrives.
+	listenerPingInterval = 90 * time.Second
+)
+
+// Listener listens to the Postgres notifications on its own connection,
+// which is re-established between minReconnectInterval and
+// maxReconnectInterval when lost.
+type Listener struct {
+	url                  string
+	minReconnectInterval time.Duration
+	maxReconnectInterval time.Duration
+}
+
+// NewListener creates a new Listener connecting to the url.
+func NewListener(url string,
+	minReconnectInterval, maxReconnectInterval time.Duration) *Listener {
+	return &Listener{
+		url:                  url,
+		minReconnectInterval: minReconnectInterval,
+		maxReconnectInterval: maxReconnectInterval,
+	}
+}
+
+// ListenTransactions listens to the encinitas_transactions inserts until the
+// context is done. The returned channel receives once per notification,
+// folding the ones not received yet, and once after every reconnection as
+// the inserts notified while disconnected are lost. When the channel can't
+// be listened to it never receives, so the callers must keep polling.
+func (l *Listener) ListenTransactions(ctx context.Context) <-chan struct{} {
+	notifications := make(chan struct{}, 1)
+
+	listener := pq.NewListener(l.url, l.minReconnectInterval, l.maxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventConnected:
+				slog.Info("transactions listener connected")
+			case pq.ListenerEventDisconnected:
+				slog.Warn("transactions listener disconnected", slog.Any("error", err))
+			case pq.ListenerEventReconnected:
+				slog.Info("transactions listener reconnected")
+			case pq.ListenerEventConnectionAttemptFailed:
+				slog.Error("transactions listener connection attempt failed",
+					slog.Any("error", err))
+			}
+		})
+
+	go func() {
+		<-ctx.Done()
+
+		if err := listener.Close(); err != nil {
+			slog.Error("listener.Close", slog.Any("error", err))
+		}
+	}()
+
+	go func() {
+		// Listen blocks until the connection is established, which may take
+		// forever, and returns when the listener is closed.
+		if err := listener.Listen(transactionsChannel); err != nil {
+			if ctx.Err() == nil {
+				slog.Error("listener.Listen", slog.Any("error", err))
+			}
+			return
+		}
+
+		ticker := time.NewTicker(listenerPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			// A nil notification is received after a reconnection.
+			case _, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+
+				select {
+				case notifications <- struct{}{}:
+				default:
+				}
+
+			case <-ticker.C:
+				if err := listener.Ping(); err != nil {
+					slog.Warn("listener.Ping", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+
+	return notifications
+}
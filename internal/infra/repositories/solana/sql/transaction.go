@@ -7,34 +7,53 @@ import (
 	"time"
 
 	"github.com/jcleira/encinitas-collector-go/internal/app/solana/aggregates"
+	tenantsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
-	selectTransactionsByProcessedAt = `
-SELECT * FROM encinitas_transactions WHERE processed_at is NULL LIMIT 1000;
+	// selectClaimableTransactions locks the oldest unprocessed transactions
+	// not claimed or whose claim timed out, skipping the ones being claimed
+	// by other replicas.
+	selectClaimableTransactions = `
+SELECT
+  slot, signature, is_vote, message_type, legacy_message, v0_loaded_message,
+  signatures, message_hash, meta, write_version, updated_on, txn_index,
+  processed_at
+FROM encinitas_transactions
+WHERE processed_at IS NULL
+  AND (claimed_at IS NULL OR claimed_at < NOW() - $1::INTERVAL)
+ORDER BY slot, txn_index
+LIMIT 1000
+FOR UPDATE SKIP LOCKED;
+`
+
+	updateTransactionsClaimedAt = `
+UPDATE encinitas_transactions SET claimed_at = NOW() WHERE signature = ANY($1);
 `
 	selectBlockTimeByBlockHash = `
 SELECT updated_on FROM block WHERE blockhash=$1;
 `
 
-	updateTransactionProcessedAt = `
+	updateTransactionsProcessedAt = `
 UPDATE encinitas_transactions
-SET processed_at = :processed_at
-WHERE signature = :signature;
+SET processed_at = $1
+WHERE signature = ANY($2);
 `
 
 	insertTransactionDetailQuery = `
 INSERT INTO encinitas_transaction_details
-(program_address, updated_on, rpc_time, solana_time, total_time)
+(project_id, program_address, updated_on, rpc_time, solana_time, total_time)
 VALUES
-(:program_address, :updated_on, :rpc_time, :solana_time, :total_time);
+(:project_id, :program_address, :updated_on, :rpc_time, :solana_time, :total_time);
 `
 
 	getTransactionDetailAggregated = `
 WITH total_time AS (
   SELECT program_address, SUM(total_time) as total_time
   FROM encinitas_transaction_details
+  WHERE $1::BIGINT = 0 OR project_id = $1::BIGINT
   GROUP BY program_address
 ),
 total_sum AS (
@@ -64,30 +83,57 @@ func (r *Repository) GetBlockTimeByBlockHash(
 	return updatedOn, nil
 }
 
-func (r *Repository) SelectTransactionsByProcessedAt(
-	ctx context.Context) ([]aggregates.Transaction, error) {
+// ClaimTransactions claims the oldest unprocessed transactions, in slot
+// order, for the visibility timeout. The transactions claimed by a replica
+// aren't claimed by any other until the timeout, so the transactions of a
+// replica that crashed before processing them are claimed again after it.
+func (r *Repository) ClaimTransactions(ctx context.Context,
+	visibilityTimeout time.Duration) ([]aggregates.Transaction, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTxx, err: %w", err)
+	}
+	defer tx.Rollback()
+
 	var dbTransactions dbTransactions
-	if err := r.db.SelectContext(ctx,
-		&dbTransactions, selectTransactionsByProcessedAt); err != nil {
-		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	if err := tx.SelectContext(ctx, &dbTransactions, selectClaimableTransactions,
+		fmt.Sprintf("%d milliseconds", visibilityTimeout.Milliseconds())); err != nil {
+		return nil, fmt.Errorf("tx.SelectContext, err: %w", err)
+	}
+
+	if len(dbTransactions) == 0 {
+		return []aggregates.Transaction{}, nil
 	}
 
+	signatures := make(pq.StringArray, len(dbTransactions))
 	transactions := make([]aggregates.Transaction, len(dbTransactions))
 	for i, dbTransaction := range dbTransactions {
+		signatures[i] = dbTransaction.Signature
 		transactions[i] = dbTransaction.toAggregate()
 	}
 
+	if _, err := tx.ExecContext(ctx,
+		updateTransactionsClaimedAt, signatures); err != nil {
+		return nil, fmt.Errorf("tx.ExecContext, err: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("tx.Commit, err: %w", err)
+	}
+
 	return transactions, nil
 }
 
-func (r *Repository) UpdateTransactionProcessedAt(
-	ctx context.Context, signature string, processedAt time.Time) error {
-	if _, err := sqlx.NamedExec(r.db, updateTransactionProcessedAt,
-		map[string]interface{}{
-			"processed_at": processedAt,
-			"signature":    signature,
-		}); err != nil {
-		return fmt.Errorf("sqlx.NamedExec, err: %w", err)
+// UpdateTransactionsProcessedAt marks the transactions as processed at once.
+func (r *Repository) UpdateTransactionsProcessedAt(ctx context.Context,
+	signatures []string, processedAt time.Time) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, updateTransactionsProcessedAt,
+		processedAt, pq.StringArray(signatures)); err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
 	}
 
 	return nil
@@ -96,6 +142,10 @@ func (r *Repository) UpdateTransactionProcessedAt(
 // InsertTransactionDetail inserts a new transaction detail into the database.
 func (r *Repository) InsertTransactionDetail(ctx context.Context,
 	detail aggregates.TransactionDetail) error {
+	if detail.ProjectID == 0 {
+		detail.ProjectID = tenantsAggregates.ProjectIDOrDefault(ctx)
+	}
+
 	dbTransactionDetail := dbTransactionDetailFromAggregate(detail)
 
 	if _, err := sqlx.NamedExec(r.db, insertTransactionDetailQuery,
@@ -108,9 +158,15 @@ func (r *Repository) InsertTransactionDetail(ctx context.Context,
 
 func (r *Repository) GetTransactionDetailAggregated(
 	ctx context.Context) ([]aggregates.TransactionDetailAggregated, error) {
+	projectID, err := tenantsAggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenantsAggregates.ProjectIDFromContext, err: %w", err)
+	}
+
 	var dbTransactionDetailsAggregated dbTransactionDetailsAggregated
-	if err := r.db.SelectContext(ctx,
-		&dbTransactionDetailsAggregated, getTransactionDetailAggregated); err != nil {
+	if err := r.db.SelectContext(ctx, &dbTransactionDetailsAggregated,
+		getTransactionDetailAggregated,
+		projectID); err != nil {
 		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
 	}
 
@@ -182,6 +238,7 @@ func dbTransactionFromAggregate(e aggregates.Transaction) dbTransaction {
 }
 
 type dbTransactionDetail struct {
+	ProjectID      int64     `db:"project_id"`
 	ProgramAddress string    `db:"program_address"`
 	UpdatedOn      time.Time `db:"updated_on"`
 	RPCTime        int64     `db:"rpc_time"`
@@ -193,6 +250,7 @@ type dbTransactionDetails []dbTransactionDetail
 
 func (dbe dbTransactionDetail) toAggregate() aggregates.TransactionDetail {
 	return aggregates.TransactionDetail{
+		ProjectID:      dbe.ProjectID,
 		ProgramAddress: dbe.ProgramAddress,
 		UpdatedOn:      dbe.UpdatedOn,
 		RPCTime:        dbe.RPCTime,
@@ -203,6 +261,7 @@ func (dbe dbTransactionDetail) toAggregate() aggregates.TransactionDetail {
 
 func dbTransactionDetailFromAggregate(e aggregates.TransactionDetail) dbTransactionDetail {
 	return dbTransactionDetail{
+		ProjectID:      e.ProjectID,
 		ProgramAddress: e.ProgramAddress,
 		UpdatedOn:      e.UpdatedOn,
 		RPCTime:        e.RPCTime,
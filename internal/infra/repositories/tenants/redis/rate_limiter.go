@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// takeTokens refills the token bucket of KEYS[1] with the time elapsed since
+// its last update and takes the requested tokens from it, at most the burst
+// so the requests larger than it are allowed once the bucket is full. It
+// returns whether the tokens were taken and, when they weren't, the
+// milliseconds until there are enough of them.
+//
+// ARGV: rate (tokens per second), burst, now (unix milliseconds), tokens.
+var takeTokens = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local requested = math.min(burst, tonumber(ARGV[4]))
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1]) or burst
+local updatedAt = tonumber(bucket[2]) or now
+
+local elapsed = math.max(0, now - updatedAt) / 1000
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+local retryAfter = 0
+if tokens >= requested then
+  tokens = tokens - requested
+  allowed = 1
+else
+  retryAfter = math.ceil((requested - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, retryAfter}
+`)
+
+// Take takes the given tokens from the bucket of an API key, refilled at
+// rate tokens per second up to burst tokens.
+func (r *Repository) Take(ctx context.Context, apiKeyID int64,
+	rate float64, burst, tokens int64) (aggregates.RateLimitDecision, error) {
+	result, err := takeTokens.Run(ctx, r.client,
+		[]string{fmt.Sprintf("api_keys:rate_limit:%d", apiKeyID)},
+		rate, burst, time.Now().UnixMilli(), tokens,
+	).Int64Slice()
+	if err != nil {
+		return aggregates.RateLimitDecision{}, fmt.Errorf("takeTokens.Run, err: %w", err)
+	}
+
+	if len(result) != 2 {
+		return aggregates.RateLimitDecision{}, fmt.Errorf(
+			"takeTokens.Run, unexpected result: %v", result)
+	}
+
+	return aggregates.RateLimitDecision{
+		Allowed:    result[0] == 1,
+		RetryAfter: time.Duration(result[1]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,18 @@
+package redis
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+// Repository is a redis repository for the API keys rate limits and usage
+// counters, shared by every collector instance.
+type Repository struct {
+	client *redis.Client
+}
+
+// New returns a new redis repository for the API keys.
+func New(client *redis.Client) *Repository {
+	return &Repository{
+		client: client,
+	}
+}
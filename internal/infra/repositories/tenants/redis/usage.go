@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+// usageTTL is how long the daily usage counters of the API keys are kept.
+const usageTTL = 32 * 24 * time.Hour
+
+// IncrementUsage increments the counter of the outcome in the current day
+// usage of an API key by the given events.
+func (r *Repository) IncrementUsage(ctx context.Context,
+	apiKeyID int64, outcome aggregates.APIKeyOutcome, events int64) error {
+	key := usageKey(apiKeyID, time.Now().UTC())
+
+	pipe := r.client.Pipeline()
+	pipe.HIncrBy(ctx, key, string(outcome), events)
+	pipe.Expire(ctx, key, usageTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("pipe.Exec, err: %w", err)
+	}
+
+	return nil
+}
+
+// SelectUsage returns the usage of an API key in the given days.
+func (r *Repository) SelectUsage(ctx context.Context,
+	apiKeyID int64, days []time.Time) ([]aggregates.APIKeyUsage, error) {
+	pipe := r.client.Pipeline()
+
+	cmds := make([]*redis.MapStringStringCmd, len(days))
+	for i, day := range days {
+		cmds[i] = pipe.HGetAll(ctx, usageKey(apiKeyID, day))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("pipe.Exec, err: %w", err)
+	}
+
+	usage := make([]aggregates.APIKeyUsage, len(days))
+	for i, cmd := range cmds {
+		counters := cmd.Val()
+
+		usage[i] = aggregates.APIKeyUsage{
+			Day:              days[i],
+			Accepted:         counter(counters, aggregates.APIKeyOutcomeAccepted),
+			RateLimited:      counter(counters, aggregates.APIKeyOutcomeRateLimited),
+			OriginNotAllowed: counter(counters, aggregates.APIKeyOutcomeOriginNotAllowed),
+		}
+	}
+
+	return usage, nil
+}
+
+func usageKey(apiKeyID int64, day time.Time) string {
+	return fmt.Sprintf("api_keys:usage:%d:%s", apiKeyID, day.Format(time.DateOnly))
+}
+
+func counter(counters map[string]string, outcome aggregates.APIKeyOutcome) int64 {
+	value, _ := strconv.ParseInt(counters[string(outcome)], 10, 64)
+	return value
+}
@@ -0,0 +1,273 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectAPIKeys = `
+SELECT k.id, p.organization_id, k.project_id, k.name, k.hint, k.key_hash,
+  k.allowed_origins, k.rate_limit, k.burst, k.expires_at, k.revoked_at,
+  k.created_at, k.updated_at
+FROM api_keys k
+JOIN projects p ON p.id = k.project_id
+WHERE $1::BIGINT = 0 OR k.project_id = $1::BIGINT
+ORDER BY k.id;
+`
+
+	selectAPIKeyByID = `
+SELECT k.id, p.organization_id, k.project_id, k.name, k.hint, k.key_hash,
+  k.allowed_origins, k.rate_limit, k.burst, k.expires_at, k.revoked_at,
+  k.created_at, k.updated_at
+FROM api_keys k
+JOIN projects p ON p.id = k.project_id
+WHERE k.id = $1 AND ($2::BIGINT = 0 OR k.project_id = $2::BIGINT);
+`
+
+	selectAPIKeyByHash = `
+SELECT k.id, p.organization_id, k.project_id, k.name, k.hint, k.key_hash,
+  k.allowed_origins, k.rate_limit, k.burst, k.expires_at, k.revoked_at,
+  k.created_at, k.updated_at
+FROM api_keys k
+JOIN projects p ON p.id = k.project_id
+WHERE k.key_hash = $1;
+`
+
+	insertAPIKey = `
+INSERT INTO api_keys
+(project_id, name, hint, key_hash, allowed_origins, rate_limit, burst,
+  created_at, updated_at)
+VALUES
+(:project_id, :name, :hint, :key_hash, :allowed_origins, :rate_limit, :burst,
+  :created_at, :updated_at)
+RETURNING id;
+`
+
+	revokeAPIKey = `
+UPDATE api_keys
+SET revoked_at = $2, updated_at = $2
+WHERE id = $1 AND revoked_at IS NULL
+  AND ($3::BIGINT = 0 OR project_id = $3::BIGINT);
+`
+
+	// rotateAPIKey expires the old key and inserts the new one in a single
+	// statement, so both keys work during the grace period.
+	rotateAPIKey = `
+WITH rotated AS (
+  UPDATE api_keys
+  SET expires_at = LEAST(COALESCE(expires_at, $2), $2), updated_at = $3
+  WHERE id = $1 AND revoked_at IS NULL
+  RETURNING project_id
+)
+INSERT INTO api_keys
+(project_id, name, hint, key_hash, allowed_origins, rate_limit, burst,
+  created_at, updated_at)
+SELECT project_id, $4, $5, $6, $7, $8, $9, $3, $3
+FROM rotated
+RETURNING id;
+`
+)
+
+// SelectAPIKeys returns the API keys of the tenant in ctx.
+func (r *Repository) SelectAPIKeys(
+	ctx context.Context) ([]aggregates.APIKey, error) {
+	projectID, err := aggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	var dbAPIKeys []dbAPIKey
+	if err := r.db.SelectContext(ctx, &dbAPIKeys, selectAPIKeys,
+		projectID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	apiKeys := make([]aggregates.APIKey, len(dbAPIKeys))
+	for i, dbAPIKey := range dbAPIKeys {
+		apiKeys[i] = dbAPIKey.toAggregate()
+	}
+
+	return apiKeys, nil
+}
+
+// SelectAPIKeyByID returns the API key with the given ID.
+func (r *Repository) SelectAPIKeyByID(
+	ctx context.Context, id int64) (aggregates.APIKey, error) {
+	projectID, err := aggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return aggregates.APIKey{}, fmt.Errorf("aggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	return r.selectAPIKey(ctx, selectAPIKeyByID,
+		id, projectID)
+}
+
+// SelectAPIKeyByHash returns the API key with the given hash, whatever its
+// tenant is, as it's how the tenant of the agents events is resolved.
+func (r *Repository) SelectAPIKeyByHash(
+	ctx context.Context, keyHash string) (aggregates.APIKey, error) {
+	return r.selectAPIKey(ctx, selectAPIKeyByHash, keyHash)
+}
+
+func (r *Repository) selectAPIKey(ctx context.Context,
+	query string, args ...interface{}) (aggregates.APIKey, error) {
+	var dbAPIKey dbAPIKey
+	if err := r.db.GetContext(ctx, &dbAPIKey, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.APIKey{}, aggregates.ErrAPIKeyNotFound
+		}
+
+		return aggregates.APIKey{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbAPIKey.toAggregate(), nil
+}
+
+// InsertAPIKey inserts a new API key, returning it with its ID.
+func (r *Repository) InsertAPIKey(ctx context.Context,
+	apiKey aggregates.APIKey) (aggregates.APIKey, error) {
+	now := time.Now().UTC()
+	apiKey.CreatedAt = now
+	apiKey.UpdatedAt = now
+
+	rows, err := r.db.NamedQueryContext(ctx,
+		insertAPIKey, dbAPIKeyFromAggregate(apiKey))
+	if err != nil {
+		return aggregates.APIKey{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.APIKey{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&apiKey.ID); err != nil {
+		return aggregates.APIKey{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey revokes the API key with the given ID.
+func (r *Repository) RevokeAPIKey(ctx context.Context, id int64) error {
+	projectID, err := aggregates.ProjectIDFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("aggregates.ProjectIDFromContext, err: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, revokeAPIKey,
+		id, time.Now().UTC(), projectID)
+	if err != nil {
+		return fmt.Errorf("r.db.ExecContext, err: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected, err: %w", err)
+	}
+
+	if affected == 0 {
+		return aggregates.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// RotateAPIKey expires the API key with the given ID at expiresAt and
+// inserts its replacement, returning it with its ID. The old API key must
+// have been read within the tenant in ctx.
+func (r *Repository) RotateAPIKey(ctx context.Context, id int64,
+	expiresAt time.Time, apiKey aggregates.APIKey) (aggregates.APIKey, error) {
+	now := time.Now().UTC()
+	apiKey.CreatedAt = now
+	apiKey.UpdatedAt = now
+
+	dbAPIKey := dbAPIKeyFromAggregate(apiKey)
+
+	if err := r.db.QueryRowxContext(ctx, rotateAPIKey,
+		id, expiresAt, now,
+		dbAPIKey.Name, dbAPIKey.Hint, dbAPIKey.KeyHash, dbAPIKey.AllowedOrigins,
+		dbAPIKey.RateLimit, dbAPIKey.Burst,
+	).Scan(&apiKey.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.APIKey{}, aggregates.ErrAPIKeyNotFound
+		}
+
+		return aggregates.APIKey{}, fmt.Errorf("r.db.QueryRowxContext, err: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+type dbAPIKey struct {
+	ID             int64          `db:"id"`
+	OrganizationID int64          `db:"organization_id"`
+	ProjectID      int64          `db:"project_id"`
+	Name           string         `db:"name"`
+	Hint           string         `db:"hint"`
+	KeyHash        string         `db:"key_hash"`
+	AllowedOrigins pq.StringArray `db:"allowed_origins"`
+	RateLimit      float64        `db:"rate_limit"`
+	Burst          int64          `db:"burst"`
+	ExpiresAt      sql.NullTime   `db:"expires_at"`
+	RevokedAt      sql.NullTime   `db:"revoked_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+func (dbk dbAPIKey) toAggregate() aggregates.APIKey {
+	apiKey := aggregates.APIKey{
+		ID:             dbk.ID,
+		OrganizationID: dbk.OrganizationID,
+		ProjectID:      dbk.ProjectID,
+		Name:           dbk.Name,
+		Hint:           dbk.Hint,
+		KeyHash:        dbk.KeyHash,
+		AllowedOrigins: []string(dbk.AllowedOrigins),
+		RateLimit:      dbk.RateLimit,
+		Burst:          dbk.Burst,
+		CreatedAt:      dbk.CreatedAt,
+		UpdatedAt:      dbk.UpdatedAt,
+	}
+
+	if dbk.ExpiresAt.Valid {
+		expiresAt := dbk.ExpiresAt.Time
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if dbk.RevokedAt.Valid {
+		revokedAt := dbk.RevokedAt.Time
+		apiKey.RevokedAt = &revokedAt
+	}
+
+	return apiKey
+}
+
+func dbAPIKeyFromAggregate(apiKey aggregates.APIKey) dbAPIKey {
+	allowedOrigins := apiKey.AllowedOrigins
+	if allowedOrigins == nil {
+		allowedOrigins = []string{}
+	}
+
+	return dbAPIKey{
+		ID:             apiKey.ID,
+		OrganizationID: apiKey.OrganizationID,
+		ProjectID:      apiKey.ProjectID,
+		Name:           apiKey.Name,
+		Hint:           apiKey.Hint,
+		KeyHash:        apiKey.KeyHash,
+		AllowedOrigins: pq.StringArray(allowedOrigins),
+		RateLimit:      apiKey.RateLimit,
+		Burst:          apiKey.Burst,
+		CreatedAt:      apiKey.CreatedAt,
+		UpdatedAt:      apiKey.UpdatedAt,
+	}
+}
@@ -0,0 +1,130 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectAllOrganizations = `
+SELECT id, name, slug, created_at, updated_at
+FROM organizations
+ORDER BY id;
+`
+
+	selectOrganizationByID = `
+SELECT id, name, slug, created_at, updated_at
+FROM organizations
+WHERE id = $1;
+`
+
+	insertOrganization = `
+INSERT INTO organizations
+(name, slug, created_at, updated_at)
+VALUES
+(:name, :slug, :created_at, :updated_at)
+RETURNING id;
+`
+
+	// uniqueViolation is the Postgres error code of unique constraints.
+	uniqueViolation = "23505"
+)
+
+// SelectAllOrganizations returns every organization.
+func (r *Repository) SelectAllOrganizations(
+	ctx context.Context) ([]aggregates.Organization, error) {
+	var dbOrganizations []dbOrganization
+	if err := r.db.SelectContext(ctx,
+		&dbOrganizations, selectAllOrganizations); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	organizations := make([]aggregates.Organization, len(dbOrganizations))
+	for i, dbOrganization := range dbOrganizations {
+		organizations[i] = dbOrganization.toAggregate()
+	}
+
+	return organizations, nil
+}
+
+// SelectOrganizationByID returns the organization with the given ID.
+func (r *Repository) SelectOrganizationByID(
+	ctx context.Context, id int64) (aggregates.Organization, error) {
+	var dbOrganization dbOrganization
+	if err := r.db.GetContext(ctx,
+		&dbOrganization, selectOrganizationByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Organization{}, aggregates.ErrOrganizationNotFound
+		}
+
+		return aggregates.Organization{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbOrganization.toAggregate(), nil
+}
+
+// InsertOrganization inserts a new organization, returning it with its ID.
+func (r *Repository) InsertOrganization(ctx context.Context,
+	organization aggregates.Organization) (aggregates.Organization, error) {
+	now := time.Now().UTC()
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+
+	rows, err := r.db.NamedQueryContext(ctx,
+		insertOrganization, dbOrganizationFromAggregate(organization))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return aggregates.Organization{}, aggregates.ErrOrganizationAlreadyExists
+		}
+
+		return aggregates.Organization{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Organization{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&organization.ID); err != nil {
+		return aggregates.Organization{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return organization, nil
+}
+
+type dbOrganization struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Slug      string    `db:"slug"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (dbo dbOrganization) toAggregate() aggregates.Organization {
+	return aggregates.Organization{
+		ID:        dbo.ID,
+		Name:      dbo.Name,
+		Slug:      dbo.Slug,
+		CreatedAt: dbo.CreatedAt,
+		UpdatedAt: dbo.UpdatedAt,
+	}
+}
+
+func dbOrganizationFromAggregate(
+	organization aggregates.Organization) dbOrganization {
+	return dbOrganization{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		CreatedAt: organization.CreatedAt,
+		UpdatedAt: organization.UpdatedAt,
+	}
+}
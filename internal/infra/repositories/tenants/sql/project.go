@@ -0,0 +1,153 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/jcleira/encinitas-collector-go/internal/app/tenants/aggregates"
+)
+
+const (
+	selectProjectsByOrganizationID = `
+SELECT id, organization_id, name, slug, token_hash, created_at, updated_at
+FROM projects
+WHERE organization_id = $1
+ORDER BY id;
+`
+
+	selectProjectByID = `
+SELECT id, organization_id, name, slug, token_hash, created_at, updated_at
+FROM projects
+WHERE id = $1;
+`
+
+	selectProjectByTokenHash = `
+SELECT id, organization_id, name, slug, token_hash, created_at, updated_at
+FROM projects
+WHERE token_hash = $1;
+`
+
+	insertProject = `
+INSERT INTO projects
+(organization_id, name, slug, token_hash, created_at, updated_at)
+VALUES
+(:organization_id, :name, :slug, :token_hash, :created_at, :updated_at)
+RETURNING id;
+`
+)
+
+// SelectProjectsByOrganizationID returns the projects of an organization.
+func (r *Repository) SelectProjectsByOrganizationID(
+	ctx context.Context, organizationID int64) ([]aggregates.Project, error) {
+	var dbProjects []dbProject
+	if err := r.db.SelectContext(ctx, &dbProjects,
+		selectProjectsByOrganizationID, organizationID); err != nil {
+		return nil, fmt.Errorf("r.db.SelectContext, err: %w", err)
+	}
+
+	projects := make([]aggregates.Project, len(dbProjects))
+	for i, dbProject := range dbProjects {
+		projects[i] = dbProject.toAggregate()
+	}
+
+	return projects, nil
+}
+
+// SelectProjectByID returns the project with the given ID.
+func (r *Repository) SelectProjectByID(
+	ctx context.Context, id int64) (aggregates.Project, error) {
+	return r.selectProject(ctx, selectProjectByID, id)
+}
+
+// SelectProjectByTokenHash returns the project whose token has the given
+// hash.
+func (r *Repository) SelectProjectByTokenHash(
+	ctx context.Context, tokenHash string) (aggregates.Project, error) {
+	return r.selectProject(ctx, selectProjectByTokenHash, tokenHash)
+}
+
+func (r *Repository) selectProject(ctx context.Context,
+	query string, arg interface{}) (aggregates.Project, error) {
+	var dbProject dbProject
+	if err := r.db.GetContext(ctx, &dbProject, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return aggregates.Project{}, aggregates.ErrProjectNotFound
+		}
+
+		return aggregates.Project{}, fmt.Errorf("r.db.GetContext, err: %w", err)
+	}
+
+	return dbProject.toAggregate(), nil
+}
+
+// InsertProject inserts a new project, returning it with its ID.
+func (r *Repository) InsertProject(ctx context.Context,
+	project aggregates.Project) (aggregates.Project, error) {
+	now := time.Now().UTC()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	rows, err := r.db.NamedQueryContext(ctx,
+		insertProject, dbProjectFromAggregate(project))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return aggregates.Project{}, aggregates.ErrProjectAlreadyExists
+		}
+
+		return aggregates.Project{}, fmt.Errorf("r.db.NamedQueryContext, err: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return aggregates.Project{}, fmt.Errorf("rows.Next, err: %w", rows.Err())
+	}
+
+	if err := rows.Scan(&project.ID); err != nil {
+		return aggregates.Project{}, fmt.Errorf("rows.Scan, err: %w", err)
+	}
+
+	return project, nil
+}
+
+type dbProject struct {
+	ID             int64          `db:"id"`
+	OrganizationID int64          `db:"organization_id"`
+	Name           string         `db:"name"`
+	Slug           string         `db:"slug"`
+	TokenHash      sql.NullString `db:"token_hash"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+func (dbp dbProject) toAggregate() aggregates.Project {
+	return aggregates.Project{
+		ID:             dbp.ID,
+		OrganizationID: dbp.OrganizationID,
+		Name:           dbp.Name,
+		Slug:           dbp.Slug,
+		TokenHash:      dbp.TokenHash.String,
+		CreatedAt:      dbp.CreatedAt,
+		UpdatedAt:      dbp.UpdatedAt,
+	}
+}
+
+func dbProjectFromAggregate(project aggregates.Project) dbProject {
+	return dbProject{
+		ID:             project.ID,
+		OrganizationID: project.OrganizationID,
+		Name:           project.Name,
+		Slug:           project.Slug,
+		TokenHash: sql.NullString{
+			String: project.TokenHash,
+			Valid:  project.TokenHash != "",
+		},
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+}
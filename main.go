@@ -21,18 +21,53 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/jcleira/encinitas-collector-go/config"
+	agentAggregates "github.com/jcleira/encinitas-collector-go/internal/app/agent/aggregates"
 	agentServices "github.com/jcleira/encinitas-collector-go/internal/app/agent/services"
+	alertsServices "github.com/jcleira/encinitas-collector-go/internal/app/alerts/services"
+	anomaliesServices "github.com/jcleira/encinitas-collector-go/internal/app/anomalies/services"
+	authAggregates "github.com/jcleira/encinitas-collector-go/internal/app/auth/aggregates"
+	authServices "github.com/jcleira/encinitas-collector-go/internal/app/auth/services"
+	digestsAggregates "github.com/jcleira/encinitas-collector-go/internal/app/digests/aggregates"
+	digestsServices "github.com/jcleira/encinitas-collector-go/internal/app/digests/services"
+	failuresServices "github.com/jcleira/encinitas-collector-go/internal/app/failures/services"
 	managerServices "github.com/jcleira/encinitas-collector-go/internal/app/manager/services"
 	metricsServices "github.com/jcleira/encinitas-collector-go/internal/app/metrics/services"
+	notificationsServices "github.com/jcleira/encinitas-collector-go/internal/app/notifications/services"
+	sessionsServices "github.com/jcleira/encinitas-collector-go/internal/app/sessions/services"
+	slosServices "github.com/jcleira/encinitas-collector-go/internal/app/slos/services"
 	solanaServices "github.com/jcleira/encinitas-collector-go/internal/app/solana/services"
+	tenantsServices "github.com/jcleira/encinitas-collector-go/internal/app/tenants/services"
 	agentHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/agent/handlers"
+	alertsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/alerts/handlers"
+	anomaliesHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/anomalies/handlers"
+	authHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/auth/handlers"
+	digestsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/digests/handlers"
+	failuresHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/failures/handlers"
 	managerHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/manager/handlers"
 	metricsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/metrics/handlers"
+	notificationsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/notifications/handlers"
+	sessionsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/sessions/handlers"
+	slosHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/slos/handlers"
+	solanaHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/solana/handlers"
+	tenantsHandlers "github.com/jcleira/encinitas-collector-go/internal/infra/http/tenants/handlers"
 	agentRepositoriesRedis "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/agent/redis"
+	alertsRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/alerts/sql"
+	alertsRepositoriesWebhook "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/alerts/webhook"
+	anomaliesRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/anomalies/sql"
+	authRepositoriesJWKS "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/auth/jwks"
+	digestsRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/digests/sql"
+	failuresRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/failures/sql"
+	mailRepositoriesSMTP "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/mail/smtp"
 	managerRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/manager/sql"
 	metricsRepositoriesInflux "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/metrics/influx"
+	notificationsRepositoriesSenders "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/notifications/senders"
+	notificationsRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/notifications/sql"
+	sessionsRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/sessions/sql"
+	slosRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/slos/sql"
 	solanaRepositoriesRedis "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/solana/redis"
 	solanaRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/solana/sql"
+	tenantsRepositoriesRedis "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/tenants/redis"
+	tenantsRepositoriesSQL "github.com/jcleira/encinitas-collector-go/internal/infra/repositories/tenants/sql"
 )
 
 var errSignalQuit = errors.New("signal quit")
@@ -43,7 +78,7 @@ func main() {
 
 	var config config.Config
 	if err := envconfig.Process("", &config); err != nil {
-		slog.Error("can't process envconfig: ", err)
+		slog.Error("can't process envconfig: ", slog.Any("error", err))
 		os.Exit(1)
 	}
 
@@ -56,19 +91,52 @@ func main() {
 		DB:       config.Redis.DB,
 	})
 
+	agentEventsOptions := agentRepositoriesRedis.Options{
+		KeyPrefix: config.AgentEvents.KeyPrefix,
+		TTLs: map[agentAggregates.EventClass]time.Duration{
+			agentAggregates.EventClassPending: config.AgentEvents.PendingTTL,
+			agentAggregates.EventClassJoined:  config.AgentEvents.JoinedTTL,
+		},
+	}
+
 	sqlx, err := sqlx.Connect("postgres", config.Postgres.URL())
 	if err != nil {
-		slog.Error("can't connect to postgres: ", err)
+		slog.Error("can't connect to postgres: ", slog.Any("error", err))
 		os.Exit(1)
 	}
 
 	influx := influxdb.NewClient(config.InfluxDB.URL, config.InfluxDB.Token)
 
+	broker := metricsServices.NewBroker(config.Stream.BufferSize)
+
+	mailer := mailRepositoriesSMTP.New(mailRepositoriesSMTP.Config{
+		Host:     config.Mail.SMTPHost,
+		Port:     config.Mail.SMTPPort,
+		Username: config.Mail.SMTPUsername,
+		Password: config.Mail.SMTPPassword,
+		From:     config.Mail.From,
+	})
+
+	digestsSchedule, err := digestsAggregates.NewSchedule(
+		config.Digests.Weekday, config.Digests.Hour)
+	if err != nil {
+		slog.Error("invalid digests schedule: ", slog.Any("error", err))
+		os.Exit(1)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
 		eventCollector := agentServices.NewEventCollector(
-			agentRepositoriesRedis.New(redisClient),
+			agentRepositoriesRedis.New(redisClient, agentEventsOptions),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			failuresRepositoriesSQL.New(sqlx),
+			sessionsRepositoriesSQL.New(sqlx),
 		)
 
 		logger.Info("starting event collector")
@@ -78,10 +146,30 @@ func main() {
 		return nil
 	})
 
+	g.Go(func() error {
+		storeReporter := agentServices.NewStoreReporter(
+			agentRepositoriesRedis.New(redisClient, agentEventsOptions),
+			config.AgentEvents.ReportInterval,
+		)
+
+		logger.Info("starting stored events reporter")
+		storeReporter.Report(ctx)
+		logger.Info("stored events reporter stopped")
+
+		return nil
+	})
+
 	g.Go(func() error {
 		transactionsCollector := solanaServices.NewTransactionsCollector(
 			solanaRepositoriesSQL.New(sqlx),
 			solanaRepositoriesRedis.New(redisClient),
+			solanaRepositoriesSQL.NewListener(
+				config.Postgres.URL(),
+				config.TransactionsCollector.MinReconnectInterval,
+				config.TransactionsCollector.MaxReconnectInterval,
+			),
+			config.TransactionsCollector.PollInterval,
+			config.TransactionsCollector.VisibilityTimeout,
 		)
 
 		logger.Info("starting transactions collector")
@@ -91,16 +179,32 @@ func main() {
 		return nil
 	})
 
+	g.Go(func() error {
+		slotTracker := solanaServices.NewSlotTracker(
+			solanaRepositoriesSQL.New(sqlx),
+			config.Lifecycles.SlotTrackingInterval,
+		)
+
+		logger.Info("starting slot tracker")
+		slotTracker.Track(ctx)
+		logger.Info("slot tracker stopped")
+
+		return nil
+	})
+
 	g.Go(func() error {
 		ingester := metricsServices.NewIngester(
 			solanaRepositoriesRedis.New(redisClient),
-			agentRepositoriesRedis.New(redisClient),
+			agentRepositoriesRedis.New(redisClient, agentEventsOptions),
 			metricsRepositoriesInflux.New(
 				influx,
 				config.InfluxDB.TelegrafURL,
 				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
 			),
 			solanaRepositoriesSQL.New(sqlx),
+			broker,
+			managerRepositoriesSQL.New(sqlx),
 		)
 
 		logger.Info("starting ingester")
@@ -110,13 +214,147 @@ func main() {
 		return nil
 	})
 
+	g.Go(func() error {
+		reconciler := metricsServices.NewReconciler(
+			agentRepositoriesRedis.New(redisClient, agentEventsOptions),
+			solanaRepositoriesSQL.New(sqlx),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			sessionsRepositoriesSQL.New(sqlx),
+			metricsServices.ReconcilerConfig{
+				Interval:      config.Reconciler.Interval,
+				ExpiryTimeout: config.Reconciler.ExpiryTimeout,
+			},
+		)
+
+		logger.Info("starting sent transactions reconciler")
+		reconciler.Reconcile(ctx)
+		logger.Info("sent transactions reconciler stopped")
+
+		return nil
+	})
+
+	g.Go(func() error {
+		evaluator := alertsServices.NewEvaluator(
+			alertsRepositoriesSQL.New(sqlx),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			alertsRepositoriesWebhook.New(
+				config.Alerts.WebhookURL,
+				config.Alerts.WebhookSecret,
+			),
+			notificationsServices.NewEnqueuer(
+				notificationsRepositoriesSQL.New(sqlx),
+			),
+			config.Alerts.EvaluationInterval,
+		)
+
+		logger.Info("starting alerts evaluator")
+		evaluator.Evaluate(ctx)
+		logger.Info("alerts evaluator stopped")
+
+		return nil
+	})
+
+	g.Go(func() error {
+		detector := anomaliesServices.NewDetector(
+			anomaliesRepositoriesSQL.New(sqlx),
+			managerRepositoriesSQL.New(sqlx),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.ProgramsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			anomaliesServices.DetectorConfig{
+				Window:      config.Anomalies.Window,
+				Sensitivity: config.Anomalies.Sensitivity,
+				Alpha:       config.Anomalies.Alpha,
+				MinSamples:  config.Anomalies.MinSamples,
+			},
+		)
+
+		logger.Info("starting anomaly detector")
+		detector.Detect(ctx)
+		logger.Info("anomaly detector stopped")
+
+		return nil
+	})
+
+	g.Go(func() error {
+		tracker := slosServices.NewTracker(
+			slosRepositoriesSQL.New(sqlx),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			config.SLOs.TrackingInterval,
+		)
+
+		logger.Info("starting slos tracker")
+		tracker.Track(ctx)
+		logger.Info("slos tracker stopped")
+
+		return nil
+	})
+
+	g.Go(func() error {
+		dispatcher := notificationsServices.NewDispatcher(
+			notificationsRepositoriesSQL.New(sqlx),
+			notificationsRepositoriesSenders.New(),
+			config.Notifications.DispatchInterval,
+			config.Notifications.MaxAttempts,
+		)
+
+		logger.Info("starting notifications dispatcher")
+		dispatcher.Dispatch(ctx)
+		logger.Info("notifications dispatcher stopped")
+
+		return nil
+	})
+
+	g.Go(func() error {
+		scheduler := digestsServices.NewScheduler(
+			digestsRepositoriesSQL.New(sqlx),
+			managerRepositoriesSQL.New(sqlx),
+			metricsRepositoriesInflux.New(
+				influx,
+				config.InfluxDB.TelegrafURL,
+				metricsRepositoriesInflux.TransactionsBucket,
+				config.Tenancy.BucketPerProject,
+			),
+			mailer,
+			digestsServices.SchedulerConfig{
+				Schedule:      digestsSchedule,
+				CheckInterval: config.Digests.CheckInterval,
+				PublicURL:     config.Digests.PublicURL,
+			},
+		)
+
+		logger.Info("starting digests scheduler")
+		scheduler.Schedule(ctx)
+		logger.Info("digests scheduler stopped")
+
+		return nil
+	})
+
 	g.Go(func() error {
 		router := gin.Default()
 
 		corsConfig := cors.Config{
 			AllowAllOrigins:  true,
 			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+			AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Content-Encoding", "X-API-Key", "X-Project-ID"},
 			ExposeHeaders:    []string{"Content-Length"},
 			AllowCredentials: true,
 			MaxAge:           12 * time.Hour,
@@ -124,41 +362,218 @@ func main() {
 
 		router.Use(cors.New(corsConfig))
 
+		waitlistConfig := managerServices.WaitlistConfig{
+			PublicURL:       config.Waitlist.PublicURL,
+			InviteURL:       config.Waitlist.InviteURL,
+			ConfirmationTTL: config.Waitlist.ConfirmationTTL,
+			ResendInterval:  config.Waitlist.ResendInterval,
+		}
+
+		adminTokens := config.Auth.AdminTokens
+		if config.Tenancy.AdminToken != "" {
+			slog.Warn("TENANCY_ADMIN_TOKEN is deprecated, set AUTH_ADMIN_TOKENS instead")
+			adminTokens = append(adminTokens, config.Tenancy.AdminToken)
+		}
+
+		authenticator := authServices.NewAuthenticator(
+			authRepositoriesJWKS.New(
+				config.Auth.JWKSFile,
+				config.Auth.JWKSURL,
+				config.Auth.JWKSRefreshInterval,
+			),
+			tenantsServices.NewResolver(
+				tenantsRepositoriesSQL.New(sqlx),
+			),
+			authServices.AuthenticatorConfig{
+				Issuer:       config.Auth.Issuer,
+				Audience:     config.Auth.Audience,
+				RoleClaim:    config.Auth.RoleClaim,
+				ProjectClaim: config.Auth.ProjectClaim,
+				AdminTokens:  adminTokens,
+				ClockSkew:    config.Auth.ClockSkew,
+			},
+		)
+
+		api := router.Group("/",
+			authHandlers.NewAuthMiddleware(
+				authenticator,
+				config.Auth.Enabled,
+			).Handle,
+			tenantsHandlers.NewTenantMiddleware(
+				tenantsServices.NewResolver(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+				config.Tenancy.Enabled,
+			).Handle,
+		)
+
+		viewer := api.Group("/",
+			authHandlers.NewRoleMiddleware(authAggregates.RoleViewer, false).Handle,
+		)
+
+		editor := api.Group("/",
+			authHandlers.NewRoleMiddleware(authAggregates.RoleEditor, false).Handle,
+		)
+
+		projectAdmin := api.Group("/",
+			authHandlers.NewRoleMiddleware(authAggregates.RoleAdmin, false).Handle,
+		)
+
+		router.GET("/agent/openapi.json",
+			agentHandlers.NewSchemaHandler().Handle)
+
+		ingestionAuthorizer := tenantsServices.NewIngestionAuthorizer(
+			tenantsRepositoriesSQL.New(sqlx),
+			tenantsRepositoriesRedis.New(redisClient),
+			tenantsRepositoriesRedis.New(redisClient),
+		)
+
 		router.POST("/agent/events",
+			tenantsHandlers.NewIngestionMiddleware(
+				ingestionAuthorizer,
+				config.Tenancy.APIKeysRequired,
+			).Handle,
 			agentHandlers.NewEventsCreatorHandler(
 				agentServices.NewEventPublisher(
-					agentRepositoriesRedis.New(redisClient),
+					agentRepositoriesRedis.New(redisClient, agentEventsOptions),
+				),
+				ingestionAuthorizer,
+			).Handle,
+		)
+
+		viewer.GET("/agent/events",
+			agentHandlers.NewEventGetterHandler(
+				agentServices.NewEventGetter(
+					agentRepositoriesRedis.New(redisClient, agentEventsOptions),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/agent/transactions/:signature/submissions",
+			agentHandlers.NewSubmissionsGetterHandler(
+				agentServices.NewEventGetter(
+					agentRepositoriesRedis.New(redisClient, agentEventsOptions),
 				),
 			).Handle,
 		)
 
-		router.GET("/metrics/query",
+		viewer.GET("/metrics/query",
 			metricsHandlers.NewMetricsRetriever(
 				metricsRepositoriesInflux.New(
 					influx,
 					config.InfluxDB.TelegrafURL,
 					metricsRepositoriesInflux.TransactionsBucket,
+					config.Tenancy.BucketPerProject,
 				),
 			).Handle,
 		)
 
-		router.GET("/metrics/programs/query",
+		viewer.GET("/metrics/programs/query",
 			metricsHandlers.NewMetricsProgramRetrieverHandler(
 				metricsRepositoriesInflux.New(
 					influx,
 					config.InfluxDB.TelegrafURL,
 					metricsRepositoriesInflux.ProgramsBucket,
+					config.Tenancy.BucketPerProject,
+				),
+			).Handle,
+		)
+
+		viewer.GET("/metrics/rpc/query",
+			metricsHandlers.NewRPCStatsRetrieverHandler(
+				metricsRepositoriesInflux.New(
+					influx,
+					config.InfluxDB.TelegrafURL,
+					metricsRepositoriesInflux.TransactionsBucket,
+					config.Tenancy.BucketPerProject,
+				),
+			).Handle,
+		)
+
+		viewer.GET("/metrics/rpc/providers",
+			metricsHandlers.NewProviderBenchmarksRetrieverHandler(
+				metricsRepositoriesInflux.New(
+					influx,
+					config.InfluxDB.TelegrafURL,
+					metricsRepositoriesInflux.TransactionsBucket,
+					config.Tenancy.BucketPerProject,
+				),
+			).Handle,
+		)
+
+		viewer.GET("/metrics/rpc/drops",
+			metricsHandlers.NewDropRatesRetrieverHandler(
+				metricsRepositoriesInflux.New(
+					influx,
+					config.InfluxDB.TelegrafURL,
+					metricsRepositoriesInflux.TransactionsBucket,
+					config.Tenancy.BucketPerProject,
+				),
+			).Handle,
+		)
+
+		viewer.GET("/metrics/rpc/failures",
+			failuresHandlers.NewFailureGetterHandler(
+				failuresServices.NewFailureGetter(
+					failuresRepositoriesSQL.New(sqlx),
 				),
 			).Handle,
 		)
 
-		router.GET("/transactions/query",
+		viewer.GET("/metrics/stream",
+			metricsHandlers.NewStreamHandler(
+				broker,
+				config.Stream.HeartbeatInterval,
+			).Handle,
+		)
+
+		viewer.GET("/metrics/stream/ws",
+			metricsHandlers.NewStreamWebSocketHandler(
+				broker,
+				config.Stream.HeartbeatInterval,
+				config.Stream.AllowedOrigins,
+			).Handle,
+		)
+
+		viewer.GET("/sessions",
+			sessionsHandlers.NewReportGetterHandler(
+				sessionsServices.NewReportGetter(
+					sessionsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/anomalies",
+			anomaliesHandlers.NewAnomaliesGetterHandler(
+				anomaliesServices.NewAnomaliesGetter(
+					anomaliesRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/transactions/query",
 			metricsHandlers.NewTransactionsRetriever(
 				solanaRepositoriesSQL.New(sqlx),
 			).Handle,
 		)
 
-		router.GET("/manager/programs",
+		viewer.GET("/transactions/lifecycles/query",
+			solanaHandlers.NewLifecycleStatsGetterHandler(
+				solanaServices.NewLifecycleGetter(
+					solanaRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/transactions/:signature/lifecycle",
+			solanaHandlers.NewLifecycleGetterHandler(
+				solanaServices.NewLifecycleGetter(
+					solanaRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/programs",
 			managerHandlers.NewProgramGetterHandler(
 				managerServices.NewProgramGetter(
 					managerRepositoriesSQL.New(sqlx),
@@ -166,7 +581,7 @@ func main() {
 			).Handle,
 		)
 
-		router.POST("/manager/programs",
+		editor.POST("/manager/programs",
 			managerHandlers.NewProgramsCreatorHandler(
 				managerServices.NewProgramCreator(
 					managerRepositoriesSQL.New(sqlx),
@@ -174,10 +589,420 @@ func main() {
 			).Handle,
 		)
 
+		viewer.GET("/manager/programs/:address",
+			managerHandlers.NewProgramByAddressGetterHandler(
+				managerServices.NewProgramGetter(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.PATCH("/manager/programs/:address",
+			managerHandlers.NewProgramUpdaterHandler(
+				managerServices.NewProgramUpdater(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.DELETE("/manager/programs/:address",
+			managerHandlers.NewProgramDeleterHandler(
+				managerServices.NewProgramDeleter(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.POST("/manager/programs/:address/restore",
+			managerHandlers.NewProgramRestorerHandler(
+				managerServices.NewProgramDeleter(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
 		router.POST("/manager/emails",
 			managerHandlers.NewEmailsCreatorHandler(
 				managerServices.NewEmailCreator(
 					managerRepositoriesSQL.New(sqlx),
+					mailer,
+					waitlistConfig,
+				),
+			).Handle,
+		)
+
+		router.GET("/manager/emails/confirm",
+			managerHandlers.NewEmailConfirmerHandler(
+				managerServices.NewEmailConfirmer(
+					managerRepositoriesSQL.New(sqlx),
+					waitlistConfig,
+				),
+			).Handle,
+		)
+
+		emailUnsubscriberHandler := managerHandlers.NewEmailUnsubscriberHandler(
+			managerServices.NewEmailUnsubscriber(
+				managerRepositoriesSQL.New(sqlx),
+			),
+		)
+
+		router.GET("/manager/emails/unsubscribe", emailUnsubscriberHandler.Handle)
+		router.POST("/manager/emails/unsubscribe", emailUnsubscriberHandler.Handle)
+
+		viewer.GET("/manager/programs/:address/subscriptions",
+			digestsHandlers.NewSubscriptionGetterHandler(
+				digestsServices.NewSubscriptionGetter(
+					digestsRepositoriesSQL.New(sqlx),
+				),
+			).Handle)
+
+		editor.POST("/manager/programs/:address/subscriptions",
+			digestsHandlers.NewSubscriptionCreatorHandler(
+				digestsServices.NewSubscriptionCreator(
+					digestsRepositoriesSQL.New(sqlx),
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle)
+
+		editor.DELETE("/manager/programs/:address/subscriptions/:id",
+			digestsHandlers.NewSubscriptionDeleterHandler(
+				digestsServices.NewSubscriptionDeleter(
+					digestsRepositoriesSQL.New(sqlx),
+				),
+			).Handle)
+
+		digestsUnsubscriberHandler := digestsHandlers.NewUnsubscriberHandler(
+			digestsServices.NewSubscriptionDeleter(
+				digestsRepositoriesSQL.New(sqlx),
+			),
+		)
+		router.GET("/manager/digests/unsubscribe", digestsUnsubscriberHandler.Handle)
+		router.POST("/manager/digests/unsubscribe", digestsUnsubscriberHandler.Handle)
+
+		viewer.GET("/manager/alerts",
+			alertsHandlers.NewRulesGetterHandler(
+				alertsServices.NewRuleGetter(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.POST("/manager/alerts",
+			alertsHandlers.NewRuleCreatorHandler(
+				alertsServices.NewRuleCreator(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/alerts/history",
+			alertsHandlers.NewHistoryGetterHandler(
+				alertsServices.NewHistoryGetter(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/alerts/:id",
+			alertsHandlers.NewRuleGetterHandler(
+				alertsServices.NewRuleGetter(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.PUT("/manager/alerts/:id",
+			alertsHandlers.NewRuleUpdaterHandler(
+				alertsServices.NewRuleUpdater(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.DELETE("/manager/alerts/:id",
+			alertsHandlers.NewRuleDeleterHandler(
+				alertsServices.NewRuleDeleter(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/alerts/:id/history",
+			alertsHandlers.NewHistoryGetterHandler(
+				alertsServices.NewHistoryGetter(
+					alertsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/slos",
+			slosHandlers.NewSLOsGetterHandler(
+				slosServices.NewSLOGetter(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.POST("/manager/slos",
+			slosHandlers.NewSLOCreatorHandler(
+				slosServices.NewSLOCreator(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/slos/status",
+			slosHandlers.NewStatusesGetterHandler(
+				slosServices.NewStatusGetter(
+					slosRepositoriesSQL.New(sqlx),
+					metricsRepositoriesInflux.New(
+						influx,
+						config.InfluxDB.TelegrafURL,
+						metricsRepositoriesInflux.TransactionsBucket,
+						config.Tenancy.BucketPerProject,
+					),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/slos/:id",
+			slosHandlers.NewSLOGetterHandler(
+				slosServices.NewSLOGetter(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.PUT("/manager/slos/:id",
+			slosHandlers.NewSLOUpdaterHandler(
+				slosServices.NewSLOUpdater(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.DELETE("/manager/slos/:id",
+			slosHandlers.NewSLODeleterHandler(
+				slosServices.NewSLODeleter(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/slos/:id/status",
+			slosHandlers.NewStatusGetterHandler(
+				slosServices.NewStatusGetter(
+					slosRepositoriesSQL.New(sqlx),
+					metricsRepositoriesInflux.New(
+						influx,
+						config.InfluxDB.TelegrafURL,
+						metricsRepositoriesInflux.TransactionsBucket,
+						config.Tenancy.BucketPerProject,
+					),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/slos/:id/history",
+			slosHandlers.NewHistoryGetterHandler(
+				slosServices.NewHistoryGetter(
+					slosRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/channels",
+			notificationsHandlers.NewChannelsGetterHandler(
+				notificationsServices.NewChannelGetter(
+					notificationsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.POST("/manager/channels",
+			notificationsHandlers.NewChannelCreatorHandler(
+				notificationsServices.NewChannelCreator(
+					notificationsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		viewer.GET("/manager/channels/:id",
+			notificationsHandlers.NewChannelGetterHandler(
+				notificationsServices.NewChannelGetter(
+					notificationsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.PUT("/manager/channels/:id",
+			notificationsHandlers.NewChannelUpdaterHandler(
+				notificationsServices.NewChannelUpdater(
+					notificationsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.DELETE("/manager/channels/:id",
+			notificationsHandlers.NewChannelDeleterHandler(
+				notificationsServices.NewChannelDeleter(
+					notificationsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		editor.POST("/manager/channels/:id/test",
+			notificationsHandlers.NewChannelTesterHandler(
+				notificationsServices.NewChannelTester(
+					notificationsRepositoriesSQL.New(sqlx),
+					notificationsRepositoriesSenders.New(),
+				),
+			).Handle,
+		)
+
+		projectAdmin.GET("/manager/keys",
+			tenantsHandlers.NewAPIKeysGetterHandler(
+				tenantsServices.NewAPIKeyGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		projectAdmin.POST("/manager/keys",
+			tenantsHandlers.NewAPIKeyCreatorHandler(
+				tenantsServices.NewAPIKeyCreator(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		projectAdmin.GET("/manager/keys/:id",
+			tenantsHandlers.NewAPIKeyGetterHandler(
+				tenantsServices.NewAPIKeyGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		projectAdmin.DELETE("/manager/keys/:id",
+			tenantsHandlers.NewAPIKeyRevokerHandler(
+				tenantsServices.NewAPIKeyRevoker(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		projectAdmin.POST("/manager/keys/:id/rotate",
+			tenantsHandlers.NewAPIKeyRotatorHandler(
+				tenantsServices.NewAPIKeyRotator(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		projectAdmin.GET("/manager/keys/:id/usage",
+			tenantsHandlers.NewAPIKeyUsageGetterHandler(
+				tenantsServices.NewAPIKeyUsageGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+					tenantsRepositoriesRedis.New(redisClient),
+				),
+			).Handle,
+		)
+
+		admin := router.Group("/admin",
+			authHandlers.NewAuthMiddleware(
+				authenticator,
+				true,
+			).Handle,
+			authHandlers.NewRoleMiddleware(authAggregates.RoleAdmin, true).Handle,
+		)
+
+		admin.GET("/organizations",
+			tenantsHandlers.NewOrganizationsGetterHandler(
+				tenantsServices.NewOrganizationGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.POST("/organizations",
+			tenantsHandlers.NewOrganizationCreatorHandler(
+				tenantsServices.NewOrganizationCreator(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.GET("/organizations/:id",
+			tenantsHandlers.NewOrganizationGetterHandler(
+				tenantsServices.NewOrganizationGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.GET("/organizations/:id/projects",
+			tenantsHandlers.NewProjectsGetterHandler(
+				tenantsServices.NewProjectGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.POST("/organizations/:id/projects",
+			tenantsHandlers.NewProjectCreatorHandler(
+				tenantsServices.NewProjectCreator(
+					tenantsRepositoriesSQL.New(sqlx),
+					metricsRepositoriesInflux.New(
+						influx,
+						config.InfluxDB.TelegrafURL,
+						metricsRepositoriesInflux.TransactionsBucket,
+						config.Tenancy.BucketPerProject,
+					),
+				),
+			).Handle,
+		)
+
+		admin.GET("/projects/:id",
+			tenantsHandlers.NewProjectGetterHandler(
+				tenantsServices.NewProjectGetter(
+					tenantsRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.GET("/emails",
+			managerHandlers.NewEmailsGetterHandler(
+				managerServices.NewEmailGetter(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.GET("/emails/export",
+			managerHandlers.NewEmailsExporterHandler(
+				managerServices.NewEmailGetter(
+					managerRepositoriesSQL.New(sqlx),
+				),
+			).Handle,
+		)
+
+		admin.POST("/emails/invite",
+			managerHandlers.NewEmailsInviterHandler(
+				managerServices.NewEmailInviter(
+					managerRepositoriesSQL.New(sqlx),
+					mailer,
+					waitlistConfig,
+				),
+			).Handle,
+		)
+
+		admin.POST("/emails/:id/invite",
+			managerHandlers.NewEmailInviterHandler(
+				managerServices.NewEmailInviter(
+					managerRepositoriesSQL.New(sqlx),
+					mailer,
+					waitlistConfig,
 				),
 			).Handle,
 		)
@@ -201,7 +1026,7 @@ func main() {
 
 	err = g.Wait()
 	if !errors.Is(err, errSignalQuit) {
-		slog.Error("error while waiting for errgroup: ", err)
+		slog.Error("error while waiting for errgroup: ", slog.Any("error", err))
 		os.Exit(1)
 	}
 }
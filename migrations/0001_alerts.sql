@@ -0,0 +1,46 @@
+-- Alert rules evaluated by the collector against the ingested metrics.
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id              BIGSERIAL PRIMARY KEY,
+  name            TEXT NOT NULL,
+  metric          TEXT NOT NULL,
+  program_address TEXT,
+  comparison      TEXT NOT NULL,
+  threshold       DOUBLE PRECISION NOT NULL,
+  hysteresis      DOUBLE PRECISION NOT NULL DEFAULT 0,
+  window_seconds  BIGINT NOT NULL,
+  for_seconds     BIGINT NOT NULL DEFAULT 0,
+  severity        TEXT NOT NULL,
+  webhook_url     TEXT,
+  enabled         BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at      TIMESTAMPTZ
+);
+
+-- Current state of every evaluated rule, so restarts don't lose pending or
+-- firing alerts.
+CREATE TABLE IF NOT EXISTS alert_states (
+  rule_id           BIGINT PRIMARY KEY REFERENCES alert_rules (id),
+  state             TEXT NOT NULL,
+  pending_since     TIMESTAMPTZ,
+  firing_since      TIMESTAMPTZ,
+  last_value        DOUBLE PRECISION NOT NULL,
+  last_evaluated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Alert history, one row per firing/resolved state change.
+CREATE TABLE IF NOT EXISTS alert_events (
+  id              BIGSERIAL PRIMARY KEY,
+  rule_id         BIGINT NOT NULL REFERENCES alert_rules (id),
+  rule_name       TEXT NOT NULL,
+  metric          TEXT NOT NULL,
+  program_address TEXT,
+  severity        TEXT NOT NULL,
+  state           TEXT NOT NULL,
+  value           DOUBLE PRECISION NOT NULL,
+  threshold       DOUBLE PRECISION NOT NULL,
+  created_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS alert_events_rule_id_created_at_idx
+  ON alert_events (rule_id, created_at DESC);
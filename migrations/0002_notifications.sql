@@ -0,0 +1,35 @@
+-- Notification channels, config holds the type specific settings and
+-- credentials (e.g. the Slack webhook URL or the PagerDuty routing key).
+CREATE TABLE IF NOT EXISTS notification_channels (
+  id         BIGSERIAL PRIMARY KEY,
+  name       TEXT NOT NULL,
+  type       TEXT NOT NULL,
+  config     JSONB NOT NULL DEFAULT '{}',
+  template   TEXT,
+  enabled    BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+-- Outbox of the notifications to deliver, one row per message and channel,
+-- failed deliveries are retried with backoff until the max attempts.
+CREATE TABLE IF NOT EXISTS notification_outbox (
+  id              BIGSERIAL PRIMARY KEY,
+  channel_id      BIGINT NOT NULL REFERENCES notification_channels (id),
+  message         JSONB NOT NULL,
+  status          TEXT NOT NULL,
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  last_error      TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  sent_at         TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS notification_outbox_pending_idx
+  ON notification_outbox (next_attempt_at)
+  WHERE status = 'pending';
+
+-- Notification channels every alert rule state change is sent to.
+ALTER TABLE alert_rules
+  ADD COLUMN IF NOT EXISTS channel_ids BIGINT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,31 @@
+-- Per program baselines of the anomaly detector metrics, one row per hour of
+-- the week (0 is Sunday 00:00 UTC) to follow the traffic seasonality.
+CREATE TABLE IF NOT EXISTS anomaly_baselines (
+  program_address TEXT NOT NULL,
+  metric          TEXT NOT NULL,
+  hour_of_week    SMALLINT NOT NULL,
+  samples         BIGINT NOT NULL,
+  mean            DOUBLE PRECISION NOT NULL,
+  variance        DOUBLE PRECISION NOT NULL,
+  last_window_end TIMESTAMPTZ NOT NULL,
+  updated_at      TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (program_address, metric, hour_of_week)
+);
+
+-- Windows whose metric value fell out of its baseline band.
+CREATE TABLE IF NOT EXISTS anomalies (
+  id              BIGSERIAL PRIMARY KEY,
+  program_address TEXT NOT NULL,
+  metric          TEXT NOT NULL,
+  window_start    TIMESTAMPTZ NOT NULL,
+  window_end      TIMESTAMPTZ NOT NULL,
+  value           DOUBLE PRECISION NOT NULL,
+  expected        DOUBLE PRECISION NOT NULL,
+  lower_bound     DOUBLE PRECISION NOT NULL,
+  upper_bound     DOUBLE PRECISION NOT NULL,
+  z_score         DOUBLE PRECISION NOT NULL,
+  created_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS anomalies_program_address_window_start_idx
+  ON anomalies (program_address, window_start DESC);
@@ -0,0 +1,29 @@
+-- Service level objectives, objective is a percentage (e.g. 99.5) and the
+-- latency threshold only applies to the latency indicator.
+CREATE TABLE IF NOT EXISTS slos (
+  id                   BIGSERIAL PRIMARY KEY,
+  name                 TEXT NOT NULL,
+  program_address      TEXT,
+  indicator            TEXT NOT NULL,
+  objective            DOUBLE PRECISION NOT NULL,
+  latency_threshold_ms BIGINT NOT NULL DEFAULT 0,
+  window_seconds       BIGINT NOT NULL,
+  enabled              BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at           TIMESTAMPTZ
+);
+
+-- Periodic SLO statuses, burn_rates follows the 1h, 6h and 3d windows order.
+CREATE TABLE IF NOT EXISTS slo_statuses (
+  slo_id           BIGINT NOT NULL REFERENCES slos (id),
+  total            BIGINT NOT NULL,
+  good             BIGINT NOT NULL,
+  compliance       DOUBLE PRECISION NOT NULL,
+  budget_remaining DOUBLE PRECISION NOT NULL,
+  burn_rates       DOUBLE PRECISION[] NOT NULL,
+  computed_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS slo_statuses_slo_id_computed_at_idx
+  ON slo_statuses (slo_id, computed_at DESC);
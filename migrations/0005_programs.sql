@@ -0,0 +1,15 @@
+-- Program management fields, the programs table itself predates the
+-- migrations folder.
+ALTER TABLE programs
+  ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE programs
+  ALTER COLUMN priority SET DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS programs_program_address_idx
+  ON programs (program_address);
+
+-- Prefix searches for the programs autocomplete.
+CREATE INDEX IF NOT EXISTS programs_program_name_prefix_idx
+  ON programs (lower(program_name) text_pattern_ops);
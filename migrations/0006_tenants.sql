@@ -0,0 +1,71 @@
+-- Organizations own projects, projects are the tenants every program, agent
+-- event, metric and configuration belongs to.
+CREATE TABLE IF NOT EXISTS organizations (
+  id         BIGSERIAL PRIMARY KEY,
+  name       TEXT NOT NULL,
+  slug       TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- token_hash is the SHA-256 of the project token, the default project has no
+-- token as it's only used when tenancy is disabled.
+CREATE TABLE IF NOT EXISTS projects (
+  id              BIGSERIAL PRIMARY KEY,
+  organization_id BIGINT NOT NULL REFERENCES organizations (id),
+  name            TEXT NOT NULL,
+  slug            TEXT NOT NULL,
+  token_hash      TEXT UNIQUE,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (organization_id, slug)
+);
+
+-- The default tenant owns every row that predates the multi-tenancy.
+INSERT INTO organizations (id, name, slug)
+VALUES (1, 'Default', 'default')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO projects (id, organization_id, name, slug)
+VALUES (1, 1, 'Default', 'default')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval('organizations_id_seq', GREATEST((SELECT MAX(id) FROM organizations), 1));
+SELECT setval('projects_id_seq', GREATEST((SELECT MAX(id) FROM projects), 1));
+
+ALTER TABLE programs
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+-- A program can be monitored by several projects, but only once per project.
+DROP INDEX IF EXISTS programs_program_address_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS programs_project_id_program_address_idx
+  ON programs (project_id, program_address);
+
+ALTER TABLE encinitas_transaction_details
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE alert_rules
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE alert_events
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE notification_channels
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE slos
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE anomalies
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+-- Baselines are per project as every project has its own metrics series.
+ALTER TABLE anomaly_baselines
+  ADD COLUMN IF NOT EXISTS project_id BIGINT NOT NULL DEFAULT 1 REFERENCES projects (id);
+
+ALTER TABLE anomaly_baselines
+  DROP CONSTRAINT IF EXISTS anomaly_baselines_pkey;
+
+ALTER TABLE anomaly_baselines
+  ADD PRIMARY KEY (project_id, program_address, metric, hour_of_week);
@@ -0,0 +1,19 @@
+-- Publishable keys the agents use to send their events to a project.
+-- key_hash is the SHA-256 of the key and hint its first characters, so users
+-- can tell the keys apart. A key with expires_at is being rotated.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id              BIGSERIAL PRIMARY KEY,
+  project_id      BIGINT NOT NULL REFERENCES projects (id),
+  name            TEXT NOT NULL,
+  hint            TEXT NOT NULL,
+  key_hash        TEXT NOT NULL UNIQUE,
+  allowed_origins TEXT[] NOT NULL DEFAULT '{}',
+  rate_limit      DOUBLE PRECISION NOT NULL,
+  burst           BIGINT NOT NULL,
+  expires_at      TIMESTAMPTZ,
+  revoked_at      TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_project_id_idx ON api_keys (project_id);
@@ -0,0 +1,41 @@
+-- The waitlist emails predate the migrations, the table is only created
+-- when it doesn't exist yet.
+CREATE TABLE IF NOT EXISTS emails (
+  email      TEXT NOT NULL,
+  email_name TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS id BIGSERIAL PRIMARY KEY;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS email_name TEXT;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
+-- The confirmation tokens are hashed, the unsubscribe tokens are kept as they
+-- are linked from every email and only allow to unsubscribe.
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS confirmation_token_hash TEXT;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS confirmation_sent_at TIMESTAMPTZ;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS unsubscribe_token TEXT;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMPTZ;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS invited_at TIMESTAMPTZ;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS unsubscribed_at TIMESTAMPTZ;
+ALTER TABLE emails ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- The duplicates check never worked, normalize the addresses and keep the
+-- oldest entry of every address.
+UPDATE emails SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+
+UPDATE emails SET deleted_at = NOW()
+WHERE deleted_at IS NULL AND id NOT IN (
+  SELECT MIN(id) FROM emails WHERE deleted_at IS NULL GROUP BY email
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS emails_email_idx
+  ON emails (email) WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS emails_confirmation_token_hash_idx
+  ON emails (confirmation_token_hash);
+
+CREATE UNIQUE INDEX IF NOT EXISTS emails_unsubscribe_token_idx
+  ON emails (unsubscribe_token);
+
+CREATE INDEX IF NOT EXISTS emails_status_idx ON emails (status);
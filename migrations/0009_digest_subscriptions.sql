@@ -0,0 +1,16 @@
+-- Emails subscribed to the weekly performance digest of a program.
+-- last_sent_at is the end of the last period the digest was sent for, it's
+-- claimed before sending so every period is sent once.
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+  id                BIGSERIAL PRIMARY KEY,
+  project_id        BIGINT NOT NULL REFERENCES projects (id),
+  program_address   TEXT NOT NULL,
+  email             TEXT NOT NULL,
+  unsubscribe_token TEXT NOT NULL UNIQUE,
+  last_sent_at      TIMESTAMPTZ,
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (project_id, program_address, email)
+);
+
+CREATE INDEX IF NOT EXISTS digest_subscriptions_last_sent_at_idx
+  ON digest_subscriptions (last_sent_at);
@@ -0,0 +1,25 @@
+-- Failed RPC calls captured by the agents, searchable by endpoint and method.
+-- rate_limit holds the rate limit headers of the response.
+CREATE TABLE IF NOT EXISTS rpc_failures (
+  id          BIGSERIAL PRIMARY KEY,
+  project_id  BIGINT NOT NULL REFERENCES projects (id),
+  event_id    TEXT NOT NULL,
+  endpoint    TEXT NOT NULL,
+  origin      TEXT NOT NULL DEFAULT '',
+  method      TEXT NOT NULL,
+  kind        TEXT NOT NULL,
+  batch       BOOLEAN NOT NULL DEFAULT FALSE,
+  http_status INTEGER NOT NULL DEFAULT 0,
+  rpc_code    BIGINT,
+  message     TEXT NOT NULL DEFAULT '',
+  logs        TEXT[] NOT NULL DEFAULT '{}',
+  rate_limit  JSONB NOT NULL DEFAULT '{}',
+  occurred_at TIMESTAMPTZ NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS rpc_failures_project_id_occurred_at_idx
+  ON rpc_failures (project_id, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS rpc_failures_endpoint_method_idx
+  ON rpc_failures (project_id, endpoint, method, occurred_at DESC);
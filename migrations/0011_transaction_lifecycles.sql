@@ -0,0 +1,38 @@
+-- The first time every slot reached every commitment level, derived from
+-- the status updates of the accountsdb plugin slot table, which only keeps
+-- the latest status of every slot.
+CREATE TABLE IF NOT EXISTS slot_statuses (
+  slot    BIGINT NOT NULL,
+  status  TEXT NOT NULL,
+  seen_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (slot, status)
+);
+
+CREATE INDEX IF NOT EXISTS slot_statuses_seen_at_idx
+  ON slot_statuses (seen_at);
+
+-- The slot table predates the migrations folder, its updates are polled by
+-- time.
+CREATE INDEX IF NOT EXISTS slot_updated_on_idx
+  ON slot (updated_on);
+
+-- The lifecycle of every ingested transaction, per project monitoring any
+-- of its programs. submitted_at is when an agent sent it, when it did, and
+-- seen_at when the geyser plugin first notified it; the commitment times
+-- come from the slot_statuses of its slot.
+CREATE TABLE IF NOT EXISTS transaction_lifecycles (
+  project_id        BIGINT NOT NULL REFERENCES projects (id),
+  signature         TEXT NOT NULL,
+  slot              BIGINT NOT NULL,
+  program_addresses TEXT[] NOT NULL DEFAULT '{}',
+  submitted_at      TIMESTAMPTZ,
+  seen_at           TIMESTAMPTZ NOT NULL,
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (project_id, signature)
+);
+
+CREATE INDEX IF NOT EXISTS transaction_lifecycles_seen_at_idx
+  ON transaction_lifecycles (project_id, seen_at DESC);
+
+CREATE INDEX IF NOT EXISTS transaction_lifecycles_program_addresses_idx
+  ON transaction_lifecycles USING GIN (program_addresses);
@@ -0,0 +1,28 @@
+-- The transactions the agents sent with sendTransaction, one row per
+-- signature whatever the amount of submissions, to reconstruct the sessions
+-- of every browser and the experience of every fee payer wallet. outcome is
+-- NULL until the transaction is known to have landed, been dropped, expired
+-- or failed, and error holds the message of the last failed submission.
+CREATE TABLE IF NOT EXISTS transaction_attempts (
+  project_id        BIGINT NOT NULL REFERENCES projects (id),
+  signature         TEXT NOT NULL,
+  browser_id        TEXT NOT NULL DEFAULT '',
+  client_id         TEXT NOT NULL DEFAULT '',
+  fee_payer         TEXT NOT NULL,
+  program_addresses TEXT[] NOT NULL DEFAULT '{}',
+  submissions       INTEGER NOT NULL DEFAULT 1,
+  submitted_at      TIMESTAMPTZ NOT NULL,
+  outcome           TEXT,
+  error             TEXT NOT NULL DEFAULT '',
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (project_id, signature)
+);
+
+CREATE INDEX IF NOT EXISTS transaction_attempts_submitted_at_idx
+  ON transaction_attempts (project_id, submitted_at DESC);
+
+CREATE INDEX IF NOT EXISTS transaction_attempts_program_addresses_idx
+  ON transaction_attempts USING GIN (program_addresses);
+
+CREATE INDEX IF NOT EXISTS transaction_attempts_fee_payer_idx
+  ON transaction_attempts (project_id, fee_payer, submitted_at DESC);
@@ -0,0 +1,16 @@
+-- Notifies the transactions collector whenever the geyser plugin inserts
+-- transactions, once per statement since the collector looks up every
+-- unprocessed transaction anyway. The payload is left empty so the
+-- notifications of the same transaction are folded into one.
+CREATE OR REPLACE FUNCTION notify_encinitas_transactions() RETURNS TRIGGER AS $$
+BEGIN
+  PERFORM pg_notify('encinitas_transactions', '');
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS encinitas_transactions_notify ON encinitas_transactions;
+
+CREATE TRIGGER encinitas_transactions_notify
+  AFTER INSERT ON encinitas_transactions
+  FOR EACH STATEMENT EXECUTE FUNCTION notify_encinitas_transactions();
@@ -0,0 +1,9 @@
+-- When a collector replica claimed every unprocessed transaction, the ones
+-- claimed longer than the visibility timeout ago are claimed again since
+-- the replica likely crashed before processing them.
+ALTER TABLE encinitas_transactions
+  ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS encinitas_transactions_unprocessed_idx
+  ON encinitas_transactions (slot, txn_index)
+  WHERE processed_at IS NULL;